@@ -10,6 +10,9 @@ import (
 
 var upgradeDryRun bool
 var upgradeVerbose bool
+var upgradeForce bool
+var upgradePruneNamespace bool
+var upgradeSelector string
 
 // upgradeCmd represents the upgrade command
 var upgradeCmd = &cobra.Command{
@@ -23,9 +26,12 @@ var upgradeCmd = &cobra.Command{
 		}
 
 		err := chart.Upgrade(chart.UpgradeOptions{
-			ChartPath: path,
-			DryRun:    upgradeDryRun,
-			Verbose:   upgradeVerbose,
+			ChartPath:      path,
+			DryRun:         upgradeDryRun,
+			Verbose:        upgradeVerbose,
+			Force:          upgradeForce,
+			PruneNamespace: upgradePruneNamespace,
+			Selector:       upgradeSelector,
 		})
 
 		if err != nil {
@@ -38,6 +44,9 @@ var upgradeCmd = &cobra.Command{
 func init() {
 	upgradeCmd.Flags().BoolVar(&upgradeDryRun, "dry-run", false, "Preview changes without applying them")
 	upgradeCmd.Flags().BoolVarP(&upgradeVerbose, "verbose", "v", false, "Verbose mode")
+	upgradeCmd.Flags().BoolVar(&upgradePruneNamespace, "prune-namespace", false, "Delete every resource the chart manages instead of reconciling it")
+	upgradeCmd.Flags().StringVarP(&upgradeSelector, "selector", "l", "", "Only upgrade resources with these labels (key=value,key2=value2)")
+	upgradeCmd.Flags().BoolVar(&upgradeForce, "force", false, "Delete volumes and secrets even when they are delete-protected")
 
 	rootCmd.AddCommand(upgradeCmd)
 }
@@ -2,6 +2,7 @@ package chart
 
 import (
 	"context"
+	"cutepod/internal/labels"
 	"cutepod/internal/resource"
 	"fmt"
 	"time"
@@ -13,6 +14,13 @@ type UpgradeOptions struct {
 	ChartPath string
 	DryRun    bool
 	Verbose   bool
+	Force     bool // Delete volumes and secrets even when they are delete-protected
+
+	// PruneNamespace deletes every resource the chart manages instead of reconciling it
+	PruneNamespace bool
+
+	// Selector limits the upgrade to the chart's resources matching "key=value,key2=value2"
+	Selector string
 }
 
 var (
@@ -22,6 +30,15 @@ var (
 )
 
 func Upgrade(opts UpgradeOptions) error {
+	if opts.PruneNamespace && opts.DryRun {
+		return fmt.Errorf("--prune-namespace cannot be combined with --dry-run")
+	}
+
+	selector, err := labels.ParseSelector(opts.Selector)
+	if err != nil {
+		return err
+	}
+
 	// Parse the chart and get resources
 	registry, err := Parse(ParseOptions{
 		ChartPath: opts.ChartPath,
@@ -34,14 +51,26 @@ func Upgrade(opts UpgradeOptions) error {
 	fmt.Printf("Upgrading chart: %s\n", registry.Chart.Name)
 
 	// Create reconciliation controller with Podman client and registry
-	controller := resource.NewReconciliationControllerWithURIAndRegistry(resource.GetPodmanURI(), registry.Registry)
+	var controllerOpts []resource.ControllerOption
+	if opts.Force {
+		controllerOpts = append(controllerOpts, resource.WithForceDelete())
+	}
+	controller := resource.NewReconciliationControllerWithURIAndRegistry(resource.GetPodmanURI(), registry.Registry, controllerOpts...)
 
 	// Get all resources from the registry
 	manifests := registry.GetAllResources()
 
-	// Execute reconciliation
+	// Execute reconciliation, or tear the chart down without looking at its manifests
 	ctx := context.Background()
-	result, err := controller.Reconcile(ctx, manifests, registry.Chart.Name, opts.DryRun)
+	if len(selector) > 0 {
+		ctx = resource.WithLabelSelector(ctx, selector)
+	}
+	var result *resource.ReconciliationResult
+	if opts.PruneNamespace {
+		result, err = controller.Destroy(ctx, registry.Chart.Name)
+	} else {
+		result, err = controller.Reconcile(ctx, manifests, registry.Chart.Name, opts.DryRun)
+	}
 	if err != nil {
 		return fmt.Errorf("reconciliation failed: %w", err)
 	}
@@ -92,6 +121,10 @@ func displayDryRunResult(result *resource.ReconciliationResult) {
 	if len(result.DeletedResources) > 0 {
 		fmt.Println(lipgloss.NewStyle().Bold(true).Render("Resources to be deleted:"))
 		for _, action := range result.DeletedResources {
+			if action.Action == resource.ActionSkip {
+				fmt.Printf("  = %s %s (%s)\n", action.Type, action.Name, action.Message)
+				continue
+			}
 			fmt.Printf("  - %s %s\n", action.Type, action.Name)
 		}
 		fmt.Println()
@@ -144,7 +177,9 @@ func displayExecutionResult(result *resource.ReconciliationResult) {
 	if len(result.DeletedResources) > 0 {
 		fmt.Println(lipgloss.NewStyle().Bold(true).Render("Deleted:"))
 		for _, action := range result.DeletedResources {
-			if action.Error == "" {
+			if action.Action == resource.ActionSkip {
+				fmt.Printf("  %s %s %s - %s\n", warnStyle.Render("="), action.Type, action.Name, action.Message)
+			} else if action.Error == "" {
 				fmt.Printf("  %s %s %s\n", doneStyle.Render("✓"), action.Type, action.Name)
 				successCount++
 			} else {
@@ -163,6 +198,9 @@ func displayExecutionResult(result *resource.ReconciliationResult) {
 			} else {
 				fmt.Printf("  %s %s: %s\n", failStyle.Render("✗"), err.Resource.Name, err.Message)
 			}
+			for _, line := range err.Logs {
+				fmt.Printf("      | %s\n", line)
+			}
 		}
 		fmt.Println()
 	}
@@ -1,6 +1,16 @@
 package labels
 
-import "fmt"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// DefaultPrefix starts the keys of every label and annotation cutepod sets or reads.
+// The prefix of the chart, version and managed-by labels can be changed, such as to
+// "acme.io/", with WithPrefix.
+const DefaultPrefix = "cutepod.io/"
 
 // Standard labels used for resource tracking and management
 const (
@@ -15,6 +25,65 @@ const (
 
 	// ManagedByValue is the value used for the managed-by label
 	ManagedByValue = "cutepod-v1"
+
+	// LabelSpecHash holds a hash of the spec a container was created from
+	LabelSpecHash = "cutepod.io/spec-hash"
+
+	// LabelContainerfileHash holds a hash of the Containerfile a container's image was built from
+	LabelContainerfileHash = "cutepod.io/containerfile-hash"
+
+	// LabelSecretHash holds a salted HMAC of the data a secret was created from, since
+	// Podman does not expose the data itself
+	LabelSecretHash = "cutepod.io/secret-hash"
+
+	// LabelSecretEnvKeys holds the comma-separated names of the environment variables a
+	// container was given from its secrets, whose keys can change without its spec changing
+	LabelSecretEnvKeys = "cutepod.io/secret-env-keys"
+
+	// LabelEmptyDirs holds the comma-separated names of the emptyDir volumes a container
+	// mounts, so that a shared emptyDir is only cleaned up once no container uses it
+	LabelEmptyDirs = "cutepod.io/empty-dirs"
+
+	// LabelComponent groups the containers of a chart that are replicas of the same
+	// component, such as three workers, so that a rolling update replaces them in turn
+	LabelComponent = "cutepod.io/component"
+
+	// LabelPreStop holds the JSON-encoded preStop command of a container, which has to
+	// be known when the container is deleted
+	LabelPreStop = "cutepod.io/pre-stop"
+
+	// AnnotationPaused marks a container that was read back in the paused state
+	AnnotationPaused = "cutepod.io/paused"
+
+	// AnnotationStopped holds the state of a container that was read back without running,
+	// such as "exited" after being stopped by hand
+	AnnotationStopped = "cutepod.io/stopped"
+
+	// AnnotationForceRecreate set to "true" makes every update of a container recreate
+	// it, even when the change could be applied in place
+	AnnotationForceRecreate = "cutepod.io/force-recreate"
+
+	// AnnotationRestartNonce restarts a container whenever its value changes, such as
+	// when it is bumped to re-run the container's startup. The container is recreated
+	// instead when its spec changed too.
+	AnnotationRestartNonce = "cutepod.io/restart-nonce"
+
+	// AnnotationDeleteProtect set to "true" keeps a volume or secret from being deleted,
+	// even once it is gone from the chart. It is stored as a label when the volume or
+	// secret is created, so that it outlives the manifest. Podman cannot add the label
+	// later, so adding the annotation to an existing volume or secret only logs a
+	// warning until the resource is recreated.
+	AnnotationDeleteProtect = "cutepod.io/delete-protect"
+
+	// AnnotationPodmanFlagPrefix starts the annotations that set a Podman create flag
+	// cutepod does not model, such as cutepod.io/podman-flag/cpu-shares. Only a fixed set
+	// of flags is supported.
+	AnnotationPodmanFlagPrefix = "cutepod.io/podman-flag/"
+
+	// AnnotationReconcile set to "paused" keeps reconciliation from changing a resource,
+	// such as while it is debugged by hand. With every resource of a chart paused, a
+	// reconcile changes nothing, orphans included.
+	AnnotationReconcile = "cutepod.io/reconcile"
 )
 
 // GetStandardLabels returns the standard labels for a resource
@@ -46,3 +115,66 @@ func MergeLabels(standardLabels, additionalLabels map[string]string) map[string]
 func GetChartLabelValue(name string) string {
 	return fmt.Sprintf("%s=%s", LabelChart, name)
 }
+
+// standardKeys are the keys of the labels identifying a chart's resources, whose prefix
+// can be changed
+var standardKeys = []string{LabelChart, LabelVersion, LabelManagedBy}
+
+// WithPrefix returns a standard label key, such as LabelChart, under prefix instead of
+// DefaultPrefix. Other keys and an empty prefix leave the key as it is.
+func WithPrefix(key, prefix string) string {
+	if prefix == "" || !slices.Contains(standardKeys, key) {
+		return key
+	}
+	return prefix + strings.TrimPrefix(key, DefaultPrefix)
+}
+
+// StandardKeys returns the keys of the standard labels under prefix
+func StandardKeys(prefix string) []string {
+	keys := make([]string, 0, len(standardKeys))
+	for _, key := range standardKeys {
+		keys = append(keys, WithPrefix(key, prefix))
+	}
+	return keys
+}
+
+// GetChartLabelValueWithPrefix returns the chart label filter for name, with the chart
+// label under prefix
+func GetChartLabelValueWithPrefix(name, prefix string) string {
+	return fmt.Sprintf("%s=%s", WithPrefix(LabelChart, prefix), name)
+}
+
+// MovePrefix returns a copy of resourceLabels with the standard labels moved under
+// prefix, or resourceLabels itself when there is nothing to move
+func MovePrefix(resourceLabels map[string]string, prefix string) map[string]string {
+	if prefix == "" || prefix == DefaultPrefix {
+		return resourceLabels
+	}
+
+	moved := maps.Clone(resourceLabels)
+	for _, key := range standardKeys {
+		if value, exists := moved[key]; exists {
+			delete(moved, key)
+			moved[WithPrefix(key, prefix)] = value
+		}
+	}
+	return moved
+}
+
+// ParseSelector parses a label selector of the form "key=value,key2=value2"
+func ParseSelector(selector string) (map[string]string, error) {
+	parsed := make(map[string]string)
+	if strings.TrimSpace(selector) == "" {
+		return parsed, nil
+	}
+
+	for _, requirement := range strings.Split(selector, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(requirement), "=")
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid label selector %q: expected key=value, got %q", selector, requirement)
+		}
+		parsed[key] = value
+	}
+
+	return parsed, nil
+}
@@ -2,48 +2,101 @@ package podman
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	buildahdefine "github.com/containers/buildah/define"
 	nettypes "github.com/containers/common/libnetwork/types"
+	"github.com/containers/common/libnetwork/util"
 	"github.com/containers/podman/v5/libpod/define"
+	"github.com/containers/podman/v5/pkg/api/handlers"
 	"github.com/containers/podman/v5/pkg/bindings"
 	"github.com/containers/podman/v5/pkg/bindings/containers"
 	"github.com/containers/podman/v5/pkg/bindings/images"
 	"github.com/containers/podman/v5/pkg/bindings/network"
+	"github.com/containers/podman/v5/pkg/bindings/pods"
 	"github.com/containers/podman/v5/pkg/bindings/secrets"
+	"github.com/containers/podman/v5/pkg/bindings/system"
 	"github.com/containers/podman/v5/pkg/bindings/volumes"
 	podmantypes "github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/containers/podman/v5/pkg/inspect"
 	"github.com/containers/podman/v5/pkg/specgen"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // PodmanAdapter implements the PodmanClient interface using Podman bindings
 type PodmanAdapter struct {
-	ctx context.Context
-	uri string
+	ctx      context.Context
+	uri      string
+	identity string
 }
 
 // NewPodmanAdapter creates a new PodmanAdapter
 func NewPodmanAdapter() *PodmanAdapter {
-	uri := getPodmanURI()
+	return NewPodmanAdapterWithURI(getPodmanURI(), getPodmanIdentity())
+}
+
+// NewPodmanAdapterWithURI creates a new PodmanAdapter for the given URI. For ssh://
+// URIs, identity is the path to the private key used to authenticate; the host key
+// is verified against the user's known_hosts file.
+func NewPodmanAdapterWithURI(uri, identity string) *PodmanAdapter {
 	return &PodmanAdapter{
-		uri: uri,
+		uri:      uri,
+		identity: identity,
 	}
 }
 
 // Connect establishes a connection to Podman
 func (p *PodmanAdapter) Connect(ctx context.Context) error {
-	connCtx, err := bindings.NewConnection(ctx, p.uri)
+	var connCtx context.Context
+	var err error
+
+	if isSSHURI(p.uri) {
+		connCtx, err = bindings.NewConnectionWithIdentity(ctx, p.uri, p.identity, false)
+	} else {
+		connCtx, err = bindings.NewConnection(ctx, p.uri)
+	}
 	if err != nil {
-		return fmt.Errorf("unable to connect to podman: %v", err)
+		return fmt.Errorf("unable to connect to podman at %s: %v", connectionHost(p.uri), err)
 	}
+
 	p.ctx = connCtx
 	return nil
 }
 
+// bindingsContext returns the context to pass to the bindings for a call: it carries
+// the connection made by Connect, and the deadline and cancellation of ctx
+func (p *PodmanAdapter) bindingsContext(ctx context.Context) context.Context {
+	return connectionContext{Context: ctx, connection: p.ctx}
+}
+
+// connectionContext is a context whose values are looked up in connection first
+type connectionContext struct {
+	context.Context
+	connection context.Context
+}
+
+func (c connectionContext) Value(key any) any {
+	if value := c.connection.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+// URI returns the URI the adapter connects to
+func (p *PodmanAdapter) URI() string {
+	return p.uri
+}
+
 // Close closes the connection to Podman
 func (p *PodmanAdapter) Close() error {
 	// Podman bindings don't require explicit cleanup
@@ -59,7 +112,7 @@ func (p *PodmanAdapter) CreateContainer(ctx context.Context, spec *specgen.SpecG
 	}
 
 	options := &containers.CreateOptions{}
-	response, err := containers.CreateWithSpec(p.ctx, spec, options)
+	response, err := containers.CreateWithSpec(p.bindingsContext(ctx), spec, options)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create container: %v", err)
 	}
@@ -75,7 +128,7 @@ func (p *PodmanAdapter) StartContainer(ctx context.Context, id string) error {
 		}
 	}
 
-	err := containers.Start(p.ctx, id, &containers.StartOptions{})
+	err := containers.Start(p.bindingsContext(ctx), id, &containers.StartOptions{})
 	if err != nil {
 		return fmt.Errorf("unable to start container: %v", err)
 	}
@@ -91,7 +144,7 @@ func (p *PodmanAdapter) StopContainer(ctx context.Context, name string, timeout
 		}
 	}
 
-	err := containers.Stop(p.ctx, name, &containers.StopOptions{Timeout: &timeout})
+	err := containers.Stop(p.bindingsContext(ctx), name, &containers.StopOptions{Timeout: &timeout})
 	if err != nil {
 		return fmt.Errorf("unable to stop container: %v", err)
 	}
@@ -99,6 +152,235 @@ func (p *PodmanAdapter) StopContainer(ctx context.Context, name string, timeout
 	return nil
 }
 
+// RestartContainer stops a container, waiting up to timeout seconds, and starts it again
+func (p *PodmanAdapter) RestartContainer(ctx context.Context, name string, timeout uint) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	seconds := int(timeout)
+	err := containers.Restart(p.bindingsContext(ctx), name, &containers.RestartOptions{Timeout: &seconds})
+	if err != nil {
+		return fmt.Errorf("unable to restart container: %v", err)
+	}
+
+	return nil
+}
+
+// ContainerStats returns a single resource usage sample for a container
+func (p *PodmanAdapter) ContainerStats(ctx context.Context, name string) (*ContainerStats, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	reports, err := containers.Stats(p.bindingsContext(ctx), []string{name}, new(containers.StatsOptions).WithStream(false))
+	if err != nil {
+		return nil, fmt.Errorf("unable to get container stats: %v", err)
+	}
+
+	// Without streaming, the first report is the only one
+	report, ok := <-reports
+	if !ok {
+		return nil, fmt.Errorf("unable to get container stats: no report for %s", name)
+	}
+	if report.Error != nil {
+		return nil, fmt.Errorf("unable to get container stats: %v", report.Error)
+	}
+	if len(report.Stats) == 0 {
+		return nil, fmt.Errorf("unable to get container stats: no stats for %s", name)
+	}
+
+	raw := report.Stats[0]
+	stats := &ContainerStats{
+		Name:        name,
+		CPUPercent:  raw.CPU,
+		MemoryUsage: raw.MemUsage,
+		MemoryLimit: raw.MemLimit,
+		BlockRead:   raw.BlockInput,
+		BlockWrite:  raw.BlockOutput,
+	}
+	for _, network := range raw.Network {
+		stats.NetworkRx += network.RxBytes
+		stats.NetworkTx += network.TxBytes
+	}
+
+	return stats, nil
+}
+
+// PauseContainer pauses a running container
+func (p *PodmanAdapter) PauseContainer(ctx context.Context, name string) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := containers.Pause(p.bindingsContext(ctx), name, &containers.PauseOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to pause container: %v", err)
+	}
+
+	return nil
+}
+
+// UnpauseContainer resumes a paused container
+func (p *PodmanAdapter) UnpauseContainer(ctx context.Context, name string) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	err := containers.Unpause(p.bindingsContext(ctx), name, &containers.UnpauseOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to unpause container: %v", err)
+	}
+
+	return nil
+}
+
+// UpdateContainer changes settings of an existing container without recreating it
+func (p *PodmanAdapter) UpdateContainer(ctx context.Context, name string, update ContainerUpdate) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	options := &podmantypes.ContainerUpdateOptions{NameOrID: name}
+	if update.RestartPolicy != "" {
+		options.RestartPolicy = &update.RestartPolicy
+	}
+	options.Env = update.Env
+
+	if _, err := containers.Update(p.bindingsContext(ctx), options); err != nil {
+		return fmt.Errorf("unable to update container: %v", err)
+	}
+
+	return nil
+}
+
+// ExecContainer runs a command inside a running container, waits for it to finish and
+// returns its exit code. Output is discarded.
+func (p *PodmanAdapter) ExecContainer(ctx context.Context, name string, command []string) (int, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return -1, err
+		}
+	}
+
+	sessionID, err := containers.ExecCreate(p.bindingsContext(ctx), name, &handlers.ExecCreateConfig{
+		ExecOptions: dockercontainer.ExecOptions{
+			AttachStdout: true,
+			AttachStderr: true,
+			Cmd:          command,
+		},
+	})
+	if err != nil {
+		return -1, fmt.Errorf("unable to create exec session: %v", err)
+	}
+	// Removed with the connection's context, so the session is cleaned up even when ctx
+	// expired while the command ran
+	defer containers.ExecRemove(p.ctx, sessionID, new(containers.ExecRemoveOptions).WithForce(true))
+
+	// Attaching blocks until the command exits
+	startOptions := new(containers.ExecStartAndAttachOptions).
+		WithOutputStream(io.Discard).
+		WithErrorStream(io.Discard).
+		WithAttachOutput(true).
+		WithAttachError(true)
+	if err := containers.ExecStartAndAttach(p.bindingsContext(ctx), sessionID, startOptions); err != nil {
+		return -1, fmt.Errorf("unable to run exec session: %v", err)
+	}
+
+	session, err := containers.ExecInspect(p.bindingsContext(ctx), sessionID, nil)
+	if err != nil {
+		return -1, fmt.Errorf("unable to inspect exec session: %v", err)
+	}
+
+	return session.ExitCode, nil
+}
+
+// ContainerLogs returns the last tail lines a container wrote to stdout and stderr
+func (p *PodmanAdapter) ContainerLogs(ctx context.Context, name string, tail int) ([]string, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	options := new(containers.LogOptions).
+		WithStdout(true).
+		WithStderr(true).
+		WithTail(strconv.Itoa(tail))
+
+	// Logs sends every line before it returns, so the lines are read while it runs
+	stdout := make(chan string)
+	stderr := make(chan string)
+	done := make(chan error, 1)
+	go func() {
+		done <- containers.Logs(p.bindingsContext(ctx), name, options, stdout, stderr)
+	}()
+
+	var lines []string
+	for {
+		select {
+		case line := <-stdout:
+			lines = append(lines, line)
+		case line := <-stderr:
+			lines = append(lines, line)
+		case err := <-done:
+			if err != nil {
+				return nil, fmt.Errorf("unable to get container logs: %v", err)
+			}
+			return lines, nil
+		}
+	}
+}
+
+// CopyFromContainer writes a tar archive of path inside the container to w. The
+// container does not have to be running.
+func (p *PodmanAdapter) CopyFromContainer(ctx context.Context, name, path string, w io.Writer) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	copyFunc, err := containers.CopyToArchive(p.bindingsContext(ctx), name, path, w)
+	if err != nil {
+		return fmt.Errorf("unable to copy from container: %v", err)
+	}
+	if err := copyFunc(); err != nil {
+		return fmt.Errorf("unable to copy from container: %v", err)
+	}
+
+	return nil
+}
+
+// CopyToContainer extracts the tar archive read from r into path inside the container
+func (p *PodmanAdapter) CopyToContainer(ctx context.Context, name, path string, r io.Reader) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	copyFunc, err := containers.CopyFromArchive(p.bindingsContext(ctx), name, path, r)
+	if err != nil {
+		return fmt.Errorf("unable to copy to container: %v", err)
+	}
+	if err := copyFunc(); err != nil {
+		return fmt.Errorf("unable to copy to container: %v", err)
+	}
+
+	return nil
+}
+
 // RemoveContainer removes a container
 func (p *PodmanAdapter) RemoveContainer(ctx context.Context, name string) error {
 	if p.ctx == nil {
@@ -107,7 +389,7 @@ func (p *PodmanAdapter) RemoveContainer(ctx context.Context, name string) error
 		}
 	}
 
-	_, err := containers.Remove(p.ctx, name, &containers.RemoveOptions{})
+	_, err := containers.Remove(p.bindingsContext(ctx), name, &containers.RemoveOptions{})
 	if err != nil {
 		return fmt.Errorf("unable to remove container: %v", err)
 	}
@@ -123,7 +405,7 @@ func (p *PodmanAdapter) ListContainers(ctx context.Context, filters map[string][
 		}
 	}
 
-	list, err := containers.List(p.ctx, &containers.ListOptions{
+	list, err := containers.List(p.bindingsContext(ctx), &containers.ListOptions{
 		All:     &all,
 		Filters: filters,
 	})
@@ -142,7 +424,7 @@ func (p *PodmanAdapter) InspectContainer(ctx context.Context, name string) (*def
 		}
 	}
 
-	inspect, err := containers.Inspect(p.ctx, name, &containers.InspectOptions{})
+	inspect, err := containers.Inspect(p.bindingsContext(ctx), name, &containers.InspectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to inspect container: %v", err)
 	}
@@ -159,14 +441,57 @@ func (p *PodmanAdapter) PullImage(ctx context.Context, image string) error {
 	}
 
 	options := &images.PullOptions{}
-	_, err := images.Pull(p.ctx, image, options)
+	_, err := images.Pull(p.bindingsContext(ctx), image, options)
+	if err != nil {
+		return fmt.Errorf("unable to pull image: %v", err)
+	}
+
+	return nil
+}
+
+// PullImageWithProgress pulls an image, calling progress for every line of the pull
+// report Podman streams back and once more when the pull is done
+func (p *PodmanAdapter) PullImageWithProgress(ctx context.Context, image string, progress func(PullProgress)) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	writer := &pullProgressWriter{image: image, progress: progress}
+	options := new(images.PullOptions).WithProgressWriter(writer)
+	_, err := images.Pull(p.bindingsContext(ctx), image, options)
 	if err != nil {
 		return fmt.Errorf("unable to pull image: %v", err)
 	}
 
+	progress(PullProgress{Image: image, Done: true})
 	return nil
 }
 
+// pullProgressWriter turns the pull report written to it into one progress call per line
+type pullProgressWriter struct {
+	image    string
+	progress func(PullProgress)
+	partial  string
+}
+
+// Write implements io.Writer
+func (w *pullProgressWriter) Write(data []byte) (int, error) {
+	w.partial += string(data)
+	for {
+		line, rest, found := strings.Cut(w.partial, "\n")
+		if !found {
+			break
+		}
+		w.partial = rest
+		if line = strings.TrimSpace(line); line != "" {
+			w.progress(PullProgress{Image: w.image, Message: line})
+		}
+	}
+	return len(data), nil
+}
+
 // GetImage gets image information
 func (p *PodmanAdapter) GetImage(ctx context.Context, image string) (*inspect.ImageData, error) {
 	if p.ctx == nil {
@@ -175,7 +500,7 @@ func (p *PodmanAdapter) GetImage(ctx context.Context, image string) (*inspect.Im
 		}
 	}
 
-	imageData, err := images.GetImage(p.ctx, image, &images.GetOptions{})
+	imageData, err := images.GetImage(p.bindingsContext(ctx), image, &images.GetOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to get image: %v", err)
 	}
@@ -183,6 +508,67 @@ func (p *PodmanAdapter) GetImage(ctx context.Context, image string) (*inspect.Im
 	return imageData.ImageData, nil
 }
 
+// BuildImage builds an image from a Containerfile and returns the ID of the built image
+func (p *PodmanAdapter) BuildImage(ctx context.Context, opts BuildOptions) (string, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return "", err
+		}
+	}
+
+	containerfile := opts.Containerfile
+	if !filepath.IsAbs(containerfile) {
+		containerfile = filepath.Join(opts.ContextDir, containerfile)
+	}
+
+	buildOptions := podmantypes.BuildOptions{
+		BuildOptions: buildahdefine.BuildOptions{
+			ContextDirectory: opts.ContextDir,
+			Output:           opts.Tag,
+			Args:             opts.BuildArgs,
+			Out:              io.Discard,
+		},
+	}
+
+	report, err := images.Build(p.bindingsContext(ctx), []string{containerfile}, buildOptions)
+	if err != nil {
+		return "", fmt.Errorf("unable to build image %s: %v", opts.Tag, err)
+	}
+
+	return report.ID, nil
+}
+
+// PruneImages removes unused images matching filters and returns the IDs of the removed
+// images. Without filters, only dangling images are removed.
+func (p *PodmanAdapter) PruneImages(ctx context.Context, filters map[string][]string) ([]string, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	pruneReports, err := images.Prune(p.bindingsContext(ctx), new(images.PruneOptions).WithFilters(filters))
+	if err != nil {
+		return nil, fmt.Errorf("unable to prune images: %v", err)
+	}
+
+	removed := make([]string, 0, len(pruneReports))
+	var pruneErrs []error
+	for _, report := range pruneReports {
+		if report.Err != nil {
+			pruneErrs = append(pruneErrs, report.Err)
+			continue
+		}
+		removed = append(removed, report.Id)
+	}
+
+	if len(pruneErrs) > 0 {
+		return removed, fmt.Errorf("unable to prune some images: %w", errors.Join(pruneErrs...))
+	}
+
+	return removed, nil
+}
+
 // CreateNetwork creates a new network
 func (p *PodmanAdapter) CreateNetwork(ctx context.Context, spec NetworkSpec) (*NetworkInfo, error) {
 	if p.ctx == nil {
@@ -191,40 +577,123 @@ func (p *PodmanAdapter) CreateNetwork(ctx context.Context, spec NetworkSpec) (*N
 		}
 	}
 
-	// Create network configuration
+	networkConfig, err := buildNetworkConfig(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := network.Create(p.bindingsContext(ctx), networkConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create network: %v", err)
+	}
+
+	info := networkInfoFromPodman(response)
+	return &info, nil
+}
+
+// buildNetworkConfig converts a NetworkSpec into the Podman network configuration
+func buildNetworkConfig(spec NetworkSpec) (*nettypes.Network, error) {
 	networkConfig := &nettypes.Network{
-		Name:    spec.Name,
-		Driver:  spec.Driver,
-		Options: spec.Options,
-		Labels:  spec.Labels,
+		Name:     spec.Name,
+		Driver:   spec.Driver,
+		Options:  spec.Options,
+		Labels:   spec.Labels,
+		Internal: spec.Internal,
+	}
+
+	// A gateway and an IP range only exist within a subnet
+	if spec.Subnet == "" {
+		if spec.Gateway != "" || spec.IPRange != "" {
+			return nil, fmt.Errorf("a gateway or IP range requires a subnet")
+		}
+		return networkConfig, nil
+	}
+
+	_, subnet, err := net.ParseCIDR(spec.Subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet format: %v", err)
+	}
+	networkSubnet := nettypes.Subnet{
+		Subnet: nettypes.IPNet{IPNet: *subnet},
+	}
+
+	if spec.Gateway != "" {
+		gateway := net.ParseIP(spec.Gateway)
+		if gateway == nil {
+			return nil, fmt.Errorf("invalid gateway format: %s", spec.Gateway)
+		}
+		if !subnet.Contains(gateway) {
+			return nil, fmt.Errorf("gateway %s is not in subnet %s", spec.Gateway, spec.Subnet)
+		}
+		networkSubnet.Gateway = gateway
 	}
 
-	// Set subnet if provided
-	if spec.Subnet != "" {
-		_, subnet, err := net.ParseCIDR(spec.Subnet)
+	if spec.IPRange != "" {
+		_, ipRange, err := net.ParseCIDR(spec.IPRange)
 		if err != nil {
-			return nil, fmt.Errorf("invalid subnet format: %v", err)
+			return nil, fmt.Errorf("invalid IP range format: %v", err)
 		}
-		networkConfig.Subnets = []nettypes.Subnet{
-			{
-				Subnet: nettypes.IPNet{IPNet: *subnet},
-			},
+		if !subnet.Contains(ipRange.IP) {
+			return nil, fmt.Errorf("IP range %s is not in subnet %s", spec.IPRange, spec.Subnet)
 		}
+		startIP, _ := util.FirstIPInSubnet(ipRange)
+		endIP, _ := util.LastIPInSubnet(ipRange)
+		networkSubnet.LeaseRange = &nettypes.LeaseRange{StartIP: startIP, EndIP: endIP}
 	}
 
-	response, err := network.Create(p.ctx, networkConfig)
-	if err != nil {
-		return nil, fmt.Errorf("unable to create network: %v", err)
+	networkConfig.Subnets = []nettypes.Subnet{networkSubnet}
+	return networkConfig, nil
+}
+
+// networkInfoFromPodman converts a Podman network into a NetworkInfo, reading the
+// subnet, gateway and IP range from its first subnet
+func networkInfoFromPodman(network nettypes.Network) NetworkInfo {
+	info := NetworkInfo{
+		ID:       network.ID,
+		Name:     network.Name,
+		Driver:   network.Driver,
+		Options:  network.Options,
+		Labels:   network.Labels,
+		Internal: network.Internal,
 	}
 
-	return &NetworkInfo{
-		ID:      response.ID,
-		Name:    response.Name,
-		Driver:  response.Driver,
-		Options: response.Options,
-		Subnet:  spec.Subnet,
-		Labels:  response.Labels,
-	}, nil
+	if len(network.Subnets) > 0 {
+		subnet := network.Subnets[0]
+		info.Subnet = subnet.Subnet.String()
+		if subnet.Gateway != nil {
+			info.Gateway = subnet.Gateway.String()
+		}
+		info.IPRange = formatLeaseRange(subnet.LeaseRange)
+	}
+
+	return info
+}
+
+// formatLeaseRange returns the CIDR a lease range was created from, falling back to
+// "start-end" for ranges that do not cover a whole CIDR block
+func formatLeaseRange(leaseRange *nettypes.LeaseRange) string {
+	if leaseRange == nil || leaseRange.StartIP == nil || leaseRange.EndIP == nil {
+		return ""
+	}
+
+	startIP, endIP := leaseRange.StartIP, leaseRange.EndIP
+	if ip := startIP.To4(); ip != nil {
+		startIP, endIP = ip, endIP.To4()
+	}
+	bits := len(startIP) * 8
+	for ones := bits; ones >= 0; ones-- {
+		block := &net.IPNet{IP: startIP.Mask(net.CIDRMask(ones, bits)), Mask: net.CIDRMask(ones, bits)}
+		first, err := util.FirstIPInSubnet(block)
+		if err != nil {
+			break
+		}
+		last, _ := util.LastIPInSubnet(block)
+		if first.Equal(startIP) && last.Equal(endIP) {
+			return block.String()
+		}
+	}
+
+	return fmt.Sprintf("%s-%s", leaseRange.StartIP, leaseRange.EndIP)
 }
 
 // RemoveNetwork removes a network
@@ -235,7 +704,7 @@ func (p *PodmanAdapter) RemoveNetwork(ctx context.Context, name string) error {
 		}
 	}
 
-	_, err := network.Remove(p.ctx, name, &network.RemoveOptions{})
+	_, err := network.Remove(p.bindingsContext(ctx), name, &network.RemoveOptions{})
 	if err != nil {
 		return fmt.Errorf("unable to remove network: %v", err)
 	}
@@ -251,7 +720,7 @@ func (p *PodmanAdapter) ListNetworks(ctx context.Context, filters map[string][]s
 		}
 	}
 
-	list, err := network.List(p.ctx, &network.ListOptions{
+	list, err := network.List(p.bindingsContext(ctx), &network.ListOptions{
 		Filters: filters,
 	})
 	if err != nil {
@@ -260,20 +729,7 @@ func (p *PodmanAdapter) ListNetworks(ctx context.Context, filters map[string][]s
 
 	var result []NetworkInfo
 	for _, net := range list {
-		// Extract subnet information
-		var subnet string
-		if len(net.Subnets) > 0 {
-			subnet = net.Subnets[0].Subnet.String()
-		}
-
-		result = append(result, NetworkInfo{
-			ID:      net.ID,
-			Name:    net.Name,
-			Driver:  net.Driver,
-			Options: net.Options,
-			Subnet:  subnet,
-			Labels:  net.Labels,
-		})
+		result = append(result, networkInfoFromPodman(net))
 	}
 
 	return result, nil
@@ -287,36 +743,25 @@ func (p *PodmanAdapter) InspectNetwork(ctx context.Context, name string) (*Netwo
 		}
 	}
 
-	inspect, err := network.Inspect(p.ctx, name, &network.InspectOptions{})
+	inspect, err := network.Inspect(p.bindingsContext(ctx), name, &network.InspectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to inspect network: %v", err)
 	}
 
-	// Extract subnet information
-	var subnet string
-	if len(inspect.Subnets) > 0 {
-		subnet = inspect.Subnets[0].Subnet.String()
-	}
-
-	return &NetworkInfo{
-		ID:      inspect.ID,
-		Name:    inspect.Name,
-		Driver:  inspect.Driver,
-		Options: inspect.Options,
-		Subnet:  subnet,
-		Labels:  inspect.Labels,
-	}, nil
+	info := networkInfoFromPodman(inspect.Network)
+	return &info, nil
 }
 
-// ConnectContainerToNetwork connects a container to a network
-func (p *PodmanAdapter) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string) error {
+// ConnectContainerToNetwork connects a container to a network with the given aliases,
+// static IP and MAC address
+func (p *PodmanAdapter) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, options nettypes.PerNetworkOptions) error {
 	if p.ctx == nil {
 		if err := p.Connect(ctx); err != nil {
 			return err
 		}
 	}
 
-	err := network.Connect(p.ctx, networkName, containerName, nil)
+	err := network.Connect(p.bindingsContext(ctx), networkName, containerName, &options)
 	if err != nil {
 		return fmt.Errorf("unable to connect container to network: %v", err)
 	}
@@ -332,7 +777,7 @@ func (p *PodmanAdapter) DisconnectContainerFromNetwork(ctx context.Context, cont
 		}
 	}
 
-	err := network.Disconnect(p.ctx, networkName, containerName, nil)
+	err := network.Disconnect(p.bindingsContext(ctx), networkName, containerName, nil)
 	if err != nil {
 		return fmt.Errorf("unable to disconnect container from network: %v", err)
 	}
@@ -356,7 +801,7 @@ func (p *PodmanAdapter) CreateVolume(ctx context.Context, spec VolumeSpec) (*Vol
 	}
 
 	options := &volumes.CreateOptions{}
-	response, err := volumes.Create(p.ctx, createOptions, options)
+	response, err := volumes.Create(p.bindingsContext(ctx), createOptions, options)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create volume: %v", err)
 	}
@@ -378,7 +823,7 @@ func (p *PodmanAdapter) RemoveVolume(ctx context.Context, name string) error {
 		}
 	}
 
-	err := volumes.Remove(p.ctx, name, &volumes.RemoveOptions{})
+	err := volumes.Remove(p.bindingsContext(ctx), name, &volumes.RemoveOptions{})
 	if err != nil {
 		return fmt.Errorf("unable to remove volume: %v", err)
 	}
@@ -394,7 +839,7 @@ func (p *PodmanAdapter) ListVolumes(ctx context.Context, filters map[string][]st
 		}
 	}
 
-	list, err := volumes.List(p.ctx, &volumes.ListOptions{
+	list, err := volumes.List(p.bindingsContext(ctx), &volumes.ListOptions{
 		Filters: filters,
 	})
 	if err != nil {
@@ -423,7 +868,7 @@ func (p *PodmanAdapter) InspectVolume(ctx context.Context, name string) (*Volume
 		}
 	}
 
-	inspect, err := volumes.Inspect(p.ctx, name, &volumes.InspectOptions{})
+	inspect, err := volumes.Inspect(p.bindingsContext(ctx), name, &volumes.InspectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to inspect volume: %v", err)
 	}
@@ -437,6 +882,162 @@ func (p *PodmanAdapter) InspectVolume(ctx context.Context, name string) (*Volume
 	}, nil
 }
 
+// Pod operations
+
+// CreatePod creates a new pod
+func (p *PodmanAdapter) CreatePod(ctx context.Context, spec PodSpec) (*PodInfo, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	podSpec := &podmantypes.PodSpec{
+		PodSpecGen: specgen.PodSpecGenerator{
+			PodBasicConfig: specgen.PodBasicConfig{
+				Name:             spec.Name,
+				Labels:           spec.Labels,
+				SharedNamespaces: spec.SharedNamespaces,
+			},
+			PodNetworkConfig: specgen.PodNetworkConfig{
+				PortMappings: spec.PortMappings,
+			},
+			PodStorageConfig: specgen.PodStorageConfig{
+				Mounts: spec.Mounts,
+			},
+		},
+	}
+
+	response, err := pods.CreatePodFromSpec(p.bindingsContext(ctx), podSpec)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create pod: %v", err)
+	}
+
+	return &PodInfo{
+		ID:               response.Id,
+		Name:             spec.Name,
+		Labels:           spec.Labels,
+		SharedNamespaces: spec.SharedNamespaces,
+		PortMappings:     spec.PortMappings,
+		Mounts:           spec.Mounts,
+	}, nil
+}
+
+// RemovePod removes a pod along with any containers still in it
+func (p *PodmanAdapter) RemovePod(ctx context.Context, name string) error {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return err
+		}
+	}
+
+	report, err := pods.Remove(p.bindingsContext(ctx), name, new(pods.RemoveOptions).WithForce(true))
+	if err != nil {
+		return fmt.Errorf("unable to remove pod: %v", err)
+	}
+	if report != nil && report.Err != nil {
+		return fmt.Errorf("unable to remove pod: %v", report.Err)
+	}
+
+	return nil
+}
+
+// ListPods lists pods with their shared namespaces and member containers
+func (p *PodmanAdapter) ListPods(ctx context.Context, filters map[string][]string) ([]PodInfo, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	list, err := pods.List(p.bindingsContext(ctx), new(pods.ListOptions).WithFilters(filters))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %v", err)
+	}
+
+	var result []PodInfo
+	for _, pod := range list {
+		// The list report does not include the shared namespaces
+		inspect, err := pods.Inspect(p.bindingsContext(ctx), pod.Id, &pods.InspectOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect pod %s: %v", pod.Name, err)
+		}
+
+		var members []string
+		for _, container := range pod.Containers {
+			if container.Id != pod.InfraId {
+				members = append(members, container.Names)
+			}
+		}
+
+		var portMappings []nettypes.PortMapping
+		if inspect.InfraConfig != nil {
+			portMappings = convertInspectPortBindings(inspect.InfraConfig.PortBindings)
+		}
+
+		var mounts []specs.Mount
+		for _, mount := range inspect.Mounts {
+			options := []string{"rw"}
+			if !mount.RW {
+				options = []string{"ro"}
+			}
+			source := mount.Source
+			if mount.Type == "volume" {
+				source = mount.Name
+			}
+			mounts = append(mounts, specs.Mount{
+				Destination: mount.Destination,
+				Source:      source,
+				Type:        mount.Type,
+				Options:     options,
+			})
+		}
+
+		result = append(result, PodInfo{
+			ID:               pod.Id,
+			Name:             pod.Name,
+			Status:           pod.Status,
+			Labels:           pod.Labels,
+			SharedNamespaces: inspect.SharedNamespaces,
+			PortMappings:     portMappings,
+			Mounts:           mounts,
+			Containers:       members,
+		})
+	}
+
+	return result, nil
+}
+
+// convertInspectPortBindings converts the port bindings of an inspected infra container,
+// keyed by "port/protocol", into port mappings
+func convertInspectPortBindings(bindings map[string][]define.InspectHostPort) []nettypes.PortMapping {
+	var mappings []nettypes.PortMapping
+	for portProto, hostPorts := range bindings {
+		port, protocol, found := strings.Cut(portProto, "/")
+		if !found {
+			continue
+		}
+		containerPort, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			continue
+		}
+
+		for _, hostPort := range hostPorts {
+			parsedHostPort, err := strconv.ParseUint(hostPort.HostPort, 10, 16)
+			if err != nil {
+				continue
+			}
+			mappings = append(mappings, nettypes.PortMapping{
+				HostIP:        hostPort.HostIP,
+				HostPort:      uint16(parsedHostPort),
+				ContainerPort: uint16(containerPort),
+				Protocol:      protocol,
+			})
+		}
+	}
+	return mappings
+}
+
 // CreateSecret creates a new secret
 func (p *PodmanAdapter) CreateSecret(ctx context.Context, spec SecretSpec) (*SecretInfo, error) {
 	if p.ctx == nil {
@@ -451,7 +1052,7 @@ func (p *PodmanAdapter) CreateSecret(ctx context.Context, spec SecretSpec) (*Sec
 		Labels: spec.Labels,
 	}
 
-	response, err := secrets.Create(p.ctx, reader, options)
+	response, err := secrets.Create(p.bindingsContext(ctx), reader, options)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create secret: %v", err)
 	}
@@ -494,7 +1095,7 @@ func (p *PodmanAdapter) RemoveSecret(ctx context.Context, name string) error {
 		}
 	}
 
-	err := secrets.Remove(p.ctx, name)
+	err := secrets.Remove(p.bindingsContext(ctx), name)
 	if err != nil {
 		return fmt.Errorf("unable to remove secret: %v", err)
 	}
@@ -530,7 +1131,7 @@ func (p *PodmanAdapter) ListSecrets(ctx context.Context, filters map[string][]st
 		}
 	}
 
-	list, err := secrets.List(p.ctx, &secrets.ListOptions{
+	list, err := secrets.List(p.bindingsContext(ctx), &secrets.ListOptions{
 		Filters: apiFilters,
 	})
 	if err != nil {
@@ -577,7 +1178,7 @@ func (p *PodmanAdapter) InspectSecret(ctx context.Context, name string) (*Secret
 		}
 	}
 
-	inspect, err := secrets.Inspect(p.ctx, name, &secrets.InspectOptions{})
+	inspect, err := secrets.Inspect(p.bindingsContext(ctx), name, &secrets.InspectOptions{})
 	if err != nil {
 		return nil, fmt.Errorf("unable to inspect secret: %v", err)
 	}
@@ -589,6 +1190,68 @@ func (p *PodmanAdapter) InspectSecret(ctx context.Context, name string) (*Secret
 	}, nil
 }
 
+// WatchEvents streams Podman events matching filters until ctx is cancelled, at which
+// point the returned channel is closed
+func (p *PodmanAdapter) WatchEvents(ctx context.Context, filters map[string][]string) (<-chan Event, error) {
+	if p.ctx == nil {
+		if err := p.Connect(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	rawEvents := make(chan podmantypes.Event)
+	cancelChan := make(chan bool)
+	options := new(system.EventsOptions).WithFilters(filters).WithStream(true)
+
+	if err := system.Events(p.bindingsContext(ctx), rawEvents, cancelChan, options); err != nil {
+		close(cancelChan)
+		return nil, fmt.Errorf("unable to watch events: %v", err)
+	}
+
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+
+		// Closing cancelChan closes the response body, which ends the decoder; drain
+		// whatever it still sends so it can exit
+		defer func() {
+			close(cancelChan)
+			for range rawEvents {
+			}
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case raw, ok := <-rawEvents:
+				if !ok {
+					return
+				}
+				select {
+				case events <- convertEvent(raw):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// convertEvent converts a Podman bindings event to an Event
+func convertEvent(raw podmantypes.Event) Event {
+	return Event{
+		Type:       string(raw.Type),
+		Action:     string(raw.Action),
+		ID:         raw.Actor.ID,
+		Name:       raw.Actor.Attributes["name"],
+		Attributes: raw.Actor.Attributes,
+		Time:       time.Unix(0, raw.TimeNano),
+	}
+}
+
 // getPodmanURI returns the Podman socket URI
 func getPodmanURI() string {
 	if env, exists := os.LookupEnv("PODMAN_SOCK"); exists {
@@ -596,3 +1259,24 @@ func getPodmanURI() string {
 	}
 	return "unix:/run/user/1000/podman/podman.sock"
 }
+
+// getPodmanIdentity returns the SSH identity file used for ssh:// URIs
+func getPodmanIdentity() string {
+	return os.Getenv("PODMAN_SSH_IDENTITY")
+}
+
+// isSSHURI reports whether uri points to a remote Podman over SSH
+func isSSHURI(uri string) bool {
+	parsed, err := url.Parse(uri)
+	return err == nil && parsed.Scheme == "ssh"
+}
+
+// connectionHost returns the host a URI points to, for use in error messages.
+// Local sockets are described by their URI since they have no host.
+func connectionHost(uri string) string {
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Host == "" {
+		return uri
+	}
+	return parsed.Host
+}
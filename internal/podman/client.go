@@ -2,11 +2,15 @@ package podman
 
 import (
 	"context"
+	"io"
+	"time"
 
+	nettypes "github.com/containers/common/libnetwork/types"
 	"github.com/containers/podman/v5/libpod/define"
 	"github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/containers/podman/v5/pkg/inspect"
 	"github.com/containers/podman/v5/pkg/specgen"
+	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
 // PodmanClient defines the interface for interacting with Podman
@@ -15,16 +19,25 @@ type PodmanClient interface {
 	CreateContainer(ctx context.Context, spec *specgen.SpecGenerator) (*types.ContainerCreateResponse, error)
 	StartContainer(ctx context.Context, id string) error
 	StopContainer(ctx context.Context, name string, timeout uint) error
+	RestartContainer(ctx context.Context, name string, timeout uint) error
+	PauseContainer(ctx context.Context, name string) error
+	UnpauseContainer(ctx context.Context, name string) error
+	UpdateContainer(ctx context.Context, name string, update ContainerUpdate) error
+	ExecContainer(ctx context.Context, name string, command []string) (int, error)
 	RemoveContainer(ctx context.Context, name string) error
 	ListContainers(ctx context.Context, filters map[string][]string, all bool) ([]types.ListContainer, error)
 	InspectContainer(ctx context.Context, name string) (*define.InspectContainerData, error)
+	ContainerStats(ctx context.Context, name string) (*ContainerStats, error)
+	ContainerLogs(ctx context.Context, name string, tail int) ([]string, error)
+	CopyFromContainer(ctx context.Context, name, path string, w io.Writer) error
+	CopyToContainer(ctx context.Context, name, path string, r io.Reader) error
 	
 	// Network operations
 	CreateNetwork(ctx context.Context, spec NetworkSpec) (*NetworkInfo, error)
 	RemoveNetwork(ctx context.Context, name string) error
 	ListNetworks(ctx context.Context, filters map[string][]string) ([]NetworkInfo, error)
 	InspectNetwork(ctx context.Context, name string) (*NetworkInfo, error)
-	ConnectContainerToNetwork(ctx context.Context, containerName, networkName string) error
+	ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, options nettypes.PerNetworkOptions) error
 	DisconnectContainerFromNetwork(ctx context.Context, containerName, networkName string) error
 	
 	// Volume operations
@@ -33,6 +46,11 @@ type PodmanClient interface {
 	ListVolumes(ctx context.Context, filters map[string][]string) ([]VolumeInfo, error)
 	InspectVolume(ctx context.Context, name string) (*VolumeInfo, error)
 	
+	// Pod operations
+	CreatePod(ctx context.Context, spec PodSpec) (*PodInfo, error)
+	RemovePod(ctx context.Context, name string) error
+	ListPods(ctx context.Context, filters map[string][]string) ([]PodInfo, error)
+	
 	// Secret operations
 	CreateSecret(ctx context.Context, spec SecretSpec) (*SecretInfo, error)
 	UpdateSecret(ctx context.Context, name string, spec SecretSpec) error
@@ -42,8 +60,14 @@ type PodmanClient interface {
 	
 	// Image operations
 	PullImage(ctx context.Context, image string) error
+	PullImageWithProgress(ctx context.Context, image string, progress func(PullProgress)) error
 	GetImage(ctx context.Context, image string) (*inspect.ImageData, error)
+	BuildImage(ctx context.Context, opts BuildOptions) (string, error)
+	PruneImages(ctx context.Context, filters map[string][]string) ([]string, error)
 	
+	// Event operations
+	WatchEvents(ctx context.Context, filters map[string][]string) (<-chan Event, error)
+
 	// Connection management
 	Connect(ctx context.Context) error
 	Close() error
@@ -91,23 +115,73 @@ type ResourceLimits struct {
 	NanoCPUs int64
 }
 
+// ContainerUpdate lists the settings of an existing container to change in place.
+// Podman has no way to change the labels of an existing container.
+type ContainerUpdate struct {
+	RestartPolicy string   // Left unchanged when empty
+	Env           []string // KEY=value pairs to set, taking effect when the container next starts
+}
+
+// ContainerStats represents a single resource usage sample of a container
+type ContainerStats struct {
+	Name        string
+	CPUPercent  float64
+	MemoryUsage uint64 // Bytes
+	MemoryLimit uint64 // Bytes
+	NetworkRx   uint64 // Bytes received across all interfaces
+	NetworkTx   uint64 // Bytes sent across all interfaces
+	BlockRead   uint64 // Bytes
+	BlockWrite  uint64 // Bytes
+}
+
+// BuildOptions represents the inputs of an image build
+type BuildOptions struct {
+	ContextDir    string
+	Containerfile string // Relative to ContextDir unless absolute
+	Tag           string
+	BuildArgs     map[string]string
+}
+
+// PullProgress reports a step of an image pull
+type PullProgress struct {
+	Image   string
+	Message string // One line of the pull report, such as "Copying blob sha256:..."
+	Done    bool   // Set on the last report, once the image was pulled
+}
+
+// Event represents a Podman lifecycle event, such as a container dying
+type Event struct {
+	Type       string // container, network, volume, ...
+	Action     string // start, died, remove, ...
+	ID         string
+	Name       string
+	Attributes map[string]string // Labels and other details of the resource
+	Time       time.Time
+}
+
 // NetworkSpec represents the specification for creating a network
 type NetworkSpec struct {
-	Name    string
-	Driver  string
-	Options map[string]string
-	Subnet  string
-	Labels  map[string]string
+	Name     string
+	Driver   string
+	Options  map[string]string
+	Subnet   string
+	Gateway  string
+	IPRange  string // CIDR the addresses of containers are allocated from
+	Labels   map[string]string
+	Internal bool // No external connectivity
 }
 
 // NetworkInfo represents network information
 type NetworkInfo struct {
-	ID      string
-	Name    string
-	Driver  string
-	Options map[string]string
-	Subnet  string
-	Labels  map[string]string
+	ID       string
+	Name     string
+	Driver   string
+	Options  map[string]string
+	Subnet   string
+	Gateway  string
+	IPRange  string // CIDR the addresses of containers are allocated from
+	Labels   map[string]string
+	Internal bool
 }
 
 // VolumeSpec represents the specification for creating a volume
@@ -128,6 +202,27 @@ type VolumeInfo struct {
 	Labels     map[string]string
 }
 
+// PodSpec represents the specification for creating a pod
+type PodSpec struct {
+	Name             string
+	Labels           map[string]string
+	SharedNamespaces []string               // Namespaces member containers share, such as "net" and "ipc"
+	PortMappings     []nettypes.PortMapping // Published on the infra container
+	Mounts           []specs.Mount          // Mounted into the infra container and inherited by members
+}
+
+// PodInfo represents pod information
+type PodInfo struct {
+	ID               string
+	Name             string
+	Status           string
+	Labels           map[string]string
+	SharedNamespaces []string
+	PortMappings     []nettypes.PortMapping
+	Mounts           []specs.Mount
+	Containers       []string // Names of the member containers, without the infra container
+}
+
 // SecretSpec represents the specification for creating a secret
 type SecretSpec struct {
 	Name   string
@@ -0,0 +1,96 @@
+package podman
+
+import (
+	"errors"
+	"net"
+	"strings"
+)
+
+// connectionErrorMarkers are fragments of the errors returned when nothing answers on
+// the Podman socket. Adapter errors are formatted with %v, so matching falls back to
+// the message.
+var connectionErrorMarkers = []string{
+	"unable to connect to podman socket",
+	"connect: connection refused",
+	"connect: no such file or directory",
+}
+
+// IsConnectionError reports whether err means Podman could not be reached at all,
+// typically because its socket does not exist or nothing is listening on it. Unlike
+// API errors, these do not go away by retrying right away.
+func IsConnectionError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range connectionErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// permanentErrorMarkers are fragments of errors that retrying the same request cannot
+// fix: images missing from their registry, and specs Podman rejects
+var permanentErrorMarkers = []string{
+	"manifest unknown",
+	"name unknown",
+	"repository does not exist",
+	"requested access to the resource is denied",
+	"invalid reference format",
+	"invalid config provided",
+	"invalid port",
+	"invalid argument",
+}
+
+// IsRetryable reports whether retrying the operation that returned err may succeed.
+// Missing images, rejected specs and an unreachable Podman socket are permanent;
+// anything else, such as a connection reset mid-request, is assumed to be transient.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if IsConnectionError(err) || IsImageNotFoundError(err) {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range permanentErrorMarkers {
+		if strings.Contains(message, marker) {
+			return false
+		}
+	}
+	return true
+}
+
+// imageNotFoundMarkers are fragments of the errors returned when an image is not in
+// local storage
+var imageNotFoundMarkers = []string{
+	"image not known",
+	"no such image",
+	"image not found",
+}
+
+// IsImageNotFoundError reports whether err means the requested image is not present
+// locally, as opposed to Podman failing to look it up
+func IsImageNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, marker := range imageNotFoundMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,8 +3,14 @@ package podman
 import (
 	"context"
 	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 
+	nettypes "github.com/containers/common/libnetwork/types"
 	"github.com/containers/podman/v5/libpod/define"
 	"github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/containers/podman/v5/pkg/inspect"
@@ -19,12 +25,28 @@ type MockPodmanClient struct {
 	containers map[string]*MockContainer
 	networks   map[string]*NetworkInfo
 	volumes    map[string]*VolumeInfo
+	pods       map[string]*PodInfo
 	secrets    map[string]*SecretInfo
 	images     map[string]*inspect.ImageData
+	stats      map[string]*ContainerStats
+	logs       map[string][]string
+	builds     []BuildOptions
+	execs      []MockExec
+
+	// Archives copied into and out of containers, keyed by the named volume mounted at
+	// the copied path
+	volumeArchives map[string][]byte
+
+	// Exit codes returned by ExecContainer, keyed by the space-joined command
+	execExitCodes map[string]int
+
+	// Event subscribers fed by EmitEvent
+	eventSubscribers []*mockEventSubscriber
 
 	// Behavior controls
 	shouldFailConnect    bool
 	shouldFailOperations map[string]bool
+	operationErrors      map[string]error
 
 	// Call tracking
 	calls map[string]int
@@ -42,15 +64,39 @@ type MockContainer struct {
 	ListData *types.ListContainer
 }
 
+// MockExec records a command run through ExecContainer
+type MockExec struct {
+	Container string
+	Command   []string
+}
+
+// mockEventSubscriber is a WatchEvents caller waiting for emitted events
+type mockEventSubscriber struct {
+	filters map[string][]string
+	events  chan Event
+}
+
+// mockSocketURI is the URI reported by the mock client
+const mockSocketURI = "unix:///run/podman/podman.sock"
+
+// mockEventBufferSize bounds how many emitted events a subscriber may leave unread
+const mockEventBufferSize = 64
+
 // NewMockPodmanClient creates a new mock Podman client
 func NewMockPodmanClient() *MockPodmanClient {
 	return &MockPodmanClient{
 		containers:           make(map[string]*MockContainer),
 		networks:             make(map[string]*NetworkInfo),
 		volumes:              make(map[string]*VolumeInfo),
+		pods:                 make(map[string]*PodInfo),
 		secrets:              make(map[string]*SecretInfo),
 		images:               make(map[string]*inspect.ImageData),
+		stats:                make(map[string]*ContainerStats),
+		logs:                 make(map[string][]string),
+		execExitCodes:        make(map[string]int),
+		volumeArchives:       make(map[string][]byte),
 		shouldFailOperations: make(map[string]bool),
+		operationErrors:      make(map[string]error),
 		calls:                make(map[string]int),
 	}
 }
@@ -63,12 +109,18 @@ func (m *MockPodmanClient) Connect(ctx context.Context) error {
 	m.calls["Connect"]++
 
 	if m.shouldFailConnect {
-		return fmt.Errorf("mock connection failed")
+		// Mirrors the error Podman bindings return when the socket does not exist
+		return fmt.Errorf("mock connection failed: dial unix %s: connect: no such file or directory", strings.TrimPrefix(mockSocketURI, "unix://"))
 	}
 
 	return nil
 }
 
+// URI returns the socket URI the mock pretends to connect to
+func (m *MockPodmanClient) URI() string {
+	return mockSocketURI
+}
+
 // Close simulates closing the connection
 func (m *MockPodmanClient) Close() error {
 	m.mu.Lock()
@@ -90,6 +142,22 @@ func (m *MockPodmanClient) CreateContainer(ctx context.Context, spec *specgen.Sp
 	if m.shouldFailOperations["CreateContainer"] {
 		return nil, fmt.Errorf("mock create container failed")
 	}
+	if err := m.operationErrors["CreateContainer"]; err != nil {
+		return nil, err
+	}
+
+	var podID string
+	if spec.Pod != "" {
+		pod, exists := m.pods[spec.Pod]
+		if !exists {
+			return nil, fmt.Errorf("no pod with name or ID %s found", spec.Pod)
+		}
+		// Like Podman, refuse ports on a container that joins the pod's network namespace
+		if len(spec.PortMappings) > 0 && slices.Contains(pod.SharedNamespaces, "net") {
+			return nil, fmt.Errorf("invalid config provided: published or exposed ports must be defined when the pod is created: network cannot be configured when it is shared with a pod")
+		}
+		podID = pod.ID
+	}
 
 	id := fmt.Sprintf("mock-container-%d", len(m.containers))
 	name := spec.Name
@@ -97,6 +165,37 @@ func (m *MockPodmanClient) CreateContainer(ctx context.Context, spec *specgen.Sp
 		name = fmt.Sprintf("container-%d", len(m.containers))
 	}
 
+	// Reflect the spec back through inspect so readback matches what was created
+	var env []string
+	envKeys := make([]string, 0, len(spec.Env))
+	for key := range spec.Env {
+		envKeys = append(envKeys, key)
+	}
+	sort.Strings(envKeys)
+	for _, key := range envKeys {
+		env = append(env, fmt.Sprintf("%s=%s", key, spec.Env[key]))
+	}
+
+	networks := make(map[string]*define.InspectAdditionalNetwork, len(spec.Networks))
+	for networkName, options := range spec.Networks {
+		networks[networkName] = mockNetworkAttachment(networkName, options)
+	}
+
+	portBindings := make(map[string][]define.InspectHostPort, len(spec.PortMappings))
+	for _, mapping := range spec.PortMappings {
+		key := fmt.Sprintf("%d/%s", mapping.ContainerPort, mapping.Protocol)
+		portBindings[key] = append(portBindings[key], define.InspectHostPort{
+			HostIP:   mapping.HostIP,
+			HostPort: strconv.Itoa(int(mapping.HostPort)),
+		})
+	}
+
+	// Podman names a container after its short ID unless given a hostname
+	hostname := spec.Hostname
+	if hostname == "" {
+		hostname = id[:min(len(id), 12)]
+	}
+
 	container := &MockContainer{
 		ID:     id,
 		Name:   name,
@@ -108,19 +207,49 @@ func (m *MockPodmanClient) CreateContainer(ctx context.Context, spec *specgen.Sp
 			ID:    id,
 			Name:  name,
 			Image: spec.Image,
+			Pod:   podID,
 			State: &define.InspectContainerState{
 				Status: "created",
 			},
 			Config: &define.InspectContainerConfig{
-				Labels: spec.Labels,
+				Image:       spec.Image,
+				Cmd:         spec.Command,
+				Env:         env,
+				WorkingDir:  spec.WorkDir,
+				User:        spec.User,
+				Hostname:    hostname,
+				Labels:      spec.Labels,
+				Annotations: spec.Annotations,
+				OpenStdin:   spec.Stdin != nil && *spec.Stdin,
+				Tty:         spec.Terminal != nil && *spec.Terminal,
+			},
+			HostConfig: &define.InspectContainerHostConfig{
+				RestartPolicy: &define.InspectRestartPolicy{
+					Name: spec.RestartPolicy,
+				},
+				PortBindings:   portBindings,
+				GroupAdd:       spec.Groups,
+				IpcMode:        m.namespaceMode(spec.IpcNS, "shareable"),
+				PidMode:        m.namespaceMode(spec.PidNS, "private"),
+				UTSMode:        m.namespaceMode(spec.UtsNS, "private"),
+				NetworkMode:    m.namespaceMode(spec.NetNS, "bridge"),
+				ReadonlyRootfs: spec.ReadOnlyFilesystem != nil && *spec.ReadOnlyFilesystem,
+				Init:           spec.Init != nil && *spec.Init,
+				AutoRemove:     spec.Remove != nil && *spec.Remove,
+				SecurityOpt:    mockSecurityOptions(spec),
+			},
+			NetworkSettings: &define.InspectNetworkSettings{
+				Networks: networks,
 			},
 		},
 		ListData: &types.ListContainer{
-			ID:     id,
-			Names:  []string{name},
-			Image:  spec.Image,
-			State:  "created",
-			Labels: spec.Labels,
+			ID:      id,
+			Names:   []string{name},
+			Image:   spec.Image,
+			State:   "created",
+			Labels:  spec.Labels,
+			Pod:     podID,
+			PodName: spec.Pod,
 		},
 	}
 
@@ -176,6 +305,239 @@ func (m *MockPodmanClient) StopContainer(ctx context.Context, name string, timeo
 	return fmt.Errorf("container not found: %s", name)
 }
 
+// RestartContainer restarts a mock container, leaving it running
+func (m *MockPodmanClient) RestartContainer(ctx context.Context, name string, timeout uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["RestartContainer"]++
+
+	if m.shouldFailOperations["RestartContainer"] {
+		return fmt.Errorf("mock restart container failed")
+	}
+
+	if container, exists := m.containers[name]; exists {
+		container.State = "running"
+		container.Inspect.State.Status = "running"
+		container.ListData.State = "running"
+		return nil
+	}
+
+	return fmt.Errorf("container not found: %s", name)
+}
+
+// ContainerStats returns the stats seeded with SetContainerStats, or a fixed default sample
+func (m *MockPodmanClient) ContainerStats(ctx context.Context, name string) (*ContainerStats, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["ContainerStats"]++
+
+	if m.shouldFailOperations["ContainerStats"] {
+		return nil, fmt.Errorf("mock container stats failed")
+	}
+
+	if _, exists := m.containers[name]; !exists {
+		return nil, fmt.Errorf("container not found: %s", name)
+	}
+
+	if stats, exists := m.stats[name]; exists {
+		seeded := *stats
+		seeded.Name = name
+		return &seeded, nil
+	}
+
+	return &ContainerStats{
+		Name:        name,
+		CPUPercent:  1.5,
+		MemoryUsage: 64 * 1024 * 1024,
+		MemoryLimit: 512 * 1024 * 1024,
+		NetworkRx:   4096,
+		NetworkTx:   2048,
+		BlockRead:   8192,
+		BlockWrite:  1024,
+	}, nil
+}
+
+// PauseContainer pauses a mock container
+func (m *MockPodmanClient) PauseContainer(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["PauseContainer"]++
+
+	if m.shouldFailOperations["PauseContainer"] {
+		return fmt.Errorf("mock pause container failed")
+	}
+
+	container, exists := m.containers[name]
+	if !exists {
+		return fmt.Errorf("container not found: %s", name)
+	}
+	if container.State != "running" {
+		return fmt.Errorf("container %s is not running", name)
+	}
+
+	container.State = "paused"
+	container.Inspect.State.Status = "paused"
+	container.Inspect.State.Paused = true
+	container.ListData.State = "paused"
+	return nil
+}
+
+// UnpauseContainer resumes a paused mock container
+func (m *MockPodmanClient) UnpauseContainer(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["UnpauseContainer"]++
+
+	if m.shouldFailOperations["UnpauseContainer"] {
+		return fmt.Errorf("mock unpause container failed")
+	}
+
+	container, exists := m.containers[name]
+	if !exists {
+		return fmt.Errorf("container not found: %s", name)
+	}
+	if container.State != "paused" {
+		return fmt.Errorf("container %s is not paused", name)
+	}
+
+	container.State = "running"
+	container.Inspect.State.Status = "running"
+	container.Inspect.State.Paused = false
+	container.ListData.State = "running"
+	return nil
+}
+
+// UpdateContainer changes the restart policy of a mock container in place
+func (m *MockPodmanClient) UpdateContainer(ctx context.Context, name string, update ContainerUpdate) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["UpdateContainer"]++
+
+	if m.shouldFailOperations["UpdateContainer"] {
+		return fmt.Errorf("mock update container failed")
+	}
+
+	container, exists := m.containers[name]
+	if !exists {
+		return fmt.Errorf("container not found: %s", name)
+	}
+
+	if update.RestartPolicy != "" {
+		container.Spec.RestartPolicy = update.RestartPolicy
+		container.Inspect.HostConfig.RestartPolicy = &define.InspectRestartPolicy{Name: update.RestartPolicy}
+	}
+	for _, env := range update.Env {
+		key, _, _ := strings.Cut(env, "=")
+		container.Inspect.Config.Env = slices.DeleteFunc(container.Inspect.Config.Env, func(existing string) bool {
+			return strings.HasPrefix(existing, key+"=")
+		})
+		container.Inspect.Config.Env = append(container.Inspect.Config.Env, env)
+	}
+	return nil
+}
+
+// ExecContainer records the command and returns the exit code seeded for it, or 0
+func (m *MockPodmanClient) ExecContainer(ctx context.Context, name string, command []string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["ExecContainer"]++
+
+	if m.shouldFailOperations["ExecContainer"] {
+		return -1, fmt.Errorf("mock exec container failed")
+	}
+
+	container, exists := m.containers[name]
+	if !exists {
+		return -1, fmt.Errorf("container not found: %s", name)
+	}
+	if container.State != "running" {
+		return -1, fmt.Errorf("container %s is not running", name)
+	}
+
+	m.execs = append(m.execs, MockExec{Container: name, Command: append([]string(nil), command...)})
+	return m.execExitCodes[strings.Join(command, " ")], nil
+}
+
+// ContainerLogs returns the last tail lines seeded for a container
+func (m *MockPodmanClient) ContainerLogs(ctx context.Context, name string, tail int) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["ContainerLogs"]++
+
+	if _, exists := m.containers[name]; !exists {
+		return nil, fmt.Errorf("container not found: %s", name)
+	}
+
+	lines := m.logs[name]
+	if tail >= 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	return slices.Clone(lines), nil
+}
+
+// CopyFromContainer writes the archive stored for the named volume mounted at path
+func (m *MockPodmanClient) CopyFromContainer(ctx context.Context, name, path string, w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["CopyFromContainer"]++
+
+	if m.shouldFailOperations["CopyFromContainer"] {
+		return fmt.Errorf("mock copy from container failed")
+	}
+
+	volume, err := m.mountedVolume(name, path)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(m.volumeArchives[volume])
+	return err
+}
+
+// CopyToContainer stores the archive read from r for the named volume mounted at path
+func (m *MockPodmanClient) CopyToContainer(ctx context.Context, name, path string, r io.Reader) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["CopyToContainer"]++
+
+	if m.shouldFailOperations["CopyToContainer"] {
+		return fmt.Errorf("mock copy to container failed")
+	}
+
+	volume, err := m.mountedVolume(name, path)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.volumeArchives[volume] = data
+	return nil
+}
+
+// mountedVolume returns the named volume the container mounts at or above path
+func (m *MockPodmanClient) mountedVolume(name, path string) (string, error) {
+	container, exists := m.containers[name]
+	if !exists {
+		return "", fmt.Errorf("container not found: %s", name)
+	}
+	for _, volume := range container.Spec.Volumes {
+		if path == volume.Dest || strings.HasPrefix(path, strings.TrimSuffix(volume.Dest, "/")+"/") {
+			return volume.Name, nil
+		}
+	}
+	return "", fmt.Errorf("no volume mounted at %s in container %s", path, name)
+}
+
 // RemoveContainer removes a mock container
 func (m *MockPodmanClient) RemoveContainer(ctx context.Context, name string) error {
 	m.mu.Lock()
@@ -209,7 +571,7 @@ func (m *MockPodmanClient) ListContainers(ctx context.Context, filters map[strin
 	var result []types.ListContainer
 	for _, container := range m.containers {
 		// Apply filters
-		if m.matchesFilters(container.Labels, filters) {
+		if m.matchesFilters(container.Labels, filters) && m.matchesNetworkFilter(container, filters["network"]) {
 			if all || container.State == "running" {
 				result = append(result, *container.ListData)
 			}
@@ -233,6 +595,11 @@ func (m *MockPodmanClient) InspectContainer(ctx context.Context, name string) (*
 	if container, exists := m.containers[name]; exists {
 		return container.Inspect, nil
 	}
+	for _, container := range m.containers {
+		if container.ID == name {
+			return container.Inspect, nil
+		}
+	}
 
 	return nil, fmt.Errorf("container not found: %s", name)
 }
@@ -249,11 +616,32 @@ func (m *MockPodmanClient) PullImage(ctx context.Context, image string) error {
 	if m.shouldFailOperations["PullImage"] {
 		return fmt.Errorf("mock pull image failed")
 	}
+	if err := m.operationErrors["PullImage"]; err != nil {
+		return err
+	}
 
 	// Add image to mock storage
 	m.images[image] = &inspect.ImageData{
-		ID: fmt.Sprintf("mock-image-%s", image),
+		ID:       fmt.Sprintf("mock-image-%s", image),
+		RepoTags: []string{image},
+	}
+
+	return nil
+}
+
+// PullImageWithProgress simulates pulling an image, reporting the steps of a pull
+func (m *MockPodmanClient) PullImageWithProgress(ctx context.Context, image string, progress func(PullProgress)) error {
+	m.mu.Lock()
+	m.calls["PullImageWithProgress"]++
+	m.mu.Unlock()
+
+	progress(PullProgress{Image: image, Message: fmt.Sprintf("Trying to pull %s...", image)})
+	progress(PullProgress{Image: image, Message: "Copying blob sha256:mock"})
+	if err := m.PullImage(ctx, image); err != nil {
+		return err
 	}
+	progress(PullProgress{Image: image, Message: "Writing manifest to image destination"})
+	progress(PullProgress{Image: image, Done: true})
 
 	return nil
 }
@@ -276,6 +664,58 @@ func (m *MockPodmanClient) GetImage(ctx context.Context, image string) (*inspect
 	return nil, fmt.Errorf("image not found: %s", image)
 }
 
+// BuildImage records the build request and registers the tag as a mock image
+func (m *MockPodmanClient) BuildImage(ctx context.Context, opts BuildOptions) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["BuildImage"]++
+
+	if m.shouldFailOperations["BuildImage"] {
+		return "", fmt.Errorf("mock build image failed")
+	}
+
+	m.builds = append(m.builds, opts)
+
+	id := fmt.Sprintf("mock-build-%d", len(m.builds))
+	m.images[opts.Tag] = &inspect.ImageData{
+		ID:       id,
+		RepoTags: []string{opts.Tag},
+	}
+
+	return id, nil
+}
+
+// PruneImages removes mock images that have no tags and are not used by any container,
+// returning their IDs in sorted order
+func (m *MockPodmanClient) PruneImages(ctx context.Context, filters map[string][]string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["PruneImages"]++
+
+	if m.shouldFailOperations["PruneImages"] {
+		return nil, fmt.Errorf("mock prune images failed")
+	}
+
+	inUse := make(map[string]bool, len(m.containers))
+	for _, container := range m.containers {
+		inUse[container.Image] = true
+	}
+
+	removed := make([]string, 0)
+	for name, image := range m.images {
+		if len(image.RepoTags) > 0 || inUse[name] || inUse[image.ID] {
+			continue
+		}
+		removed = append(removed, image.ID)
+		delete(m.images, name)
+	}
+	sort.Strings(removed)
+
+	return removed, nil
+}
+
 // Network operations
 
 // CreateNetwork creates a mock network
@@ -290,12 +730,15 @@ func (m *MockPodmanClient) CreateNetwork(ctx context.Context, spec NetworkSpec)
 	}
 
 	network := &NetworkInfo{
-		ID:      fmt.Sprintf("mock-network-%s", spec.Name),
-		Name:    spec.Name,
-		Driver:  spec.Driver,
-		Options: spec.Options,
-		Subnet:  spec.Subnet,
-		Labels:  spec.Labels,
+		ID:       fmt.Sprintf("mock-network-%s", spec.Name),
+		Name:     spec.Name,
+		Driver:   spec.Driver,
+		Options:  spec.Options,
+		Subnet:   spec.Subnet,
+		Gateway:  spec.Gateway,
+		IPRange:  spec.IPRange,
+		Labels:   spec.Labels,
+		Internal: spec.Internal,
 	}
 
 	m.networks[spec.Name] = network
@@ -361,7 +804,7 @@ func (m *MockPodmanClient) InspectNetwork(ctx context.Context, name string) (*Ne
 }
 
 // ConnectContainerToNetwork connects a container to a network (mock)
-func (m *MockPodmanClient) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string) error {
+func (m *MockPodmanClient) ConnectContainerToNetwork(ctx context.Context, containerName, networkName string, options nettypes.PerNetworkOptions) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -371,17 +814,36 @@ func (m *MockPodmanClient) ConnectContainerToNetwork(ctx context.Context, contai
 		return fmt.Errorf("mock connect container to network failed")
 	}
 
-	// Just verify both exist
-	if _, exists := m.containers[containerName]; !exists {
+	container, exists := m.containers[containerName]
+	if !exists {
 		return fmt.Errorf("container not found: %s", containerName)
 	}
 	if _, exists := m.networks[networkName]; !exists {
 		return fmt.Errorf("network not found: %s", networkName)
 	}
 
+	// Record the attachment so inspect reflects its options
+	container.Inspect.NetworkSettings.Networks[networkName] = mockNetworkAttachment(networkName, options)
+
 	return nil
 }
 
+// mockNetworkAttachment reports a network attachment the way inspect would, with the
+// static IP and MAC address as the ones assigned
+func mockNetworkAttachment(networkName string, options nettypes.PerNetworkOptions) *define.InspectAdditionalNetwork {
+	attachment := &define.InspectAdditionalNetwork{
+		NetworkID: networkName,
+		Aliases:   options.Aliases,
+	}
+	if len(options.StaticIPs) > 0 {
+		attachment.IPAddress = options.StaticIPs[0].String()
+	}
+	if len(options.StaticMAC) > 0 {
+		attachment.MacAddress = options.StaticMAC.String()
+	}
+	return attachment
+}
+
 // DisconnectContainerFromNetwork disconnects a container from a network (mock)
 func (m *MockPodmanClient) DisconnectContainerFromNetwork(ctx context.Context, containerName, networkName string) error {
 	m.mu.Lock()
@@ -393,6 +855,10 @@ func (m *MockPodmanClient) DisconnectContainerFromNetwork(ctx context.Context, c
 		return fmt.Errorf("mock disconnect container from network failed")
 	}
 
+	if container, exists := m.containers[containerName]; exists {
+		delete(container.Inspect.NetworkSettings.Networks, networkName)
+	}
+
 	return nil
 }
 
@@ -479,6 +945,93 @@ func (m *MockPodmanClient) InspectVolume(ctx context.Context, name string) (*Vol
 	return nil, fmt.Errorf("volume not found: %s", name)
 }
 
+// Pod operations
+
+// CreatePod creates a mock pod
+func (m *MockPodmanClient) CreatePod(ctx context.Context, spec PodSpec) (*PodInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["CreatePod"]++
+
+	if m.shouldFailOperations["CreatePod"] {
+		return nil, fmt.Errorf("mock create pod failed")
+	}
+
+	if _, exists := m.pods[spec.Name]; exists {
+		return nil, fmt.Errorf("pod already exists: %s", spec.Name)
+	}
+
+	pod := &PodInfo{
+		ID:               fmt.Sprintf("mock-pod-%s", spec.Name),
+		Name:             spec.Name,
+		Status:           "Created",
+		Labels:           spec.Labels,
+		SharedNamespaces: spec.SharedNamespaces,
+		PortMappings:     spec.PortMappings,
+		Mounts:           spec.Mounts,
+	}
+
+	m.pods[spec.Name] = pod
+	return pod, nil
+}
+
+// RemovePod removes a mock pod and the containers in it
+func (m *MockPodmanClient) RemovePod(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["RemovePod"]++
+
+	if m.shouldFailOperations["RemovePod"] {
+		return fmt.Errorf("mock remove pod failed")
+	}
+
+	if _, exists := m.pods[name]; !exists {
+		return fmt.Errorf("pod not found: %s", name)
+	}
+
+	for containerName, container := range m.containers {
+		if container.Spec != nil && container.Spec.Pod == name {
+			delete(m.containers, containerName)
+		}
+	}
+	delete(m.pods, name)
+	return nil
+}
+
+// ListPods lists mock pods with their member containers
+func (m *MockPodmanClient) ListPods(ctx context.Context, filters map[string][]string) ([]PodInfo, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	m.calls["ListPods"]++
+
+	if m.shouldFailOperations["ListPods"] {
+		return nil, fmt.Errorf("mock list pods failed")
+	}
+
+	var result []PodInfo
+	for _, pod := range m.pods {
+		if !m.matchesFilters(pod.Labels, filters) {
+			continue
+		}
+
+		listed := *pod
+		listed.Containers = nil
+		for _, container := range m.containers {
+			if container.Spec != nil && container.Spec.Pod == pod.Name {
+				listed.Containers = append(listed.Containers, container.Name)
+			}
+		}
+		sort.Strings(listed.Containers)
+
+		result = append(result, listed)
+	}
+
+	return result, nil
+}
+
 // Secret operations
 
 // CreateSecret creates a mock secret
@@ -583,8 +1136,87 @@ func (m *MockPodmanClient) InspectSecret(ctx context.Context, name string) (*Sec
 	return nil, fmt.Errorf("secret not found: %s", name)
 }
 
+// Event operations
+
+// WatchEvents returns a channel fed by EmitEvent; it is closed when ctx is cancelled
+func (m *MockPodmanClient) WatchEvents(ctx context.Context, filters map[string][]string) (<-chan Event, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls["WatchEvents"]++
+
+	if m.shouldFailOperations["WatchEvents"] {
+		return nil, fmt.Errorf("mock watch events failed")
+	}
+
+	subscriber := &mockEventSubscriber{
+		filters: filters,
+		events:  make(chan Event, mockEventBufferSize),
+	}
+	m.eventSubscribers = append(m.eventSubscribers, subscriber)
+
+	go func() {
+		<-ctx.Done()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		for i, s := range m.eventSubscribers {
+			if s == subscriber {
+				m.eventSubscribers = append(m.eventSubscribers[:i], m.eventSubscribers[i+1:]...)
+				close(subscriber.events)
+				break
+			}
+		}
+	}()
+
+	return subscriber.events, nil
+}
+
+// matchesEventFilters checks an event against the type, event, container and label filters
+func (m *MockPodmanClient) matchesEventFilters(event Event, filters map[string][]string) bool {
+	for filterKey, filterValues := range filters {
+		matched := false
+		for _, filterValue := range filterValues {
+			switch filterKey {
+			case "type":
+				matched = event.Type == filterValue
+			case "event":
+				matched = event.Action == filterValue
+			case "container":
+				matched = event.Name == filterValue || event.ID == filterValue
+			case "label":
+				key, value, hasValue := strings.Cut(filterValue, "=")
+				actual, exists := event.Attributes[key]
+				matched = exists && (!hasValue || actual == value)
+			default:
+				matched = true
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
 // Test helper methods
 
+// EmitEvent delivers an event to every WatchEvents subscriber whose filters match it
+func (m *MockPodmanClient) EmitEvent(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, subscriber := range m.eventSubscribers {
+		if m.matchesEventFilters(event, subscriber.filters) {
+			subscriber.events <- event
+		}
+	}
+}
+
 // SetShouldFailConnect sets whether Connect should fail
 func (m *MockPodmanClient) SetShouldFailConnect(shouldFail bool) {
 	m.mu.Lock()
@@ -599,6 +1231,14 @@ func (m *MockPodmanClient) SetShouldFailOperation(operation string, shouldFail b
 	m.shouldFailOperations[operation] = shouldFail
 }
 
+// SetOperationError makes CreateContainer or PullImage fail with err, or succeed again
+// when err is nil
+func (m *MockPodmanClient) SetOperationError(operation string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.operationErrors[operation] = err
+}
+
 // GetCallCount returns the number of times a method was called
 func (m *MockPodmanClient) GetCallCount(method string) int {
 	m.mu.RLock()
@@ -606,6 +1246,72 @@ func (m *MockPodmanClient) GetCallCount(method string) int {
 	return m.calls[method]
 }
 
+// GetNetworkAliases returns the aliases a container was given on a network
+func (m *MockPodmanClient) GetNetworkAliases(containerName, networkName string) ([]string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	container, exists := m.containers[containerName]
+	if !exists {
+		return nil, false
+	}
+
+	attachment, exists := container.Inspect.NetworkSettings.Networks[networkName]
+	if !exists {
+		return nil, false
+	}
+	return attachment.Aliases, true
+}
+
+// GetBuildRequests returns every BuildImage request in the order they were made
+func (m *MockPodmanClient) GetBuildRequests() []BuildOptions {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]BuildOptions(nil), m.builds...)
+}
+
+// SetExecExitCode seeds the exit code ExecContainer returns for a command
+func (m *MockPodmanClient) SetExecExitCode(command []string, exitCode int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.execExitCodes[strings.Join(command, " ")] = exitCode
+}
+
+// SetVolumeArchive seeds the archive copied out of a named volume
+func (m *MockPodmanClient) SetVolumeArchive(volume string, data []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.volumeArchives[volume] = data
+}
+
+// GetVolumeArchive returns the archive last copied into a named volume
+func (m *MockPodmanClient) GetVolumeArchive(volume string) []byte {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.volumeArchives[volume]
+}
+
+// GetExecs returns the commands run through ExecContainer, in order
+func (m *MockPodmanClient) GetExecs() []MockExec {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]MockExec(nil), m.execs...)
+}
+
+// SetContainerStats seeds the stats returned for a container
+func (m *MockPodmanClient) SetContainerStats(name string, stats ContainerStats) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats[name] = &stats
+}
+
+// SetContainerLogs seeds the log lines returned for a container
+func (m *MockPodmanClient) SetContainerLogs(name string, lines []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.logs[name] = lines
+}
+
 // Reset clears all mock data and call counts
 func (m *MockPodmanClient) Reset() {
 	m.mu.Lock()
@@ -616,6 +1322,11 @@ func (m *MockPodmanClient) Reset() {
 	m.volumes = make(map[string]*VolumeInfo)
 	m.secrets = make(map[string]*SecretInfo)
 	m.images = make(map[string]*inspect.ImageData)
+	m.stats = make(map[string]*ContainerStats)
+	m.logs = make(map[string][]string)
+	m.builds = nil
+	m.execs = nil
+	m.execExitCodes = make(map[string]int)
 	m.shouldFailOperations = make(map[string]bool)
 	m.calls = make(map[string]int)
 	m.shouldFailConnect = false
@@ -628,22 +1339,56 @@ func (m *MockPodmanClient) AddMockImage(name string, imageData *inspect.ImageDat
 	m.images[name] = imageData
 }
 
-// matchesFilters checks if labels match the given filters
-func (m *MockPodmanClient) matchesFilters(labels map[string]string, filters map[string][]string) bool {
-	if len(filters) == 0 {
+// namespaceMode returns the namespace mode Podman reports for ns, naming joined
+// containers by ID like Podman does. Pod namespaces are not modeled, since mock pods have
+// no infra container.
+func (m *MockPodmanClient) namespaceMode(ns specgen.Namespace, defaultMode string) string {
+	switch ns.NSMode {
+	case "", specgen.Default:
+		return defaultMode
+	case specgen.FromContainer:
+		if container, exists := m.containers[ns.Value]; exists {
+			return "container:" + container.ID
+		}
+		return "container:" + ns.Value
+	case specgen.FromPod:
+		return ""
+	}
+	return string(ns.NSMode)
+}
+
+// mockSecurityOptions returns the security options Podman reports for a container
+// created from spec, which include the seccomp profile given by its annotation
+func mockSecurityOptions(spec *specgen.SpecGenerator) []string {
+	var options []string
+	if profile, ok := spec.Annotations[define.InspectAnnotationSeccomp]; ok {
+		options = append(options, "seccomp="+profile)
+	}
+	return options
+}
+
+// matchesNetworkFilter checks if a container is attached to one of the given networks
+func (m *MockPodmanClient) matchesNetworkFilter(container *MockContainer, networks []string) bool {
+	if len(networks) == 0 {
 		return true
 	}
 
-	for filterKey, filterValues := range filters {
-		if filterKey == "label" {
-			for _, filterValue := range filterValues {
-				// Handle label filters in format "key=value"
-				for labelKey, labelValue := range labels {
-					if filterValue == fmt.Sprintf("%s=%s", labelKey, labelValue) {
-						return true
-					}
-				}
-			}
+	for _, networkName := range networks {
+		if _, attached := container.Inspect.NetworkSettings.Networks[networkName]; attached {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesFilters checks if labels match the given filters; every label filter has to match
+func (m *MockPodmanClient) matchesFilters(labels map[string]string, filters map[string][]string) bool {
+	for _, filterValue := range filters["label"] {
+		// Handle label filters in format "key=value", or "key" for any value
+		key, value, hasValue := strings.Cut(filterValue, "=")
+		labelValue, exists := labels[key]
+		if !exists || (hasValue && labelValue != value) {
 			return false
 		}
 	}
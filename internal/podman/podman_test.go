@@ -2,8 +2,16 @@ package podman
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	nettypes "github.com/containers/common/libnetwork/types"
 	"github.com/containers/podman/v5/pkg/specgen"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -45,6 +53,57 @@ func TestClientProvider(t *testing.T) {
 	assert.Same(t, client, mockClient) // Should be the same instance
 }
 
+// TestPodmanAdapterURI verifies URI and identity handling for local and remote connections
+func TestPodmanAdapterURI(t *testing.T) {
+	t.Setenv("PODMAN_SOCK", "ssh://core@build.example.com:2222/run/podman/podman.sock")
+	t.Setenv("PODMAN_SSH_IDENTITY", "/home/core/.ssh/id_ed25519")
+
+	adapter := NewPodmanAdapter()
+	assert.Equal(t, "ssh://core@build.example.com:2222/run/podman/podman.sock", adapter.uri)
+	assert.Equal(t, "/home/core/.ssh/id_ed25519", adapter.identity)
+
+	adapter = NewPodmanAdapterWithURI("ssh://core@10.0.0.5/run/podman/podman.sock", "/tmp/key")
+	assert.Equal(t, "/tmp/key", adapter.identity)
+
+	assert.True(t, isSSHURI("ssh://core@10.0.0.5/run/podman/podman.sock"))
+	assert.False(t, isSSHURI("unix:/run/user/1000/podman/podman.sock"))
+	assert.False(t, isSSHURI("tcp://localhost:8080"))
+
+	assert.Equal(t, "build.example.com:2222", connectionHost("ssh://core@build.example.com:2222/run/podman/podman.sock"))
+	assert.Equal(t, "unix:/run/user/1000/podman/podman.sock", connectionHost("unix:/run/user/1000/podman/podman.sock"))
+}
+
+// TestPodmanAdapter_CallHonoursContextDeadline verifies that a call is bounded by the
+// caller's context rather than the one the connection was made with
+func TestPodmanAdapter_CallHonoursContextDeadline(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "podman.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	// Answers the ping made when connecting, then hangs until the request is abandoned
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/_ping") {
+			w.Header().Set("Libpod-API-Version", "5.0.0")
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		<-r.Context().Done()
+	})}
+	go server.Serve(listener)
+	defer server.Close()
+
+	adapter := NewPodmanAdapterWithURI("unix://"+socketPath, "")
+	require.NoError(t, adapter.Connect(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	startTime := time.Now()
+	_, err = adapter.ExecContainer(ctx, "web", []string{"true"})
+	require.Error(t, err)
+	assert.Less(t, time.Since(startTime), 5*time.Second)
+}
+
 // TestConnectedClient verifies the connected client wrapper
 func TestConnectedClient(t *testing.T) {
 	mockClient := NewMockPodmanClient()
@@ -93,6 +152,33 @@ func TestConnectedClient(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+// TestConnectedClient_SharedConnection verifies that a shared connection is reused and closed once
+func TestConnectedClient_SharedConnection(t *testing.T) {
+	mockClient := NewMockPodmanClient()
+	shared := NewConnectedClient(mockClient)
+	ctx := WithSharedConnection(context.Background(), shared)
+
+	for i := 0; i < 3; i++ {
+		connectedClient := NewConnectedClient(mockClient)
+		client, err := connectedClient.GetClient(ctx)
+		require.NoError(t, err)
+		assert.Same(t, mockClient, client)
+		require.NoError(t, connectedClient.Close())
+	}
+
+	assert.Equal(t, 1, mockClient.GetCallCount("Connect"))
+	assert.Equal(t, 0, mockClient.GetCallCount("Close"))
+
+	require.NoError(t, shared.Close())
+	assert.Equal(t, 1, mockClient.GetCallCount("Close"))
+
+	// A different client does not pick up the shared connection
+	otherClient := NewMockPodmanClient()
+	_, err := NewConnectedClient(otherClient).GetClient(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, otherClient.GetCallCount("Connect"))
+}
+
 // TestMockPodmanClient_BasicOperations tests basic mock client functionality
 func TestMockPodmanClient_BasicOperations(t *testing.T) {
 	client := NewMockPodmanClient()
@@ -166,6 +252,85 @@ func TestMockPodmanClient_ContainerOperations(t *testing.T) {
 	assert.Len(t, containers, 0)
 }
 
+// TestMockPodmanClient_ContainerStats tests default and seeded container stats
+func TestMockPodmanClient_ContainerStats(t *testing.T) {
+	client := NewMockPodmanClient()
+	ctx := context.Background()
+
+	_, err := client.ContainerStats(ctx, "missing")
+	assert.Error(t, err)
+
+	_, err = client.CreateContainer(ctx, &specgen.SpecGenerator{
+		ContainerBasicConfig: specgen.ContainerBasicConfig{Name: "test-container"},
+	})
+	require.NoError(t, err)
+
+	stats, err := client.ContainerStats(ctx, "test-container")
+	require.NoError(t, err)
+	assert.Equal(t, "test-container", stats.Name)
+	assert.NotZero(t, stats.MemoryLimit)
+
+	client.SetContainerStats("test-container", ContainerStats{CPUPercent: 42, MemoryUsage: 1024})
+	stats, err = client.ContainerStats(ctx, "test-container")
+	require.NoError(t, err)
+	assert.Equal(t, 42.0, stats.CPUPercent)
+	assert.Equal(t, uint64(1024), stats.MemoryUsage)
+}
+
+// TestMockPodmanClient_ExecContainer tests exec recording and seeded exit codes
+func TestMockPodmanClient_ExecContainer(t *testing.T) {
+	client := NewMockPodmanClient()
+	ctx := context.Background()
+
+	response, err := client.CreateContainer(ctx, &specgen.SpecGenerator{
+		ContainerBasicConfig: specgen.ContainerBasicConfig{Name: "test-container"},
+	})
+	require.NoError(t, err)
+
+	_, err = client.ExecContainer(ctx, "test-container", []string{"true"})
+	assert.Error(t, err, "exec requires a running container")
+
+	require.NoError(t, client.StartContainer(ctx, response.ID))
+
+	exitCode, err := client.ExecContainer(ctx, "test-container", []string{"true"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, exitCode)
+
+	client.SetExecExitCode([]string{"test", "-f", "/ready"}, 1)
+	exitCode, err = client.ExecContainer(ctx, "test-container", []string{"test", "-f", "/ready"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, exitCode)
+
+	execs := client.GetExecs()
+	require.Len(t, execs, 2)
+	assert.Equal(t, MockExec{Container: "test-container", Command: []string{"test", "-f", "/ready"}}, execs[1])
+}
+
+// TestMockPodmanClient_WatchEvents tests event filtering and closing on cancellation
+func TestMockPodmanClient_WatchEvents(t *testing.T) {
+	client := NewMockPodmanClient()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := client.WatchEvents(ctx, map[string][]string{
+		"type":  {"container"},
+		"event": {"died"},
+		"label": {"cutepod.io/chart=demo"},
+	})
+	require.NoError(t, err)
+
+	client.EmitEvent(Event{Type: "container", Action: "start", Name: "web", Attributes: map[string]string{"cutepod.io/chart": "demo"}})
+	client.EmitEvent(Event{Type: "container", Action: "died", Name: "other", Attributes: map[string]string{"cutepod.io/chart": "other"}})
+	client.EmitEvent(Event{Type: "container", Action: "died", Name: "web", Attributes: map[string]string{"cutepod.io/chart": "demo"}})
+
+	event := <-events
+	assert.Equal(t, "web", event.Name)
+	assert.Equal(t, "died", event.Action)
+
+	cancel()
+	_, open := <-events
+	assert.False(t, open)
+}
+
 // TestMockPodmanClient_NetworkOperations tests network operations
 func TestMockPodmanClient_NetworkOperations(t *testing.T) {
 	client := NewMockPodmanClient()
@@ -213,13 +378,20 @@ func TestMockPodmanClient_NetworkOperations(t *testing.T) {
 	require.NoError(t, err)
 
 	// Connect container to network
-	err = client.ConnectContainerToNetwork(ctx, "test-container", "test-network")
+	err = client.ConnectContainerToNetwork(ctx, "test-container", "test-network", nettypes.PerNetworkOptions{Aliases: []string{"web"}})
 	assert.NoError(t, err)
 
+	aliases, connected := client.GetNetworkAliases("test-container", "test-network")
+	assert.True(t, connected)
+	assert.Equal(t, []string{"web"}, aliases)
+
 	// Disconnect container from network
 	err = client.DisconnectContainerFromNetwork(ctx, "test-container", "test-network")
 	assert.NoError(t, err)
 
+	_, connected = client.GetNetworkAliases("test-container", "test-network")
+	assert.False(t, connected)
+
 	// Remove network
 	err = client.RemoveNetwork(ctx, "test-network")
 	assert.NoError(t, err)
@@ -230,6 +402,60 @@ func TestMockPodmanClient_NetworkOperations(t *testing.T) {
 	assert.Len(t, networks, 0)
 }
 
+// TestMockPodmanClient_InternalNetwork tests that the internal flag survives create, list and inspect
+func TestMockPodmanClient_InternalNetwork(t *testing.T) {
+	client := NewMockPodmanClient()
+	ctx := context.Background()
+
+	network, err := client.CreateNetwork(ctx, NetworkSpec{Name: "isolated", Driver: "bridge", Internal: true})
+	require.NoError(t, err)
+	assert.True(t, network.Internal)
+
+	networks, err := client.ListNetworks(ctx, nil)
+	require.NoError(t, err)
+	require.Len(t, networks, 1)
+	assert.True(t, networks[0].Internal)
+
+	inspectNetwork, err := client.InspectNetwork(ctx, "isolated")
+	require.NoError(t, err)
+	assert.True(t, inspectNetwork.Internal)
+
+	config, err := buildNetworkConfig(NetworkSpec{Name: "isolated", Internal: true})
+	require.NoError(t, err)
+	assert.True(t, config.Internal)
+}
+
+// TestAdapterNetworkGatewayAndIPRange tests that the gateway and IP range are applied to
+// the Podman network and read back from it
+func TestAdapterNetworkGatewayAndIPRange(t *testing.T) {
+	config, err := buildNetworkConfig(NetworkSpec{
+		Name:    "backend",
+		Subnet:  "172.20.0.0/16",
+		Gateway: "172.20.0.1",
+		IPRange: "172.20.5.0/24",
+	})
+	require.NoError(t, err)
+	require.Len(t, config.Subnets, 1)
+	assert.Equal(t, "172.20.0.1", config.Subnets[0].Gateway.String())
+	require.NotNil(t, config.Subnets[0].LeaseRange)
+	assert.Equal(t, "172.20.5.1", config.Subnets[0].LeaseRange.StartIP.String())
+	assert.Equal(t, "172.20.5.255", config.Subnets[0].LeaseRange.EndIP.String())
+
+	info := networkInfoFromPodman(*config)
+	assert.Equal(t, "172.20.0.0/16", info.Subnet)
+	assert.Equal(t, "172.20.0.1", info.Gateway)
+	assert.Equal(t, "172.20.5.0/24", info.IPRange)
+
+	// Ranges set outside cutepod that are not a CIDR block are still reported
+	config.Subnets[0].LeaseRange.EndIP = net.ParseIP("172.20.5.100")
+	assert.Equal(t, "172.20.5.1-172.20.5.100", networkInfoFromPodman(*config).IPRange)
+
+	_, err = buildNetworkConfig(NetworkSpec{Name: "backend", Subnet: "172.20.0.0/16", Gateway: "10.0.0.1"})
+	assert.Error(t, err)
+	_, err = buildNetworkConfig(NetworkSpec{Name: "backend", Gateway: "172.20.0.1"})
+	assert.Error(t, err)
+}
+
 // TestMockPodmanClient_VolumeOperations tests volume operations
 func TestMockPodmanClient_VolumeOperations(t *testing.T) {
 	client := NewMockPodmanClient()
@@ -351,6 +577,7 @@ func TestMockPodmanClient_ErrorHandling(t *testing.T) {
 	err := client.Connect(ctx)
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "mock connection failed")
+	assert.True(t, IsConnectionError(err))
 
 	// Reset and test operation failure
 	client.Reset()
@@ -422,6 +649,23 @@ func TestMockPodmanClient_FilterMatching(t *testing.T) {
 	containers, err = client.ListContainers(ctx, filters, true)
 	require.NoError(t, err)
 	assert.Len(t, containers, 0)
+
+	// Test that every label filter has to match
+	filters = map[string][]string{
+		"label": {"namespace=default", "app=other"},
+	}
+
+	containers, err = client.ListContainers(ctx, filters, true)
+	require.NoError(t, err)
+	assert.Len(t, containers, 0)
+
+	filters = map[string][]string{
+		"label": {"namespace=default", "app"},
+	}
+
+	containers, err = client.ListContainers(ctx, filters, true)
+	require.NoError(t, err)
+	assert.Len(t, containers, 1)
 }
 
 // TestMockPodmanClient_Reset tests the reset functionality
@@ -550,3 +794,73 @@ func TestResourceInfo(t *testing.T) {
 	assert.Equal(t, "test-secret", secretInfo.Name)
 	assert.Equal(t, "secret-123", secretInfo.ID)
 }
+
+// TestIsConnectionError tests detection of an unreachable Podman socket
+func TestIsConnectionError(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "unix", Err: errors.New("connect: permission denied")}
+
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"missing socket", errors.New("unable to connect to podman at /run/podman/podman.sock: unable to connect to Podman socket: Get \"http://d/v5.5.2/libpod/_ping\": dial unix /run/podman/podman.sock: connect: no such file or directory"), true},
+		{"refused", errors.New("dial unix /run/user/1000/podman/podman.sock: connect: connection refused"), true},
+		{"refused over tcp", errors.New("dial tcp 127.0.0.1:8080: connect: connection refused"), true},
+		{"wrapped dial error", fmt.Errorf("failed to list containers: %w", dialErr), true},
+		{"missing container", errors.New("no container with name or ID \"web\" found: no such container"), false},
+		{"missing Containerfile", errors.New("open ./app/Containerfile: no such file or directory"), false},
+		{"api error", errors.New("failed to create container: image not known"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsConnectionError(tt.err))
+		})
+	}
+}
+
+// TestIsImageNotFoundError tests detection of an image missing from local storage
+func TestIsImageNotFoundError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"not known", errors.New("unable to get image: docker.io/library/nginx:1.25: image not known"), true},
+		{"mock", errors.New("image not found: nginx:1.25"), true},
+		{"connection", errors.New("dial unix /run/podman/podman.sock: connect: connection refused"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsImageNotFoundError(tt.err))
+		})
+	}
+}
+
+// TestIsRetryable tests the classification of transient and permanent errors
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{"nil", nil, false},
+		{"connection reset", errors.New("read unix @->/run/podman/podman.sock: read: connection reset by peer"), true},
+		{"server error", errors.New("failed to create container: internal libpod error"), true},
+		{"missing from registry", errors.New("initializing source docker://example/missing:1.0: reading manifest 1.0 in docker.io/example/missing: manifest unknown"), false},
+		{"access denied", errors.New("reading manifest latest in quay.io/private/app: requested access to the resource is denied"), false},
+		{"missing locally", errors.New("image not known"), false},
+		{"invalid port", errors.New("invalid config provided: invalid port number 70000"), false},
+		{"socket missing", errors.New("dial unix /run/podman/podman.sock: connect: no such file or directory"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, IsRetryable(tt.err))
+		})
+	}
+}
@@ -3,6 +3,7 @@ package podman
 import (
 	"context"
 	"fmt"
+	"sync"
 )
 
 // ClientProvider provides Podman clients
@@ -53,10 +54,30 @@ func (p *MockClientProvider) GetMockClient() *MockPodmanClient {
 
 // ConnectedClient wraps a PodmanClient with automatic connection management
 type ConnectedClient struct {
+	mu        sync.Mutex
 	client    PodmanClient
 	connected bool
 }
 
+type sharedConnectionKey struct{}
+
+// WithSharedConnection returns a context carrying a long-lived connected client.
+// ConnectedClients wrapping the same PodmanClient reuse its connection instead of
+// opening their own, and their Close becomes a no-op; the caller that created the
+// shared client is responsible for closing it.
+func WithSharedConnection(ctx context.Context, shared *ConnectedClient) context.Context {
+	return context.WithValue(ctx, sharedConnectionKey{}, shared)
+}
+
+// sharedConnection returns the shared connected client from ctx if it wraps the same client
+func (c *ConnectedClient) sharedConnection(ctx context.Context) (*ConnectedClient, bool) {
+	shared, ok := ctx.Value(sharedConnectionKey{}).(*ConnectedClient)
+	if !ok || shared == c || shared.client != c.client {
+		return nil, false
+	}
+	return shared, true
+}
+
 // NewConnectedClient creates a new connected client wrapper
 func NewConnectedClient(client PodmanClient) *ConnectedClient {
 	return &ConnectedClient{
@@ -67,6 +88,9 @@ func NewConnectedClient(client PodmanClient) *ConnectedClient {
 
 // ensureConnected ensures the client is connected
 func (c *ConnectedClient) ensureConnected(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if !c.connected {
 		if err := c.client.Connect(ctx); err != nil {
 			return fmt.Errorf("failed to connect to podman: %w", err)
@@ -78,6 +102,9 @@ func (c *ConnectedClient) ensureConnected(ctx context.Context) error {
 
 // Close closes the connection and cleans up
 func (c *ConnectedClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	if c.connected {
 		err := c.client.Close()
 		c.connected = false
@@ -88,6 +115,10 @@ func (c *ConnectedClient) Close() error {
 
 // GetClient returns the underlying client, ensuring it's connected
 func (c *ConnectedClient) GetClient(ctx context.Context) (PodmanClient, error) {
+	if shared, ok := c.sharedConnection(ctx); ok {
+		return shared.GetClient(ctx)
+	}
+
 	if err := c.ensureConnected(ctx); err != nil {
 		return nil, err
 	}
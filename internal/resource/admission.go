@@ -0,0 +1,114 @@
+package resource
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ErrorTypeAdmission represents a resource rejected by an admission check
+const ErrorTypeAdmission ErrorType = "admission"
+
+// defaultImageRegistry is the registry of image references that do not name one
+const defaultImageRegistry = "docker.io"
+
+// AdmissionChecker enforces a policy on every manifest before reconciliation creates or
+// changes anything
+type AdmissionChecker interface {
+	// Name identifies the rule in the errors of rejected resources
+	Name() string
+
+	// Check returns an error describing why the resource violates the rule, or nil
+	Check(resource Resource) error
+}
+
+// WithAdmissionCheckers runs the given checks on every manifest during validation. A
+// resource that fails any of them stops the reconcile before anything is changed.
+func WithAdmissionCheckers(checkers ...AdmissionChecker) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.admissionCheckers = append(rc.admissionCheckers, checkers...)
+	}
+}
+
+// checkAdmission runs the admission checks on a resource, returning a non-recoverable
+// error naming the resource and the first rule it violates
+func (rc *DefaultReconciliationController) checkAdmission(resource Resource) error {
+	for _, checker := range rc.admissionCheckers {
+		if err := checker.Check(resource); err != nil {
+			return NewReconciliationError(ErrorTypeAdmission,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("rejected by admission rule %s: %v", checker.Name(), err), err, false)
+		}
+	}
+	return nil
+}
+
+// DenyPrivilegedChecker rejects containers that run privileged
+type DenyPrivilegedChecker struct{}
+
+// NewDenyPrivilegedChecker creates a checker that rejects privileged containers
+func NewDenyPrivilegedChecker() *DenyPrivilegedChecker {
+	return &DenyPrivilegedChecker{}
+}
+
+// Name returns the rule name
+func (c *DenyPrivilegedChecker) Name() string {
+	return "deny-privileged"
+}
+
+// Check rejects a container whose security context makes it privileged
+func (c *DenyPrivilegedChecker) Check(resource Resource) error {
+	container, ok := resource.(*ContainerResource)
+	if !ok {
+		return nil
+	}
+
+	if secCtx := container.Spec.SecurityContext; secCtx != nil && secCtx.Privileged != nil && *secCtx.Privileged {
+		return fmt.Errorf("privileged containers are not allowed")
+	}
+	return nil
+}
+
+// RegistryAllowListChecker rejects containers whose image comes from a registry that is
+// not allowed. Images built from a Containerfile are never pulled and always pass.
+type RegistryAllowListChecker struct {
+	registries []string
+}
+
+// NewRegistryAllowListChecker creates a checker that only allows images from the given
+// registries. An entry may also name a namespace within a registry, such as
+// "quay.io/example", to allow only the images under it.
+func NewRegistryAllowListChecker(registries ...string) *RegistryAllowListChecker {
+	return &RegistryAllowListChecker{registries: registries}
+}
+
+// Name returns the rule name
+func (c *RegistryAllowListChecker) Name() string {
+	return "registry-allow-list"
+}
+
+// Check rejects a container whose image is outside every allowed registry
+func (c *RegistryAllowListChecker) Check(resource Resource) error {
+	container, ok := resource.(*ContainerResource)
+	if !ok || container.Spec.Build != nil {
+		return nil
+	}
+
+	image := qualifiedImageName(container.Spec.Image)
+	for _, registry := range c.registries {
+		if strings.HasPrefix(image, strings.TrimSuffix(registry, "/")+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("image %s is not from an allowed registry (%s)", container.Spec.Image, strings.Join(c.registries, ", "))
+}
+
+// qualifiedImageName prefixes an image reference with the default registry when its
+// first component is not a registry host, the way Podman resolves short names
+// without search registries
+func qualifiedImageName(image string) string {
+	host, _, found := strings.Cut(image, "/")
+	if found && (strings.ContainsAny(host, ".:") || host == "localhost") {
+		return image
+	}
+	return defaultImageRegistry + "/" + image
+}
@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAllowListChecker(t *testing.T) {
+	checker := NewRegistryAllowListChecker("docker.io", "quay.io/example/")
+
+	tests := []struct {
+		image   string
+		allowed bool
+	}{
+		{image: "nginx:1.25", allowed: true},
+		{image: "docker.io/library/nginx:1.25", allowed: true},
+		{image: "quay.io/example/app:1.0", allowed: true},
+		{image: "quay.io/other/app:1.0", allowed: false},
+		{image: "ghcr.io/example/app:1.0", allowed: false},
+		{image: "localhost:5000/app", allowed: false},
+		{image: "docker.io.evil.example/app", allowed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			err := checker.Check(newExplainTestContainer(tt.image))
+			if tt.allowed && err != nil {
+				t.Errorf("Expected %s to be allowed, got %v", tt.image, err)
+			}
+			if !tt.allowed && err == nil {
+				t.Errorf("Expected %s to be rejected", tt.image)
+			}
+		})
+	}
+
+	built := newExplainTestContainer("localhost/app:dev")
+	built.Spec.Build = &BuildSpec{Context: "/srv/app"}
+	if err := checker.Check(built); err != nil {
+		t.Errorf("Expected built images to pass, got %v", err)
+	}
+}
+
+func TestReconcile_AdmissionCheckRejectsResource(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithAdmissionCheckers(NewDenyPrivilegedChecker()))
+
+	privileged := true
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.SecurityContext = &SecurityContext{Privileged: &privileged}
+
+	result, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false)
+	if err == nil {
+		t.Fatal("Expected the privileged container to be rejected")
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %+v", result.Errors)
+	}
+	rejection := result.Errors[0]
+	if rejection.Type != ErrorTypeAdmission || rejection.Recoverable {
+		t.Errorf("Expected a non-recoverable admission error, got %+v", rejection)
+	}
+	if rejection.Resource != (ResourceReference{Type: ResourceTypeContainer, Name: "web"}) {
+		t.Errorf("Expected the container to be named, got %+v", rejection.Resource)
+	}
+	if !strings.Contains(rejection.Message, "deny-privileged") {
+		t.Errorf("Expected the rule to be named, got %q", rejection.Message)
+	}
+	if mockClient.GetCallCount("CreateContainer") != 0 {
+		t.Error("Expected nothing to be created")
+	}
+}
@@ -0,0 +1,55 @@
+package resource
+
+// recordAutoRemove remembers a container created with autoRemove, along with the spec it
+// was created from
+func (cm *ContainerManager) recordAutoRemove(container *ContainerResource) {
+	if !autoRemove(container.Spec) {
+		return
+	}
+	specHash, err := computeContainerSpecHash(container.Spec)
+	if err != nil {
+		return
+	}
+
+	cm.autoRemovedMu.Lock()
+	defer cm.autoRemovedMu.Unlock()
+	if cm.autoRemoved == nil {
+		cm.autoRemoved = make(map[string]string)
+	}
+	cm.autoRemoved[container.GetName()] = specHash
+}
+
+// autoRemovedBefore reports whether this manager already created the container from the
+// same spec with autoRemove, so that its absence means it ran and removed itself
+func (cm *ContainerManager) autoRemovedBefore(container *ContainerResource) bool {
+	if !autoRemove(container.Spec) {
+		return false
+	}
+	specHash, err := computeContainerSpecHash(container.Spec)
+	if err != nil {
+		return false
+	}
+
+	cm.autoRemovedMu.Lock()
+	defer cm.autoRemovedMu.Unlock()
+	return cm.autoRemoved[container.GetName()] == specHash
+}
+
+// skipAutoRemovedContainers keeps containers that removed themselves after running from
+// being created again, which would otherwise happen on every reconcile, and with watch
+// mode reacting to their removal, in a loop. A container runs again once its spec
+// changes. The record is kept in memory, so each new process runs such containers once.
+func (rc *DefaultReconciliationController) skipAutoRemovedContainers(diff *StateDiff) {
+	containerManager, ok := rc.managers[ResourceTypeContainer].(*ContainerManager)
+	if !ok {
+		return
+	}
+
+	toCreate := make([]Resource, 0, len(diff.ToCreate))
+	for _, resource := range diff.ToCreate {
+		if container, ok := resource.(*ContainerResource); !ok || !containerManager.autoRemovedBefore(container) {
+			toCreate = append(toCreate, resource)
+		}
+	}
+	diff.ToCreate = toCreate
+}
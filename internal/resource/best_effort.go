@@ -0,0 +1,154 @@
+package resource
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// WithBestEffort applies as much of the chart as possible when some resources fail.
+// With image pre-pulling, a container whose image cannot be pulled fails on its own
+// instead of stopping the reconcile. Resources depending on one that failed to be
+// created or updated, directly or through another skipped resource, are skipped rather
+// than attempted, and every other resource is applied as usual. Use the result's
+// Succeeded, Failed and Skipped methods to tell the outcomes apart.
+func WithBestEffort() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.bestEffort = true
+	}
+}
+
+// Succeeded returns the actions that were applied
+func (r *ReconciliationResult) Succeeded() []ResourceAction {
+	return r.actionsMatching(func(action ResourceAction) bool {
+		return action.Error == "" && action.Action != ActionSkip
+	})
+}
+
+// Failed returns the actions that were attempted and failed
+func (r *ReconciliationResult) Failed() []ResourceAction {
+	return r.actionsMatching(func(action ResourceAction) bool {
+		return action.Error != ""
+	})
+}
+
+// Skipped returns the actions that were deliberately not applied: deletions of
+// protected resources, changes to paused resources and, in best-effort mode, changes to
+// resources whose dependencies failed
+func (r *ReconciliationResult) Skipped() []ResourceAction {
+	return r.actionsMatching(func(action ResourceAction) bool {
+		return action.Error == "" && action.Action == ActionSkip
+	})
+}
+
+// actionsMatching returns the created, updated and deleted actions matching keep
+func (r *ReconciliationResult) actionsMatching(keep func(ResourceAction) bool) []ResourceAction {
+	var matching []ResourceAction
+	for _, actions := range [][]ResourceAction{r.CreatedResources, r.UpdatedResources, r.DeletedResources} {
+		for _, action := range actions {
+			if keep(action) {
+				matching = append(matching, action)
+			}
+		}
+	}
+	return matching
+}
+
+// failedDependency returns a dependency of the resource that failed or was skipped
+// earlier in this reconcile. A paused resource that already exists is left as it is,
+// so its dependents are still applied.
+func failedDependency(result *ReconciliationResult, resource Resource) (ResourceReference, bool) {
+	notApplied := make(map[ResourceReference]bool)
+	for _, action := range result.CreatedResources {
+		if action.Error != "" || action.Action == ActionSkip {
+			notApplied[ResourceReference{Type: action.Type, Name: action.Name}] = true
+		}
+	}
+	for _, action := range result.UpdatedResources {
+		ref := ResourceReference{Type: action.Type, Name: action.Name}
+		if action.Error != "" || (action.Action == ActionSkip && !slices.Contains(result.Paused, ref)) {
+			notApplied[ref] = true
+		}
+	}
+
+	for _, dep := range resource.GetDependencies() {
+		if notApplied[dep] {
+			return dep, true
+		}
+	}
+	return ResourceReference{}, false
+}
+
+// skipForFailedDependency records the resource as skipped when best-effort mode is on
+// and one of its dependencies was not applied, reporting whether it was skipped. The
+// skip lands with the created or updated resources, depending on what was planned.
+func (rc *DefaultReconciliationController) skipForFailedDependency(result *ReconciliationResult, resource Resource, planned ActionType) bool {
+	if !rc.bestEffort {
+		return false
+	}
+	dep, failed := failedDependency(result, resource)
+	if !failed {
+		return false
+	}
+
+	action := ResourceAction{
+		Type:      resource.GetType(),
+		Name:      resource.GetName(),
+		Action:    ActionSkip,
+		Message:   fmt.Sprintf("skipped %s: depends on %s '%s', which was not applied", planned, dep.Type, dep.Name),
+		Timestamp: time.Now(),
+	}
+	if planned == ActionUpdate {
+		result.UpdatedResources = append(result.UpdatedResources, action)
+	} else {
+		result.CreatedResources = append(result.CreatedResources, action)
+	}
+	rc.logAction(&action)
+	return true
+}
+
+// dropFailedPulls removes the containers whose image could not be pre-pulled from the
+// diff, recording each as a failed create or update so that its dependents are skipped
+func (rc *DefaultReconciliationController) dropFailedPulls(result *ReconciliationResult, diff *StateDiff, failed map[string]error) {
+	pullFailure := func(resource Resource, planned ActionType) bool {
+		container, ok := resource.(*ContainerResource)
+		if !ok || container.Spec.Build != nil {
+			return false
+		}
+		err, exists := failed[container.Spec.Image]
+		if !exists {
+			return false
+		}
+
+		action := ResourceAction{
+			Type:      container.GetType(),
+			Name:      container.GetName(),
+			Action:    planned,
+			Error:     fmt.Sprintf("unable to pull image %s: %v", container.Spec.Image, err),
+			Timestamp: time.Now(),
+		}
+		if planned == ActionUpdate {
+			result.UpdatedResources = append(result.UpdatedResources, action)
+		} else {
+			result.CreatedResources = append(result.CreatedResources, action)
+		}
+		rc.logAction(&action)
+		return true
+	}
+
+	toCreate := make([]Resource, 0, len(diff.ToCreate))
+	for _, resource := range diff.ToCreate {
+		if !pullFailure(resource, ActionCreate) {
+			toCreate = append(toCreate, resource)
+		}
+	}
+	diff.ToCreate = toCreate
+
+	toUpdate := make([]ResourcePair, 0, len(diff.ToUpdate))
+	for _, pair := range diff.ToUpdate {
+		if !pullFailure(pair.Desired, ActionUpdate) {
+			toUpdate = append(toUpdate, pair)
+		}
+	}
+	diff.ToUpdate = toUpdate
+}
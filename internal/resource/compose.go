@@ -0,0 +1,802 @@
+package resource
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// composeDefaultNetwork is the network Compose attaches services to when they do not
+// list any. Unlike Podman's default network it resolves service names, so it is
+// imported as a network of its own.
+const composeDefaultNetwork = "default"
+
+// composeRestartPolicies maps Compose restart policies to cutepod's. An unquoted "no"
+// reads as a boolean.
+var composeRestartPolicies = map[string]string{
+	"no":             "no",
+	"false":          "no",
+	"always":         "always",
+	"on-failure":     "on-failure",
+	"unless-stopped": "unless-stopped",
+}
+
+// composeFile holds the parts of a Compose file that are imported. Services, networks
+// and volumes are kept as generic maps because Compose accepts several forms for most
+// of their attributes.
+type composeFile struct {
+	Services map[string]map[string]any `yaml:"services"`
+	Networks map[string]map[string]any `yaml:"networks"`
+	Volumes  map[string]map[string]any `yaml:"volumes"`
+}
+
+// composeImporter translates one Compose file, collecting warnings as it goes
+type composeImporter struct {
+	file     composeFile
+	networks map[string]*NetworkResource
+	volumes  map[string]*VolumeResource
+	warnings []string
+}
+
+// ImportCompose translates a Docker Compose file into cutepod resources, as a starting
+// point for moving a Compose deployment under cutepod. Services become containers named
+// after the service, and top-level networks and named volumes become networks and
+// volumes. Bind mounts become hostPath volumes named after the service and mount path.
+// Compose features cutepod has no equivalent for are skipped and reported in the
+// returned warnings; malformed files and undefined references are errors. Resources are
+// ordered like ExportState orders them.
+func ImportCompose(reader io.Reader) ([]Resource, []string, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	var topLevel map[string]any
+	if err := yaml.Unmarshal(content, &topLevel); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+
+	importer := &composeImporter{
+		networks: make(map[string]*NetworkResource),
+		volumes:  make(map[string]*VolumeResource),
+	}
+	if err := yaml.Unmarshal(content, &importer.file); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse compose file: %w", err)
+	}
+	if len(importer.file.Services) == 0 {
+		return nil, nil, fmt.Errorf("compose file defines no services")
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(topLevel)) {
+		switch key {
+		case "services", "networks", "volumes", "version", "name":
+		default:
+			importer.warnf("top-level %s is not supported and was skipped", key)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(importer.file.Networks)) {
+		if err := importer.importNetwork(name, importer.file.Networks[name]); err != nil {
+			return nil, nil, fmt.Errorf("network %s: %w", name, err)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(importer.file.Volumes)) {
+		if err := importer.importVolume(name, importer.file.Volumes[name]); err != nil {
+			return nil, nil, fmt.Errorf("volume %s: %w", name, err)
+		}
+	}
+
+	var containers []Resource
+	for _, name := range slices.Sorted(maps.Keys(importer.file.Services)) {
+		container, err := importer.importService(name, importer.file.Services[name])
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		containers = append(containers, container)
+	}
+
+	resources := make([]Resource, 0, len(importer.networks)+len(importer.volumes)+len(containers))
+	for _, name := range slices.Sorted(maps.Keys(importer.networks)) {
+		resources = append(resources, importer.networks[name])
+	}
+	for _, name := range slices.Sorted(maps.Keys(importer.volumes)) {
+		resources = append(resources, importer.volumes[name])
+	}
+	resources = append(resources, containers...)
+
+	return resources, importer.warnings, nil
+}
+
+// warnf records a warning about a skipped or approximated Compose feature
+func (ci *composeImporter) warnf(format string, args ...any) {
+	ci.warnings = append(ci.warnings, fmt.Sprintf(format, args...))
+}
+
+// importNetwork translates a top-level network. External networks are not managed by
+// the chart, so they are left out.
+func (ci *composeImporter) importNetwork(name string, attrs map[string]any) error {
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = name
+
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		value := attrs[key]
+		switch key {
+		case "driver":
+			network.Spec.Driver = composeScalar(value)
+		case "driver_opts":
+			options, err := composeMapping(value)
+			if err != nil {
+				return fmt.Errorf("driver_opts: %w", err)
+			}
+			network.Spec.Options = options
+		case "internal":
+			network.Spec.Internal = composeScalar(value) == "true"
+		case "labels":
+			networkLabels, err := composeMapping(value)
+			if err != nil {
+				return fmt.Errorf("labels: %w", err)
+			}
+			network.SetLabels(networkLabels)
+		case "ipam":
+			if err := ci.importIPAM(name, network, value); err != nil {
+				return fmt.Errorf("ipam: %w", err)
+			}
+		case "external":
+			if composeScalar(value) == "true" {
+				ci.warnf("network %s is external and was not imported; it must exist before the chart is installed", name)
+				return nil
+			}
+		default:
+			ci.warnf("network %s: %s is not supported and was skipped", name, key)
+		}
+	}
+
+	ci.networks[name] = network
+	return nil
+}
+
+// importIPAM takes the subnet and gateway of the network's first IPAM pool
+func (ci *composeImporter) importIPAM(name string, network *NetworkResource, value any) error {
+	ipam, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected a mapping, got %T", value)
+	}
+
+	pools, _ := ipam["config"].([]any)
+	if len(pools) == 0 {
+		return nil
+	}
+	if len(pools) > 1 {
+		ci.warnf("network %s: only the first IPAM pool was imported", name)
+	}
+
+	pool, ok := pools[0].(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected config entries to be mappings, got %T", pools[0])
+	}
+	network.Spec.Subnet = composeScalar(pool["subnet"])
+	network.Spec.Gateway = composeScalar(pool["gateway"])
+	return nil
+}
+
+// importVolume translates a top-level named volume. External volumes are not managed by
+// the chart, so they are left out.
+func (ci *composeImporter) importVolume(name string, attrs map[string]any) error {
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = name
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{}
+
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		value := attrs[key]
+		switch key {
+		case "driver":
+			volume.Spec.Volume.Driver = composeScalar(value)
+		case "driver_opts":
+			options, err := composeMapping(value)
+			if err != nil {
+				return fmt.Errorf("driver_opts: %w", err)
+			}
+			volume.Spec.Volume.Options = options
+		case "labels":
+			volumeLabels, err := composeMapping(value)
+			if err != nil {
+				return fmt.Errorf("labels: %w", err)
+			}
+			volume.SetLabels(volumeLabels)
+		case "external":
+			if composeScalar(value) == "true" {
+				ci.warnf("volume %s is external and was not imported; it must exist before the chart is installed", name)
+				return nil
+			}
+		default:
+			ci.warnf("volume %s: %s is not supported and was skipped", name, key)
+		}
+	}
+
+	ci.volumes[name] = volume
+	return nil
+}
+
+// importService translates a service into a container named after it
+func (ci *composeImporter) importService(name string, attrs map[string]any) (*ContainerResource, error) {
+	container := NewContainerResource()
+	container.ObjectMeta.Name = name
+
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		value := attrs[key]
+		var err error
+
+		switch key {
+		case "image":
+			container.Spec.Image = composeScalar(value)
+		case "build":
+			err = ci.importBuild(container, value)
+		case "command":
+			container.Spec.Command, err = composeCommand(value)
+		case "environment":
+			err = ci.importEnvironment(container, value)
+		case "env_file":
+			var envFiles []string
+			envFiles, err = composeList(value)
+			if len(envFiles) > 0 {
+				container.Spec.EnvFile = envFiles[0]
+			}
+			if len(envFiles) > 1 {
+				ci.warnf("service %s: only the first env_file was imported", name)
+			}
+		case "working_dir":
+			container.Spec.WorkingDir = composeScalar(value)
+		case "user":
+			container.Spec.RunAsUser = composeScalar(value)
+		case "group_add":
+			container.Spec.GroupAdd, err = composeList(value)
+		case "labels":
+			var serviceLabels map[string]string
+			serviceLabels, err = composeMapping(value)
+			container.SetLabels(serviceLabels)
+		case "ports":
+			err = ci.importPorts(container, value)
+		case "volumes":
+			err = ci.importMounts(container, value)
+		case "networks":
+			err = ci.importAttachments(container, value)
+		case "depends_on":
+			err = ci.importDependsOn(container, value)
+		case "restart":
+			ci.importRestart(container, composeScalar(value))
+		case "cap_add", "cap_drop":
+			var capabilities []string
+			capabilities, err = composeList(value)
+			if container.Spec.SecurityContext == nil {
+				container.Spec.SecurityContext = &SecurityContext{}
+			}
+			if container.Spec.SecurityContext.Capabilities == nil {
+				container.Spec.SecurityContext.Capabilities = &Capabilities{}
+			}
+			if key == "cap_add" {
+				container.Spec.SecurityContext.Capabilities.Add = capabilities
+			} else {
+				container.Spec.SecurityContext.Capabilities.Drop = capabilities
+			}
+		case "privileged":
+			privileged := composeScalar(value) == "true"
+			if container.Spec.SecurityContext == nil {
+				container.Spec.SecurityContext = &SecurityContext{}
+			}
+			container.Spec.SecurityContext.Privileged = &privileged
+		case "sysctls":
+			container.Spec.Sysctl, err = composeMapping(value)
+		case "hostname":
+			container.Spec.Hostname = composeScalar(value)
+		case "domainname":
+			container.Spec.DomainName = composeScalar(value)
+		case "healthcheck":
+			err = ci.importHealthcheck(container, value)
+		case "container_name":
+			if composeScalar(value) != name {
+				ci.warnf("service %s: container_name is not supported; the container is named after the service", name)
+			}
+		case "expose":
+			// Containers sharing a network reach each other's ports without exposing them
+		default:
+			ci.warnf("service %s: %s is not supported and was skipped", name, key)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	if container.Spec.Image == "" {
+		if container.Spec.Build == nil {
+			return nil, fmt.Errorf("service has neither an image nor a build")
+		}
+		container.Spec.Image = "localhost/" + name + ":latest"
+	}
+
+	// Compose attaches services that list no networks to its default network
+	if len(container.Spec.Networks) == 0 {
+		if err := ci.attach(container, NetworkAttachment{Name: composeDefaultNetwork}); err != nil {
+			return nil, err
+		}
+	}
+
+	return container, nil
+}
+
+// importBuild translates the short and long forms of a service's build section
+func (ci *composeImporter) importBuild(container *ContainerResource, value any) error {
+	build := &BuildSpec{}
+
+	switch value := value.(type) {
+	case string:
+		build.Context = value
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			switch key {
+			case "context":
+				build.Context = composeScalar(value[key])
+			case "dockerfile":
+				build.Containerfile = composeScalar(value[key])
+			case "args":
+				args, err := composeMapping(value[key])
+				if err != nil {
+					return fmt.Errorf("args: %w", err)
+				}
+				build.Args = args
+			default:
+				ci.warnf("service %s: build.%s is not supported and was skipped", container.GetName(), key)
+			}
+		}
+	default:
+		return fmt.Errorf("expected a path or a mapping, got %T", value)
+	}
+
+	if build.Context == "" {
+		build.Context = "."
+	}
+	if !filepath.IsAbs(build.Context) {
+		ci.warnf("service %s: build context %s is relative to the compose file and must be made absolute", container.GetName(), build.Context)
+	}
+
+	container.Spec.Build = build
+	return nil
+}
+
+// importEnvironment translates a service's environment. Variables without a value take
+// it from the shell running Compose, which cutepod has no equivalent for.
+func (ci *composeImporter) importEnvironment(container *ContainerResource, value any) error {
+	variables := make(map[string]*string)
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, raw := range value {
+			if raw != nil {
+				variable := composeScalar(raw)
+				variables[key] = &variable
+			} else {
+				variables[key] = nil
+			}
+		}
+	case []any:
+		for _, entry := range value {
+			key, variable, found := strings.Cut(composeScalar(entry), "=")
+			if found {
+				variables[key] = &variable
+			} else {
+				variables[key] = nil
+			}
+		}
+	default:
+		return fmt.Errorf("expected a mapping or a list, got %T", value)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(variables)) {
+		if variables[key] == nil {
+			ci.warnf("service %s: environment variable %s takes its value from the host and was skipped", container.GetName(), key)
+			continue
+		}
+		container.Spec.Env = append(container.Spec.Env, EnvVar{Name: key, Value: *variables[key]})
+	}
+	return nil
+}
+
+// importPorts translates the short ("[host_ip:][host:]container[/protocol]") and long
+// forms of a service's published ports
+func (ci *composeImporter) importPorts(container *ContainerResource, value any) error {
+	entries, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("expected a list, got %T", value)
+	}
+
+	for _, entry := range entries {
+		var target, published, protocol, hostIP string
+
+		if long, ok := entry.(map[string]any); ok {
+			target = composeScalar(long["target"])
+			published = composeScalar(long["published"])
+			protocol = composeScalar(long["protocol"])
+			hostIP = composeScalar(long["host_ip"])
+		} else {
+			spec := composeScalar(entry)
+			spec, protocol, _ = strings.Cut(spec, "/")
+			parts := strings.Split(spec, ":")
+			target = parts[len(parts)-1]
+			if len(parts) > 1 {
+				published = parts[len(parts)-2]
+			}
+			if len(parts) > 2 {
+				hostIP = strings.Join(parts[:len(parts)-2], ":")
+			}
+		}
+
+		if strings.Contains(target, "-") || strings.Contains(published, "-") {
+			ci.warnf("service %s: port range %v is not supported and was skipped", container.GetName(), entry)
+			continue
+		}
+		if hostIP != "" {
+			ci.warnf("service %s: host IP %s of port %s is not supported; the port is published on all addresses", container.GetName(), hostIP, target)
+		}
+
+		port := ContainerPort{Protocol: strings.ToUpper(protocol)}
+		containerPort, err := strconv.ParseUint(target, 10, 16)
+		if err != nil {
+			return fmt.Errorf("invalid container port %q", target)
+		}
+		port.ContainerPort = uint16(containerPort)
+		if published != "" {
+			hostPort, err := strconv.ParseUint(published, 10, 16)
+			if err != nil {
+				return fmt.Errorf("invalid published port %q", published)
+			}
+			port.HostPort = uint16(hostPort)
+		}
+		if port.Protocol == "TCP" {
+			port.Protocol = ""
+		}
+
+		container.Spec.Ports = append(container.Spec.Ports, port)
+	}
+	return nil
+}
+
+// importMounts translates the short ("source:target[:mode]") and long forms of a
+// service's volumes. Sources that look like paths are bind mounts.
+func (ci *composeImporter) importMounts(container *ContainerResource, value any) error {
+	entries, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("expected a list, got %T", value)
+	}
+
+	for _, entry := range entries {
+		var mountType, source, target, mode string
+
+		if long, ok := entry.(map[string]any); ok {
+			mountType = composeScalar(long["type"])
+			source = composeScalar(long["source"])
+			target = composeScalar(long["target"])
+			if composeScalar(long["read_only"]) == "true" {
+				mode = "ro"
+			}
+		} else {
+			parts := strings.SplitN(composeScalar(entry), ":", 3)
+			if len(parts) == 1 {
+				target = parts[0]
+			} else {
+				source, target = parts[0], parts[1]
+			}
+			if len(parts) == 3 {
+				mode = parts[2]
+			}
+			if source != "" {
+				mountType = "volume"
+				if strings.HasPrefix(source, "/") || strings.HasPrefix(source, ".") || strings.HasPrefix(source, "~") {
+					mountType = "bind"
+				}
+			}
+		}
+
+		if target == "" {
+			return fmt.Errorf("mount %v has no target", entry)
+		}
+
+		mount := VolumeMount{MountPath: target}
+		switch {
+		case source == "" || (mountType != "volume" && mountType != "bind"):
+			ci.warnf("service %s: mount of %s is not a named volume or bind mount and was skipped", container.GetName(), target)
+			continue
+		case mountType == "volume":
+			if _, declared := ci.file.Volumes[source]; !declared {
+				return fmt.Errorf("undefined volume %s", source)
+			}
+			mount.Name = source
+		default:
+			mount.Name = ci.bindVolume(container.GetName(), source, target)
+		}
+
+		for _, option := range strings.Split(mode, ",") {
+			switch option {
+			case "", "rw":
+			case "ro":
+				mount.ReadOnly = true
+			case "z", "Z":
+				mount.MountOptions = &VolumeMountOptions{SELinuxLabel: option}
+			default:
+				ci.warnf("service %s: mount option %s of %s is not supported and was skipped", container.GetName(), option, target)
+			}
+		}
+
+		container.Spec.Volumes = append(container.Spec.Volumes, mount)
+	}
+	return nil
+}
+
+// bindVolume creates a hostPath volume for a bind mount and returns its name, which is
+// made unique among the imported volumes
+func (ci *composeImporter) bindVolume(serviceName, source, target string) string {
+	if !filepath.IsAbs(source) {
+		ci.warnf("service %s: bind mount source %s is relative to the compose file and must be made absolute", serviceName, source)
+	}
+
+	base := serviceName
+	if targetBase := strings.Trim(strings.ReplaceAll(path.Clean(target), "/", "-"), "-"); targetBase != "" {
+		base += "-" + targetBase
+	}
+
+	name := base
+	for suffix := 2; ci.volumes[name] != nil; suffix++ {
+		name = fmt.Sprintf("%s-%d", base, suffix)
+	}
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = name
+	volume.Spec.Type = VolumeTypeHostPath
+	volume.Spec.HostPath = &HostPathVolumeSource{Path: source}
+	ci.volumes[name] = volume
+
+	return name
+}
+
+// importAttachments translates the list and mapping forms of a service's networks
+func (ci *composeImporter) importAttachments(container *ContainerResource, value any) error {
+	switch value := value.(type) {
+	case []any:
+		for _, entry := range value {
+			if err := ci.attach(container, NetworkAttachment{Name: composeScalar(entry)}); err != nil {
+				return err
+			}
+		}
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			attachment := NetworkAttachment{Name: name}
+			if attrs, ok := value[name].(map[string]any); ok {
+				for _, key := range slices.Sorted(maps.Keys(attrs)) {
+					switch key {
+					case "aliases":
+						aliases, err := composeList(attrs[key])
+						if err != nil {
+							return fmt.Errorf("aliases: %w", err)
+						}
+						attachment.Aliases = aliases
+					case "ipv4_address":
+						attachment.StaticIP = composeScalar(attrs[key])
+					case "mac_address":
+						attachment.StaticMAC = composeScalar(attrs[key])
+					default:
+						ci.warnf("service %s: network %s option %s is not supported and was skipped", container.GetName(), name, key)
+					}
+				}
+			}
+			if err := ci.attach(container, attachment); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("expected a list or a mapping, got %T", value)
+	}
+	return nil
+}
+
+// attach attaches the container to a network declared by the Compose file, creating the
+// default network on first use
+func (ci *composeImporter) attach(container *ContainerResource, attachment NetworkAttachment) error {
+	if _, declared := ci.file.Networks[attachment.Name]; !declared {
+		if attachment.Name != composeDefaultNetwork {
+			return fmt.Errorf("undefined network %s", attachment.Name)
+		}
+		if ci.networks[composeDefaultNetwork] == nil {
+			network := NewNetworkResource()
+			network.ObjectMeta.Name = composeDefaultNetwork
+			ci.networks[composeDefaultNetwork] = network
+		}
+	} else if ci.networks[attachment.Name] == nil {
+		// Declared but not imported, so the network is external
+		attachment.External = true
+	}
+
+	container.Spec.Networks = append(container.Spec.Networks, attachment)
+	return nil
+}
+
+// importDependsOn translates the list and mapping forms of depends_on. cutepod orders
+// creation but does not wait for the conditions of the long form.
+func (ci *composeImporter) importDependsOn(container *ContainerResource, value any) error {
+	var dependencies []string
+
+	switch value := value.(type) {
+	case []any:
+		for _, entry := range value {
+			dependencies = append(dependencies, composeScalar(entry))
+		}
+	case map[string]any:
+		for _, name := range slices.Sorted(maps.Keys(value)) {
+			dependencies = append(dependencies, name)
+			if attrs, ok := value[name].(map[string]any); ok {
+				if condition := composeScalar(attrs["condition"]); condition != "" && condition != "service_started" {
+					ci.warnf("service %s: depends_on condition %s on %s is not supported; %s is only created after it", container.GetName(), condition, name, container.GetName())
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("expected a list or a mapping, got %T", value)
+	}
+
+	for _, dependency := range dependencies {
+		if _, defined := ci.file.Services[dependency]; !defined {
+			return fmt.Errorf("undefined service %s", dependency)
+		}
+	}
+	container.Spec.DependsOn = dependencies
+	return nil
+}
+
+// importRestart translates a restart policy. The retry limit of "on-failure:N" is
+// dropped since cutepod does not support it.
+func (ci *composeImporter) importRestart(container *ContainerResource, policy string) {
+	policy, limit, _ := strings.Cut(policy, ":")
+	restartPolicy, supported := composeRestartPolicies[policy]
+	if !supported {
+		ci.warnf("service %s: restart policy %s is not supported and was skipped", container.GetName(), policy)
+		return
+	}
+	if limit != "" {
+		ci.warnf("service %s: the retry limit of restart policy %s:%s was dropped", container.GetName(), policy, limit)
+	}
+	container.Spec.RestartPolicy = restartPolicy
+}
+
+// importHealthcheck translates a service's healthcheck into an exec health check
+func (ci *composeImporter) importHealthcheck(container *ContainerResource, value any) error {
+	attrs, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected a mapping, got %T", value)
+	}
+	if composeScalar(attrs["disable"]) == "true" {
+		return nil
+	}
+
+	var command []string
+	switch test := attrs["test"].(type) {
+	case string:
+		command = []string{"/bin/sh", "-c", test}
+	case []any:
+		if len(test) == 0 {
+			return fmt.Errorf("test must not be empty")
+		}
+		args := make([]string, 0, len(test)-1)
+		for _, arg := range test[1:] {
+			args = append(args, composeScalar(arg))
+		}
+		switch composeScalar(test[0]) {
+		case "NONE":
+			return nil
+		case "CMD":
+			command = args
+		case "CMD-SHELL":
+			command = append([]string{"/bin/sh", "-c"}, strings.Join(args, " "))
+		default:
+			return fmt.Errorf("test must start with NONE, CMD or CMD-SHELL")
+		}
+	default:
+		return fmt.Errorf("test must be a string or a list, got %T", attrs["test"])
+	}
+
+	health := &HealthCheck{Type: "exec", Command: command}
+	for _, key := range slices.Sorted(maps.Keys(attrs)) {
+		var err error
+		switch key {
+		case "test", "disable":
+		case "interval":
+			health.IntervalSeconds, err = composeSeconds(attrs[key])
+		case "timeout":
+			health.TimeoutSeconds, err = composeSeconds(attrs[key])
+		case "start_period":
+			health.StartPeriodSeconds, err = composeSeconds(attrs[key])
+		case "retries":
+			var retries int64
+			retries, err = strconv.ParseInt(composeScalar(attrs[key]), 10, 32)
+			health.Retries = int32(retries)
+		default:
+			ci.warnf("service %s: healthcheck %s is not supported and was skipped", container.GetName(), key)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	container.Spec.Health = health
+	return nil
+}
+
+// composeScalar renders a scalar value as Compose would interpolate it into a string.
+// nil becomes the empty string.
+func composeScalar(value any) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprint(value)
+}
+
+// composeList reads a single string or a list of scalars
+func composeList(value any) ([]string, error) {
+	switch value := value.(type) {
+	case string:
+		return []string{value}, nil
+	case []any:
+		list := make([]string, 0, len(value))
+		for _, entry := range value {
+			list = append(list, composeScalar(entry))
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("expected a string or a list, got %T", value)
+	}
+}
+
+// composeMapping reads a mapping or a list of "key=value" entries
+func composeMapping(value any) (map[string]string, error) {
+	mapping := make(map[string]string)
+
+	switch value := value.(type) {
+	case map[string]any:
+		for key, raw := range value {
+			mapping[key] = composeScalar(raw)
+		}
+	case []any:
+		for _, entry := range value {
+			key, raw, _ := strings.Cut(composeScalar(entry), "=")
+			mapping[key] = raw
+		}
+	default:
+		return nil, fmt.Errorf("expected a mapping or a list, got %T", value)
+	}
+
+	return mapping, nil
+}
+
+// composeCommand reads a command given as a list or as a string, which is split the
+// way a shell would
+func composeCommand(value any) ([]string, error) {
+	if command, ok := value.(string); ok {
+		return splitCommandString(command)
+	}
+	return composeList(value)
+}
+
+// composeSeconds reads a Compose duration such as "1m30s" as whole seconds
+func composeSeconds(value any) (int32, error) {
+	duration, err := time.ParseDuration(composeScalar(value))
+	if err != nil {
+		return 0, err
+	}
+	return int32(duration.Round(time.Second) / time.Second), nil
+}
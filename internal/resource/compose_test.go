@@ -0,0 +1,198 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testComposeFile = `
+version: "3.8"
+services:
+  web:
+    image: nginx:1.25
+    ports:
+      - "8080:80"
+      - "127.0.0.1:8443:443/udp"
+      - target: 9000
+        published: 9001
+    environment:
+      MODE: production
+      FROM_HOST:
+    volumes:
+      - /srv/site:/usr/share/nginx/html:ro,Z
+      - cache:/var/cache/nginx
+    depends_on:
+      db:
+        condition: service_healthy
+    networks:
+      front:
+        aliases: [www]
+    restart: on-failure:3
+    logging:
+      driver: json-file
+  db:
+    image: postgres:16
+    environment:
+      - POSTGRES_DB=app
+    command: postgres -c fsync=off
+    user: "999:999"
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready"]
+      interval: 10s
+      retries: 5
+volumes:
+  cache:
+    driver: local
+networks:
+  front:
+    ipam:
+      config:
+        - subnet: 10.89.0.0/24
+secrets:
+  token:
+    file: ./token
+`
+
+func TestImportCompose(t *testing.T) {
+	resources, warnings, err := ImportCompose(strings.NewReader(testComposeFile))
+	if err != nil {
+		t.Fatalf("ImportCompose failed: %v", err)
+	}
+
+	var names []string
+	for _, resource := range resources {
+		names = append(names, string(resource.GetType())+"/"+resource.GetName())
+	}
+	expected := []string{
+		"network/default", "network/front",
+		"volume/cache", "volume/web-usr-share-nginx-html",
+		"container/db", "container/web",
+	}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("Expected resources %v, got %v", expected, names)
+	}
+
+	front := resources[1].(*NetworkResource)
+	if front.Spec.Subnet != "10.89.0.0/24" {
+		t.Errorf("Expected the IPAM subnet to be imported, got %q", front.Spec.Subnet)
+	}
+
+	bind := resources[3].(*VolumeResource)
+	if bind.Spec.Type != VolumeTypeHostPath || bind.Spec.HostPath.Path != "/srv/site" {
+		t.Errorf("Expected the bind mount to become a hostPath volume, got %+v", bind.Spec)
+	}
+
+	db := resources[4].(*ContainerResource)
+	if !slices.Equal(db.Spec.Command, []string{"postgres", "-c", "fsync=off"}) {
+		t.Errorf("Expected the command to be split, got %v", db.Spec.Command)
+	}
+	if db.Spec.RunAsUser != "999:999" {
+		t.Errorf("Expected the user to be passed through, got %q", db.Spec.RunAsUser)
+	}
+	if len(db.Spec.Networks) != 1 || db.Spec.Networks[0].Name != composeDefaultNetwork {
+		t.Errorf("Expected db to join the default network, got %+v", db.Spec.Networks)
+	}
+	if db.Spec.Health == nil || !slices.Equal(db.Spec.Health.Command, []string{"/bin/sh", "-c", "pg_isready"}) ||
+		db.Spec.Health.IntervalSeconds != 10 || db.Spec.Health.Retries != 5 {
+		t.Errorf("Expected the healthcheck to be imported, got %+v", db.Spec.Health)
+	}
+
+	web := resources[5].(*ContainerResource)
+	expectedPorts := []ContainerPort{
+		{ContainerPort: 80, HostPort: 8080},
+		{ContainerPort: 443, HostPort: 8443, Protocol: "UDP"},
+		{ContainerPort: 9000, HostPort: 9001},
+	}
+	if !slices.Equal(web.Spec.Ports, expectedPorts) {
+		t.Errorf("Expected ports %+v, got %+v", expectedPorts, web.Spec.Ports)
+	}
+	if len(web.Spec.Env) != 1 || web.Spec.Env[0] != (EnvVar{Name: "MODE", Value: "production"}) {
+		t.Errorf("Expected only MODE to be imported, got %+v", web.Spec.Env)
+	}
+	if len(web.Spec.Volumes) != 2 || !web.Spec.Volumes[0].ReadOnly || web.Spec.Volumes[0].MountOptions.SELinuxLabel != "Z" ||
+		web.Spec.Volumes[1].Name != "cache" {
+		t.Errorf("Expected the bind mount and named volume, got %+v", web.Spec.Volumes)
+	}
+	if !slices.Equal(web.Spec.DependsOn, []string{"db"}) {
+		t.Errorf("Expected web to depend on db, got %v", web.Spec.DependsOn)
+	}
+	if len(web.Spec.Networks) != 1 || !slices.Equal(web.Spec.Networks[0].Aliases, []string{"www"}) {
+		t.Errorf("Expected web to join front with its alias, got %+v", web.Spec.Networks)
+	}
+	if web.Spec.RestartPolicy != "on-failure" {
+		t.Errorf("Expected restart policy on-failure, got %q", web.Spec.RestartPolicy)
+	}
+
+	for _, fragment := range []string{
+		"top-level secrets",
+		"host IP 127.0.0.1",
+		"FROM_HOST takes its value from the host",
+		"condition service_healthy",
+		"retry limit",
+		"web: logging is not supported",
+	} {
+		if !slices.ContainsFunc(warnings, func(warning string) bool { return strings.Contains(warning, fragment) }) {
+			t.Errorf("Expected a warning mentioning %q, got %v", fragment, warnings)
+		}
+	}
+}
+
+func TestImportCompose_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		compose  string
+		contains string
+	}{
+		{name: "no services", compose: "version: '3'\n", contains: "defines no services"},
+		{name: "no image", compose: "services:\n  web:\n    ports: ['80']\n", contains: "neither an image nor a build"},
+		{name: "undefined volume", compose: "services:\n  web:\n    image: nginx\n    volumes: ['data:/data']\n", contains: "undefined volume data"},
+		{name: "undefined network", compose: "services:\n  web:\n    image: nginx\n    networks: [back]\n", contains: "undefined network back"},
+		{name: "undefined dependency", compose: "services:\n  web:\n    image: nginx\n    depends_on: [db]\n", contains: "undefined service db"},
+		{name: "invalid port", compose: "services:\n  web:\n    image: nginx\n    ports: ['http:80']\n", contains: "invalid published port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ImportCompose(strings.NewReader(tt.compose))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected an error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
+
+func TestImportCompose_DependsOnOrdersCreation(t *testing.T) {
+	compose := `
+services:
+  web:
+    image: nginx:1.25
+    depends_on: [db]
+  db:
+    image: postgres:16
+`
+	resources, _, err := ImportCompose(strings.NewReader(compose))
+	if err != nil {
+		t.Fatalf("ImportCompose failed: %v", err)
+	}
+
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	result, err := controller.Reconcile(context.Background(), resources, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	var created []string
+	for _, ref := range result.CreatedResources {
+		created = append(created, ref.Name)
+	}
+	if slices.Index(created, "db") > slices.Index(created, "web") {
+		t.Errorf("Expected db to be created before web, got %v", created)
+	}
+}
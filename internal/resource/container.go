@@ -1,13 +1,28 @@
 package resource
 
 import (
+	"cutepod/internal/labels"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/goccy/go-yaml"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
+// settableAnnotations are the cutepod.io/ annotations a container manifest may set
+var settableAnnotations = []string{
+	labels.AnnotationForceRecreate,
+	labels.AnnotationRestartNonce,
+	labels.AnnotationReconcile,
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:scope=Namespaced,shortName=cc
 // +kubebuilder:subresource:status
@@ -26,24 +41,46 @@ type ContainerResource struct {
 type CuteContainerSpec struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
-	Image           string                `json:"image"`
-	Command         []string              `json:"command,omitempty"`
-	Args            []string              `json:"args,omitempty"`
-	Env             []EnvVar              `json:"env,omitempty"`
-	EnvFile         string                `json:"envFile,omitempty"`
-	WorkingDir      string                `json:"workingDir,omitempty"`
-	UID             *int64                `json:"uid,omitempty"`
-	GID             *int64                `json:"gid,omitempty"`
-	Pod             string                `json:"pod,omitempty"`
-	Ports           []ContainerPort       `json:"ports,omitempty"`
-	Volumes         []VolumeMount         `json:"volumes,omitempty"`
-	Networks        []string              `json:"networks,omitempty"`
-	Secrets         []SecretReference     `json:"secrets,omitempty"`
-	Sysctl          map[string]string     `json:"sysctl,omitempty"`
-	Health          *HealthCheck          `json:"health,omitempty"`
-	SecurityContext *SecurityContext      `json:"securityContext,omitempty"`
-	Resources       *ResourceRequirements `json:"resources,omitempty"`
-	RestartPolicy   string                `json:"restartPolicy,omitempty"`
+	Image              string                `json:"image"`
+	Build              *BuildSpec            `json:"build,omitempty"`
+	Command            []string              `json:"command,omitempty"`
+	CommandString      string                `json:"commandString,omitempty"` // Shell-style command line, split into the command; excludes command
+	Args               []string              `json:"args,omitempty"`
+	Env                []EnvVar              `json:"env,omitempty"`
+	EnvFrom            []EnvFromSource       `json:"envFrom,omitempty"` // Sources whose keys all become environment variables
+	EnvFile            string                `json:"envFile,omitempty"`
+	WorkingDir         string                `json:"workingDir,omitempty"`
+	UID                *int64                `json:"uid,omitempty"`
+	GID                *int64                `json:"gid,omitempty"`
+	RunAsUser          string                `json:"runAsUser,omitempty"`  // User name or ID, optionally "user:group"; preferred over uid
+	RunAsGroup         string                `json:"runAsGroup,omitempty"` // Group name or ID; preferred over gid
+	GroupAdd           []string              `json:"groupAdd,omitempty"`   // Supplementary groups, by name or ID
+	Pod                string                `json:"pod,omitempty"`
+	IpcMode            string                `json:"ipcMode,omitempty"`            // IPC namespace: host, private, shareable, none, pod or container:<name>
+	PidMode            string                `json:"pidMode,omitempty"`            // PID namespace: host, private, pod or container:<name>
+	UtsMode            string                `json:"utsMode,omitempty"`            // UTS namespace: host, private, pod or container:<name>
+	Hostname           string                `json:"hostname,omitempty"`           // Defaults to the container name when it has a UTS namespace of its own
+	DomainName         string                `json:"domainName,omitempty"`         // NIS domain name, set through the kernel.domainname sysctl
+	NetworkMode        string                `json:"networkMode,omitempty"`        // Network namespace instead of networks: host, none or container:<name>
+	ExternalNamespaces bool                  `json:"externalNamespaces,omitempty"` // container:<name> modes may name containers cutepod does not manage
+	DependsOn          []string              `json:"dependsOn,omitempty"`          // Containers to create before this one
+	Priority           int                   `json:"priority,omitempty"`           // Creation order within a dependency level, highest first
+	Ports              []ContainerPort       `json:"ports,omitempty"`
+	Volumes            []VolumeMount         `json:"volumes,omitempty"`
+	Networks           []NetworkAttachment   `json:"networks,omitempty"`
+	Secrets            []SecretReference     `json:"secrets,omitempty"`
+	Sysctl             map[string]string     `json:"sysctl,omitempty"`
+	Health             *HealthCheck          `json:"health,omitempty"`
+	ReadinessProbe     *Probe                `json:"readinessProbe,omitempty"`
+	Lifecycle          *LifecycleSpec        `json:"lifecycle,omitempty"`
+	SecurityContext    *SecurityContext      `json:"securityContext,omitempty"`
+	Resources          *ResourceRequirements `json:"resources,omitempty"`
+	RestartPolicy      string                `json:"restartPolicy,omitempty"`
+	Init               *bool                 `json:"init,omitempty"`       // Run an init process as PID 1 that reaps zombie processes
+	AutoRemove         *bool                 `json:"autoRemove,omitempty"` // Remove the container once it exits, for one-off jobs
+	Stdin              *bool                 `json:"stdin,omitempty"`      // Keep stdin open, for interactive tooling containers
+	StdinOnce          *bool                 `json:"stdinOnce,omitempty"`  // Close stdin after the first attach; Podman does not apply it
+	TTY                *bool                 `json:"tty,omitempty"`        // Allocate a pseudo-terminal
 }
 
 type EnvVar struct {
@@ -73,9 +110,18 @@ type VolumeMount struct {
 
 // VolumeMountOptions defines Podman-specific mount options
 type VolumeMountOptions struct {
-	SELinuxLabel string         `json:"seLinuxLabel,omitempty"` // "z", "Z", or custom SELinux label
-	UIDMapping   *UIDGIDMapping `json:"uidMapping,omitempty"`   // UID mapping for rootless Podman
-	GIDMapping   *UIDGIDMapping `json:"gidMapping,omitempty"`   // GID mapping for rootless Podman
+	SELinuxLabel   string         `json:"seLinuxLabel,omitempty"`   // "z", "Z", or custom SELinux label
+	SELinuxContext string         `json:"seLinuxContext,omitempty"` // Full context such as system_u:object_r:container_file_t:s0, overriding seLinuxLabel
+	UIDMapping     *UIDGIDMapping `json:"uidMapping,omitempty"`     // UID mapping for rootless Podman
+	GIDMapping     *UIDGIDMapping `json:"gidMapping,omitempty"`     // GID mapping for rootless Podman
+	// +kubebuilder:validation:Enum=private;rprivate;rshared;rslave
+	Propagation       string `json:"propagation,omitempty"`       // Mount propagation mode
+	RecursiveReadOnly bool   `json:"recursiveReadOnly,omitempty"` // Make submounts read-only too (requires readOnly)
+}
+
+// validMountPropagations lists the supported mount propagation modes
+var validMountPropagations = map[string]bool{
+	"private": true, "rprivate": true, "rshared": true, "rslave": true,
 }
 
 // UIDGIDMapping defines user/group ID mapping for rootless containers
@@ -85,10 +131,92 @@ type UIDGIDMapping struct {
 	Size        int64 `json:"size"`        // Range size for the mapping
 }
 
+// BuildSpec builds the container image from a local Containerfile instead of pulling it.
+// The built image is tagged with the container's image.
+type BuildSpec struct {
+	// +kubebuilder:validation:Required
+	Context       string            `json:"context"`                 // Absolute path of the build context directory
+	Containerfile string            `json:"containerfile,omitempty"` // Relative to the context, defaults to Containerfile
+	Args          map[string]string `json:"args,omitempty"`
+}
+
+// ContainerfilePath returns the path of the Containerfile to build from
+func (b *BuildSpec) ContainerfilePath() string {
+	if b.Containerfile == "" {
+		return filepath.Join(b.Context, "Containerfile")
+	}
+	if filepath.IsAbs(b.Containerfile) {
+		return b.Containerfile
+	}
+	return filepath.Join(b.Context, b.Containerfile)
+}
+
+// NetworkAttachment attaches a container to a network. Manifests may also list a bare
+// network name, which is shorthand for an attachment with only Name set, so the schema
+// leaves the type open and only checks the fields of the object form.
+// +kubebuilder:validation:Type=""
+// +kubebuilder:pruning:PreserveUnknownFields
+type NetworkAttachment struct {
+	Name      string   `json:"name"`                // Network name reference (required)
+	StaticIP  string   `json:"staticIP,omitempty"`  // Fixed IP address within the network
+	StaticMAC string   `json:"staticMAC,omitempty"` // Fixed MAC address of the interface
+	Aliases   []string `json:"aliases,omitempty"`   // Additional DNS names on the network
+	External  bool     `json:"external,omitempty"`  // Network is not managed by cutepod and must already exist
+}
+
+// UnmarshalYAML accepts either a bare network name or a full attachment
+func (n *NetworkAttachment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err == nil {
+		*n = NetworkAttachment{Name: name}
+		return nil
+	}
+
+	type rawNetworkAttachment NetworkAttachment
+	var raw rawNetworkAttachment
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+	*n = NetworkAttachment(raw)
+	return nil
+}
+
+// UnmarshalJSON accepts either a bare network name or a full attachment
+func (n *NetworkAttachment) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*n = NetworkAttachment{Name: name}
+		return nil
+	}
+
+	type rawNetworkAttachment NetworkAttachment
+	var raw rawNetworkAttachment
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*n = NetworkAttachment(raw)
+	return nil
+}
+
+// EnvFromSource exposes every key of a secret as an environment variable
+type EnvFromSource struct {
+	SecretName string `json:"secretName"`
+	Prefix     string `json:"prefix,omitempty"` // Prepended to each key
+}
+
 type SecretReference struct {
-	Name string `json:"name"`           // Secret name reference
-	Env  bool   `json:"env,omitempty"`  // Mount as environment variables
-	Path string `json:"path,omitempty"` // Mount as file (optional)
+	Name   string       `json:"name"`             // Secret name reference
+	Env    bool         `json:"env,omitempty"`    // Mount as environment variables
+	Prefix string       `json:"prefix,omitempty"` // Prepended to each key exposed as an environment variable
+	Path   string       `json:"path,omitempty"`   // Mount as file (optional)
+	Items  []SecretItem `json:"items,omitempty"`  // Mount individual keys below Path instead of the whole secret
+}
+
+// SecretItem mounts a single key of a secret to a file below the reference's path
+type SecretItem struct {
+	Key  string `json:"key"`
+	Path string `json:"path"`           // Relative to the secret reference's path
+	Mode *int32 `json:"mode,omitempty"` // File mode, 0644 by default
 }
 
 type HealthCheck struct {
@@ -106,16 +234,126 @@ type HTTPProbe struct {
 	Port int32  `json:"port"`
 }
 
+// Probe checks whether a container is ready. As a readiness probe it decides when a
+// newly created container is ready, so containers that depend on it are only created
+// afterwards. TCP and HTTP probes connect to the host port the probed container port
+// is published on.
+type Probe struct {
+	Type           ProbeType        `json:"type"` // exec, tcp, http, or a type handled by a registered checker
+	Command        []string         `json:"command,omitempty"`
+	TCPSocket      *TCPSocketAction `json:"tcpSocket,omitempty"`
+	HTTPGet        *HTTPGetAction   `json:"httpGet,omitempty"`
+	Host           string           `json:"host,omitempty"`           // Host TCP and HTTP probes connect to, defaults to localhost
+	PeriodSeconds  int32            `json:"periodSeconds,omitempty"`  // Delay between attempts, defaults to 1
+	TimeoutSeconds int32            `json:"timeoutSeconds,omitempty"` // How long to wait for readiness, defaults to 30
+}
+
+// HTTPGetAction probes a container with an HTTP GET; a 2xx or 3xx status means success
+type HTTPGetAction struct {
+	Path string `json:"path,omitempty"`
+	Port int32  `json:"port"`
+	// +kubebuilder:validation:Enum=HTTP;HTTPS
+	Scheme  string            `json:"scheme,omitempty"` // Defaults to HTTP; HTTPS certificates are not verified
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// TCPSocketAction probes a container by opening a TCP connection
+type TCPSocketAction struct {
+	Port int32 `json:"port"`
+}
+
+// LifecycleSpec holds commands run inside the container around its lifetime
+type LifecycleSpec struct {
+	PostStart *ExecAction `json:"postStart,omitempty"` // Run after start; a non-zero exit fails creation
+	PreStop   *ExecAction `json:"preStop,omitempty"`   // Run before stop, best-effort
+}
+
+// ExecAction is a command run inside the container
+type ExecAction struct {
+	// +kubebuilder:validation:MinItems=1
+	Command []string `json:"command"`
+}
+
 type SecurityContext struct {
-	Privileged   *bool         `json:"privileged,omitempty"`
-	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	Privileged *bool `json:"privileged,omitempty"`
+	// +kubebuilder:validation:Enum=minimal;network-admin;web-server
+	CapabilityPreset       string        `json:"capabilityPreset,omitempty"` // Named set of capabilities, combined with capabilities
+	Capabilities           *Capabilities `json:"capabilities,omitempty"`
+	ReadOnlyRootFilesystem *bool         `json:"readOnlyRootFilesystem,omitempty"`
+	SeccompProfile         string        `json:"seccompProfile,omitempty"` // Path to a seccomp profile, or "unconfined"
 }
 
+// SeccompUnconfined runs a container without any seccomp profile
+const SeccompUnconfined = "unconfined"
+
 type Capabilities struct {
 	Add  []string `json:"add,omitempty"`
 	Drop []string `json:"drop,omitempty"`
 }
 
+// capabilityPresets are the built-in capability sets a security context can name
+var capabilityPresets = map[string]Capabilities{
+	// Nothing beyond what the process needs to run
+	"minimal": {Drop: []string{"ALL"}},
+	// Binding ports below 1024 as a non-root user
+	"web-server": {Drop: []string{"ALL"}, Add: []string{"NET_BIND_SERVICE"}},
+	// Managing interfaces, routes and firewall rules
+	"network-admin": {Add: []string{"NET_ADMIN", "NET_RAW"}},
+}
+
+// resolveCapabilities combines a security context's preset with its explicit
+// capabilities into the capabilities to add and drop. An explicit add overrides the
+// preset dropping the same capability, and an explicit drop overrides the preset adding
+// it. Names are normalized, so that equivalent specs resolve to the same lists.
+func resolveCapabilities(secCtx *SecurityContext) (add, drop []string, err error) {
+	if secCtx == nil {
+		return nil, nil, nil
+	}
+
+	var preset, explicit Capabilities
+	if secCtx.CapabilityPreset != "" {
+		var ok bool
+		preset, ok = capabilityPresets[secCtx.CapabilityPreset]
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown capability preset %q (known presets: %s)",
+				secCtx.CapabilityPreset, strings.Join(slices.Sorted(maps.Keys(capabilityPresets)), ", "))
+		}
+	}
+	if secCtx.Capabilities != nil {
+		explicit = *secCtx.Capabilities
+	}
+
+	explicitAdd := normalizeCapabilities(explicit.Add)
+	explicitDrop := normalizeCapabilities(explicit.Drop)
+	for _, capability := range normalizeCapabilities(preset.Add) {
+		if !slices.Contains(explicitDrop, capability) {
+			add = append(add, capability)
+		}
+	}
+	for _, capability := range normalizeCapabilities(preset.Drop) {
+		if !slices.Contains(explicitAdd, capability) {
+			drop = append(drop, capability)
+		}
+	}
+
+	return normalizeCapabilities(append(add, explicitAdd...)), normalizeCapabilities(append(drop, explicitDrop...)), nil
+}
+
+// normalizeCapabilities upper-cases capability names and strips their CAP_ prefix,
+// which Podman accepts either way, and sorts and deduplicates them
+func normalizeCapabilities(capabilities []string) []string {
+	if len(capabilities) == 0 {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(capabilities))
+	for _, capability := range capabilities {
+		normalized = append(normalized, strings.TrimPrefix(strings.ToUpper(capability), "CAP_"))
+	}
+	slices.Sort(normalized)
+	return slices.Compact(normalized)
+}
+
 type ResourceRequirements struct {
 	Limits   ResourceList `json:"limits,omitempty"`
 	Requests ResourceList `json:"requests,omitempty"`
@@ -159,15 +397,23 @@ func (c *ContainerResource) SetLabels(labels map[string]string) {
 	c.ObjectMeta.Labels = labels
 }
 
+// GetPriority returns the container's creation priority within its dependency level
+func (c *ContainerResource) GetPriority() int {
+	return c.Spec.Priority
+}
+
 // GetDependencies returns the resources this container depends on
 func (c *ContainerResource) GetDependencies() []ResourceReference {
 	var deps []ResourceReference
 
-	// Add network dependencies
+	// Add network dependencies; external networks are not part of the chart
 	for _, network := range c.Spec.Networks {
+		if network.External {
+			continue
+		}
 		deps = append(deps, ResourceReference{
 			Type: ResourceTypeNetwork,
-			Name: network,
+			Name: network.Name,
 		})
 	}
 
@@ -188,6 +434,12 @@ func (c *ContainerResource) GetDependencies() []ResourceReference {
 			Name: secret.Name,
 		})
 	}
+	for _, source := range c.Spec.EnvFrom {
+		deps = append(deps, ResourceReference{
+			Type: ResourceTypeSecret,
+			Name: source.SecretName,
+		})
+	}
 
 	// Add pod dependency if specified
 	if c.Spec.Pod != "" {
@@ -197,6 +449,26 @@ func (c *ContainerResource) GetDependencies() []ResourceReference {
 		})
 	}
 
+	// Add container dependencies
+	for _, name := range c.Spec.DependsOn {
+		deps = append(deps, ResourceReference{
+			Type: ResourceTypeContainer,
+			Name: name,
+		})
+	}
+
+	// Containers whose namespaces are joined must exist first
+	if !c.Spec.ExternalNamespaces {
+		for _, ns := range namespaceModes(c.Spec) {
+			if target, ok := namespaceTarget(ns.mode); ok {
+				deps = append(deps, ResourceReference{
+					Type: ResourceTypeContainer,
+					Name: target,
+				})
+			}
+		}
+	}
+
 	return deps
 }
 
@@ -221,12 +493,150 @@ func (c *ContainerResource) Validate(yml string) []error {
 	if c.Spec.Image == "" {
 		addErr("$.spec.image", "image must not be empty")
 	}
+	for key := range c.GetAnnotations() {
+		if strings.HasPrefix(key, "cutepod.io/") && !strings.HasPrefix(key, labels.AnnotationPodmanFlagPrefix) && !slices.Contains(settableAnnotations, key) {
+			addErr("$.metadata.annotations", fmt.Sprintf("annotation %s uses the reserved cutepod.io/ prefix", key))
+		}
+	}
+	for _, problem := range validatePodmanFlags(c) {
+		addErr("$.metadata.annotations", problem)
+	}
+	if c.Spec.CommandString != "" {
+		if len(c.Spec.Command) > 0 {
+			addErr("$.spec.commandString", "commandString and command are mutually exclusive")
+		} else if _, err := splitCommandString(c.Spec.CommandString); err != nil {
+			addErr("$.spec.commandString", fmt.Sprintf("invalid commandString: %v", err))
+		}
+	}
+	if _, _, err := resolveCapabilities(c.Spec.SecurityContext); err != nil {
+		addErr("$.spec.securityContext.capabilityPreset", err.Error())
+	}
+	if secCtx := c.Spec.SecurityContext; secCtx != nil && secCtx.SeccompProfile != "" && secCtx.SeccompProfile != SeccompUnconfined {
+		if profile, err := os.Open(secCtx.SeccompProfile); err != nil {
+			addErr("$.spec.securityContext.seccompProfile", fmt.Sprintf("seccomp profile is not readable: %v", err))
+		} else {
+			profile.Close()
+		}
+	}
+	if c.Spec.Build != nil {
+		if c.Spec.Build.Context == "" {
+			addErr("$.spec.build.context", "build.context must not be empty")
+		} else if !filepath.IsAbs(c.Spec.Build.Context) {
+			addErr("$.spec.build.context", "build.context must be an absolute path")
+		}
+	}
+	if probe := c.Spec.ReadinessProbe; probe != nil {
+		switch {
+		case probe.Type == "":
+			addErr("$.spec.readinessProbe.type", "readinessProbe type must not be empty")
+		case probe.Type == ProbeTypeExec && len(probe.Command) == 0:
+			addErr("$.spec.readinessProbe.command", "exec readinessProbe requires a command")
+		case probe.Type == ProbeTypeTCP && probe.TCPSocket == nil:
+			addErr("$.spec.readinessProbe.tcpSocket", "tcp readinessProbe requires tcpSocket")
+		case probe.Type == ProbeTypeHTTP && probe.HTTPGet == nil:
+			addErr("$.spec.readinessProbe.httpGet", "http readinessProbe requires httpGet")
+		}
+		if probe.HTTPGet != nil && probe.HTTPGet.Scheme != "" && probe.HTTPGet.Scheme != "HTTP" && probe.HTTPGet.Scheme != "HTTPS" {
+			addErr("$.spec.readinessProbe.httpGet.scheme", "httpGet scheme must be HTTP or HTTPS")
+		}
+		if probe.PeriodSeconds < 0 || probe.TimeoutSeconds < 0 {
+			addErr("$.spec.readinessProbe", "readinessProbe periodSeconds and timeoutSeconds must be >= 0")
+		}
+	}
+	if c.Spec.Lifecycle != nil {
+		if c.Spec.Lifecycle.PostStart != nil && len(c.Spec.Lifecycle.PostStart.Command) == 0 {
+			addErr("$.spec.lifecycle.postStart.command", "postStart command must not be empty")
+		}
+		if c.Spec.Lifecycle.PreStop != nil && len(c.Spec.Lifecycle.PreStop.Command) == 0 {
+			addErr("$.spec.lifecycle.preStop.command", "preStop command must not be empty")
+		}
+	}
 	if c.Spec.UID != nil && *c.Spec.UID < 0 {
 		addErr("$.spec.uid", "uid must be >= 0")
 	}
 	if c.Spec.GID != nil && *c.Spec.GID < 0 {
 		addErr("$.spec.gid", "gid must be >= 0")
 	}
+	if c.Spec.RunAsUser != "" {
+		if user, group, hasGroup := strings.Cut(c.Spec.RunAsUser, ":"); strings.TrimSpace(user) == "" || (hasGroup && strings.TrimSpace(group) == "") {
+			addErr("$.spec.runAsUser", "runAsUser must not be empty")
+		} else if hasGroup && c.Spec.RunAsGroup != "" {
+			addErr("$.spec.runAsGroup", "runAsGroup must not be set when runAsUser already names a group")
+		}
+	}
+	if c.Spec.RunAsGroup != "" {
+		if strings.TrimSpace(c.Spec.RunAsGroup) == "" {
+			addErr("$.spec.runAsGroup", "runAsGroup must not be empty")
+		} else if c.Spec.RunAsUser == "" && c.Spec.UID == nil {
+			addErr("$.spec.runAsGroup", "runAsGroup requires runAsUser or uid")
+		}
+	}
+	for i, name := range c.Spec.DependsOn {
+		if strings.TrimSpace(name) == "" {
+			addErr(fmt.Sprintf("$.spec.dependsOn[%d]", i), "dependsOn entries must not be empty")
+		} else if name == c.GetName() {
+			addErr(fmt.Sprintf("$.spec.dependsOn[%d]", i), "container must not depend on itself")
+		}
+	}
+	for _, ns := range namespaceModes(c.Spec) {
+		if msg := validateNamespaceMode(ns, c); msg != "" {
+			addErr("$.spec."+ns.field, msg)
+		}
+	}
+	if c.Spec.Hostname != "" {
+		if problems := validation.IsDNS1123Label(c.Spec.Hostname); len(problems) > 0 {
+			addErr("$.spec.hostname", fmt.Sprintf("hostname must be a DNS label: %s", strings.Join(problems, "; ")))
+		}
+	}
+	if c.Spec.DomainName != "" {
+		if problems := validation.IsDNS1123Subdomain(c.Spec.DomainName); len(problems) > 0 {
+			addErr("$.spec.domainName", fmt.Sprintf("domainName must be a DNS name: %s", strings.Join(problems, "; ")))
+		}
+	}
+	// Only a container with a UTS namespace of its own can name itself
+	if (c.Spec.Hostname != "" || c.Spec.DomainName != "") && !ownUTSNamespace(c.Spec) {
+		addErr("$.spec.utsMode", fmt.Sprintf("hostname and domainName cannot be set with utsMode %s", c.Spec.UtsMode))
+	}
+	if c.Spec.NetworkMode != "" {
+		if len(c.Spec.Networks) > 0 {
+			addErr("$.spec.networkMode", "networkMode cannot be combined with networks")
+		}
+		if len(c.Spec.Ports) > 0 {
+			addErr("$.spec.networkMode", "networkMode cannot be combined with ports")
+		}
+		if c.Spec.Pod != "" {
+			addErr("$.spec.networkMode", "networkMode cannot be set on a pod member, which uses the pod's network")
+		}
+	}
+	for i, group := range c.Spec.GroupAdd {
+		if strings.TrimSpace(group) == "" {
+			addErr(fmt.Sprintf("$.spec.groupAdd[%d]", i), "groupAdd entries must not be empty")
+		}
+	}
+	for i, source := range c.Spec.EnvFrom {
+		if source.SecretName == "" {
+			addErr(fmt.Sprintf("$.spec.envFrom[%d].secretName", i), "envFrom secretName must not be empty")
+		}
+	}
+	for i, secret := range c.Spec.Secrets {
+		if secret.Prefix != "" && !secret.Env {
+			addErr(fmt.Sprintf("$.spec.secrets[%d].prefix", i), "prefix requires env")
+		}
+		if len(secret.Items) > 0 && secret.Path == "" {
+			addErr(fmt.Sprintf("$.spec.secrets[%d].items", i), "items require path")
+		}
+		for j, item := range secret.Items {
+			if item.Key == "" {
+				addErr(fmt.Sprintf("$.spec.secrets[%d].items[%d].key", i, j), "item key must not be empty")
+			}
+			if !filepath.IsLocal(item.Path) {
+				addErr(fmt.Sprintf("$.spec.secrets[%d].items[%d].path", i, j), "item path must be a relative path within the secret's path")
+			}
+			if item.Mode != nil && (*item.Mode < 0 || *item.Mode > 0777) {
+				addErr(fmt.Sprintf("$.spec.secrets[%d].items[%d].mode", i, j), "item mode must be between 0 and 0777")
+			}
+		}
+	}
 
 	validRestart := map[string]bool{
 		"no": true, "on-failure": true, "always": true, "unless-stopped": true,
@@ -236,6 +646,13 @@ func (c *ContainerResource) Validate(yml string) []error {
 	if c.Spec.RestartPolicy != "" && !validRestart[c.Spec.RestartPolicy] {
 		addErr("$.spec.restartPolicy", "invalid restartPolicy: must be no, on-failure, always, unless-stopped, Always, OnFailure, or Never")
 	}
+	// Podman refuses to restart a container it removes on exit
+	if autoRemove(c.Spec) && c.Spec.RestartPolicy != "" && c.Spec.RestartPolicy != "no" && c.Spec.RestartPolicy != "Never" {
+		addErr("$.spec.autoRemove", "autoRemove requires restartPolicy no")
+	}
+	if c.Spec.StdinOnce != nil && *c.Spec.StdinOnce && !stdinOpen(c.Spec) {
+		addErr("$.spec.stdinOnce", "stdinOnce requires stdin")
+	}
 
 	for i, env := range c.Spec.Env {
 		if strings.TrimSpace(env.Name) == "" {
@@ -261,6 +678,20 @@ func (c *ContainerResource) Validate(yml string) []error {
 		}
 	}
 
+	for i, network := range c.Spec.Networks {
+		if strings.TrimSpace(network.Name) == "" {
+			addErr(fmt.Sprintf("$.spec.networks[%d].name", i), "network name must not be empty")
+		}
+		if network.StaticIP != "" && net.ParseIP(network.StaticIP) == nil {
+			addErr(fmt.Sprintf("$.spec.networks[%d].staticIP", i), "staticIP must be a valid IP address")
+		}
+		if network.StaticMAC != "" {
+			if _, err := net.ParseMAC(network.StaticMAC); err != nil {
+				addErr(fmt.Sprintf("$.spec.networks[%d].staticMAC", i), "staticMAC must be a valid MAC address")
+			}
+		}
+	}
+
 	// Validate volume mounts
 	for i, volume := range c.Spec.Volumes {
 		if strings.TrimSpace(volume.Name) == "" {
@@ -304,6 +735,11 @@ func (c *ContainerResource) Validate(yml string) []error {
 						"seLinuxLabel must be one of: z, Z, shared, private")
 				}
 			}
+			if volume.MountOptions.SELinuxContext != "" {
+				if err := validateSELinuxContext(volume.MountOptions.SELinuxContext); err != nil {
+					addErr(fmt.Sprintf("$.spec.volumes[%d].mountOptions.seLinuxContext", i), err.Error())
+				}
+			}
 
 			// Validate UID mapping
 			if volume.MountOptions.UIDMapping != nil {
@@ -320,8 +756,82 @@ func (c *ContainerResource) Validate(yml string) []error {
 						"gidMapping.size must be greater than 0")
 				}
 			}
+
+			// Validate mount propagation
+			if volume.MountOptions.Propagation != "" && !validMountPropagations[volume.MountOptions.Propagation] {
+				addErr(fmt.Sprintf("$.spec.volumes[%d].mountOptions.propagation", i),
+					"propagation must be one of: private, rprivate, rshared, rslave")
+			}
+
+			// Recursive read-only only makes sense on a read-only mount
+			if volume.MountOptions.RecursiveReadOnly && !volume.ReadOnly {
+				addErr(fmt.Sprintf("$.spec.volumes[%d].mountOptions.recursiveReadOnly", i),
+					"recursiveReadOnly requires readOnly: true")
+			}
 		}
 	}
 
 	return errs
 }
+
+// splitCommandString splits a command line into words the way a POSIX shell does,
+// without expanding anything. Single quotes keep their content literally, double quotes
+// allow backslash escapes of '"', '\', '$' and '`', and a backslash outside quotes
+// escapes the next character.
+func splitCommandString(command string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+
+	for _, r := range command {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune("\"\\$`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\\':
+			escaped = true
+			inWord = true
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inWord = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+
+	switch {
+	case escaped:
+		return nil, fmt.Errorf("command ends with an unfinished escape")
+	case quote != 0:
+		return nil, fmt.Errorf("unbalanced %c quote in command", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words, nil
+}
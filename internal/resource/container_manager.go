@@ -2,41 +2,124 @@ package resource
 
 import (
 	"context"
+	"crypto/sha256"
+	"cutepod/internal/labels"
 	"cutepod/internal/podman"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"net"
 	"os"
+	"path"
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	nettypes "github.com/containers/common/libnetwork/types"
+	"github.com/containers/podman/v5/libpod/define"
 	podmantypes "github.com/containers/podman/v5/pkg/domain/entities/types"
 	"github.com/containers/podman/v5/pkg/specgen"
 	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
+// defaultPodmanNetwork is the network Podman attaches containers to when none is requested
+const defaultPodmanNetwork = "podman"
+
+// stoppedContainerStates are the Podman states of a container that is not running and
+// was not paused either
+var stoppedContainerStates = map[string]bool{
+	"created": true,
+	"exited":  true,
+	"stopped": true,
+}
+
+// runningRestartPolicies are the restart policies under which a container is expected
+// to be running at all times
+var runningRestartPolicies = map[string]bool{
+	"always":         true,
+	"Always":         true,
+	"unless-stopped": true,
+}
+
+// podmanAnnotationPrefixes match the annotations Podman sets on containers by itself,
+// which are left out when reading annotations back
+var podmanAnnotationPrefixes = []string{
+	"io.container.manager",
+	"io.podman.annotations.",
+	"io.kubernetes.cri-o.",
+	"org.opencontainers.",
+	"org.systemd.property.",
+}
+
+// bookkeepingAnnotations are attached to containers read back from Podman by cutepod
+// itself and are never part of a manifest's annotations
+var bookkeepingAnnotations = []string{
+	labels.LabelSpecHash,
+	labels.LabelContainerfileHash,
+	labels.LabelSecretEnvKeys,
+	labels.LabelEmptyDirs,
+	labels.AnnotationPaused,
+	labels.AnnotationStopped,
+}
+
 // ContainerManager implements ResourceManager for container resources
 type ContainerManager struct {
 	client        podman.PodmanClient
 	pathManager   *VolumePathManager
 	permissionMgr *VolumePermissionManager
 	registry      *ManifestRegistry
+	logger        Logger
+
+	// recreateOnAnnotationChange makes annotation drift recreate the container; by
+	// default it is only reported, since Podman cannot change annotations in place
+	recreateOnAnnotationChange bool
+	// ignoredLabelKeys are the comparator's ignore patterns, whose annotations never
+	// trigger a recreate
+	ignoredLabelKeys []string
+
+	// offlineMode never pulls images, for hosts whose images are loaded by other means
+	offlineMode bool
+
+	// failureLogLines is how many log lines are kept from a container that fails to start
+	failureLogLines int
+
+	// pullSlots holds a token for every image pull in progress, limiting how many run at once
+	pullSlots chan struct{}
+
+	// pullProgress, when set, receives the progress of every image pull along with the
+	// chart of the container the image is pulled for
+	pullProgress func(chartName string, progress podman.PullProgress)
+
+	// autoRemoved holds the spec hash of every container created with autoRemove, by name,
+	// so that one which ran and removed itself is not created again
+	autoRemoved   map[string]string
+	autoRemovedMu sync.Mutex
+
+	// labelPrefix, when set, replaces cutepod.io/ in the chart label containers are listed by
+	labelPrefix string
 }
 
+// defaultPullConcurrency is how many images a container manager pulls at once by default
+const defaultPullConcurrency = 2
+
 // NewContainerManager creates a new ContainerManager
 func NewContainerManager(client podman.PodmanClient) *ContainerManager {
 	pathManager := NewVolumePathManager("")
 	permissionMgr, err := NewVolumePermissionManager()
 	if err != nil {
 		// Log error but continue with nil permission manager
-		fmt.Printf("Warning: failed to initialize volume permission manager: %v\n", err)
+		defaultLogger.Warn("failed to initialize volume permission manager", "error", err)
 	}
 
 	return &ContainerManager{
 		client:        client,
 		pathManager:   pathManager,
 		permissionMgr: permissionMgr,
+		logger:        defaultLogger,
+		pullSlots:     make(chan struct{}, defaultPullConcurrency),
 	}
 }
 
@@ -46,7 +129,7 @@ func NewContainerManagerWithRegistry(client podman.PodmanClient, registry *Manif
 	permissionMgr, err := NewVolumePermissionManager()
 	if err != nil {
 		// Log error but continue with nil permission manager
-		fmt.Printf("Warning: failed to initialize volume permission manager: %v\n", err)
+		defaultLogger.Warn("failed to initialize volume permission manager", "error", err)
 	}
 
 	return &ContainerManager{
@@ -54,9 +137,36 @@ func NewContainerManagerWithRegistry(client podman.PodmanClient, registry *Manif
 		pathManager:   pathManager,
 		permissionMgr: permissionMgr,
 		registry:      registry,
+		logger:        defaultLogger,
+		pullSlots:     make(chan struct{}, defaultPullConcurrency),
+	}
+}
+
+// SetLogger sets the logger of the manager and of the volume helpers it uses
+func (cm *ContainerManager) SetLogger(logger Logger) {
+	cm.logger = logger
+	cm.pathManager.logger = logger
+	if cm.permissionMgr != nil {
+		cm.permissionMgr.logger = logger
 	}
 }
 
+// SetPullConcurrency limits how many images the manager pulls at once; values below 1
+// are treated as 1
+func (cm *ContainerManager) SetPullConcurrency(concurrency int) {
+	cm.pullSlots = make(chan struct{}, max(concurrency, 1))
+}
+
+// SetHostPathPrefixes restricts the hostPath volumes the manager mounts to the given prefixes
+func (cm *ContainerManager) SetHostPathPrefixes(prefixes []string) {
+	cm.pathManager.SetAllowedPrefixes(prefixes)
+}
+
+// SetLabelPrefix makes the manager list containers by the chart label under prefix
+func (cm *ContainerManager) SetLabelPrefix(prefix string) {
+	cm.labelPrefix = prefix
+}
+
 // GetResourceType returns the resource type this manager handles
 func (cm *ContainerManager) GetResourceType() ResourceType {
 	return ResourceTypeContainer
@@ -85,13 +195,7 @@ func (cm *ContainerManager) GetActualState(ctx context.Context, chartName string
 		return nil, fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	containers, err := podmanClient.ListContainers(
-		ctx,
-		map[string][]string{
-			"label": {"cutepod.io/chart=" + chartName},
-		},
-		true,
-	)
+	containers, err := podmanClient.ListContainers(ctx, chartFilters(ctx, cm.labelPrefix, chartName), true)
 	if err != nil {
 		return nil, fmt.Errorf("unable to list containers: %w", err)
 	}
@@ -122,7 +226,7 @@ func (cm *ContainerManager) CreateResource(ctx context.Context, resource Resourc
 	}
 
 	// Prepare volume paths and permissions
-	if err := cm.prepareVolumeMounts(container); err != nil {
+	if err := cm.prepareVolumeMounts(container.Spec.Volumes); err != nil {
 		return fmt.Errorf("failed to prepare volume mounts: %w", err)
 	}
 
@@ -134,8 +238,12 @@ func (cm *ContainerManager) CreateResource(ctx context.Context, resource Resourc
 		return fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	// Pull image if needed
-	if err := cm.pullImageIfNeeded(ctx, podmanClient, container.Spec.Image); err != nil {
+	// Build the image from its Containerfile, or pull it if needed
+	if container.Spec.Build != nil {
+		if err := cm.buildImage(ctx, podmanClient, container); err != nil {
+			return fmt.Errorf("unable to build image: %w", err)
+		}
+	} else if err := cm.pullImageIfNeeded(ctx, podmanClient, container); err != nil {
 		return fmt.Errorf("unable to pull image: %w", err)
 	}
 
@@ -151,16 +259,54 @@ func (cm *ContainerManager) CreateResource(ctx context.Context, resource Resourc
 		return fmt.Errorf("unable to create container: %w", err)
 	}
 
-	// Start container
+	// Start container, removing it again if it cannot start so that no created but never
+	// started container is left behind for the next reconcile to find
 	if err := podmanClient.StartContainer(ctx, response.ID); err != nil {
-		return fmt.Errorf("unable to start container: %w", err)
+		err = withContainerLogs(ctx, podmanClient, container.GetName(), cm.failureLogLines,
+			fmt.Errorf("unable to start container: %w", err))
+		if removeErr := cm.removeContainer(ctx, podmanClient, container.GetName()); removeErr != nil {
+			loggerOrDefault(cm.logger).Warn("failed to remove container after start failure",
+				"container", container.GetName(), "error", removeErr)
+		}
+		return err
+	}
+
+	// A failed postStart hook fails creation; the container is removed so a retry starts afresh
+	if container.Spec.Lifecycle != nil && container.Spec.Lifecycle.PostStart != nil {
+		if err := cm.runPostStart(ctx, podmanClient, container); err != nil {
+			err = withContainerLogs(ctx, podmanClient, container.GetName(), cm.failureLogLines, err)
+			if removeErr := cm.removeContainer(ctx, podmanClient, container.GetName()); removeErr != nil {
+				loggerOrDefault(cm.logger).Warn("failed to remove container after postStart failure",
+					"container", container.GetName(), "error", removeErr)
+			}
+			return err
+		}
 	}
 
+	cm.recordAutoRemove(container)
 	return nil
 }
 
 // UpdateResource updates an existing container resource
 func (cm *ContainerManager) UpdateResource(ctx context.Context, desired, actual Resource) error {
+	desiredContainer, ok := desired.(*ContainerResource)
+	if !ok {
+		return fmt.Errorf("expected ContainerResource for desired, got %T", desired)
+	}
+
+	actualContainer, ok := actual.(*ContainerResource)
+	if !ok {
+		return fmt.Errorf("expected ContainerResource for actual, got %T", actual)
+	}
+
+	inPlace, err := cm.canUpdateInPlace(desiredContainer, actualContainer)
+	if err != nil {
+		return err
+	}
+	if inPlace {
+		return cm.updateInPlace(ctx, desiredContainer, actualContainer)
+	}
+
 	// For containers, update typically means recreate
 	// First remove the existing container, then create the new one
 	if err := cm.DeleteResource(ctx, actual); err != nil {
@@ -189,9 +335,106 @@ func (cm *ContainerManager) DeleteResource(ctx context.Context, resource Resourc
 		return fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
+	if container.Spec.Lifecycle != nil && container.Spec.Lifecycle.PreStop != nil {
+		cm.runPreStop(ctx, podmanClient, container)
+	}
+
 	return cm.removeContainer(ctx, podmanClient, container.GetName())
 }
 
+// PauseResource pauses a container instead of deleting it, keeping it around for debugging.
+// Containers that are already paused or not running are left as they are.
+func (cm *ContainerManager) PauseResource(ctx context.Context, resource Resource) error {
+	container, ok := resource.(*ContainerResource)
+	if !ok {
+		return fmt.Errorf("expected ContainerResource, got %T", resource)
+	}
+
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	inspect, err := podmanClient.InspectContainer(ctx, container.GetName())
+	if err != nil {
+		return fmt.Errorf("unable to inspect container %s: %w", container.GetName(), err)
+	}
+	if inspect.State == nil || inspect.State.Paused || inspect.State.Status != "running" {
+		return nil
+	}
+
+	if err := podmanClient.PauseContainer(ctx, container.GetName()); err != nil {
+		return fmt.Errorf("unable to pause container %s: %w", container.GetName(), err)
+	}
+
+	return nil
+}
+
+// StopResource stops a running container while the resources it depends on are replaced,
+// reporting whether it was running. Paused and stopped containers are left as they are.
+func (cm *ContainerManager) StopResource(ctx context.Context, resource Resource) (bool, error) {
+	container, ok := resource.(*ContainerResource)
+	if !ok {
+		return false, fmt.Errorf("expected ContainerResource, got %T", resource)
+	}
+
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	inspect, err := podmanClient.InspectContainer(ctx, container.GetName())
+	if err != nil {
+		return false, fmt.Errorf("unable to inspect container %s: %w", container.GetName(), err)
+	}
+	if inspect.State == nil || inspect.State.Status != "running" {
+		return false, nil
+	}
+
+	if err := podmanClient.StopContainer(ctx, container.GetName(), 15); err != nil {
+		return false, fmt.Errorf("unable to stop container %s: %w", container.GetName(), err)
+	}
+
+	return true, nil
+}
+
+// StartResource starts a container stopped by StopResource again, unless it was recreated
+// and is already running
+func (cm *ContainerManager) StartResource(ctx context.Context, resource Resource) error {
+	container, ok := resource.(*ContainerResource)
+	if !ok {
+		return fmt.Errorf("expected ContainerResource, got %T", resource)
+	}
+
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	inspect, err := podmanClient.InspectContainer(ctx, container.GetName())
+	if err != nil {
+		return fmt.Errorf("unable to inspect container %s: %w", container.GetName(), err)
+	}
+	if inspect.State != nil && inspect.State.Status == "running" {
+		return nil
+	}
+
+	if err := podmanClient.StartContainer(ctx, container.GetName()); err != nil {
+		return fmt.Errorf("unable to start container %s: %w", container.GetName(), err)
+	}
+
+	return nil
+}
+
 // CompareResources compares desired vs actual container resource
 func (cm *ContainerManager) CompareResources(desired, actual Resource) (bool, error) {
 	desiredContainer, ok := desired.(*ContainerResource)
@@ -204,12 +447,55 @@ func (cm *ContainerManager) CompareResources(desired, actual Resource) (bool, er
 		return false, fmt.Errorf("expected ContainerResource for actual, got %T", actual)
 	}
 
-	// Compare key fields that would require recreation
+	// A paused container has to be resumed, even when its spec is unchanged, and so does
+	// a stopped container that is meant to keep running
+	if isContainerPaused(actualContainer) || needsRestart(desiredContainer, actualContainer) {
+		return false, nil
+	}
+
+	if restartRequested(desiredContainer, actualContainer) {
+		return false, nil
+	}
+
+	if cm.annotationsForceRecreate(desiredContainer, actualContainer) {
+		return false, nil
+	}
+
+	// Containers created by cutepod carry a hash of their spec, which catches any change
+	if actualHash := actualContainer.GetAnnotations()[labels.LabelSpecHash]; actualHash != "" {
+		hashMatches, err := specHashMatches(desiredContainer.Spec, actualHash)
+		if err != nil {
+			return false, fmt.Errorf("unable to hash desired container spec: %w", err)
+		}
+		if !hashMatches || !cm.sameSecretEnvKeys(desiredContainer, actualContainer) ||
+			!samePodmanFlags(desiredContainer, actualContainer) {
+			return false, nil
+		}
+
+		// The Containerfile can change without the spec changing, which requires a rebuild
+		if desiredContainer.Spec.Build != nil {
+			containerfileHash, err := computeContainerfileHash(desiredContainer.Spec.Build)
+			if err != nil {
+				return false, err
+			}
+			if containerfileHash != actualContainer.GetAnnotations()[labels.LabelContainerfileHash] {
+				return false, nil
+			}
+		}
+
+		if !sameRestartPolicy(desiredContainer.Spec.RestartPolicy, actualContainer.Spec.RestartPolicy) {
+			return false, nil
+		}
+
+		return cm.compareNetworkAliases(desiredContainer.Spec.Networks, actualContainer.Spec.Networks), nil
+	}
+
+	// Fall back to comparing key fields that would require recreation
 	if desiredContainer.Spec.Image != actualContainer.Spec.Image {
 		return false, nil
 	}
 
-	if !slices.Equal(desiredContainer.Spec.Command, actualContainer.Spec.Command) {
+	if desiredCommand, err := containerCommand(desiredContainer.Spec); err != nil || !slices.Equal(desiredCommand, actualContainer.Spec.Command) {
 		return false, nil
 	}
 
@@ -221,6 +507,39 @@ func (cm *ContainerManager) CompareResources(desired, actual Resource) (bool, er
 		return false, nil
 	}
 
+	if containerUser(desiredContainer.Spec) != containerUser(actualContainer.Spec) {
+		return false, nil
+	}
+
+	if !sameGroups(desiredContainer.Spec.GroupAdd, actualContainer.Spec.GroupAdd) {
+		return false, nil
+	}
+
+	// Namespaces are fixed when a container is created, so a new mode requires recreation
+	if !sameNamespaceMode(desiredContainer.Spec.IpcMode, actualContainer.Spec.IpcMode) ||
+		!sameNamespaceMode(desiredContainer.Spec.PidMode, actualContainer.Spec.PidMode) ||
+		!sameNamespaceMode(desiredContainer.Spec.UtsMode, actualContainer.Spec.UtsMode) ||
+		!sameNamespaceMode(desiredContainer.Spec.NetworkMode, actualContainer.Spec.NetworkMode) {
+		return false, nil
+	}
+
+	if readOnlyRootFilesystem(desiredContainer.Spec) != readOnlyRootFilesystem(actualContainer.Spec) ||
+		seccompProfile(desiredContainer.Spec) != seccompProfile(actualContainer.Spec) {
+		return false, nil
+	}
+
+	// An actual container without a hostname is one whose hostname Podman did not report
+	if hostname := containerHostname(desiredContainer); hostname != "" && actualContainer.Spec.Hostname != "" && hostname != actualContainer.Spec.Hostname {
+		return false, nil
+	}
+
+	if initEnabled(desiredContainer.Spec) != initEnabled(actualContainer.Spec) ||
+		autoRemove(desiredContainer.Spec) != autoRemove(actualContainer.Spec) ||
+		stdinOpen(desiredContainer.Spec) != stdinOpen(actualContainer.Spec) ||
+		ttyEnabled(desiredContainer.Spec) != ttyEnabled(actualContainer.Spec) {
+		return false, nil
+	}
+
 	// Compare environment variables
 	if !cm.compareEnvVars(desiredContainer.Spec.Env, actualContainer.Spec.Env) {
 		return false, nil
@@ -237,7 +556,7 @@ func (cm *ContainerManager) CompareResources(desired, actual Resource) (bool, er
 	}
 
 	// Compare networks
-	if !slices.Equal(desiredContainer.Spec.Networks, actualContainer.Spec.Networks) {
+	if !cm.compareNetworks(desiredContainer.Spec.Networks, actualContainer.Spec.Networks) {
 		return false, nil
 	}
 
@@ -247,13 +566,45 @@ func (cm *ContainerManager) CompareResources(desired, actual Resource) (bool, er
 	}
 
 	// Compare restart policy
-	if desiredContainer.Spec.RestartPolicy != actualContainer.Spec.RestartPolicy {
+	if !sameRestartPolicy(desiredContainer.Spec.RestartPolicy, actualContainer.Spec.RestartPolicy) {
 		return false, nil
 	}
 
 	return true, nil
 }
 
+// comparisonKey summarises what CompareResources reads from a container that carries a
+// spec hash: its annotations, restart policy and network aliases. Containers built from
+// a Containerfile or taking environment variables from secrets are left out, since
+// those can change on disk or in the registry without the spec changing.
+func (cm *ContainerManager) comparisonKey(desired, actual Resource) (string, bool) {
+	desiredContainer, ok := desired.(*ContainerResource)
+	if !ok || desiredContainer.Spec.Build != nil {
+		return "", false
+	}
+	if len(desiredContainer.Spec.EnvFrom) > 0 || slices.ContainsFunc(desiredContainer.Spec.Secrets, func(secret SecretReference) bool { return secret.Env }) {
+		return "", false
+	}
+
+	actualContainer, ok := actual.(*ContainerResource)
+	if !ok || actualContainer.GetAnnotations()[labels.LabelSpecHash] == "" {
+		return "", false
+	}
+
+	var key strings.Builder
+	writeComparisonKey(&key, actualContainer.GetAnnotations())
+	key.WriteString(actualContainer.Spec.RestartPolicy)
+	for _, network := range actualContainer.Spec.Networks {
+		key.WriteByte(0)
+		key.WriteString(network.Name)
+		for _, alias := range network.Aliases {
+			key.WriteByte(',')
+			key.WriteString(alias)
+		}
+	}
+	return key.String(), true
+}
+
 // Helper methods
 
 func (cm *ContainerManager) convertPodmanContainerToResource(ctx context.Context, client podman.PodmanClient, container podmantypes.ListContainer) (*ContainerResource, error) {
@@ -265,7 +616,50 @@ func (cm *ContainerManager) convertPodmanContainerToResource(ctx context.Context
 
 	resource := NewContainerResource()
 	resource.ObjectMeta.Name = strings.TrimPrefix(container.Names[0], "/")
-	resource.SetLabels(container.Labels)
+
+	// The spec and Containerfile hashes are bookkeeping rather than user labels, so keep them
+	// out of the labels; the preStop command goes back into the spec
+	annotations := make(map[string]string)
+	if inspect.Config != nil {
+		for key, value := range inspect.Config.Annotations {
+			if !isPodmanAnnotation(key) {
+				annotations[key] = value
+			}
+		}
+	}
+	containerLabels := make(map[string]string, len(container.Labels))
+	for key, value := range container.Labels {
+		if key == labels.LabelSpecHash || key == labels.LabelContainerfileHash || key == labels.LabelSecretEnvKeys || key == labels.LabelEmptyDirs {
+			annotations[key] = value
+			continue
+		}
+		if key == labels.LabelPreStop {
+			var command []string
+			if err := json.Unmarshal([]byte(value), &command); err == nil {
+				resource.Spec.Lifecycle = &LifecycleSpec{PreStop: &ExecAction{Command: command}}
+			}
+			continue
+		}
+		containerLabels[key] = value
+	}
+	resource.SetLabels(containerLabels)
+
+	if inspect.State != nil && inspect.State.Paused {
+		annotations[labels.AnnotationPaused] = "true"
+	}
+	if inspect.State != nil && stoppedContainerStates[inspect.State.Status] {
+		annotations[labels.AnnotationStopped] = inspect.State.Status
+	}
+	if inspect.Config != nil {
+		for _, env := range inspect.Config.Env {
+			if nonce, ok := strings.CutPrefix(env, restartNonceEnv+"="); ok {
+				annotations[labels.AnnotationRestartNonce] = nonce
+			}
+		}
+	}
+	if len(annotations) > 0 {
+		resource.SetAnnotations(annotations)
+	}
 
 	// Convert inspect data to ContainerResource spec
 	if inspect.Config != nil {
@@ -275,11 +669,15 @@ func (cm *ContainerManager) convertPodmanContainerToResource(ctx context.Context
 	}
 	resource.Spec.Args = inspect.Args
 
-	// Convert environment variables
+	// Convert environment variables, leaving out the values given from secrets
+	var secretEnvKeys []string
+	if keys := container.Labels[labels.LabelSecretEnvKeys]; keys != "" {
+		secretEnvKeys = strings.Split(keys, ",")
+	}
 	if inspect.Config != nil && inspect.Config.Env != nil {
 		for _, env := range inspect.Config.Env {
 			parts := strings.SplitN(env, "=", 2)
-			if len(parts) == 2 {
+			if len(parts) == 2 && !slices.Contains(secretEnvKeys, parts[0]) && parts[0] != restartNonceEnv {
 				resource.Spec.Env = append(resource.Spec.Env, EnvVar{
 					Name:  parts[0],
 					Value: parts[1],
@@ -289,31 +687,7 @@ func (cm *ContainerManager) convertPodmanContainerToResource(ctx context.Context
 	}
 
 	// Convert ports
-	if inspect.HostConfig != nil && inspect.HostConfig.PortBindings != nil {
-		for portProto, bindings := range inspect.HostConfig.PortBindings {
-			parts := strings.Split(portProto, "/")
-			if len(parts) != 2 {
-				continue
-			}
-			containerPort, err := strconv.ParseUint(parts[0], 10, 16)
-			if err != nil {
-				continue
-			}
-			protocol := strings.ToUpper(parts[1])
-
-			for _, binding := range bindings {
-				hostPort, err := strconv.ParseUint(binding.HostPort, 10, 16)
-				if err != nil {
-					continue
-				}
-				resource.Spec.Ports = append(resource.Spec.Ports, ContainerPort{
-					ContainerPort: uint16(containerPort),
-					HostPort:      uint16(hostPort),
-					Protocol:      protocol,
-				})
-			}
-		}
-	}
+	resource.Spec.Ports = convertPortBindings(inspect)
 
 	// Convert volumes
 	for _, mount := range inspect.Mounts {
@@ -338,20 +712,119 @@ func (cm *ContainerManager) convertPodmanContainerToResource(ctx context.Context
 	}
 
 	// Convert restart policy
+	if inspect.Config != nil {
+		resource.Spec.RunAsUser = inspect.Config.User
+		// Podman does not report stdinOnce, so changes to it are caught by the spec hash
+		if inspect.Config.OpenStdin {
+			enabled := true
+			resource.Spec.Stdin = &enabled
+		}
+		if inspect.Config.Tty {
+			enabled := true
+			resource.Spec.TTY = &enabled
+		}
+	}
+	if inspect.HostConfig != nil {
+		resource.Spec.GroupAdd = inspect.HostConfig.GroupAdd
+		resource.Spec.IpcMode = namespaceModeFromInspect(ctx, client, inspect.HostConfig.IpcMode)
+		resource.Spec.PidMode = namespaceModeFromInspect(ctx, client, inspect.HostConfig.PidMode)
+		resource.Spec.UtsMode = namespaceModeFromInspect(ctx, client, inspect.HostConfig.UTSMode)
+		resource.Spec.NetworkMode = namespaceModeFromInspect(ctx, client, inspect.HostConfig.NetworkMode)
+		resource.Spec.SecurityContext = securityContextFromInspect(inspect.HostConfig)
+		// Podman does not report the domain name, so changes to it are caught by the spec hash
+		if utsMode := inspect.HostConfig.UTSMode; inspect.Config != nil && (utsMode == "" || utsMode == "private") {
+			resource.Spec.Hostname = inspect.Config.Hostname
+		}
+		if inspect.HostConfig.Init {
+			enabled := true
+			resource.Spec.Init = &enabled
+		}
+		if inspect.HostConfig.AutoRemove {
+			enabled := true
+			resource.Spec.AutoRemove = &enabled
+		}
+	}
+
 	if inspect.HostConfig != nil && inspect.HostConfig.RestartPolicy != nil {
 		resource.Spec.RestartPolicy = inspect.HostConfig.RestartPolicy.Name
 	}
 
+	// Convert network attachments; static addresses cannot be told apart from assigned
+	// ones, so only names and aliases are read back
+	if inspect.NetworkSettings != nil {
+		networkNames := make([]string, 0, len(inspect.NetworkSettings.Networks))
+		for networkName := range inspect.NetworkSettings.Networks {
+			// Podman attaches containers without networks to its default network
+			if networkName == defaultPodmanNetwork {
+				continue
+			}
+			networkNames = append(networkNames, networkName)
+		}
+		slices.Sort(networkNames)
+
+		for _, networkName := range networkNames {
+			attachment := NetworkAttachment{Name: networkName}
+			for _, alias := range inspect.NetworkSettings.Networks[networkName].Aliases {
+				// Podman adds the container name and short ID as implicit aliases
+				if alias == resource.GetName() || (len(inspect.ID) >= 12 && alias == inspect.ID[:12]) {
+					continue
+				}
+				attachment.Aliases = append(attachment.Aliases, alias)
+			}
+			resource.Spec.Networks = append(resource.Spec.Networks, attachment)
+		}
+	}
+
 	return resource, nil
 }
 
-func (cm *ContainerManager) pullImageIfNeeded(ctx context.Context, client podman.PodmanClient, image string) error {
-	existingImage, err := client.GetImage(ctx, image)
+func (cm *ContainerManager) pullImageIfNeeded(ctx context.Context, client podman.PodmanClient, container *ContainerResource) error {
+	image := container.Spec.Image
+	existingImage, err := lookupImage(ctx, client, image)
 	if err == nil && existingImage != nil {
 		return nil
 	}
 
-	return client.PullImage(ctx, image)
+	if cm.offlineMode {
+		if err == nil || podman.IsImageNotFoundError(err) {
+			return fmt.Errorf("image %s is not present locally and pulling is disabled in offline mode", image)
+		}
+		return fmt.Errorf("unable to look up image %s: %w", image, err)
+	}
+
+	if cm.pullSlots != nil {
+		select {
+		case cm.pullSlots <- struct{}{}:
+			defer func() { <-cm.pullSlots }()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		// Another create may have pulled the image while this one waited for a slot
+		if existingImage, err := lookupImage(ctx, client, image); err == nil && existingImage != nil {
+			return nil
+		}
+	}
+
+	if cm.pullProgress == nil {
+		return client.PullImage(ctx, image)
+	}
+	chartName := container.GetLabels()[labels.WithPrefix(labels.LabelChart, cm.labelPrefix)]
+	return client.PullImageWithProgress(ctx, image, func(progress podman.PullProgress) {
+		cm.pullProgress(chartName, progress)
+	})
+}
+
+// buildImage builds the container's image from its Containerfile, tagged with the container's image
+func (cm *ContainerManager) buildImage(ctx context.Context, client podman.PodmanClient, container *ContainerResource) error {
+	build := container.Spec.Build
+	_, err := client.BuildImage(ctx, podman.BuildOptions{
+		ContextDir:    build.Context,
+		Containerfile: build.ContainerfilePath(),
+		Tag:           container.Spec.Image,
+		BuildArgs:     build.Args,
+	})
+	return err
 }
 
 func (cm *ContainerManager) buildContainerSpec(container *ContainerResource) (*specgen.SpecGenerator, error) {
@@ -361,18 +834,59 @@ func (cm *ContainerManager) buildContainerSpec(container *ContainerResource) (*s
 		return nil, fmt.Errorf("failed to convert volume mounts: %w", err)
 	}
 
-	// Process secrets
+	// Process secrets, whose keys become environment variables
 	env := cm.convertEnvVars(container.Spec.Env)
 	secretMounts, err := cm.processSecrets(container.Spec.Secrets)
 	if err != nil {
 		return nil, fmt.Errorf("failed to process secrets: %w", err)
 	}
+	secretEnv, err := cm.secretEnvVars(container.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process secrets: %w", err)
+	}
+	maps.Copy(env, secretEnv)
+
+	capAdd, capDrop, err := resolveCapabilities(container.Spec.SecurityContext)
+	if err != nil {
+		return nil, err
+	}
+
+	specHash, err := computeContainerSpecHash(container.Spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash container spec: %w", err)
+	}
+	bookkeepingLabels := map[string]string{
+		labels.LabelSpecHash: specHash,
+	}
+	if container.Spec.Build != nil {
+		containerfileHash, err := computeContainerfileHash(container.Spec.Build)
+		if err != nil {
+			return nil, err
+		}
+		bookkeepingLabels[labels.LabelContainerfileHash] = containerfileHash
+	}
+	if container.Spec.Lifecycle != nil && container.Spec.Lifecycle.PreStop != nil {
+		preStop, err := json.Marshal(container.Spec.Lifecycle.PreStop.Command)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode preStop command: %w", err)
+		}
+		bookkeepingLabels[labels.LabelPreStop] = string(preStop)
+	}
+	if len(secretEnv) > 0 {
+		bookkeepingLabels[labels.LabelSecretEnvKeys] = strings.Join(slices.Sorted(maps.Keys(secretEnv)), ",")
+	}
+	if emptyDirs := cm.emptyDirNames(container.Spec.Volumes); len(emptyDirs) > 0 {
+		bookkeepingLabels[labels.LabelEmptyDirs] = strings.Join(emptyDirs, ",")
+	}
+	containerLabels := labels.MergeLabels(container.GetLabels(), bookkeepingLabels)
 
 	spec := &specgen.SpecGenerator{
 		ContainerBasicConfig: specgen.ContainerBasicConfig{
-			Name:   container.GetName(),
-			Env:    env,
-			Labels: container.GetLabels(),
+			Name:        container.GetName(),
+			Pod:         container.Spec.Pod,
+			Env:         env,
+			Labels:      containerLabels,
+			Annotations: userAnnotations(container),
 		},
 		ContainerNetworkConfig: specgen.ContainerNetworkConfig{
 			PortMappings: cm.convertPortMappings(container.Spec.Ports),
@@ -387,16 +901,31 @@ func (cm *ContainerManager) buildContainerSpec(container *ContainerResource) (*s
 			HealthLogDestination: "/tmp",
 		},
 		ContainerSecurityConfig: specgen.ContainerSecurityConfig{
-			Privileged: cm.getPrivileged(container.Spec.SecurityContext),
-			CapAdd:     cm.getCapabilities(container.Spec.SecurityContext, true),
-			CapDrop:    cm.getCapabilities(container.Spec.SecurityContext, false),
+			Privileged:         cm.getPrivileged(container.Spec.SecurityContext),
+			CapAdd:             capAdd,
+			CapDrop:            capDrop,
+			ReadOnlyFilesystem: cm.getReadOnlyRootFilesystem(container.Spec.SecurityContext),
+			SeccompProfilePath: seccompProfile(container.Spec),
 		},
 	}
 
+	// Podman only reports the seccomp profile of containers carrying this annotation, which
+	// its CLI sets as well
+	if spec.SeccompProfilePath != "" {
+		if spec.Annotations == nil {
+			spec.Annotations = make(map[string]string)
+		}
+		spec.Annotations[define.InspectAnnotationSeccomp] = spec.SeccompProfilePath
+	}
+
 	// Set command and args
 	// In Podman, args are combined with command into a single Command field
-	if len(container.Spec.Command) > 0 {
-		spec.Command = container.Spec.Command
+	command, err := containerCommand(container.Spec)
+	if err != nil {
+		return nil, err
+	}
+	if len(command) > 0 {
+		spec.Command = command
 		// Append args to command
 		if len(container.Spec.Args) > 0 {
 			spec.Command = append(spec.Command, container.Spec.Args...)
@@ -406,10 +935,35 @@ func (cm *ContainerManager) buildContainerSpec(container *ContainerResource) (*s
 		spec.Command = container.Spec.Args
 	}
 
-	// Set UID/GID
-	if container.Spec.UID != nil {
-		spec.User = strconv.FormatInt(*container.Spec.UID, 10)
+	// Attach networks with their per-network options
+	networks, err := cm.convertNetworkAttachments(container.Spec.Networks)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert network attachments: %w", err)
+	}
+	spec.Networks = networks
+
+	// Set the user and supplementary groups
+	spec.User = containerUser(container.Spec)
+	spec.Groups = container.Spec.GroupAdd
+
+	if err := applyNamespaceModes(spec, container.Spec); err != nil {
+		return nil, err
+	}
+	spec.Hostname = containerHostname(container)
+	spec.Sysctl = maps.Clone(container.Spec.Sysctl)
+	if container.Spec.DomainName != "" {
+		if spec.Sysctl == nil {
+			spec.Sysctl = make(map[string]string)
+		}
+		spec.Sysctl["kernel.domainname"] = container.Spec.DomainName
+	}
+	if err := applyPodmanFlags(spec, container); err != nil {
+		return nil, err
 	}
+	spec.Init = container.Spec.Init
+	spec.Remove = container.Spec.AutoRemove
+	spec.Stdin = container.Spec.Stdin
+	spec.Terminal = container.Spec.TTY
 
 	// Set restart policy
 	if container.Spec.RestartPolicy != "" {
@@ -419,6 +973,119 @@ func (cm *ContainerManager) buildContainerSpec(container *ContainerResource) (*s
 	return spec, nil
 }
 
+// computeContainerSpecHash returns a stable hash of the full container spec. Lists whose
+// order does not matter are sorted first so that reordering them does not change the hash.
+func computeContainerSpecHash(spec CuteContainerSpec) (string, error) {
+	normalized, err := normalizeContainerSpec(spec)
+	if err != nil {
+		return "", err
+	}
+	// The restart policy is compared separately because it can be changed without recreating
+	normalized.RestartPolicy = ""
+	return hashContainerSpec(normalized)
+}
+
+// specHashMatches reports whether hash, the spec hash a container was created with, is
+// the hash of spec. Containers created before the restart policy was left out of the
+// hash carry one that includes it, which is accepted too, so that they are not all
+// recreated once after upgrading.
+func specHashMatches(spec CuteContainerSpec, hash string) (bool, error) {
+	currentHash, err := computeContainerSpecHash(spec)
+	if err != nil {
+		return false, err
+	}
+	if currentHash == hash {
+		return true, nil
+	}
+
+	normalized, err := normalizeContainerSpec(spec)
+	if err != nil {
+		return false, err
+	}
+	legacyHash, err := hashContainerSpec(normalized)
+	if err != nil {
+		return false, err
+	}
+	return legacyHash == hash, nil
+}
+
+// normalizeContainerSpec returns spec in the form its hash is computed from
+func normalizeContainerSpec(spec CuteContainerSpec) (CuteContainerSpec, error) {
+	normalized := spec
+	normalized.Env = slices.Clone(spec.Env)
+	slices.SortStableFunc(normalized.Env, func(a, b EnvVar) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	normalized.Ports = slices.Clone(spec.Ports)
+	slices.SortStableFunc(normalized.Ports, func(a, b ContainerPort) int {
+		return strings.Compare(fmt.Sprintf("%05d/%05d/%s", a.HostPort, a.ContainerPort, strings.ToLower(a.Protocol)),
+			fmt.Sprintf("%05d/%05d/%s", b.HostPort, b.ContainerPort, strings.ToLower(b.Protocol)))
+	})
+	normalized.Volumes = slices.Clone(spec.Volumes)
+	slices.SortStableFunc(normalized.Volumes, func(a, b VolumeMount) int {
+		return strings.Compare(a.Name+":"+a.MountPath, b.Name+":"+b.MountPath)
+	})
+	normalized.GroupAdd = slices.Clone(spec.GroupAdd)
+	slices.Sort(normalized.GroupAdd)
+	// Aliases are compared separately because they can be changed without recreating, and
+	// dependencies and priority only order creation
+	normalized.DependsOn = nil
+	normalized.Priority = 0
+	normalized.Networks = slices.Clone(spec.Networks)
+	for i := range normalized.Networks {
+		normalized.Networks[i].Aliases = nil
+	}
+	normalized.Secrets = slices.Clone(spec.Secrets)
+	slices.SortStableFunc(normalized.Secrets, func(a, b SecretReference) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	for i := range normalized.Secrets {
+		normalized.Secrets[i].Items = slices.Clone(normalized.Secrets[i].Items)
+		slices.SortFunc(normalized.Secrets[i].Items, func(a, b SecretItem) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+	}
+	// A preset and the explicit capabilities it stands for create the same container
+	if spec.SecurityContext != nil {
+		capAdd, capDrop, err := resolveCapabilities(spec.SecurityContext)
+		if err != nil {
+			return CuteContainerSpec{}, err
+		}
+		securityContext := *spec.SecurityContext
+		securityContext.CapabilityPreset = ""
+		securityContext.Capabilities = nil
+		if capAdd != nil || capDrop != nil {
+			securityContext.Capabilities = &Capabilities{Add: capAdd, Drop: capDrop}
+		}
+		normalized.SecurityContext = &securityContext
+	}
+
+	return normalized, nil
+}
+
+// hashContainerSpec hashes a normalized container spec
+func hashContainerSpec(normalized CuteContainerSpec) (string, error) {
+	// encoding/json sorts map keys, so the encoding is deterministic
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// computeContainerfileHash returns a hash of the Containerfile content a build uses
+func computeContainerfileHash(build *BuildSpec) (string, error) {
+	content, err := os.ReadFile(build.ContainerfilePath())
+	if err != nil {
+		return "", fmt.Errorf("unable to read Containerfile: %w", err)
+	}
+
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
 func (cm *ContainerManager) convertEnvVars(envVars []EnvVar) map[string]string {
 	env := make(map[string]string)
 	for _, e := range envVars {
@@ -427,6 +1094,39 @@ func (cm *ContainerManager) convertEnvVars(envVars []EnvVar) map[string]string {
 	return env
 }
 
+func (cm *ContainerManager) convertNetworkAttachments(attachments []NetworkAttachment) (map[string]nettypes.PerNetworkOptions, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	networks := make(map[string]nettypes.PerNetworkOptions, len(attachments))
+	for _, attachment := range attachments {
+		options := nettypes.PerNetworkOptions{
+			Aliases: attachment.Aliases,
+		}
+
+		if attachment.StaticIP != "" {
+			ip := net.ParseIP(attachment.StaticIP)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid static IP '%s' for network '%s'", attachment.StaticIP, attachment.Name)
+			}
+			options.StaticIPs = []net.IP{ip}
+		}
+
+		if attachment.StaticMAC != "" {
+			mac, err := net.ParseMAC(attachment.StaticMAC)
+			if err != nil {
+				return nil, fmt.Errorf("invalid static MAC '%s' for network '%s': %w", attachment.StaticMAC, attachment.Name, err)
+			}
+			options.StaticMAC = nettypes.HardwareAddr(mac)
+		}
+
+		networks[attachment.Name] = options
+	}
+
+	return networks, nil
+}
+
 func (cm *ContainerManager) convertPortMappings(ports []ContainerPort) []nettypes.PortMapping {
 	var mappings []nettypes.PortMapping
 	for _, port := range ports {
@@ -440,7 +1140,60 @@ func (cm *ContainerManager) convertPortMappings(ports []ContainerPort) []nettype
 			Protocol:      strings.ToLower(protocol),
 		})
 	}
-	return mappings
+	return mappings
+}
+
+// securityContextFromInspect reads back the read-only root filesystem and seccomp
+// profile of an inspected container, or returns nil when it uses neither
+func securityContextFromInspect(hostConfig *define.InspectContainerHostConfig) *SecurityContext {
+	var secCtx SecurityContext
+	if hostConfig.ReadonlyRootfs {
+		readOnly := true
+		secCtx.ReadOnlyRootFilesystem = &readOnly
+	}
+	for _, option := range hostConfig.SecurityOpt {
+		if profile, found := strings.CutPrefix(option, "seccomp="); found {
+			secCtx.SeccompProfile = profile
+		}
+	}
+
+	if secCtx.ReadOnlyRootFilesystem == nil && secCtx.SeccompProfile == "" {
+		return nil
+	}
+	return &secCtx
+}
+
+// convertPortBindings returns the published ports of an inspected container
+func convertPortBindings(inspect *define.InspectContainerData) []ContainerPort {
+	if inspect.HostConfig == nil || inspect.HostConfig.PortBindings == nil {
+		return nil
+	}
+
+	var ports []ContainerPort
+	for portProto, bindings := range inspect.HostConfig.PortBindings {
+		parts := strings.Split(portProto, "/")
+		if len(parts) != 2 {
+			continue
+		}
+		containerPort, err := strconv.ParseUint(parts[0], 10, 16)
+		if err != nil {
+			continue
+		}
+		protocol := strings.ToUpper(parts[1])
+
+		for _, binding := range bindings {
+			hostPort, err := strconv.ParseUint(binding.HostPort, 10, 16)
+			if err != nil {
+				continue
+			}
+			ports = append(ports, ContainerPort{
+				ContainerPort: uint16(containerPort),
+				HostPort:      uint16(hostPort),
+				Protocol:      protocol,
+			})
+		}
+	}
+	return ports
 }
 
 func (cm *ContainerManager) convertVolumeMounts(volumes []VolumeMount, container *ContainerResource) ([]specs.Mount, error) {
@@ -488,6 +1241,21 @@ func (cm *ContainerManager) convertVolumeMounts(volumes []VolumeMount, container
 	return mounts, nil
 }
 
+// emptyDirNames returns the sorted names of the emptyDir volumes among the mounts,
+// each listed once
+func (cm *ContainerManager) emptyDirNames(volumes []VolumeMount) []string {
+	var names []string
+	for _, vol := range volumes {
+		volumeResource, err := cm.resolveVolumeReference(vol.Name)
+		if err != nil || volumeResource.Spec.Type != VolumeTypeEmptyDir {
+			continue
+		}
+		names = append(names, vol.Name)
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
 // validateVolumeDependencies validates that all referenced volumes exist
 func (cm *ContainerManager) validateVolumeDependencies(container *ContainerResource) error {
 	if cm.registry == nil {
@@ -501,7 +1269,7 @@ func (cm *ContainerManager) validateVolumeDependencies(container *ContainerResou
 		}
 
 		// Check if volume exists in registry
-		_, exists := cm.registry.GetResource(vol.Name)
+		_, exists := cm.registry.GetResourceByTypeName(ResourceTypeVolume, vol.Name)
 		if !exists {
 			return fmt.Errorf("referenced volume '%s' does not exist", vol.Name)
 		}
@@ -511,8 +1279,8 @@ func (cm *ContainerManager) validateVolumeDependencies(container *ContainerResou
 }
 
 // prepareVolumeMounts prepares volume paths and permissions before container creation
-func (cm *ContainerManager) prepareVolumeMounts(container *ContainerResource) error {
-	for _, vol := range container.Spec.Volumes {
+func (cm *ContainerManager) prepareVolumeMounts(volumes []VolumeMount) error {
+	for _, vol := range volumes {
 		// Resolve volume reference
 		volumeResource, err := cm.resolveVolumeReference(vol.Name)
 		if err != nil {
@@ -527,13 +1295,13 @@ func (cm *ContainerManager) prepareVolumeMounts(container *ContainerResource) er
 
 		// Ensure volume path exists
 		if err := cm.pathManager.EnsureVolumePath(pathInfo, volumeResource); err != nil {
-			return fmt.Errorf("failed to ensure path for volume '%s': %w", vol.Name, err)
+			return fmt.Errorf("failed to ensure path for volume '%s': %w", vol.Name, cm.diagnoseVolumeError(err, volumeResource, &vol, pathInfo.SourcePath))
 		}
 
 		// Manage host directory ownership if needed
 		if cm.permissionMgr != nil && volumeResource.Spec.Type == VolumeTypeHostPath {
 			if err := cm.permissionMgr.ManageHostDirectoryOwnership(pathInfo.SourcePath, volumeResource); err != nil {
-				return fmt.Errorf("failed to manage ownership for volume '%s': %w", vol.Name, err)
+				return fmt.Errorf("failed to manage ownership for volume '%s': %w", vol.Name, cm.diagnoseVolumeError(err, volumeResource, &vol, pathInfo.SourcePath))
 			}
 		}
 	}
@@ -541,13 +1309,31 @@ func (cm *ContainerManager) prepareVolumeMounts(container *ContainerResource) er
 	return nil
 }
 
+// diagnoseVolumeError turns a failure to prepare a volume into a VolumePermissionError,
+// whose message suggests how to fix it
+func (cm *ContainerManager) diagnoseVolumeError(err error, volume *VolumeResource, mount *VolumeMount, hostPath string) error {
+	if cm.permissionMgr == nil {
+		return err
+	}
+	return cm.permissionMgr.DiagnosePermissionError(err, volume, mount, hostPath)
+}
+
+// podMounts prepares the volumes a pod declares and converts them into mounts for the
+// pod's infra container
+func (cm *ContainerManager) podMounts(volumes []VolumeMount) ([]specs.Mount, error) {
+	if err := cm.prepareVolumeMounts(volumes); err != nil {
+		return nil, fmt.Errorf("failed to prepare volume mounts: %w", err)
+	}
+	return cm.convertVolumeMounts(volumes, nil)
+}
+
 // resolveVolumeReference resolves a volume name to a VolumeResource
 func (cm *ContainerManager) resolveVolumeReference(volumeName string) (*VolumeResource, error) {
 	if cm.registry == nil {
 		return nil, fmt.Errorf("no registry available to resolve volume '%s'", volumeName)
 	}
 
-	resource, exists := cm.registry.GetResource(volumeName)
+	resource, exists := cm.registry.GetResourceByTypeName(ResourceTypeVolume, volumeName)
 	if !exists {
 		return nil, fmt.Errorf("volume '%s' not found in registry", volumeName)
 	}
@@ -581,6 +1367,16 @@ func (cm *ContainerManager) buildMountOptions(volume *VolumeResource, mount *Vol
 		options = append(options, "rw")
 	}
 
+	// Propagation and recursive read-only
+	if mount.MountOptions != nil {
+		if mount.MountOptions.Propagation != "" {
+			options = append(options, mount.MountOptions.Propagation)
+		}
+		if mount.MountOptions.RecursiveReadOnly {
+			options = append(options, "rro")
+		}
+	}
+
 	// Use permission manager to build additional options
 	if cm.permissionMgr != nil {
 		// Determine if this volume is shared (used by multiple containers)
@@ -667,7 +1463,7 @@ func (cm *ContainerManager) processSecrets(secrets []SecretReference) ([]specgen
 			secretMounts = append(secretMounts, secretMount)
 		}
 
-		if secretRef.Path != "" {
+		if secretRef.Path != "" && len(secretRef.Items) == 0 {
 			// Mount secret as files
 			secretMount := specgen.Secret{
 				Source: secretRef.Name,
@@ -676,11 +1472,120 @@ func (cm *ContainerManager) processSecrets(secrets []SecretReference) ([]specgen
 			}
 			secretMounts = append(secretMounts, secretMount)
 		}
+
+		if len(secretRef.Items) > 0 {
+			if err := cm.validateSecretItems(secretRef); err != nil {
+				return nil, err
+			}
+		}
+		for _, item := range secretRef.Items {
+			secretMounts = append(secretMounts, specgen.Secret{
+				Source: secretRef.Name,
+				Target: path.Join(secretRef.Path, item.Path),
+				Mode:   uint32(secretItemMode(item)),
+			})
+		}
 	}
 
 	return secretMounts, nil
 }
 
+// validateSecretItems checks the keys a secret reference's items select against the
+// secret in the registry. Podman stores a secret as a single value, so a key can only
+// be mounted on its own when it is the only key of the secret. Without a registry the
+// items are mounted unchecked.
+func (cm *ContainerManager) validateSecretItems(secretRef SecretReference) error {
+	if cm.registry == nil {
+		return nil
+	}
+
+	resource, exists := cm.registry.GetResourceByTypeName(ResourceTypeSecret, secretRef.Name)
+	if !exists {
+		return fmt.Errorf("secret '%s' not found in registry", secretRef.Name)
+	}
+	secret, ok := resource.(*SecretResource)
+	if !ok {
+		return fmt.Errorf("resource '%s' is not a secret", secretRef.Name)
+	}
+
+	keys := secret.Keys()
+	for _, item := range secretRef.Items {
+		if !slices.Contains(keys, item.Key) {
+			return fmt.Errorf("secret '%s' has no key '%s'", secretRef.Name, item.Key)
+		}
+	}
+	if len(keys) > 1 {
+		return fmt.Errorf("secret '%s' has %d keys; Podman stores a secret as a single value, so items can only mount the key of a single-key secret", secretRef.Name, len(keys))
+	}
+	return nil
+}
+
+// secretEnvVars resolves the secrets referenced with env or envFrom through the registry
+// and returns each of their keys, with the reference's prefix, as an environment variable.
+// Variables the spec sets explicitly are left out, since those take precedence. Without
+// a registry the secrets referenced with env are only mounted as Podman env secrets, and
+// envFrom cannot be resolved at all.
+func (cm *ContainerManager) secretEnvVars(spec CuteContainerSpec) (map[string]string, error) {
+	env := make(map[string]string)
+	if cm.registry == nil {
+		if len(spec.EnvFrom) > 0 {
+			return nil, fmt.Errorf("no registry available to resolve envFrom secret '%s'", spec.EnvFrom[0].SecretName)
+		}
+		return env, nil
+	}
+
+	expand := func(name, prefix string) error {
+		resource, exists := cm.registry.GetResourceByTypeName(ResourceTypeSecret, name)
+		if !exists {
+			return fmt.Errorf("secret '%s' not found in registry", name)
+		}
+		secret, ok := resource.(*SecretResource)
+		if !ok {
+			return fmt.Errorf("resource '%s' is not a secret", name)
+		}
+
+		data, err := secret.ResolveData()
+		if err != nil {
+			return fmt.Errorf("unable to resolve secret '%s': %w", name, err)
+		}
+		for key, value := range data {
+			env[prefix+key] = string(value)
+		}
+		return nil
+	}
+
+	for _, secretRef := range spec.Secrets {
+		if !secretRef.Env {
+			continue
+		}
+		if err := expand(secretRef.Name, secretRef.Prefix); err != nil {
+			return nil, err
+		}
+	}
+	for _, source := range spec.EnvFrom {
+		if err := expand(source.SecretName, source.Prefix); err != nil {
+			return nil, fmt.Errorf("envFrom: %w", err)
+		}
+	}
+
+	for _, explicit := range spec.Env {
+		delete(env, explicit.Name)
+	}
+
+	return env, nil
+}
+
+// sameSecretEnvKeys reports whether the environment variables the desired container
+// would get from its secrets are the ones the actual container was created with.
+// Secrets that cannot be resolved count as changed, so the update reports why.
+func (cm *ContainerManager) sameSecretEnvKeys(desired, actual *ContainerResource) bool {
+	secretEnv, err := cm.secretEnvVars(desired.Spec)
+	if err != nil {
+		return false
+	}
+	return strings.Join(slices.Sorted(maps.Keys(secretEnv)), ",") == actual.GetAnnotations()[labels.LabelSecretEnvKeys]
+}
+
 func (cm *ContainerManager) getMountOptions(readOnly bool) []string {
 	if readOnly {
 		return []string{"ro"}
@@ -695,14 +1600,60 @@ func (cm *ContainerManager) getPrivileged(secCtx *SecurityContext) *bool {
 	return nil
 }
 
-func (cm *ContainerManager) getCapabilities(secCtx *SecurityContext, add bool) []string {
-	if secCtx == nil || secCtx.Capabilities == nil {
-		return nil
+func (cm *ContainerManager) getReadOnlyRootFilesystem(secCtx *SecurityContext) *bool {
+	if secCtx != nil && secCtx.ReadOnlyRootFilesystem != nil {
+		return secCtx.ReadOnlyRootFilesystem
+	}
+	return nil
+}
+
+// readOnlyRootFilesystem reports whether the container's root filesystem is read-only
+func readOnlyRootFilesystem(spec CuteContainerSpec) bool {
+	return spec.SecurityContext != nil && spec.SecurityContext.ReadOnlyRootFilesystem != nil && *spec.SecurityContext.ReadOnlyRootFilesystem
+}
+
+// ownUTSNamespace reports whether the container gets a UTS namespace of its own, rather
+// than sharing the host's or another container's
+func ownUTSNamespace(spec CuteContainerSpec) bool {
+	return spec.UtsMode == "" || spec.UtsMode == "private"
+}
+
+// containerHostname returns the hostname the container is created with: its own, or its
+// name so that it is stable across recreations. Pod members usually share the pod's UTS
+// namespace, so they only get a hostname when they set one.
+func containerHostname(container *ContainerResource) string {
+	if container.Spec.Hostname != "" || container.Spec.Pod != "" || !ownUTSNamespace(container.Spec) {
+		return container.Spec.Hostname
 	}
-	if add {
-		return secCtx.Capabilities.Add
+	return container.GetName()
+}
+
+// initEnabled reports whether the container runs an init process as PID 1
+func initEnabled(spec CuteContainerSpec) bool {
+	return spec.Init != nil && *spec.Init
+}
+
+// autoRemove reports whether the container is removed once it exits
+func autoRemove(spec CuteContainerSpec) bool {
+	return spec.AutoRemove != nil && *spec.AutoRemove
+}
+
+// stdinOpen reports whether the container keeps its stdin open
+func stdinOpen(spec CuteContainerSpec) bool {
+	return spec.Stdin != nil && *spec.Stdin
+}
+
+// ttyEnabled reports whether the container is given a pseudo-terminal
+func ttyEnabled(spec CuteContainerSpec) bool {
+	return spec.TTY != nil && *spec.TTY
+}
+
+// seccompProfile returns the seccomp profile the container runs with, or "" for Podman's default
+func seccompProfile(spec CuteContainerSpec) string {
+	if spec.SecurityContext == nil {
+		return ""
 	}
-	return secCtx.Capabilities.Drop
+	return spec.SecurityContext.SeccompProfile
 }
 
 func (cm *ContainerManager) removeContainer(ctx context.Context, client podman.PodmanClient, name string) error {
@@ -712,7 +1663,7 @@ func (cm *ContainerManager) removeContainer(ctx context.Context, client podman.P
 	// Stop container first
 	if err := client.StopContainer(timeout, name, 15); err != nil {
 		// Continue with removal even if stop fails
-		fmt.Printf("Warning: failed to stop container %s: %v\n", name, err)
+		loggerOrDefault(cm.logger).Warn("failed to stop container", "container", name, "error", err)
 	}
 
 	// Remove container
@@ -723,8 +1674,344 @@ func (cm *ContainerManager) removeContainer(ctx context.Context, client podman.P
 	return nil
 }
 
+// runPostStart runs the postStart hook of a container that was just started
+func (cm *ContainerManager) runPostStart(ctx context.Context, client podman.PodmanClient, container *ContainerResource) error {
+	exitCode, err := client.ExecContainer(ctx, container.GetName(), container.Spec.Lifecycle.PostStart.Command)
+	if err != nil {
+		return fmt.Errorf("unable to run postStart hook: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("postStart hook exited with code %d", exitCode)
+	}
+
+	return nil
+}
+
+// runPreStop runs the preStop hook of a container about to be stopped. It is best-effort:
+// failures are logged and the container is stopped regardless.
+func (cm *ContainerManager) runPreStop(ctx context.Context, client podman.PodmanClient, container *ContainerResource) {
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Second)
+	defer cancel()
+
+	exitCode, err := client.ExecContainer(timeout, container.GetName(), container.Spec.Lifecycle.PreStop.Command)
+	if err != nil {
+		loggerOrDefault(cm.logger).Warn("failed to run preStop hook", "container", container.GetName(), "error", err)
+	} else if exitCode != 0 {
+		loggerOrDefault(cm.logger).Warn("preStop hook failed", "container", container.GetName(), "exitCode", exitCode)
+	}
+}
+
+func (cm *ContainerManager) unpauseContainer(ctx context.Context, name string) error {
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	if err := podmanClient.UnpauseContainer(ctx, name); err != nil {
+		return fmt.Errorf("unable to unpause container %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// restartContainer restarts a container, which also starts a stopped one. A restart
+// nonce is recorded in the container's environment first, since Podman cannot change the
+// annotation it was created with.
+func (cm *ContainerManager) restartContainer(ctx context.Context, name, nonce string) error {
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	if nonce != "" {
+		update := podman.ContainerUpdate{Env: []string{restartNonceEnv + "=" + nonce}}
+		if err := podmanClient.UpdateContainer(ctx, name, update); err != nil {
+			return fmt.Errorf("unable to record restart nonce of container %s: %w", name, err)
+		}
+	}
+
+	if err := podmanClient.RestartContainer(ctx, name, 15); err != nil {
+		return fmt.Errorf("unable to restart container %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// needsRestart reports whether a container read back stopped should be running again,
+// because its desired restart policy keeps it running or because it was never started,
+// such as when cutepod was interrupted between creating and starting it
+func needsRestart(desired, actual *ContainerResource) bool {
+	state := actual.GetAnnotations()[labels.AnnotationStopped]
+	return state == neverStartedState || (state != "" && runningRestartPolicies[desired.Spec.RestartPolicy])
+}
+
+// neverStartedState is the Podman state of a container that was created but not started
+const neverStartedState = "created"
+
+// isContainerPaused reports whether a container read back from Podman is paused
+func isContainerPaused(container *ContainerResource) bool {
+	return container.GetAnnotations()[labels.AnnotationPaused] == "true"
+}
+
+// isPodmanAnnotation reports whether Podman set the annotation rather than the manifest
+func isPodmanAnnotation(key string) bool {
+	for _, prefix := range podmanAnnotationPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// forcesRecreate reports whether a container asks to be recreated on every update
+func forcesRecreate(container *ContainerResource) bool {
+	return container.GetAnnotations()[labels.AnnotationForceRecreate] == "true"
+}
+
+// restartNonceEnv holds the restart nonce a container was last restarted for, which
+// supersedes the nonce annotation it was created with
+const restartNonceEnv = "CUTEPOD_RESTART_NONCE"
+
+// restartRequested reports whether the desired container carries a restart nonce other
+// than the one the actual container was created or last restarted with. Removing the
+// nonce restarts nothing.
+func restartRequested(desired, actual *ContainerResource) bool {
+	nonce := desired.GetAnnotations()[labels.AnnotationRestartNonce]
+	return nonce != "" && nonce != actual.GetAnnotations()[labels.AnnotationRestartNonce]
+}
+
+// annotationsForceRecreate reports whether recreation on annotation changes is enabled
+// and the annotations changed, leaving out the ignored keys
+func (cm *ContainerManager) annotationsForceRecreate(desired, actual *ContainerResource) bool {
+	return cm.recreateOnAnnotationChange && !maps.Equal(
+		withoutMatchingKeys(userAnnotations(desired), cm.ignoredLabelKeys),
+		withoutMatchingKeys(userAnnotations(actual), cm.ignoredLabelKeys))
+}
+
+// userAnnotations returns a container's annotations without cutepod's bookkeeping, or
+// nil if it has none
+func userAnnotations(container *ContainerResource) map[string]string {
+	var annotations map[string]string
+	for key, value := range container.GetAnnotations() {
+		if slices.Contains(bookkeepingAnnotations, key) {
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string)
+		}
+		annotations[key] = value
+	}
+	return annotations
+}
+
 // Comparison helper methods
 
+// compareNetworkAliases reports whether every desired network attachment has the same
+// set of aliases on the actual container
+func (cm *ContainerManager) compareNetworkAliases(desired, actual []NetworkAttachment) bool {
+	actualAliases := make(map[string][]string, len(actual))
+	for _, attachment := range actual {
+		actualAliases[attachment.Name] = attachment.Aliases
+	}
+
+	for _, attachment := range desired {
+		if !cm.compareAliasSets(attachment.Aliases, actualAliases[attachment.Name]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (cm *ContainerManager) compareAliasSets(desired, actual []string) bool {
+	desiredSorted := slices.Clone(desired)
+	slices.Sort(desiredSorted)
+	actualSorted := slices.Clone(actual)
+	slices.Sort(actualSorted)
+	return slices.Equal(desiredSorted, actualSorted)
+}
+
+// canUpdateInPlace reports whether the stored spec hash still matches, meaning that any
+// difference is limited to what Podman can change on an existing container: the restart
+// policy and network aliases. Labels and annotations that are not configured to trigger
+// a recreate never count as a difference, since Podman cannot change them: a container
+// keeps the labels it was created with until something else recreates it. A changed
+// restart nonce only restarts the container, while a container that forces recreation
+// is never updated in place.
+func (cm *ContainerManager) canUpdateInPlace(desired, actual *ContainerResource) (bool, error) {
+	actualHash := actual.GetAnnotations()[labels.LabelSpecHash]
+	if actualHash == "" {
+		return false, nil
+	}
+
+	if forcesRecreate(desired) {
+		return false, nil
+	}
+
+	if cm.annotationsForceRecreate(desired, actual) {
+		return false, nil
+	}
+
+	hashMatches, err := specHashMatches(desired.Spec, actualHash)
+	if err != nil {
+		return false, fmt.Errorf("unable to hash desired container spec: %w", err)
+	}
+
+	return hashMatches && cm.sameSecretEnvKeys(desired, actual) && samePodmanFlags(desired, actual), nil
+}
+
+// updateInPlace applies the differences canUpdateInPlace allows to the existing container.
+// A paused container is resumed, and a stopped one or one whose restart nonce changed is
+// restarted, which keeps its writable layer.
+func (cm *ContainerManager) updateInPlace(ctx context.Context, desired, actual *ContainerResource) error {
+	if isContainerPaused(actual) {
+		if err := cm.unpauseContainer(ctx, desired.GetName()); err != nil {
+			return err
+		}
+	}
+
+	if !sameRestartPolicy(desired.Spec.RestartPolicy, actual.Spec.RestartPolicy) {
+		connectedClient := podman.NewConnectedClient(cm.client)
+		defer connectedClient.Close()
+
+		podmanClient, err := connectedClient.GetClient(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to connect to podman: %w", err)
+		}
+
+		update := podman.ContainerUpdate{RestartPolicy: restartPolicyName(desired.Spec.RestartPolicy)}
+		if err := podmanClient.UpdateContainer(ctx, desired.GetName(), update); err != nil {
+			return fmt.Errorf("unable to update restart policy of container %s: %w", desired.GetName(), err)
+		}
+	}
+
+	if restartRequested(desired, actual) {
+		if err := cm.restartContainer(ctx, desired.GetName(), desired.GetAnnotations()[labels.AnnotationRestartNonce]); err != nil {
+			return err
+		}
+	} else if needsRestart(desired, actual) {
+		if err := cm.restartContainer(ctx, desired.GetName(), ""); err != nil {
+			return err
+		}
+	}
+
+	return cm.reconnectNetworks(ctx, desired, actual)
+}
+
+// restartPolicyName returns the restart policy Podman reports for policy, which calls
+// the absence of a policy "no"
+func restartPolicyName(policy string) string {
+	if policy == "" {
+		return "no"
+	}
+	return policy
+}
+
+// sameRestartPolicy reports whether two restart policies are the same
+func sameRestartPolicy(a, b string) bool {
+	return restartPolicyName(a) == restartPolicyName(b)
+}
+
+// containerCommand returns the command the container runs, split from its commandString
+// when that is set instead of the command
+func containerCommand(spec CuteContainerSpec) ([]string, error) {
+	if spec.CommandString == "" {
+		return spec.Command, nil
+	}
+	if len(spec.Command) > 0 {
+		return nil, fmt.Errorf("commandString and command are mutually exclusive")
+	}
+
+	command, err := splitCommandString(spec.CommandString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commandString: %w", err)
+	}
+	return command, nil
+}
+
+// containerUser returns the user the container runs as, in Podman's "user[:group]" form.
+// The name forms are preferred over the numeric uid and gid, and a group is only given
+// together with a user.
+func containerUser(spec CuteContainerSpec) string {
+	user := spec.RunAsUser
+	if user == "" && spec.UID != nil {
+		user = strconv.FormatInt(*spec.UID, 10)
+	}
+	if user == "" || strings.Contains(user, ":") {
+		return user
+	}
+
+	group := spec.RunAsGroup
+	if group == "" && spec.GID != nil {
+		group = strconv.FormatInt(*spec.GID, 10)
+	}
+	if group == "" {
+		return user
+	}
+	return user + ":" + group
+}
+
+// sameGroups reports whether two lists of supplementary groups hold the same groups
+func sameGroups(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// reconnectNetworks reconnects the container to every network whose aliases changed,
+// with its static IP and MAC address
+func (cm *ContainerManager) reconnectNetworks(ctx context.Context, desired, actual *ContainerResource) error {
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	networks, err := cm.convertNetworkAttachments(desired.Spec.Networks)
+	if err != nil {
+		return err
+	}
+
+	actualAliases := make(map[string][]string, len(actual.Spec.Networks))
+	for _, attachment := range actual.Spec.Networks {
+		actualAliases[attachment.Name] = attachment.Aliases
+	}
+
+	for _, attachment := range desired.Spec.Networks {
+		if cm.compareAliasSets(attachment.Aliases, actualAliases[attachment.Name]) {
+			continue
+		}
+
+		if err := podmanClient.DisconnectContainerFromNetwork(ctx, desired.GetName(), attachment.Name); err != nil {
+			return fmt.Errorf("unable to disconnect container from network %s: %w", attachment.Name, err)
+		}
+
+		if err := podmanClient.ConnectContainerToNetwork(ctx, desired.GetName(), attachment.Name, networks[attachment.Name]); err != nil {
+			return fmt.Errorf("unable to reconnect container to network %s: %w", attachment.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (cm *ContainerManager) compareNetworks(desired, actual []NetworkAttachment) bool {
+	return slices.EqualFunc(desired, actual, func(a, b NetworkAttachment) bool {
+		return a.Name == b.Name &&
+			a.StaticIP == b.StaticIP &&
+			strings.EqualFold(a.StaticMAC, b.StaticMAC) &&
+			slices.Equal(a.Aliases, b.Aliases)
+	})
+}
+
 func (cm *ContainerManager) compareEnvVars(desired, actual []EnvVar) bool {
 	if len(desired) != len(actual) {
 		return false
@@ -845,7 +2132,8 @@ func (cm *ContainerManager) compareMountOptions(desired, actual *VolumeMountOpti
 		return false
 	}
 
-	// Compare SELinux labels
+	// Compare SELinux labels. Full contexts, propagation and recursive read-only are not
+	// read back from Podman, so changes to them are only caught by the spec hash.
 	if desired.SELinuxLabel != actual.SELinuxLabel {
 		return false
 	}
@@ -902,7 +2190,10 @@ func (cm *ContainerManager) compareSecrets(desired, actual []SecretReference) bo
 		if !exists {
 			return false
 		}
-		if desiredSecret.Env != actualSecret.Env || desiredSecret.Path != actualSecret.Path {
+		if desiredSecret.Env != actualSecret.Env || desiredSecret.Prefix != actualSecret.Prefix || desiredSecret.Path != actualSecret.Path {
+			return false
+		}
+		if !slices.EqualFunc(desiredSecret.Items, actualSecret.Items, sameSecretItem) {
 			return false
 		}
 	}
@@ -910,6 +2201,18 @@ func (cm *ContainerManager) compareSecrets(desired, actual []SecretReference) bo
 	return true
 }
 
+func sameSecretItem(a, b SecretItem) bool {
+	return a.Key == b.Key && a.Path == b.Path && secretItemMode(a) == secretItemMode(b)
+}
+
+// secretItemMode returns the file mode an item is mounted with
+func secretItemMode(item SecretItem) int32 {
+	if item.Mode == nil {
+		return 0644
+	}
+	return *item.Mode
+}
+
 // GetPodmanURI returns the Podman socket URI
 func GetPodmanURI() string {
 	if env, exists := os.LookupEnv("PODMAN_SOCK"); exists {
@@ -917,3 +2220,8 @@ func GetPodmanURI() string {
 	}
 	return "unix:/run/user/1000/podman/podman.sock"
 }
+
+// GetPodmanIdentity returns the SSH identity file used for ssh:// Podman URIs
+func GetPodmanIdentity() string {
+	return os.Getenv("PODMAN_SSH_IDENTITY")
+}
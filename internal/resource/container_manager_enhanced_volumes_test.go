@@ -1,6 +1,9 @@
 package resource
 
 import (
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 
 	"cutepod/internal/podman"
@@ -154,6 +157,31 @@ func TestContainerManager_EnhancedVolumeIntegration(t *testing.T) {
 		}
 	})
 
+	// Test propagation and recursive read-only translation
+	t.Run("BuildMountOptions_Propagation", func(t *testing.T) {
+		mount := &VolumeMount{
+			Name:      "test-hostpath",
+			MountPath: "/host",
+			ReadOnly:  true,
+			MountOptions: &VolumeMountOptions{
+				Propagation:       "rshared",
+				RecursiveReadOnly: true,
+			},
+		}
+
+		options, err := cm.buildMountOptions(hostPathVolume, mount, container, nil)
+		if err != nil {
+			t.Fatalf("Building mount options failed: %v", err)
+		}
+
+		if !containsString(options, "rshared") {
+			t.Errorf("Expected 'rshared' propagation option, got %v", options)
+		}
+		if !containsString(options, "rro") {
+			t.Errorf("Expected 'rro' option for recursive read-only mount, got %v", options)
+		}
+	})
+
 	// Test volume dependency validation with missing volume
 	t.Run("ValidateVolumeDependencies_MissingVolume", func(t *testing.T) {
 		containerWithMissingVol := NewContainerResource()
@@ -228,6 +256,16 @@ func TestContainerManager_VolumeComparison(t *testing.T) {
 		if cm.compareVolumes(desired, actual) {
 			t.Error("Expected volumes to be different due to SELinux label")
 		}
+
+		// Propagation, recursive read-only and full SELinux contexts are not read back
+		// from Podman, so they are left to the spec hash
+		actual[0].MountOptions.SELinuxLabel = "z"
+		desired[0].MountOptions.Propagation = "rslave"
+		desired[0].MountOptions.RecursiveReadOnly = true
+		desired[0].MountOptions.SELinuxContext = "system_u:object_r:container_file_t:s0"
+		if !cm.compareVolumes(desired, actual) {
+			t.Error("Expected options Podman does not report to be ignored")
+		}
 	})
 }
 
@@ -240,3 +278,26 @@ func containsString(slice []string, item string) bool {
 	}
 	return false
 }
+
+func TestContainerManager_DiagnoseVolumeError(t *testing.T) {
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+	cm.permissionMgr = &VolumePermissionManager{seLinuxEnabled: true}
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	mount := &VolumeMount{Name: "data", MountPath: "/data"}
+	cause := errors.New("mkdir /srv/data: permission denied")
+
+	err := fmt.Errorf("failed to ensure path for volume 'data': %w", cm.diagnoseVolumeError(cause, volume, mount, "/srv/data"))
+
+	var permissionErr *VolumePermissionError
+	if !errors.As(err, &permissionErr) || permissionErr.ErrorType != SELinuxDenied {
+		t.Fatalf("Expected an SELinux diagnosis, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "seLinuxLabel: 'z'") {
+		t.Errorf("Expected the SELinux suggestion in the error, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected the original error to stay in the chain")
+	}
+}
@@ -4,7 +4,14 @@ import (
 	"context"
 	"cutepod/internal/labels"
 	"cutepod/internal/podman"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/containers/podman/v5/pkg/specgen"
 )
@@ -103,6 +110,1167 @@ func TestContainerManager_CompareResources(t *testing.T) {
 	}
 }
 
+func TestContainerManager_CompareResourcesUsesSpecHash(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Env = []EnvVar{
+		{Name: "ENV1", Value: "value1"},
+		{Name: "ENV2", Value: "value2"},
+	}
+
+	if err := cm.CreateResource(context.Background(), container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	actual, err := cm.GetActualState(context.Background(), "chart-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d", len(actual))
+	}
+
+	actualContainer := actual[0].(*ContainerResource)
+	if actualContainer.GetAnnotations()[labels.LabelSpecHash] == "" {
+		t.Fatal("Expected the spec hash to be read back from the container labels")
+	}
+	if _, exists := actualContainer.GetLabels()[labels.LabelSpecHash]; exists {
+		t.Error("Expected the spec hash label to be kept out of the resource labels")
+	}
+
+	// Reordering env vars does not change the spec
+	reordered := NewContainerResource()
+	reordered.ObjectMeta.Name = "test-container"
+	reordered.Spec.Image = "nginx:latest"
+	reordered.Spec.Env = []EnvVar{
+		{Name: "ENV2", Value: "value2"},
+		{Name: "ENV1", Value: "value1"},
+	}
+
+	match, err := cm.CompareResources(reordered, actualContainer)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Error("Expected reordered env vars to match the stored spec hash")
+	}
+
+	// Sysctl is not part of the field-by-field comparison, but the hash catches it
+	changed := NewContainerResource()
+	changed.ObjectMeta.Name = "test-container"
+	changed.Spec.Image = "nginx:latest"
+	changed.Spec.Env = container.Spec.Env
+	changed.Spec.Sysctl = map[string]string{"net.core.somaxconn": "1024"}
+
+	match, err = cm.CompareResources(changed, actualContainer)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Error("Expected a sysctl change to be detected through the spec hash")
+	}
+}
+
+func TestContainerManager_BuildContainerSpecNetworks(t *testing.T) {
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Networks = []NetworkAttachment{
+		{Name: "web-network"},
+		{Name: "db-network", StaticIP: "172.21.0.10", StaticMAC: "02:42:ac:15:00:0a", Aliases: []string{"web"}},
+	}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+
+	if len(spec.Networks) != 2 {
+		t.Fatalf("Expected 2 networks, got %d", len(spec.Networks))
+	}
+
+	if _, exists := spec.Networks["web-network"]; !exists {
+		t.Error("Expected web-network to be attached")
+	}
+
+	options := spec.Networks["db-network"]
+	if len(options.StaticIPs) != 1 || options.StaticIPs[0].String() != "172.21.0.10" {
+		t.Errorf("Expected static IP 172.21.0.10, got %v", options.StaticIPs)
+	}
+	if mac := net.HardwareAddr(options.StaticMAC).String(); mac != "02:42:ac:15:00:0a" {
+		t.Errorf("Expected static MAC 02:42:ac:15:00:0a, got %s", mac)
+	}
+	if len(options.Aliases) != 1 || options.Aliases[0] != "web" {
+		t.Errorf("Expected aliases [web], got %v", options.Aliases)
+	}
+
+	// A changed static IP must be detected by the field comparison
+	changed := NewContainerResource()
+	changed.ObjectMeta.Name = "test-container"
+	changed.Spec.Image = "nginx:latest"
+	changed.Spec.Networks = []NetworkAttachment{
+		{Name: "web-network"},
+		{Name: "db-network", StaticIP: "172.21.0.11", StaticMAC: "02:42:ac:15:00:0a", Aliases: []string{"web"}},
+	}
+
+	match, err := cm.CompareResources(changed, container)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Error("Expected a static IP change to be detected")
+	}
+}
+
+func TestContainerManager_BuildContainerSpecUser(t *testing.T) {
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+	uid, gid := int64(1000), int64(2000)
+
+	tests := []struct {
+		name     string
+		spec     CuteContainerSpec
+		expected string
+	}{
+		{name: "numeric uid", spec: CuteContainerSpec{UID: &uid}, expected: "1000"},
+		{name: "numeric uid and gid", spec: CuteContainerSpec{UID: &uid, GID: &gid}, expected: "1000:2000"},
+		{name: "user and group names", spec: CuteContainerSpec{RunAsUser: "appuser", RunAsGroup: "appgroup"}, expected: "appuser:appgroup"},
+		{name: "combined form passed through", spec: CuteContainerSpec{RunAsUser: "1000:1000"}, expected: "1000:1000"},
+		{name: "names preferred over ids", spec: CuteContainerSpec{UID: &uid, GID: &gid, RunAsUser: "appuser"}, expected: "appuser:2000"},
+		{name: "group name with numeric uid", spec: CuteContainerSpec{UID: &uid, RunAsGroup: "appgroup"}, expected: "1000:appgroup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewContainerResource()
+			container.ObjectMeta.Name = "test-container"
+			container.Spec = tt.spec
+			container.Spec.Image = "nginx:latest"
+			container.Spec.GroupAdd = []string{"audio", "video"}
+
+			spec, err := cm.buildContainerSpec(container)
+			if err != nil {
+				t.Fatalf("buildContainerSpec failed: %v", err)
+			}
+			if spec.User != tt.expected {
+				t.Errorf("Expected user %q, got %q", tt.expected, spec.User)
+			}
+			if !slices.Equal(spec.Groups, []string{"audio", "video"}) {
+				t.Errorf("Expected supplementary groups [audio video], got %v", spec.Groups)
+			}
+		})
+	}
+
+	// Without the spec hash the user and groups are compared field by field
+	actual := NewContainerResource()
+	actual.Spec.Image = "nginx:latest"
+	actual.Spec.RunAsUser = "1000:2000"
+	actual.Spec.GroupAdd = []string{"video", "audio"}
+
+	desired := NewContainerResource()
+	desired.Spec.Image = "nginx:latest"
+	desired.Spec.UID, desired.Spec.GID = &uid, &gid
+	desired.Spec.GroupAdd = []string{"audio", "video"}
+
+	match, err := cm.CompareResources(desired, actual)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Error("Expected the same user and groups to match")
+	}
+
+	desired.Spec.RunAsGroup = "appgroup"
+	match, err = cm.CompareResources(desired, actual)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Error("Expected a group change to be detected")
+	}
+}
+
+func TestContainerManager_BuildContainerSpecCommandString(t *testing.T) {
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.Spec.Image = "nginx:latest"
+	container.Spec.CommandString = `nginx -g "daemon off;"`
+	container.Spec.Args = []string{"-c", "/etc/nginx/nginx.conf"}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	expected := []string{"nginx", "-g", "daemon off;", "-c", "/etc/nginx/nginx.conf"}
+	if !slices.Equal(spec.Command, expected) {
+		t.Errorf("Expected command %q, got %q", expected, spec.Command)
+	}
+
+	container.Spec.Command = []string{"nginx"}
+	if _, err := cm.buildContainerSpec(container); err == nil || !strings.Contains(err.Error(), "mutually exclusive") {
+		t.Errorf("Expected setting both command forms to fail, got %v", err)
+	}
+}
+
+func TestContainerManager_BuildContainerSpecPodmanFlags(t *testing.T) {
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+	yml := `
+kind: CuteContainer
+metadata:
+  name: test-container
+  annotations:
+    cutepod.io/podman-flag/privileged: "true"
+spec:
+  image: nginx:latest
+`
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.Spec.Image = "nginx:latest"
+	container.SetAnnotations(map[string]string{labels.AnnotationPodmanFlagPrefix + "cpu-shares": "512"})
+
+	if errs := container.Validate(yml); len(errs) != 0 {
+		t.Fatalf("Expected no validation errors, got %v", errs)
+	}
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.ResourceLimits == nil || spec.ResourceLimits.CPU == nil || spec.ResourceLimits.CPU.Shares == nil ||
+		*spec.ResourceLimits.CPU.Shares != 512 {
+		t.Errorf("Expected 512 CPU shares, got %+v", spec.ResourceLimits)
+	}
+
+	// Flags outside the supported set are rejected rather than ignored
+	container.SetAnnotations(map[string]string{labels.AnnotationPodmanFlagPrefix + "privileged": "true"})
+	errs := container.Validate(yml)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), `unsupported Podman flag "privileged"`) {
+		t.Errorf("Expected the unsupported flag to be reported, got %v", errs)
+	}
+	if _, err := cm.buildContainerSpec(container); err == nil || !strings.Contains(err.Error(), "unsupported Podman flag") {
+		t.Errorf("Expected the unsupported flag to fail, got %v", err)
+	}
+}
+
+func TestContainerManager_HostnameDefaultsToName(t *testing.T) {
+	ctx := context.Background()
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "web"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.Hostname != "web" {
+		t.Errorf("Expected the hostname to default to the container name, got %q", spec.Hostname)
+	}
+
+	// The defaulted hostname reads back as the same spec
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	if actual[0].(*ContainerResource).Spec.Hostname != "web" {
+		t.Errorf("Expected hostname web to read back, got %q", actual[0].(*ContainerResource).Spec.Hostname)
+	}
+	delete(actual[0].(*ContainerResource).Annotations, labels.LabelSpecHash)
+	if equal, err := cm.CompareResources(container, actual[0]); err != nil || !equal {
+		t.Errorf("Expected the container to be unchanged, got %v (err: %v)", equal, err)
+	}
+
+	container.Spec.Hostname = "frontend"
+	container.Spec.DomainName = "example.internal"
+	container.Spec.Sysctl = map[string]string{"net.core.somaxconn": "1024"}
+	spec, err = cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.Hostname != "frontend" || spec.Sysctl["kernel.domainname"] != "example.internal" {
+		t.Errorf("Expected hostname frontend in domain example.internal, got %q and %v", spec.Hostname, spec.Sysctl)
+	}
+	if spec.Sysctl["net.core.somaxconn"] != "1024" {
+		t.Errorf("Expected the container's sysctls to be kept alongside the domain name, got %v", spec.Sysctl)
+	}
+	if equal, _ := cm.CompareResources(container, actual[0]); equal {
+		t.Error("Expected a hostname change to be detected")
+	}
+
+	// Pod members share the pod's hostname unless they set one
+	member := NewContainerResource()
+	member.ObjectMeta.Name = "sidecar"
+	member.Spec.Image = "nginx:latest"
+	member.Spec.Pod = "web-pod"
+	if spec, err := cm.buildContainerSpec(member); err != nil || spec.Hostname != "" {
+		t.Errorf("Expected a pod member to get no hostname, got %q (err: %v)", spec.Hostname, err)
+	}
+}
+
+func TestContainerManager_CapabilityPresets(t *testing.T) {
+	ctx := context.Background()
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.SecurityContext = &SecurityContext{
+		CapabilityPreset: "web-server",
+		Capabilities:     &Capabilities{Add: []string{"CHOWN"}},
+	}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if !slices.Equal(spec.CapAdd, []string{"CHOWN", "NET_BIND_SERVICE"}) || !slices.Equal(spec.CapDrop, []string{"ALL"}) {
+		t.Errorf("Expected the preset to be resolved, got add %q and drop %q", spec.CapAdd, spec.CapDrop)
+	}
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+
+	// Spelling out what the preset stands for is the same container
+	explicit := NewContainerResource()
+	explicit.ObjectMeta.Name = "test-container"
+	explicit.Spec.Image = "nginx:latest"
+	explicit.Spec.SecurityContext = &SecurityContext{
+		Capabilities: &Capabilities{Add: []string{"NET_BIND_SERVICE", "CAP_CHOWN"}, Drop: []string{"ALL"}},
+	}
+	matches, err := cm.CompareResources(explicit, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !matches {
+		t.Error("Expected the explicit capabilities to match the preset")
+	}
+}
+
+func TestContainerManager_ReadOnlyRootFilesystemAndSeccomp(t *testing.T) {
+	ctx := context.Background()
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+
+	readOnly := true
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.SecurityContext = &SecurityContext{ReadOnlyRootFilesystem: &readOnly, SeccompProfile: SeccompUnconfined}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.ReadOnlyFilesystem == nil || !*spec.ReadOnlyFilesystem || spec.SeccompProfilePath != SeccompUnconfined {
+		t.Errorf("Expected a read-only, unconfined container, got %v and %q", spec.ReadOnlyFilesystem, spec.SeccompProfilePath)
+	}
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	actualContainer := actual[0].(*ContainerResource)
+	if !readOnlyRootFilesystem(actualContainer.Spec) || seccompProfile(actualContainer.Spec) != SeccompUnconfined {
+		t.Errorf("Expected the security context to be read back, got %+v", actualContainer.Spec.SecurityContext)
+	}
+
+	if matches, err := cm.CompareResources(container, actualContainer); err != nil || !matches {
+		t.Errorf("Expected the container to match (err: %v)", err)
+	}
+
+	// Toggling either option recreates the container, with or without a spec hash
+	writable := NewContainerResource()
+	writable.ObjectMeta.Name = "test-container"
+	writable.Spec.Image = "nginx:latest"
+	writable.Spec.SecurityContext = &SecurityContext{SeccompProfile: SeccompUnconfined}
+
+	unhashed := *actualContainer
+	unhashed.SetAnnotations(nil)
+	for _, actual := range []*ContainerResource{actualContainer, &unhashed} {
+		if matches, err := cm.CompareResources(writable, actual); err != nil || matches {
+			t.Errorf("Expected a writable root filesystem to differ (err: %v)", err)
+		}
+	}
+}
+
+func TestContainerManager_NamespaceModes(t *testing.T) {
+	ctx := context.Background()
+	cm := NewContainerManager(podman.NewMockPodmanClient())
+
+	newContainer := func(name string) *ContainerResource {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = name
+		container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+		container.Spec.Image = "nginx:latest"
+		return container
+	}
+	app := newContainer("app")
+	monitor := newContainer("monitor")
+	monitor.Spec.IpcMode = "host"
+	monitor.Spec.PidMode = "container:app"
+	monitor.Spec.UtsMode = "private"
+
+	spec, err := cm.buildContainerSpec(monitor)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.IpcNS.NSMode != specgen.Host || spec.PidNS.NSMode != specgen.FromContainer || spec.PidNS.Value != "app" ||
+		spec.UtsNS.NSMode != specgen.Private {
+		t.Errorf("Expected the namespace modes to be mapped, got %+v, %+v and %+v", spec.IpcNS, spec.PidNS, spec.UtsNS)
+	}
+
+	for _, container := range []*ContainerResource{app, monitor} {
+		if err := cm.CreateResource(ctx, container); err != nil {
+			t.Fatalf("CreateResource failed: %v", err)
+		}
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 2 {
+		t.Fatalf("Expected 2 containers, got %d (err: %v)", len(actual), err)
+	}
+	var actualMonitor *ContainerResource
+	for _, resource := range actual {
+		if resource.GetName() == "monitor" {
+			actualMonitor = resource.(*ContainerResource)
+		}
+	}
+
+	// The joined container is reported by ID and read back by name, and the private UTS
+	// namespace is Podman's default
+	if actualMonitor.Spec.IpcMode != "host" || actualMonitor.Spec.PidMode != "container:app" || actualMonitor.Spec.UtsMode != "" {
+		t.Errorf("Expected the namespace modes to be read back, got %q, %q and %q",
+			actualMonitor.Spec.IpcMode, actualMonitor.Spec.PidMode, actualMonitor.Spec.UtsMode)
+	}
+
+	unhashed := *actualMonitor
+	unhashed.SetAnnotations(nil)
+	for _, actual := range []*ContainerResource{actualMonitor, &unhashed} {
+		if matches, err := cm.CompareResources(monitor, actual); err != nil || !matches {
+			t.Errorf("Expected the container to match (err: %v)", err)
+		}
+	}
+
+	// Moving to the host PID namespace recreates the container
+	hostPID := *monitor
+	hostPID.Spec.PidMode = "host"
+	for _, actual := range []*ContainerResource{actualMonitor, &unhashed} {
+		if matches, err := cm.CompareResources(&hostPID, actual); err != nil || matches {
+			t.Errorf("Expected a new PID namespace to differ (err: %v)", err)
+		}
+	}
+}
+
+func TestContainerManager_SecretEnvVars(t *testing.T) {
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "db"
+	secret.SetData(map[string][]byte{"USER": []byte("admin"), "PASSWORD": []byte("s3cret")})
+
+	registry := NewManifestRegistry()
+	if err := registry.AddResource(secret); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	cm := NewContainerManagerWithRegistry(podman.NewMockPodmanClient(), registry)
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Env = []EnvVar{{Name: "DB_USER", Value: "override"}}
+	container.Spec.Secrets = []SecretReference{{Name: "db", Env: true, Prefix: "DB_"}}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.Env["DB_PASSWORD"] != "s3cret" {
+		t.Errorf("Expected the secret key to become a prefixed env var, got %v", spec.Env)
+	}
+	if spec.Env["DB_USER"] != "override" {
+		t.Errorf("Expected the explicit env var to take precedence, got %q", spec.Env["DB_USER"])
+	}
+	if spec.Labels[labels.LabelSecretEnvKeys] != "DB_PASSWORD" {
+		t.Errorf("Expected the expanded keys to be recorded, got %q", spec.Labels[labels.LabelSecretEnvKeys])
+	}
+
+	if err := cm.CreateResource(context.Background(), container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(context.Background(), "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	actualContainer := actual[0].(*ContainerResource)
+	if !slices.Equal(actualContainer.Spec.Env, container.Spec.Env) {
+		t.Errorf("Expected the secret values to be left out of the read back env, got %+v", actualContainer.Spec.Env)
+	}
+
+	match, err := cm.CompareResources(container, actualContainer)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Error("Expected the unchanged container to match")
+	}
+
+	// A key added to the secret changes the container's environment
+	secret.SetData(map[string][]byte{"USER": []byte("admin"), "PASSWORD": []byte("s3cret"), "HOST": []byte("db")})
+	match, err = cm.CompareResources(container, actualContainer)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Error("Expected a change to the secret's keys to be detected")
+	}
+}
+
+func TestContainerManager_EnvFrom(t *testing.T) {
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "db"
+	secret.SetData(map[string][]byte{"USER": []byte("admin"), "PASSWORD": []byte("s3cret")})
+
+	registry := NewManifestRegistry()
+	if err := registry.AddResource(secret); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	cm := NewContainerManagerWithRegistry(podman.NewMockPodmanClient(), registry)
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.EnvFrom = []EnvFromSource{{SecretName: "db", Prefix: "DB_"}}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if spec.Env["DB_USER"] != "admin" || spec.Env["DB_PASSWORD"] != "s3cret" {
+		t.Errorf("Expected every key to become a prefixed env var, got %v", spec.Env)
+	}
+	if len(spec.Secrets) != 0 {
+		t.Errorf("Expected envFrom not to mount the secret, got %+v", spec.Secrets)
+	}
+
+	if err := cm.CreateResource(context.Background(), container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(context.Background(), "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	match, err := cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Error("Expected the unchanged container to match")
+	}
+
+	// A key removed from the secret changes the container's environment
+	delete(secret.Spec.Data, "USER")
+	match, err = cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Error("Expected a change to the source's keys to be detected")
+	}
+
+	container.Spec.EnvFrom = []EnvFromSource{{SecretName: "missing"}}
+	if _, err := cm.buildContainerSpec(container); err == nil || !strings.Contains(err.Error(), "secret 'missing' not found") {
+		t.Errorf("Expected a missing source to be rejected, got %v", err)
+	}
+}
+
+func TestContainerManager_SecretItems(t *testing.T) {
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "tls"
+	secret.SetData(map[string][]byte{"cert.pem": []byte("certificate")})
+
+	registry := NewManifestRegistry()
+	if err := registry.AddResource(secret); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	cm := NewContainerManagerWithRegistry(podman.NewMockPodmanClient(), registry)
+
+	mode := int32(0400)
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Secrets = []SecretReference{{
+		Name:  "tls",
+		Path:  "/etc/tls",
+		Items: []SecretItem{{Key: "cert.pem", Path: "server.crt", Mode: &mode}},
+	}}
+
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if len(spec.Secrets) != 1 || spec.Secrets[0].Target != "/etc/tls/server.crt" || spec.Secrets[0].Mode != 0400 {
+		t.Errorf("Expected the key to be mounted at its item's path and mode, got %+v", spec.Secrets)
+	}
+
+	// A different mode creates a different container
+	hash, err := computeContainerSpecHash(container.Spec)
+	if err != nil {
+		t.Fatalf("computeContainerSpecHash failed: %v", err)
+	}
+	changed := container.Spec
+	changed.Secrets = []SecretReference{{Name: "tls", Path: "/etc/tls", Items: []SecretItem{{Key: "cert.pem", Path: "server.crt"}}}}
+	changedHash, err := computeContainerSpecHash(changed)
+	if err != nil {
+		t.Fatalf("computeContainerSpecHash failed: %v", err)
+	}
+	if hash == changedHash {
+		t.Error("Expected a change to an item to change the spec hash")
+	}
+
+	container.Spec.Secrets[0].Items[0].Key = "key.pem"
+	if _, err := cm.buildContainerSpec(container); err == nil || !strings.Contains(err.Error(), "has no key 'key.pem'") {
+		t.Errorf("Expected a missing key to be rejected, got %v", err)
+	}
+
+	// Podman holds the keys of a multi-key secret as one value
+	container.Spec.Secrets[0].Items[0].Key = "cert.pem"
+	secret.SetData(map[string][]byte{"cert.pem": []byte("certificate"), "key.pem": []byte("key")})
+	if _, err := cm.buildContainerSpec(container); err == nil || !strings.Contains(err.Error(), "single-key secret") {
+		t.Errorf("Expected items of a multi-key secret to be rejected, got %v", err)
+	}
+}
+
+// slowPullClient records how many image pulls run at once
+type slowPullClient struct {
+	*podman.MockPodmanClient
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (c *slowPullClient) PullImage(ctx context.Context, image string) error {
+	c.mu.Lock()
+	c.inFlight++
+	c.maxInFlight = max(c.maxInFlight, c.inFlight)
+	c.mu.Unlock()
+
+	time.Sleep(20 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+	return c.MockPodmanClient.PullImage(ctx, image)
+}
+
+func TestContainerManager_PullConcurrency(t *testing.T) {
+	client := &slowPullClient{MockPodmanClient: podman.NewMockPodmanClient()}
+	cm := NewContainerManager(client)
+	cm.SetPullConcurrency(1)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, name := range []string{"web", "db"} {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = name
+		container.Spec.Image = name + ":latest"
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- cm.CreateResource(context.Background(), container)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("CreateResource failed: %v", err)
+		}
+	}
+	if client.GetCallCount("PullImage") != 2 {
+		t.Errorf("Expected both images to be pulled, got %d pulls", client.GetCallCount("PullImage"))
+	}
+	if client.maxInFlight != 1 {
+		t.Errorf("Expected the pulls to be serialized, got %d at once", client.maxInFlight)
+	}
+
+	// An image that is already present does not wait for a pull slot
+	cm.pullSlots <- struct{}{}
+	defer func() { <-cm.pullSlots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	present := NewContainerResource()
+	present.ObjectMeta.Name = "cache"
+	present.Spec.Image = "web:latest"
+	if err := cm.CreateResource(ctx, present); err != nil {
+		t.Errorf("Expected a present image not to need a pull slot, got %v", err)
+	}
+}
+
+func TestContainerManager_ForceRecreateAnnotation(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.SetAnnotations(map[string]string{labels.AnnotationForceRecreate: "true"})
+	container.Spec.Image = "nginx:latest"
+	container.Spec.RestartPolicy = "no"
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+
+	// A restart policy change would otherwise be applied in place
+	container.Spec.RestartPolicy = "always"
+	match, err := cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Fatal("Expected the restart policy change to be detected")
+	}
+	if err := cm.UpdateResource(ctx, container, actual[0]); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	if mockClient.GetCallCount("UpdateContainer") != 0 || mockClient.GetCallCount("RemoveContainer") != 1 {
+		t.Error("Expected the container to be recreated instead of updated in place")
+	}
+}
+
+func TestContainerManager_RestartNonce(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.SetAnnotations(map[string]string{labels.AnnotationRestartNonce: "1"})
+	container.Spec.Image = "nginx:latest"
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+
+	match, err := cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Fatal("Expected an unchanged nonce to match")
+	}
+
+	container.SetAnnotations(map[string]string{labels.AnnotationRestartNonce: "2"})
+	match, err = cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Fatal("Expected a changed nonce to be detected")
+	}
+	if err := cm.UpdateResource(ctx, container, actual[0]); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+	if mockClient.GetCallCount("RestartContainer") != 1 || mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Error("Expected a changed nonce to restart the container instead of recreating it")
+	}
+
+	actual, err = cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	if actual[0].(*ContainerResource).GetAnnotations()[labels.AnnotationRestartNonce] != "2" {
+		t.Errorf("Expected the new nonce to be recorded, got %v", actual[0].(*ContainerResource).GetAnnotations())
+	}
+	if len(actual[0].(*ContainerResource).Spec.Env) != 0 {
+		t.Errorf("Expected the recorded nonce to stay out of the environment, got %v", actual[0].(*ContainerResource).Spec.Env)
+	}
+	match, err = cm.CompareResources(container, actual[0])
+	if err != nil || !match {
+		t.Errorf("Expected the restarted container to match (err: %v)", err)
+	}
+
+	// Dropping the nonce does not restart the container again
+	container.SetAnnotations(nil)
+	match, err = cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Error("Expected removing the nonce not to require a restart")
+	}
+}
+
+func TestContainerManager_UpdateResourceReconnectsOnAliasChange(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	if _, err := mockClient.CreateNetwork(ctx, podman.NetworkSpec{Name: "web-network"}); err != nil {
+		t.Fatalf("Failed to create mock network: %v", err)
+	}
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "test-container"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Networks = []NetworkAttachment{{Name: "web-network", Aliases: []string{"web"}}}
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d", len(actual))
+	}
+
+	match, err := cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if !match {
+		t.Fatal("Expected freshly created container to match")
+	}
+
+	desired := NewContainerResource()
+	desired.ObjectMeta.Name = "test-container"
+	desired.SetLabels(container.GetLabels())
+	desired.Spec.Image = "nginx:latest"
+	desired.Spec.Networks = []NetworkAttachment{{Name: "web-network", Aliases: []string{"web", "frontend"}}}
+
+	match, err = cm.CompareResources(desired, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Fatal("Expected an alias change to be detected")
+	}
+
+	if err := cm.UpdateResource(ctx, desired, actual[0]); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	if mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Error("Expected an alias change not to recreate the container")
+	}
+
+	aliases, connected := mockClient.GetNetworkAliases("test-container", "web-network")
+	if !connected {
+		t.Fatal("Expected the container to be reconnected to web-network")
+	}
+	if len(aliases) != 2 || aliases[0] != "web" || aliases[1] != "frontend" {
+		t.Errorf("Expected aliases [web frontend], got %v", aliases)
+	}
+}
+
+func TestContainerManager_BuildsImageFromContainerfile(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	contextDir := t.TempDir()
+	containerfile := filepath.Join(contextDir, "Containerfile")
+	if err := os.WriteFile(containerfile, []byte("FROM alpine:3.20\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write Containerfile: %v", err)
+	}
+
+	newContainer := func(args map[string]string) *ContainerResource {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = "app"
+		container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+		container.Spec.Image = "localhost/app:dev"
+		container.Spec.Build = &BuildSpec{Context: contextDir, Args: args}
+		return container
+	}
+
+	container := newContainer(map[string]string{"VERSION": "1"})
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	builds := mockClient.GetBuildRequests()
+	if len(builds) != 1 {
+		t.Fatalf("Expected 1 build, got %d", len(builds))
+	}
+	if builds[0].Tag != "localhost/app:dev" || builds[0].Containerfile != containerfile || builds[0].BuildArgs["VERSION"] != "1" {
+		t.Errorf("Unexpected build request: %+v", builds[0])
+	}
+	if mockClient.GetCallCount("PullImage") != 0 {
+		t.Error("Expected built images not to be pulled")
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+
+	if match, err := cm.CompareResources(container, actual[0]); err != nil || !match {
+		t.Fatalf("Expected the built container to match (err: %v)", err)
+	}
+
+	if match, _ := cm.CompareResources(newContainer(map[string]string{"VERSION": "2"}), actual[0]); match {
+		t.Error("Expected a build arg change to require a rebuild")
+	}
+
+	if err := os.WriteFile(containerfile, []byte("FROM alpine:3.21\n"), 0o644); err != nil {
+		t.Fatalf("Failed to update Containerfile: %v", err)
+	}
+	if match, _ := cm.CompareResources(container, actual[0]); match {
+		t.Error("Expected a Containerfile change to require a rebuild")
+	}
+}
+
+func TestContainerManager_LifecycleHooks(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "db"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "postgres:16"
+	container.Spec.Lifecycle = &LifecycleSpec{
+		PostStart: &ExecAction{Command: []string{"pg_isready"}},
+		PreStop:   &ExecAction{Command: []string{"pg_ctl", "stop", "-m", "fast"}},
+	}
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	execs := mockClient.GetExecs()
+	if len(execs) != 1 || execs[0].Container != "db" || execs[0].Command[0] != "pg_isready" {
+		t.Fatalf("Expected the postStart hook to run, got %+v", execs)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	actualContainer := actual[0].(*ContainerResource)
+	if _, exists := actualContainer.GetLabels()[labels.LabelPreStop]; exists {
+		t.Error("Expected the preStop label to be kept out of the labels")
+	}
+
+	if match, err := cm.CompareResources(container, actualContainer); err != nil || !match {
+		t.Fatalf("Expected the container to match (err: %v)", err)
+	}
+
+	changed := *container
+	changed.Spec.Lifecycle = &LifecycleSpec{PostStart: &ExecAction{Command: []string{"pg_isready", "-q"}}}
+	if match, _ := cm.CompareResources(&changed, actualContainer); match {
+		t.Error("Expected a hook change to require recreation")
+	}
+
+	// The preStop hook is read back from the container, not the manifest
+	if err := cm.DeleteResource(ctx, actualContainer); err != nil {
+		t.Fatalf("DeleteResource failed: %v", err)
+	}
+
+	execs = mockClient.GetExecs()
+	if len(execs) != 2 || strings.Join(execs[1].Command, " ") != "pg_ctl stop -m fast" {
+		t.Fatalf("Expected the preStop hook to run before stopping, got %+v", execs)
+	}
+}
+
+func TestContainerManager_PostStartFailureFailsCreation(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetExecExitCode([]string{"/bin/false"}, 1)
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "app"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "alpine:3.20"
+	container.Spec.Lifecycle = &LifecycleSpec{PostStart: &ExecAction{Command: []string{"/bin/false"}}}
+
+	err := cm.CreateResource(ctx, container)
+	if err == nil || !strings.Contains(err.Error(), "exited with code 1") {
+		t.Fatalf("Expected the postStart failure to fail creation, got %v", err)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("Expected the container to be removed after the hook failed, got %d", len(actual))
+	}
+}
+
+func TestContainerManager_StartFailureRemovesContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("StartContainer", true)
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "app"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "alpine:3.20"
+
+	err := cm.CreateResource(ctx, container)
+	if err == nil || !strings.Contains(err.Error(), "unable to start container") {
+		t.Fatalf("Expected the start failure to fail creation, got %v", err)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("Expected the container to be removed after it failed to start, got %d", len(actual))
+	}
+	if mockClient.GetCallCount("RemoveContainer") != 1 {
+		t.Errorf("Expected the created container to be removed once, got %d", mockClient.GetCallCount("RemoveContainer"))
+	}
+}
+
+func TestContainerManager_StartsNeverStartedContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "job"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "alpine:3.20"
+	container.Spec.RestartPolicy = "no"
+
+	// Left behind by a run interrupted between creating and starting the container
+	spec, err := cm.buildContainerSpec(container)
+	if err != nil {
+		t.Fatalf("buildContainerSpec failed: %v", err)
+	}
+	if _, err := mockClient.CreateContainer(ctx, spec); err != nil {
+		t.Fatalf("CreateContainer failed: %v", err)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected the created container to be read back, got %d (%v)", len(actual), err)
+	}
+	equal, err := cm.CompareResources(container, actual[0])
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if equal {
+		t.Fatal("Expected a never started container to need starting, even without a restart policy")
+	}
+
+	if err := cm.UpdateResource(ctx, container, actual[0]); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+	inspect, err := mockClient.InspectContainer(ctx, "job")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if inspect.State.Status != "running" {
+		t.Errorf("Expected the container to be started, got %s", inspect.State.Status)
+	}
+
+	// An exited container without a restart policy has run to completion and is left alone
+	if err := mockClient.StopContainer(ctx, "job", 0); err != nil {
+		t.Fatalf("StopContainer failed: %v", err)
+	}
+	actual, err = cm.GetActualState(ctx, "chart-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if equal, err := cm.CompareResources(container, actual[0]); err != nil || !equal {
+		t.Errorf("Expected the exited container to be left alone, got equal=%v (%v)", equal, err)
+	}
+}
+
+func TestContainerManager_Annotations(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "api"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.SetAnnotations(map[string]string{"owner": "team@example.com", "git-sha": "3f2a9c1"})
+	container.Spec.Image = "ghcr.io/example/api:1.0"
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	actual, err := cm.GetActualState(ctx, "chart-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	actualContainer := actual[0].(*ContainerResource)
+	if owner := actualContainer.GetAnnotations()["owner"]; owner != "team@example.com" {
+		t.Errorf("Expected the annotations to be read back, got %v", actualContainer.GetAnnotations())
+	}
+	if _, exists := actualContainer.GetLabels()["owner"]; exists {
+		t.Error("Expected annotations to be kept out of the labels")
+	}
+
+	if match, err := cm.CompareResources(container, actualContainer); err != nil || !match {
+		t.Fatalf("Expected the container to match (err: %v)", err)
+	}
+
+	changed := *container
+	changed.SetAnnotations(map[string]string{"owner": "team@example.com", "git-sha": "8be04d7"})
+	diffs, err := DiffResources(&changed, actualContainer)
+	if err != nil || len(diffs) != 1 || diffs[0].Path != "metadata.annotations" {
+		t.Errorf("Expected the annotation drift to be reported, got %+v (err: %v)", diffs, err)
+	}
+
+	if match, _ := cm.CompareResources(&changed, actualContainer); !match {
+		t.Error("Expected annotation drift not to require recreation by default")
+	}
+
+	cm.recreateOnAnnotationChange = true
+	if match, _ := cm.CompareResources(&changed, actualContainer); match {
+		t.Error("Expected annotation drift to require recreation when configured")
+	}
+}
+
 func TestContainerManager_GetActualState(t *testing.T) {
 	mockClient := podman.NewMockPodmanClient()
 	cm := NewContainerManager(mockClient)
@@ -1,6 +1,10 @@
 package resource
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -11,7 +15,7 @@ func TestContainerResource_GetDependencies(t *testing.T) {
 	container.Spec.Image = "nginx:latest"
 
 	// Add network dependencies
-	container.Spec.Networks = []string{"web-network", "db-network"}
+	container.Spec.Networks = []NetworkAttachment{{Name: "web-network"}, {Name: "db-network"}}
 
 	// Add volume dependencies
 	container.Spec.Volumes = []VolumeMount{
@@ -80,7 +84,7 @@ func TestContainerResource_GetDependencies_PartialDependencies(t *testing.T) {
 	container.Spec.Image = "nginx:latest"
 
 	// Only add network and volume dependencies
-	container.Spec.Networks = []string{"web-network"}
+	container.Spec.Networks = []NetworkAttachment{{Name: "web-network"}}
 	container.Spec.Volumes = []VolumeMount{
 		{Name: "data-volume", MountPath: "/data"},
 	}
@@ -173,3 +177,420 @@ spec:
 		t.Error("Expected validation error for invalid port")
 	}
 }
+
+func TestContainerResource_Validate_RelativeBuildContext(t *testing.T) {
+	// Test validation with a relative build context
+	container := NewContainerResource()
+	container.Spec.Image = "localhost/app:dev"
+	container.Spec.Build = &BuildSpec{Context: "./app"}
+
+	errors := container.Validate(`
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: localhost/app:dev
+  build:
+    context: ./app
+`)
+
+	if len(errors) == 0 {
+		t.Error("Expected validation error for relative build context")
+	}
+}
+
+func TestContainerResource_Validate_User(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: nginx:latest
+  runAsUser: appuser
+  runAsGroup: appgroup
+  groupAdd:
+    - audio
+`
+
+	tests := []struct {
+		name      string
+		user      string
+		group     string
+		groupAdd  []string
+		wantError bool
+	}{
+		{name: "user and group", user: "appuser", group: "appgroup", groupAdd: []string{"audio"}},
+		{name: "combined form", user: "1000:1000"},
+		{name: "blank user", user: " ", wantError: true},
+		{name: "missing group after colon", user: "appuser:", wantError: true},
+		{name: "group given twice", user: "appuser:appgroup", group: "other", wantError: true},
+		{name: "group without user", group: "appgroup", wantError: true},
+		{name: "blank supplementary group", user: "appuser", groupAdd: []string{""}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewContainerResource()
+			container.Spec.Image = "nginx:latest"
+			container.Spec.RunAsUser = tt.user
+			container.Spec.RunAsGroup = tt.group
+			container.Spec.GroupAdd = tt.groupAdd
+
+			errs := container.Validate(yml)
+			if tt.wantError && len(errs) == 0 {
+				t.Error("Expected a validation error")
+			}
+			if !tt.wantError && len(errs) != 0 {
+				t.Errorf("Expected no validation errors, got %v", errs)
+			}
+		})
+	}
+}
+
+func TestSplitCommandString(t *testing.T) {
+	tests := []struct {
+		name     string
+		command  string
+		expected []string
+		wantErr  string
+	}{
+		{name: "plain words", command: "  nginx -g  off ", expected: []string{"nginx", "-g", "off"}},
+		{name: "double quotes", command: `nginx -g "daemon off;"`, expected: []string{"nginx", "-g", "daemon off;"}},
+		{name: "single quotes are literal", command: `sh -c 'echo "$HOME" \n'`, expected: []string{"sh", "-c", `echo "$HOME" \n`}},
+		{name: "escapes", command: `echo a\ b "q\"uote" "back\slash"`, expected: []string{"echo", "a b", `q"uote`, `back\slash`}},
+		{name: "adjacent quotes join", command: `--name='my app'"-1"`, expected: []string{"--name=my app-1"}},
+		{name: "empty quoted word", command: `printf ''`, expected: []string{"printf", ""}},
+		{name: "unbalanced quote", command: `echo "oops`, wantErr: `unbalanced " quote`},
+		{name: "trailing escape", command: `echo \`, wantErr: "unfinished escape"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := splitCommandString(tt.command)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitCommandString failed: %v", err)
+			}
+			if !slices.Equal(words, tt.expected) {
+				t.Errorf("Expected %q, got %q", tt.expected, words)
+			}
+		})
+	}
+}
+
+func TestContainerResource_Validate_CommandString(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: nginx:latest
+  commandString: nginx -g "daemon off;"
+`
+
+	tests := []struct {
+		name          string
+		command       []string
+		commandString string
+		wantError     bool
+	}{
+		{name: "command string", commandString: `nginx -g "daemon off;"`},
+		{name: "both forms", command: []string{"nginx"}, commandString: "nginx", wantError: true},
+		{name: "unbalanced quotes", commandString: `nginx -g "daemon off;`, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewContainerResource()
+			container.Spec.Image = "nginx:latest"
+			container.Spec.Command = tt.command
+			container.Spec.CommandString = tt.commandString
+
+			errors := container.Validate(yml)
+			if tt.wantError && len(errors) == 0 {
+				t.Error("Expected a validation error")
+			}
+			if !tt.wantError && len(errors) != 0 {
+				t.Errorf("Expected no validation errors, got %v", errors)
+			}
+		})
+	}
+}
+
+func TestResolveCapabilities(t *testing.T) {
+	tests := []struct {
+		name     string
+		secCtx   *SecurityContext
+		wantAdd  []string
+		wantDrop []string
+	}{
+		{
+			name:     "preset",
+			secCtx:   &SecurityContext{CapabilityPreset: "web-server"},
+			wantAdd:  []string{"NET_BIND_SERVICE"},
+			wantDrop: []string{"ALL"},
+		},
+		{
+			name: "preset with explicit capabilities",
+			secCtx: &SecurityContext{
+				CapabilityPreset: "network-admin",
+				Capabilities:     &Capabilities{Add: []string{"CAP_CHOWN"}, Drop: []string{"NET_RAW"}},
+			},
+			wantAdd:  []string{"CHOWN", "NET_ADMIN"},
+			wantDrop: []string{"NET_RAW"},
+		},
+		{
+			name:    "explicit capabilities only",
+			secCtx:  &SecurityContext{Capabilities: &Capabilities{Add: []string{"net_admin", "CAP_NET_ADMIN"}}},
+			wantAdd: []string{"NET_ADMIN"},
+		},
+		{
+			name: "no security context",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, drop, err := resolveCapabilities(tt.secCtx)
+			if err != nil {
+				t.Fatalf("resolveCapabilities failed: %v", err)
+			}
+			if !slices.Equal(add, tt.wantAdd) || !slices.Equal(drop, tt.wantDrop) {
+				t.Errorf("Expected add %q and drop %q, got %q and %q", tt.wantAdd, tt.wantDrop, add, drop)
+			}
+		})
+	}
+}
+
+func TestContainerResource_Validate_CapabilityPreset(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: nginx:latest
+  securityContext:
+    capabilityPreset: database
+`
+
+	container := NewContainerResource()
+	container.Spec.Image = "nginx:latest"
+	container.Spec.SecurityContext = &SecurityContext{CapabilityPreset: "web-server"}
+	if errors := container.Validate(yml); len(errors) != 0 {
+		t.Errorf("Expected a built-in preset to be valid, got %v", errors)
+	}
+
+	container.Spec.SecurityContext.CapabilityPreset = "database"
+	errors := container.Validate(yml)
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), `unknown capability preset "database"`) {
+		t.Errorf("Expected an unknown preset to be rejected, got %v", errors)
+	}
+}
+
+func TestContainerResource_Validate_SecretItems(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: nginx:latest
+  secrets:
+    - name: tls
+      items:
+        - key: cert.pem
+          path: ../server.crt
+          mode: 0o1000
+`
+
+	mode := int32(0o1000)
+	container := NewContainerResource()
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Secrets = []SecretReference{{
+		Name:  "tls",
+		Items: []SecretItem{{Key: "cert.pem", Path: "../server.crt", Mode: &mode}},
+	}}
+
+	var messages []string
+	for _, err := range container.Validate(yml) {
+		messages = append(messages, err.Error())
+	}
+	for _, expected := range []string{"items require path", "item path must be a relative path", "item mode must be between 0 and 0777"} {
+		if !slices.ContainsFunc(messages, func(message string) bool { return strings.Contains(message, expected) }) {
+			t.Errorf("Expected an error containing %q, got %v", expected, messages)
+		}
+	}
+
+	mode = 0400
+	container.Spec.Secrets[0].Path = "/etc/tls"
+	container.Spec.Secrets[0].Items[0].Path = "server.crt"
+	if errors := container.Validate(yml); len(errors) != 0 {
+		t.Errorf("Expected valid items to pass, got %v", errors)
+	}
+}
+
+func TestContainerResource_Validate_NamespaceModes(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: web
+spec:
+  image: nginx:latest
+  ipcMode: host
+  pidMode: host
+  utsMode: host
+`
+
+	tests := []struct {
+		name      string
+		modify    func(*ContainerResource)
+		wantError bool
+	}{
+		{name: "host", modify: func(c *ContainerResource) { c.Spec.IpcMode, c.Spec.PidMode, c.Spec.UtsMode = "host", "host", "host" }},
+		{name: "shareable ipc", modify: func(c *ContainerResource) { c.Spec.IpcMode = "shareable" }},
+		{name: "other container", modify: func(c *ContainerResource) { c.Spec.PidMode = "container:db" }},
+		{name: "pod in a pod", modify: func(c *ContainerResource) { c.Spec.Pod, c.Spec.UtsMode = "app", "pod" }},
+		{name: "pod outside a pod", modify: func(c *ContainerResource) { c.Spec.UtsMode = "pod" }, wantError: true},
+		{name: "shareable pid", modify: func(c *ContainerResource) { c.Spec.PidMode = "shareable" }, wantError: true},
+		{name: "unknown mode", modify: func(c *ContainerResource) { c.Spec.IpcMode = "bogus" }, wantError: true},
+		{name: "missing container name", modify: func(c *ContainerResource) { c.Spec.PidMode = "container:" }, wantError: true},
+		{name: "itself", modify: func(c *ContainerResource) { c.Spec.PidMode = "container:web" }, wantError: true},
+		{name: "network of another container", modify: func(c *ContainerResource) { c.Spec.NetworkMode = "container:db" }},
+		{name: "pod network", modify: func(c *ContainerResource) { c.Spec.NetworkMode = "pod" }, wantError: true},
+		{name: "network mode with networks", modify: func(c *ContainerResource) {
+			c.Spec.NetworkMode, c.Spec.Networks = "host", []NetworkAttachment{{Name: "backend"}}
+		}, wantError: true},
+		{name: "network mode with ports", modify: func(c *ContainerResource) {
+			c.Spec.NetworkMode, c.Spec.Ports = "container:db", []ContainerPort{{ContainerPort: 80}}
+		}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewContainerResource()
+			container.ObjectMeta.Name = "web"
+			container.Spec.Image = "nginx:latest"
+			tt.modify(container)
+
+			errors := container.Validate(yml)
+			if tt.wantError && len(errors) == 0 {
+				t.Error("Expected a validation error")
+			}
+			if !tt.wantError && len(errors) != 0 {
+				t.Errorf("Expected no validation errors, got %v", errors)
+			}
+		})
+	}
+}
+
+func TestContainerResource_Validate_AutoRemoveRestartPolicy(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: job
+spec:
+  image: busybox:latest
+  autoRemove: true
+  restartPolicy: always
+`
+
+	enabled := true
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "job"
+	container.Spec.Image = "busybox:latest"
+	container.Spec.AutoRemove = &enabled
+	container.Spec.RestartPolicy = "always"
+
+	errors := container.Validate(yml)
+	if len(errors) != 1 || !strings.Contains(errors[0].Error(), "autoRemove requires restartPolicy no") {
+		t.Errorf("Expected autoRemove with a restart policy to be rejected, got %v", errors)
+	}
+
+	container.Spec.RestartPolicy = "no"
+	if errors := container.Validate(yml); len(errors) != 0 {
+		t.Errorf("Expected no validation errors, got %v", errors)
+	}
+}
+
+func TestContainerResource_Validate_SeccompProfile(t *testing.T) {
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: test-container
+spec:
+  image: nginx:latest
+  securityContext:
+    seccompProfile: /etc/cutepod/seccomp.json
+`
+
+	profile := filepath.Join(t.TempDir(), "seccomp.json")
+	if err := os.WriteFile(profile, []byte(`{"defaultAction": "SCMP_ACT_ALLOW"}`), 0o644); err != nil {
+		t.Fatalf("Failed to write profile: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		profile   string
+		wantError bool
+	}{
+		{name: "readable profile", profile: profile},
+		{name: "unconfined", profile: SeccompUnconfined},
+		{name: "missing profile", profile: filepath.Join(t.TempDir(), "missing.json"), wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container := NewContainerResource()
+			container.Spec.Image = "nginx:latest"
+			container.Spec.SecurityContext = &SecurityContext{SeccompProfile: tt.profile}
+
+			errors := container.Validate(yml)
+			if tt.wantError && len(errors) == 0 {
+				t.Error("Expected a validation error")
+			}
+			if !tt.wantError && len(errors) != 0 {
+				t.Errorf("Expected no validation errors, got %v", errors)
+			}
+		})
+	}
+}
+
+func TestContainerResource_Validate_Hostname(t *testing.T) {
+	yml := `
+kind: CuteContainer
+metadata:
+  name: web
+spec:
+  image: nginx:latest
+  hostname: Web_1
+  utsMode: host
+`
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "web"
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Hostname = "Web_1"
+
+	errs := container.Validate(yml)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "hostname must be a DNS label") {
+		t.Errorf("Expected the hostname to be rejected, got %v", errs)
+	}
+
+	container.Spec.Hostname = "web-1"
+	container.Spec.UtsMode = "host"
+	errs = container.Validate(yml)
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "cannot be set with utsMode host") {
+		t.Errorf("Expected the shared UTS namespace to be rejected, got %v", errs)
+	}
+}
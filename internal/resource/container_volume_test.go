@@ -194,6 +194,32 @@ spec:
 			expectError: true,
 			errorMsg:    "seLinuxLabel must be one of: z, Z, shared, private",
 		},
+		{
+			name: "SELinux context with too few components",
+			spec: CuteContainerSpec{
+				Image: "nginx:latest",
+				Volumes: []VolumeMount{
+					{
+						Name:      "data",
+						MountPath: "/data",
+						MountOptions: &VolumeMountOptions{
+							SELinuxContext: "container_file_t:s0",
+						},
+					},
+				},
+			},
+			yaml: `
+spec:
+  image: nginx:latest
+  volumes:
+    - name: data
+      mountPath: /data
+      mountOptions:
+        seLinuxContext: container_file_t:s0
+`,
+			expectError: true,
+			errorMsg:    "must have four colon-separated components",
+		},
 		{
 			name: "valid SELinux labels",
 			spec: CuteContainerSpec{
@@ -249,6 +275,87 @@ spec:
       mountPath: /data4
       mountOptions:
         seLinuxLabel: private
+`,
+			expectError: false,
+		},
+		{
+			name: "invalid mount propagation",
+			spec: CuteContainerSpec{
+				Image: "nginx:latest",
+				Volumes: []VolumeMount{
+					{
+						Name:      "data",
+						MountPath: "/data",
+						MountOptions: &VolumeMountOptions{
+							Propagation: "shared",
+						},
+					},
+				},
+			},
+			yaml: `
+spec:
+  image: nginx:latest
+  volumes:
+    - name: data
+      mountPath: /data
+      mountOptions:
+        propagation: shared
+`,
+			expectError: true,
+			errorMsg:    "propagation must be one of: private, rprivate, rshared, rslave",
+		},
+		{
+			name: "recursive read-only without readOnly",
+			spec: CuteContainerSpec{
+				Image: "nginx:latest",
+				Volumes: []VolumeMount{
+					{
+						Name:      "data",
+						MountPath: "/data",
+						MountOptions: &VolumeMountOptions{
+							RecursiveReadOnly: true,
+						},
+					},
+				},
+			},
+			yaml: `
+spec:
+  image: nginx:latest
+  volumes:
+    - name: data
+      mountPath: /data
+      mountOptions:
+        recursiveReadOnly: true
+`,
+			expectError: true,
+			errorMsg:    "recursiveReadOnly requires readOnly: true",
+		},
+		{
+			name: "valid propagation with recursive read-only",
+			spec: CuteContainerSpec{
+				Image: "nginx:latest",
+				Volumes: []VolumeMount{
+					{
+						Name:      "host",
+						MountPath: "/host",
+						ReadOnly:  true,
+						MountOptions: &VolumeMountOptions{
+							Propagation:       "rslave",
+							RecursiveReadOnly: true,
+						},
+					},
+				},
+			},
+			yaml: `
+spec:
+  image: nginx:latest
+  volumes:
+    - name: host
+      mountPath: /host
+      readOnly: true
+      mountOptions:
+        propagation: rslave
+        recursiveReadOnly: true
 `,
 			expectError: false,
 		},
@@ -1,8 +1,11 @@
 package resource
 
 import (
+	"cmp"
 	"fmt"
-	"sort"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // DependencyResolver manages resource creation order and dependency tracking
@@ -28,6 +31,21 @@ type ResourceNode struct {
 	Resource     Resource `json:"resource"`
 	Dependencies []string `json:"dependencies"`
 	Dependents   []string `json:"dependents"`
+	Priority     int      `json:"priority"`
+}
+
+// prioritizedResource is implemented by resources that can order their creation among
+// the other resources of their dependency level
+type prioritizedResource interface {
+	GetPriority() int
+}
+
+// resourcePriority returns the creation priority of a resource, which defaults to 0
+func resourcePriority(resource Resource) int {
+	if prioritized, ok := resource.(prioritizedResource); ok {
+		return prioritized.GetPriority()
+	}
+	return 0
 }
 
 // DefaultDependencyResolver implements DependencyResolver
@@ -59,6 +77,7 @@ func (dr *DefaultDependencyResolver) BuildDependencyGraph(resources []Resource)
 			Resource:     resource,
 			Dependencies: make([]string, 0),
 			Dependents:   make([]string, 0),
+			Priority:     resourcePriority(resource),
 		}
 		graph.Edges[key] = make([]string, 0)
 	}
@@ -122,11 +141,8 @@ func (dr *DefaultDependencyResolver) extractDependencies(resource Resource, reso
 	}
 
 	// Add implicit dependencies based on resource type
-	switch res := resource.(type) {
-	case *ContainerResource:
-		dependencies = append(dependencies, dr.extractContainerDependencies(res, resourceMap)...)
-	case *PodResource:
-		dependencies = append(dependencies, dr.extractPodDependencies(res, resourceMap)...)
+	if container, ok := resource.(*ContainerResource); ok {
+		dependencies = append(dependencies, dr.extractContainerDependencies(container, resourceMap)...)
 	}
 
 	return dependencies
@@ -137,8 +153,11 @@ func (dr *DefaultDependencyResolver) extractContainerDependencies(container *Con
 	dependencies := make([]string, 0)
 
 	// Network dependencies
-	for _, networkName := range container.Spec.Networks {
-		networkKey := fmt.Sprintf("%s/%s", ResourceTypeNetwork, networkName)
+	for _, network := range container.Spec.Networks {
+		if network.External {
+			continue
+		}
+		networkKey := fmt.Sprintf("%s/%s", ResourceTypeNetwork, network.Name)
 		if _, exists := resourceMap[networkKey]; exists {
 			dependencies = append(dependencies, networkKey)
 		}
@@ -160,33 +179,48 @@ func (dr *DefaultDependencyResolver) extractContainerDependencies(container *Con
 		}
 	}
 
-	return dependencies
-}
-
-// extractPodDependencies extracts dependencies specific to pods
-func (dr *DefaultDependencyResolver) extractPodDependencies(pod *PodResource, resourceMap map[string]Resource) []string {
-	dependencies := make([]string, 0)
-
-	// Container dependencies (pods depend on their containers)
-	for _, containerName := range pod.Spec.Containers {
-		containerKey := fmt.Sprintf("%s/%s", ResourceTypeContainer, containerName)
-		if _, exists := resourceMap[containerKey]; exists {
-			dependencies = append(dependencies, containerKey)
+	// Pod dependencies: a container can only be created inside a pod that exists, so
+	// it depends on every pod listing it as a member
+	for key, resource := range resourceMap {
+		pod, ok := resource.(*PodResource)
+		if ok && slices.Contains(pod.Spec.Containers, container.GetName()) && !slices.Contains(dependencies, key) {
+			dependencies = append(dependencies, key)
 		}
 	}
 
 	return dependencies
 }
 
+// CircularDependencyError reports resources that depend on each other in a cycle
+type CircularDependencyError struct {
+	// Cycle lists the resource keys along the cycle, starting and ending with the same
+	// resource, each depending on the next
+	Cycle []string
+}
+
+// Error implements the error interface
+func (e *CircularDependencyError) Error() string {
+	return fmt.Sprintf("circular dependency detected: %s", strings.Join(e.Cycle, " → "))
+}
+
 // validateGraph validates the dependency graph for circular dependencies
 func (dr *DefaultDependencyResolver) validateGraph(graph *DependencyGraph) error {
+	if cycle := dr.findCycle(graph); cycle != nil {
+		return &CircularDependencyError{Cycle: cycle}
+	}
+	return nil
+}
+
+// findCycle returns the path of a cycle in the dependency graph, or nil if there is
+// none. Nodes are visited in sorted order, so the same graph reports the same cycle.
+func (dr *DefaultDependencyResolver) findCycle(graph *DependencyGraph) []string {
 	visited := make(map[string]bool)
 	recStack := make(map[string]bool)
 
-	for nodeKey := range graph.Nodes {
+	for _, nodeKey := range slices.Sorted(maps.Keys(graph.Nodes)) {
 		if !visited[nodeKey] {
-			if dr.hasCycle(nodeKey, graph, visited, recStack) {
-				return fmt.Errorf("circular dependency detected involving resource '%s'", nodeKey)
+			if cycle := dr.hasCycle(nodeKey, graph, visited, recStack, nil); cycle != nil {
+				return cycle
 			}
 		}
 	}
@@ -194,23 +228,27 @@ func (dr *DefaultDependencyResolver) validateGraph(graph *DependencyGraph) error
 	return nil
 }
 
-// hasCycle performs DFS to detect cycles in the dependency graph
-func (dr *DefaultDependencyResolver) hasCycle(nodeKey string, graph *DependencyGraph, visited, recStack map[string]bool) bool {
+// hasCycle performs DFS to detect cycles in the dependency graph. path holds the
+// resources on the way to nodeKey; the cycle found, if any, is returned as a path that
+// closes on its first resource.
+func (dr *DefaultDependencyResolver) hasCycle(nodeKey string, graph *DependencyGraph, visited, recStack map[string]bool, path []string) []string {
 	visited[nodeKey] = true
 	recStack[nodeKey] = true
+	path = append(path, nodeKey)
 
 	for _, depKey := range graph.Edges[nodeKey] {
 		if !visited[depKey] {
-			if dr.hasCycle(depKey, graph, visited, recStack) {
-				return true
+			if cycle := dr.hasCycle(depKey, graph, visited, recStack, path); cycle != nil {
+				return cycle
 			}
 		} else if recStack[depKey] {
-			return true
+			cycle := slices.Clone(path[slices.Index(path, depKey):])
+			return append(cycle, depKey)
 		}
 	}
 
 	recStack[nodeKey] = false
-	return false
+	return nil
 }
 
 // topologicalSort performs topological sorting using Kahn's algorithm
@@ -236,8 +274,10 @@ func (dr *DefaultDependencyResolver) topologicalSort(graph *DependencyGraph, rev
 		currentLevel := make([]Resource, 0)
 		nextQueue := make([]string, 0)
 
-		// Sort queue for consistent ordering
-		sort.Strings(queue)
+		// Create higher-priority resources first, breaking ties by key for consistent ordering
+		slices.SortFunc(queue, func(a, b string) int {
+			return cmp.Or(cmp.Compare(graph.Nodes[b].Priority, graph.Nodes[a].Priority), strings.Compare(a, b))
+		})
 
 		for _, nodeKey := range queue {
 			node := graph.Nodes[nodeKey]
@@ -262,7 +302,7 @@ func (dr *DefaultDependencyResolver) topologicalSort(graph *DependencyGraph, rev
 		totalProcessed += len(level)
 	}
 	if totalProcessed != len(graph.Nodes) {
-		return nil, fmt.Errorf("circular dependency detected in resource graph")
+		return nil, &CircularDependencyError{Cycle: dr.findCycle(graph)}
 	}
 
 	return result, nil
@@ -0,0 +1,148 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newCyclicContainers returns containers a, b and c, each depending on the next
+func newCyclicContainers() []Resource {
+	var containers []Resource
+	for _, link := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "a"}} {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = link[0]
+		container.Spec.Image = "nginx:latest"
+		container.Spec.DependsOn = []string{link[1]}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+func TestDependencyResolver_ReportsCyclePath(t *testing.T) {
+	_, err := NewDependencyResolver().BuildDependencyGraph(newCyclicContainers())
+
+	var cycleErr *CircularDependencyError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("Expected a CircularDependencyError, got %v", err)
+	}
+
+	expected := []string{"container/a", "container/b", "container/c", "container/a"}
+	if !slices.Equal(cycleErr.Cycle, expected) {
+		t.Errorf("Expected cycle %v, got %v", expected, cycleErr.Cycle)
+	}
+	if !strings.Contains(err.Error(), "container/a → container/b → container/c → container/a") {
+		t.Errorf("Expected the error to show the cycle, got %q", err)
+	}
+}
+
+func TestReconcile_ReportsDependencyCyclePath(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	result, err := controller.Reconcile(context.Background(), newCyclicContainers(), "demo", false)
+	if err == nil {
+		t.Fatal("Expected the cycle to fail reconciliation")
+	}
+
+	// A cycle is reported once, without retrying
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %+v", result.Errors)
+	}
+	if result.Errors[0].Type != ErrorTypeDependency ||
+		!strings.Contains(result.Errors[0].Message, "container/a → container/b → container/c → container/a") {
+		t.Errorf("Expected a dependency error showing the cycle, got %+v", result.Errors[0])
+	}
+}
+
+func TestDependencyResolver_OrdersLevelByPriority(t *testing.T) {
+	var containers []Resource
+	for name, priority := range map[string]int{"cache": 0, "db": 10, "app": 0, "web": 10, "queue": 5} {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = name
+		container.Spec.Image = "nginx:latest"
+		container.Spec.Priority = priority
+		containers = append(containers, container)
+	}
+
+	resolver := NewDependencyResolver()
+	graph, err := resolver.BuildDependencyGraph(containers)
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	order, err := resolver.GetCreationOrder(graph)
+	if err != nil {
+		t.Fatalf("GetCreationOrder failed: %v", err)
+	}
+
+	var names []string
+	for _, resource := range order[0] {
+		names = append(names, resource.GetName())
+	}
+	expected := []string{"db", "web", "queue", "app", "cache"}
+	if len(order) != 1 || !slices.Equal(names, expected) {
+		t.Errorf("Expected a single level ordered %v, got %v", expected, names)
+	}
+
+	// Priority only orders creation, so changing it does not recreate the container
+	container := containers[0].(*ContainerResource)
+	before, _ := computeContainerSpecHash(container.Spec)
+	container.Spec.Priority++
+	if after, _ := computeContainerSpecHash(container.Spec); before != after {
+		t.Error("Expected the spec hash to ignore the priority")
+	}
+}
+
+func TestDependencyResolver_OrdersNamespaceSharing(t *testing.T) {
+	newContainer := func(name string) *ContainerResource {
+		container := NewContainerResource()
+		container.ObjectMeta.Name = name
+		container.Spec.Image = "nginx:latest"
+		return container
+	}
+	a := newContainer("a")
+	b := newContainer("b")
+	b.Spec.PidMode = "container:a"
+	c := newContainer("c")
+	c.Spec.NetworkMode = "container:b"
+
+	resolver := NewDependencyResolver()
+	graph, err := resolver.BuildDependencyGraph([]Resource{c, b, a})
+	if err != nil {
+		t.Fatalf("BuildDependencyGraph failed: %v", err)
+	}
+	order, err := resolver.GetCreationOrder(graph)
+	if err != nil {
+		t.Fatalf("GetCreationOrder failed: %v", err)
+	}
+
+	var levels [][]string
+	for _, level := range order {
+		var names []string
+		for _, resource := range level {
+			names = append(names, resource.GetName())
+		}
+		levels = append(levels, names)
+	}
+	expected := [][]string{{"a"}, {"b"}, {"c"}}
+	if !slices.EqualFunc(levels, expected, slices.Equal[[]string]) {
+		t.Errorf("Expected levels %v, got %v", expected, levels)
+	}
+
+	// Reconciling creates each container after the one whose namespace it joins
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	result, err := controller.Reconcile(context.Background(), []Resource{c, b, a}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	var created []string
+	for _, action := range result.CreatedResources {
+		created = append(created, action.Name)
+	}
+	if !slices.Equal(created, []string{"a", "b", "c"}) {
+		t.Errorf("Expected a, b and c to be created in order, got %v", created)
+	}
+}
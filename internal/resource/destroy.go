@@ -0,0 +1,113 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Destroy deletes every resource cutepod manages for the chart, without needing its
+// manifests. The deletion order is inferred from the references between the live
+// resources, so containers go before the pods, networks, volumes and secrets they use.
+// Delete-protected volumes and secrets are kept unless the controller forces deletion.
+func (rc *DefaultReconciliationController) Destroy(ctx context.Context, chartName string) (*ReconciliationResult, error) {
+	ctx, span := rc.tracer.Start(ctx, "destroy", Attribute(SpanAttributeChart, chartName))
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	rc.logger.Info("destroy started", "chart", chartName)
+
+	result, err := rc.destroy(ctx, chartName)
+	endSpan(span, err)
+
+	if err != nil {
+		rc.logger.Error("destroy failed", "chart", chartName, "error", err)
+	} else {
+		rc.logger.Info("destroy finished", "chart", chartName,
+			"deleted", len(result.DeletedResources),
+			"errors", len(result.Errors),
+			"duration", result.Duration)
+	}
+
+	return result, err
+}
+
+// destroy deletes the chart's actual resources level by level in reverse dependency order
+func (rc *DefaultReconciliationController) destroy(ctx context.Context, chartName string) (*ReconciliationResult, error) {
+	startTime := time.Now()
+
+	result := &ReconciliationResult{
+		CreatedResources: make([]ResourceAction, 0),
+		UpdatedResources: make([]ResourceAction, 0),
+		DeletedResources: make([]ResourceAction, 0),
+		Errors:           make([]*ReconciliationError, 0),
+		ChartName:        chartName,
+	}
+
+	actualStateByType, err := rc.getCurrentStateWithRetry(ctx, chartName, result)
+	if err != nil {
+		return result, err
+	}
+
+	live := make([]Resource, 0)
+	for _, resources := range actualStateByType {
+		live = append(live, resources...)
+	}
+
+	deletionOrder, err := rc.liveDeletionOrder(live)
+	if err != nil {
+		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+			fmt.Sprintf("failed to determine deletion order: %v", err), err, false)
+	}
+	result.DeletionLevels = resourceLevels(deletionOrder)
+
+	rc.deleteInOrder(ctx, result, deletionOrder)
+
+	rc.updateReconciliationStatus(chartName, result, startTime)
+	result.Duration = time.Since(startTime)
+	result.PerTypeDurations = result.durationsByType()
+	if len(live) == 0 {
+		result.Summary = "No resources to destroy"
+	} else {
+		result.Summary = rc.generateSummary(result)
+	}
+
+	return result, nil
+}
+
+// liveDeletionOrder orders resources read back from Podman for deletion, inferring their
+// dependencies from the references between them since they have no manifests
+func (rc *DefaultReconciliationController) liveDeletionOrder(resources []Resource) ([][]Resource, error) {
+	// Sorted so that resources of the same level are deleted in a stable order
+	sorted := slices.Clone(resources)
+	slices.SortFunc(sorted, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(exportOrder, a.GetType()), slices.Index(exportOrder, b.GetType())),
+			cmp.Compare(a.GetName(), b.GetName()))
+	})
+
+	dependencyGraph, err := rc.dependencyResolver.BuildDependencyGraph(sorted)
+	if err != nil {
+		return nil, err
+	}
+	return rc.dependencyResolver.GetDeletionOrder(dependencyGraph)
+}
+
+// deleteInOrder deletes every resource of deletionOrder, one level after the other
+func (rc *DefaultReconciliationController) deleteInOrder(ctx context.Context, result *ReconciliationResult, deletionOrder [][]Resource) {
+	for levelIndex, level := range deletionOrder {
+		for _, resource := range level {
+			rc.executeDeleteWithRetry(ctx, result, resource, levelIndex)
+
+			// Check if context was cancelled
+			if ctx.Err() != nil {
+				rc.addError(result, ErrorTypeConfiguration, ResourceReference{},
+					"destroy cancelled by context", ctx.Err(), false)
+				return
+			}
+		}
+	}
+}
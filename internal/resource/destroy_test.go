@@ -0,0 +1,69 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"testing"
+)
+
+func TestDestroy_DeletesLiveResourcesInDependencyOrder(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	network.Spec.Driver = "bridge"
+	network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{}
+	volume.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	volume.SetAnnotations(map[string]string{labels.AnnotationDeleteProtect: "true"})
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+
+	if _, err := controller.Reconcile(ctx, []Resource{network, volume, container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	result, err := controller.Destroy(ctx, "demo")
+	if err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	// The container uses the network, so it is deleted first
+	actions := make(map[ResourceReference]ResourceAction)
+	for _, action := range result.DeletedResources {
+		actions[ResourceReference{Type: action.Type, Name: action.Name}] = action
+	}
+	containerAction := actions[ResourceReference{Type: ResourceTypeContainer, Name: "web"}]
+	networkAction := actions[ResourceReference{Type: ResourceTypeNetwork, Name: "backend"}]
+	if containerAction.Action != ActionDelete || networkAction.Action != ActionDelete {
+		t.Fatalf("Expected the container and network to be deleted, got %+v", result.DeletedResources)
+	}
+	if networkAction.Timestamp.Before(containerAction.Timestamp) {
+		t.Error("Expected the container to be deleted before the network it is attached to")
+	}
+	if mockClient.GetCallCount("RemoveContainer") != 1 || mockClient.GetCallCount("RemoveNetwork") != 1 {
+		t.Error("Expected the container and network to be removed from Podman")
+	}
+
+	// The protected volume is kept
+	if action := actions[ResourceReference{Type: ResourceTypeVolume, Name: "data"}]; action.Action != ActionSkip {
+		t.Errorf("Expected the protected volume to be skipped, got %+v", action)
+	}
+	if mockClient.GetCallCount("RemoveVolume") != 0 {
+		t.Error("Expected the protected volume not to be removed")
+	}
+
+	status, err := controller.GetStatus("demo")
+	if err != nil || status.ResourceCounts["deleted"] != 2 {
+		t.Errorf("Expected 2 deletions in the status, got %+v (err: %v)", status, err)
+	}
+}
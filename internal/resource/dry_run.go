@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"fmt"
+	"strings"
+)
+
+// plannedCreations returns the resources the diffs would create
+func plannedCreations(diffs ...*StateDiff) map[ResourceReference]bool {
+	planned := make(map[ResourceReference]bool)
+	for _, diff := range diffs {
+		for _, resource := range diff.ToCreate {
+			planned[ResourceReference{Type: resource.GetType(), Name: resource.GetName()}] = true
+		}
+	}
+	return planned
+}
+
+// dryRunValidate checks the resources a dry run would create against the live state of
+// Podman without changing anything. Problems an apply would run into, such as a local
+// image that is missing, a missing secret or network, or a name already taken outside
+// the chart, are recorded on the result as recoverable validation errors. Secrets and
+// networks in planned are about to be created, so they are not expected to exist yet.
+func (rc *DefaultReconciliationController) dryRunValidate(ctx context.Context, result *ReconciliationResult, diff *StateDiff, planned map[ResourceReference]bool) {
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		rc.addError(result, ErrorTypePodmanAPI, ResourceReference{},
+			fmt.Sprintf("skipped checking the plan against Podman: %v", err), err, true)
+		return
+	}
+
+	for _, resource := range diff.ToCreate {
+		ref := ResourceReference{Type: resource.GetType(), Name: resource.GetName()}
+		warn := func(format string, args ...any) {
+			rc.addError(result, ErrorTypeValidation, ref, fmt.Sprintf(format, args...), nil, true)
+		}
+
+		switch r := resource.(type) {
+		case *ContainerResource:
+			// Images from localhost only exist where they were built or loaded, so one
+			// missing there cannot be pulled either
+			if r.Spec.Build == nil && strings.HasPrefix(qualifiedImageName(r.Spec.Image), "localhost/") {
+				if _, err := lookupImage(ctx, podmanClient, r.Spec.Image); err != nil {
+					warn("image %s is not available locally and cannot be pulled", r.Spec.Image)
+				}
+			}
+			for _, secret := range r.Spec.Secrets {
+				if planned[ResourceReference{Type: ResourceTypeSecret, Name: secret.Name}] {
+					continue
+				}
+				if _, err := podmanClient.InspectSecret(ctx, secret.Name); err != nil {
+					warn("secret '%s' does not exist in Podman", secret.Name)
+				}
+			}
+			for _, network := range r.Spec.Networks {
+				if network.Name == defaultPodmanNetwork || planned[ResourceReference{Type: ResourceTypeNetwork, Name: network.Name}] {
+					continue
+				}
+				if _, err := podmanClient.InspectNetwork(ctx, network.Name); err != nil {
+					warn("network '%s' does not exist in Podman", network.Name)
+				}
+			}
+
+		case *SecretResource:
+			// The chart's own secrets were read back as actual state, so one found here
+			// belongs to something else and creating it would fail
+			if _, err := podmanClient.InspectSecret(ctx, r.GetName()); err == nil {
+				warn("secret '%s' already exists in Podman outside of this chart; creating it will fail", r.GetName())
+			}
+
+		case *NetworkResource:
+			if _, err := podmanClient.InspectNetwork(ctx, r.GetName()); err == nil {
+				warn("network '%s' already exists in Podman outside of this chart; creating it will fail", r.GetName())
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"strings"
+	"testing"
+)
+
+func TestReconcile_DryRunValidatesAgainstLiveState(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	ctx := context.Background()
+
+	// A network of the same name created outside of the chart
+	if _, err := mockClient.CreateNetwork(ctx, podman.NetworkSpec{Name: "shared"}); err != nil {
+		t.Fatalf("Failed to create mock network: %v", err)
+	}
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "shared"
+	container := newExplainTestContainer("localhost/app:dev")
+	container.Spec.Networks = []NetworkAttachment{{Name: "shared"}}
+
+	controller := NewReconciliationController(mockClient)
+	result, err := controller.Reconcile(ctx, []Resource{network, container}, "demo", true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+
+	warnings := make(map[ResourceReference]string)
+	for _, reconciliationError := range result.Errors {
+		if reconciliationError.Type != ErrorTypeValidation || !reconciliationError.Recoverable {
+			t.Errorf("Expected recoverable validation warnings, got %+v", reconciliationError)
+		}
+		warnings[reconciliationError.Resource] = reconciliationError.Message
+	}
+
+	if message := warnings[ResourceReference{Type: ResourceTypeNetwork, Name: "shared"}]; !strings.Contains(message, "already exists in Podman outside of this chart") {
+		t.Errorf("Expected a warning about the existing network, got %q", message)
+	}
+	if message := warnings[ResourceReference{Type: ResourceTypeContainer, Name: "web"}]; !strings.Contains(message, "cannot be pulled") {
+		t.Errorf("Expected a warning about the missing local image, got %q", message)
+	}
+	if len(warnings) != 2 {
+		t.Errorf("Expected 2 warnings, got %+v", result.Errors)
+	}
+
+	if mockClient.GetCallCount("CreateNetwork") != 1 || mockClient.GetCallCount("CreateContainer") != 0 {
+		t.Error("Expected the dry run not to create anything")
+	}
+}
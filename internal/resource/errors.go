@@ -1,6 +1,8 @@
 package resource
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -21,6 +23,9 @@ type ReconciliationError struct {
 	Message     string            `json:"message"`
 	Cause       error             `json:"cause,omitempty"`
 	Recoverable bool              `json:"recoverable"`
+
+	// Logs holds the last log lines of a container that failed to start or become ready
+	Logs []string `json:"logs,omitempty"`
 }
 
 // Error implements the error interface
@@ -34,6 +39,53 @@ func (r *ReconciliationError) Unwrap() error {
 	return r.Cause
 }
 
+// reconciliationErrorJSON is the serialized form of a ReconciliationError, which carries
+// its cause as a message since error values have no JSON form of their own
+type reconciliationErrorJSON struct {
+	Type        ErrorType         `json:"type"`
+	Resource    ResourceReference `json:"resource"`
+	Message     string            `json:"message"`
+	Cause       string            `json:"cause,omitempty"`
+	Recoverable bool              `json:"recoverable"`
+	Logs        []string          `json:"logs,omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler, writing the cause as its message
+func (r *ReconciliationError) MarshalJSON() ([]byte, error) {
+	serialized := reconciliationErrorJSON{
+		Type:        r.Type,
+		Resource:    r.Resource,
+		Message:     r.Message,
+		Recoverable: r.Recoverable,
+		Logs:        r.Logs,
+	}
+	if r.Cause != nil {
+		serialized.Cause = r.Cause.Error()
+	}
+	return json.Marshal(serialized)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The cause comes back as a plain error with
+// the original message.
+func (r *ReconciliationError) UnmarshalJSON(data []byte) error {
+	var serialized reconciliationErrorJSON
+	if err := json.Unmarshal(data, &serialized); err != nil {
+		return err
+	}
+
+	*r = ReconciliationError{
+		Type:        serialized.Type,
+		Resource:    serialized.Resource,
+		Message:     serialized.Message,
+		Recoverable: serialized.Recoverable,
+		Logs:        serialized.Logs,
+	}
+	if serialized.Cause != "" {
+		r.Cause = errors.New(serialized.Cause)
+	}
+	return nil
+}
+
 // NewReconciliationError creates a new ReconciliationError
 func NewReconciliationError(errorType ErrorType, resource ResourceReference, message string, cause error, recoverable bool) *ReconciliationError {
 	return &ReconciliationError{
@@ -65,15 +117,16 @@ func NewConfigurationError(resource ResourceReference, message string, cause err
 	return NewReconciliationError(ErrorTypeConfiguration, resource, message, cause, false)
 }
 
-// IsReconciliationError checks if an error is a ReconciliationError
+// IsReconciliationError checks if an error is, or wraps, a ReconciliationError
 func IsReconciliationError(err error) bool {
-	_, ok := err.(*ReconciliationError)
+	_, ok := AsReconciliationError(err)
 	return ok
 }
 
-// AsReconciliationError attempts to cast an error to ReconciliationError
+// AsReconciliationError finds the first ReconciliationError in the error's chain
 func AsReconciliationError(err error) (*ReconciliationError, bool) {
-	if recErr, ok := err.(*ReconciliationError); ok {
+	var recErr *ReconciliationError
+	if errors.As(err, &recErr) {
 		return recErr, true
 	}
 	return nil, false
@@ -0,0 +1,63 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestReconciliationError_UnwrapsCause(t *testing.T) {
+	errImageMissing := errors.New("image missing")
+	recErr := NewPodmanAPIError(ResourceReference{Type: ResourceTypeContainer, Name: "web"},
+		"failed to create resource", fmt.Errorf("pull failed: %w", errImageMissing), false)
+
+	if !errors.Is(recErr, errImageMissing) {
+		t.Error("Expected errors.Is to find the sentinel wrapped in the cause")
+	}
+
+	// The error is still found when wrapped itself
+	wrapped := fmt.Errorf("reconcile demo: %w", recErr)
+	if !errors.Is(wrapped, errImageMissing) {
+		t.Error("Expected errors.Is to find the sentinel through the wrapped error")
+	}
+	found, ok := AsReconciliationError(wrapped)
+	if !ok || found != recErr {
+		t.Errorf("Expected AsReconciliationError to find the error, got %v", found)
+	}
+	if !IsReconciliationError(wrapped) || IsReconciliationError(errImageMissing) {
+		t.Error("Expected IsReconciliationError to only match errors carrying a ReconciliationError")
+	}
+}
+
+func TestReconciliationError_MarshalJSON(t *testing.T) {
+	recErr := NewPodmanAPIError(ResourceReference{Type: ResourceTypeContainer, Name: "web"},
+		"failed to create resource", errors.New("connection reset by peer"), true)
+
+	data, err := json.Marshal(recErr)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	for _, expected := range []string{
+		`"type":"podman_api"`,
+		`"resource":{"type":"container","name":"web"}`,
+		`"message":"failed to create resource"`,
+		`"cause":"connection reset by peer"`,
+		`"recoverable":true`,
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("Expected %s in %s", expected, data)
+		}
+	}
+
+	// Without a cause the field is left out
+	data, err = json.Marshal(NewValidationError(ResourceReference{}, "invalid", nil))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(data), "cause") {
+		t.Errorf("Expected no cause in %s", data)
+	}
+}
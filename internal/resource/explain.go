@@ -0,0 +1,104 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ExplainResult describes what reconciliation would do to a single resource and why
+type ExplainResult struct {
+	Resource ResourceReference `json:"resource"`
+	Action   ActionType        `json:"action"`
+	Reasons  []string          `json:"reasons,omitempty"`
+	Diffs    []FieldDiff       `json:"diffs,omitempty"`
+	Message  string            `json:"message"`
+}
+
+// Explain compares the desired and actual state of a single resource and reports the
+// exact fields that differ, without changing anything on the system. The verdict and
+// reasons come from the same comparison reconcile runs, so a field difference the
+// resource's manager ignores is not reported, a paused resource is skipped, and a
+// container using a rotated secret is recreated.
+func (rc *DefaultReconciliationController) Explain(ctx context.Context, manifests []Resource, chartName string, resourceRef ResourceReference) (*ExplainResult, error) {
+	if _, exists := rc.managers[resourceRef.Type]; !exists {
+		return nil, fmt.Errorf("unsupported resource type: %s", resourceRef.Type)
+	}
+
+	result := &ExplainResult{
+		Resource: resourceRef,
+		Diffs:    make([]FieldDiff, 0),
+	}
+
+	// Errors are only collected here to satisfy the helpers; Explain itself is side-effect free
+	scratch := &ReconciliationResult{Errors: make([]*ReconciliationError, 0)}
+
+	assignPodMembers(manifests)
+	rc.applyLabelPrefix(manifests)
+
+	actualStateByType, err := rc.getCurrentState(ctx, chartName, scratch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actual state: %w", err)
+	}
+
+	diff, err := rc.compareAllStatesWithValidation(manifests, actualStateByType, scratch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare resources: %w", err)
+	}
+	if len(scratch.Errors) > 0 {
+		return nil, fmt.Errorf("explain failed: %w", scratch.Errors[len(scratch.Errors)-1])
+	}
+
+	matches := func(resource Resource) bool {
+		return resource.GetType() == resourceRef.Type && resource.GetName() == resourceRef.Name
+	}
+
+	for _, pair := range diff.ToUpdate {
+		if !matches(pair.Desired) {
+			continue
+		}
+		result.Action = ActionUpdate
+		result.Reasons = pair.Reasons
+		if len(pair.Diffs) == 0 {
+			result.Message = fmt.Sprintf("resource would be updated: %s", strings.Join(pair.Reasons, ", "))
+		} else {
+			result.Diffs = pair.Diffs
+			result.Message = fmt.Sprintf("resource would be updated: %d field(s) differ", len(pair.Diffs))
+		}
+		return result, nil
+	}
+	if slices.ContainsFunc(diff.ToCreate, matches) {
+		result.Action = ActionCreate
+		result.Message = "resource does not exist and would be created"
+		return result, nil
+	}
+	if slices.ContainsFunc(diff.ToDelete, matches) {
+		result.Action = ActionDelete
+		result.Message = "resource is not in the manifests and would be deleted"
+		return result, nil
+	}
+
+	// Changes reconcile skips, such as those to paused resources, are recorded as skipped actions
+	skipped := slices.Concat(scratch.CreatedResources, scratch.UpdatedResources, scratch.DeletedResources)
+	for _, action := range skipped {
+		if action.Type == resourceRef.Type && action.Name == resourceRef.Name {
+			result.Action = ActionSkip
+			result.Diffs = append(result.Diffs, action.Diffs...)
+			result.Message = fmt.Sprintf("resource would be skipped: %s", action.Message)
+			return result, nil
+		}
+	}
+
+	if !slices.ContainsFunc(manifests, matches) && !slices.ContainsFunc(actualStateByType[resourceRef.Type], matches) {
+		return nil, fmt.Errorf("resource %s/%s not found in manifests or on the system", resourceRef.Type, resourceRef.Name)
+	}
+
+	result.Action = ActionSkip
+	if slices.ContainsFunc(diff.Unchanged, matches) {
+		result.Message = "resource is up to date"
+	} else {
+		result.Message = "resource would be left as it is"
+	}
+	return result, nil
+}
@@ -0,0 +1,220 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func newExplainTestContainer(image string) *ContainerResource {
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "web"
+	container.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	container.Spec.Image = image
+	container.Spec.Env = []EnvVar{
+		{Name: "MODE", Value: "production"},
+		{Name: "PORT", Value: "8080"},
+	}
+	container.Spec.RestartPolicy = "always"
+	return container
+}
+
+func TestExplain_ChurningContainerWithSingleDriftedField(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	// Deploy the container once so it exists on the system
+	deployed := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(ctx, []Resource{deployed}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// The manifest now drifts on a single field
+	desired := newExplainTestContainer("nginx:1.26")
+
+	result, err := controller.Explain(ctx, []Resource{desired}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	if result.Action != ActionUpdate {
+		t.Errorf("Expected action %s, got %s (%s)", ActionUpdate, result.Action, result.Message)
+	}
+
+	if len(result.Diffs) != 1 {
+		t.Fatalf("Expected exactly 1 field diff, got %d: %+v", len(result.Diffs), result.Diffs)
+	}
+
+	diff := result.Diffs[0]
+	if diff.Path != "spec.image" {
+		t.Errorf("Expected diff path 'spec.image', got '%s'", diff.Path)
+	}
+	if diff.OldValue != "nginx:1.25" {
+		t.Errorf("Expected old value 'nginx:1.25', got '%s'", diff.OldValue)
+	}
+	if diff.NewValue != "nginx:1.26" {
+		t.Errorf("Expected new value 'nginx:1.26', got '%s'", diff.NewValue)
+	}
+
+	// Explain must not mutate anything
+	if mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Error("Expected Explain not to remove any container")
+	}
+}
+
+func TestExplain_UpToDateContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	deployed := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(ctx, []Resource{deployed}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// Same spec with env declared in a different order
+	desired := newExplainTestContainer("nginx:1.25")
+	desired.Spec.Env = []EnvVar{
+		{Name: "PORT", Value: "8080"},
+		{Name: "MODE", Value: "production"},
+	}
+
+	result, err := controller.Explain(ctx, []Resource{desired}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	if result.Action != ActionSkip {
+		t.Errorf("Expected action %s, got %s", ActionSkip, result.Action)
+	}
+
+	if len(result.Diffs) != 0 {
+		t.Errorf("Expected no field diffs, got %+v", result.Diffs)
+	}
+}
+
+func TestExplain_IgnoresDifferencesTheManagerIgnores(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// Podman cannot relabel a container, so reconcile leaves a label-only change alone
+	relabeled := newExplainTestContainer("nginx:1.25")
+	relabeled.SetLabels(labels.MergeLabels(relabeled.GetLabels(), map[string]string{"team": "web"}))
+
+	result, err := controller.Explain(ctx, []Resource{relabeled}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if result.Action != ActionSkip || len(result.Diffs) != 0 || len(result.Reasons) != 0 {
+		t.Errorf("Expected the container to be up to date, got %+v", result)
+	}
+}
+
+func TestExplain_PausedResourceIsSkipped(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	paused := newExplainTestContainer("nginx:1.26")
+	paused.SetAnnotations(map[string]string{labels.AnnotationReconcile: "paused"})
+
+	result, err := controller.Explain(ctx, []Resource{paused}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if result.Action != ActionSkip || !strings.Contains(result.Message, "paused") {
+		t.Errorf("Expected the paused container to be skipped, got %s (%s)", result.Action, result.Message)
+	}
+	if !slices.ContainsFunc(result.Diffs, func(diff FieldDiff) bool { return diff.Path == "spec.image" }) {
+		t.Errorf("Expected the pending image change to be reported, got %+v", result.Diffs)
+	}
+}
+
+func TestExplain_ContainerUsingRotatedSecret(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	newSecret := func(token string) *SecretResource {
+		secret := NewSecretResource()
+		secret.ObjectMeta.Name = "creds"
+		secret.Spec.Type = SecretTypeOpaque
+		secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte(token))}
+		secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+		return secret
+	}
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Secrets = []SecretReference{{Name: "creds", Env: true}}
+
+	if _, err := controller.Reconcile(ctx, []Resource{newSecret("v1"), container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// The container's own spec is unchanged, but it still holds the old secret values
+	result, err := controller.Explain(ctx, []Resource{newSecret("v2"), container}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+	if result.Action != ActionUpdate {
+		t.Fatalf("Expected the container to be recreated, got %s (%s)", result.Action, result.Message)
+	}
+	if len(result.Reasons) != 1 || result.Reasons[0] != "secret 'creds' is rotated" {
+		t.Errorf("Expected the rotated secret as the reason, got %v", result.Reasons)
+	}
+}
+
+func TestExplain_MissingResource(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+
+	desired := newExplainTestContainer("nginx:1.25")
+
+	result, err := controller.Explain(context.Background(), []Resource{desired}, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "web",
+	})
+	if err != nil {
+		t.Fatalf("Explain failed: %v", err)
+	}
+
+	if result.Action != ActionCreate {
+		t.Errorf("Expected action %s, got %s", ActionCreate, result.Action)
+	}
+
+	_, err = controller.Explain(context.Background(), nil, "demo", ResourceReference{
+		Type: ResourceTypeContainer,
+		Name: "unknown",
+	})
+	if err == nil {
+		t.Error("Expected error for a resource that exists neither in manifests nor on the system")
+	}
+}
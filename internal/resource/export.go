@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"cmp"
+	"context"
+	"cutepod/internal/labels"
+	"fmt"
+	"slices"
+)
+
+// exportOrder lists resource types in the order a manifest would declare them
+var exportOrder = []ResourceType{
+	ResourceTypeNetwork,
+	ResourceTypeVolume,
+	ResourceTypeSecret,
+	ResourceTypePod,
+	ResourceTypeContainer,
+}
+
+// bookkeepingLabels are set by cutepod itself and are left out of exported manifests
+var bookkeepingLabels = []string{
+	labels.LabelSpecHash,
+	labels.LabelContainerfileHash,
+	labels.LabelSecretHash,
+	labels.LabelSecretEnvKeys,
+	labels.LabelEmptyDirs,
+}
+
+// ExportState reads the chart's actual resources back as manifests, as a starting point
+// for bringing an existing deployment under cutepod. Resources are ordered by type, then
+// by name. Labels and annotations cutepod manages are removed, and secrets carry metadata
+// only since Podman does not expose their data.
+func (rc *DefaultReconciliationController) ExportState(ctx context.Context, chartName string) ([]Resource, error) {
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	exported := make([]Resource, 0)
+	for _, resourceType := range exportOrder {
+		manager, exists := rc.managers[resourceType]
+		if !exists {
+			continue
+		}
+
+		resources, err := manager.GetActualState(ctx, chartName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get actual state for %s: %w", resourceType, err)
+		}
+
+		slices.SortFunc(resources, func(a, b Resource) int {
+			return cmp.Compare(a.GetName(), b.GetName())
+		})
+
+		for _, resource := range resources {
+			exported = append(exported, prepareForExport(resource, rc.labelPrefix))
+		}
+	}
+
+	return exported, nil
+}
+
+// prepareForExport strips bookkeeping, including the standard labels under labelPrefix,
+// from a resource read back from Podman and puts lists whose order Podman does not
+// preserve in a stable order
+func prepareForExport(resource Resource, labelPrefix string) Resource {
+	standardKeys := labels.StandardKeys(labelPrefix)
+	exportedLabels := make(map[string]string, len(resource.GetLabels()))
+	for key, value := range resource.GetLabels() {
+		if !slices.Contains(bookkeepingLabels, key) && !slices.Contains(standardKeys, key) {
+			exportedLabels[key] = value
+		}
+	}
+	if len(exportedLabels) == 0 {
+		exportedLabels = nil
+	}
+	resource.SetLabels(exportedLabels)
+
+	if secret, ok := resource.(*SecretResource); ok {
+		secret.SetAnnotations(nil)
+	}
+
+	if pod, ok := resource.(*PodResource); ok {
+		pod.SetAnnotations(nil)
+	}
+
+	if container, ok := resource.(*ContainerResource); ok {
+		container.SetAnnotations(userAnnotations(container))
+
+		slices.SortFunc(container.Spec.Ports, func(a, b ContainerPort) int {
+			return cmp.Or(
+				cmp.Compare(a.ContainerPort, b.ContainerPort),
+				cmp.Compare(a.HostPort, b.HostPort),
+				cmp.Compare(a.Protocol, b.Protocol),
+			)
+		})
+	}
+
+	return resource
+}
@@ -0,0 +1,65 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"testing"
+)
+
+func TestExportState_ReconstructsManifests(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	network.Spec.Driver = "bridge"
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.SetLabels(labels.MergeLabels(container.GetLabels(), map[string]string{"tier": "web"}))
+	container.Spec.Ports = []ContainerPort{
+		{ContainerPort: 8443, HostPort: 443, Protocol: "TCP"},
+		{ContainerPort: 8080, HostPort: 80, Protocol: "TCP"},
+	}
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+
+	if _, err := controller.Reconcile(ctx, []Resource{container, network}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	exported, err := controller.ExportState(ctx, "demo")
+	if err != nil {
+		t.Fatalf("ExportState failed: %v", err)
+	}
+
+	if len(exported) != 2 {
+		t.Fatalf("Expected 2 exported resources, got %d", len(exported))
+	}
+	if exported[0].GetType() != ResourceTypeNetwork || exported[1].GetType() != ResourceTypeContainer {
+		t.Fatalf("Expected the network before the container, got %s then %s", exported[0].GetType(), exported[1].GetType())
+	}
+
+	exportedContainer := exported[1].(*ContainerResource)
+	if exportedContainer.Spec.Image != "nginx:1.25" {
+		t.Errorf("Expected image nginx:1.25, got %s", exportedContainer.Spec.Image)
+	}
+	if len(exportedContainer.Spec.Env) != 2 {
+		t.Errorf("Expected 2 env vars, got %v", exportedContainer.Spec.Env)
+	}
+	if len(exportedContainer.Spec.Ports) != 2 || exportedContainer.Spec.Ports[0].ContainerPort != 8080 {
+		t.Errorf("Expected ports sorted by container port, got %v", exportedContainer.Spec.Ports)
+	}
+	if len(exportedContainer.Spec.Networks) != 1 || exportedContainer.Spec.Networks[0].Name != "backend" {
+		t.Errorf("Expected the backend network attachment, got %v", exportedContainer.Spec.Networks)
+	}
+
+	exportedLabels := exportedContainer.GetLabels()
+	if len(exportedLabels) != 1 || exportedLabels["tier"] != "web" {
+		t.Errorf("Expected only user labels to be exported, got %v", exportedLabels)
+	}
+	if len(exportedContainer.GetAnnotations()) != 0 {
+		t.Errorf("Expected bookkeeping annotations to be removed, got %v", exportedContainer.GetAnnotations())
+	}
+}
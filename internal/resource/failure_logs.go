@@ -0,0 +1,70 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+)
+
+const (
+	// defaultFailureLogLines is how many log lines of a failed container are kept by default
+	defaultFailureLogLines = 20
+
+	// maxFailureLogLines caps WithFailureLogLines so that errors stay readable
+	maxFailureLogLines = 500
+)
+
+// WithFailureLogLines sets how many of its last log lines are attached to the error of a
+// container that fails to start, to run its postStart hook or to become ready. Values
+// above 500 are capped and zero turns log collection off. The default is 20.
+func WithFailureLogLines(lines int) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.failureLogLines = min(max(lines, 0), maxFailureLogLines)
+	}
+}
+
+// containerLogsError is the failure of a container, with its last log lines
+type containerLogsError struct {
+	err  error
+	logs []string
+}
+
+// Error implements the error interface
+func (e *containerLogsError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the failure the logs were collected for
+func (e *containerLogsError) Unwrap() error {
+	return e.err
+}
+
+// withContainerLogs attaches the last lines of the named container's logs to err, so
+// that they survive the container being removed. Logs that cannot be read are skipped.
+func withContainerLogs(ctx context.Context, client podman.PodmanClient, name string, lines int, err error) error {
+	if lines <= 0 {
+		return err
+	}
+
+	logs, logErr := client.ContainerLogs(ctx, name, lines)
+	if logErr != nil || len(logs) == 0 {
+		return err
+	}
+	return &containerLogsError{err: err, logs: logs}
+}
+
+// containerLogs returns the log lines attached to err by withContainerLogs, if any
+func containerLogs(err error) []string {
+	var logsErr *containerLogsError
+	if errors.As(err, &logsErr) {
+		return logsErr.logs
+	}
+	return nil
+}
+
+// attachContainerLogs adds the log lines carried by err to the error last recorded on result
+func attachContainerLogs(result *ReconciliationResult, err error) {
+	if logs := containerLogs(err); len(logs) > 0 && len(result.Errors) > 0 {
+		result.Errors[len(result.Errors)-1].Logs = logs
+	}
+}
@@ -0,0 +1,408 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FieldDiff describes a single field whose actual value differs from the desired one
+type FieldDiff struct {
+	Path     string `json:"path"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// fieldComparison extracts a normalized, printable value for one field of a resource.
+// Values are normalized the same way the resource managers compare them, so that
+// ordering-only differences (env, ports, ...) never show up as diffs.
+type fieldComparison struct {
+	path  string
+	value func(Resource) string
+	// metadata is set instead of value for label and annotation maps, so that keys the
+	// comparator ignores can be left out
+	metadata func(Resource) map[string]string
+}
+
+// format returns the printable value of the field, without the metadata keys matching
+// ignoredKeys
+func (c fieldComparison) format(resource Resource, ignoredKeys []string) string {
+	if c.metadata != nil {
+		return formatStringMap(withoutMatchingKeys(c.metadata(resource), ignoredKeys))
+	}
+	return c.value(resource)
+}
+
+// secretValueMask replaces secret values in diffs so they never leak into output
+const secretValueMask = "(hidden)"
+
+var labelFieldComparisons = []fieldComparison{
+	{path: "metadata.labels", metadata: func(r Resource) map[string]string { return r.GetLabels() }},
+}
+
+var containerFieldComparisons = []fieldComparison{
+	{path: "metadata.annotations", metadata: func(r Resource) map[string]string { return userAnnotations(r.(*ContainerResource)) }},
+	{path: "spec.image", value: func(r Resource) string { return r.(*ContainerResource).Spec.Image }},
+	{path: "spec.command", value: func(r Resource) string {
+		// A commandString that cannot be split is reported by validation instead
+		command, _ := containerCommand(r.(*ContainerResource).Spec)
+		return formatStringSlice(command)
+	}},
+	{path: "spec.args", value: func(r Resource) string { return formatStringSlice(r.(*ContainerResource).Spec.Args) }},
+	{path: "spec.workingDir", value: func(r Resource) string { return r.(*ContainerResource).Spec.WorkingDir }},
+	{path: "spec.ipcMode", value: func(r Resource) string { return r.(*ContainerResource).Spec.IpcMode }},
+	{path: "spec.pidMode", value: func(r Resource) string { return r.(*ContainerResource).Spec.PidMode }},
+	{path: "spec.utsMode", value: func(r Resource) string { return r.(*ContainerResource).Spec.UtsMode }},
+	{path: "spec.hostname", value: func(r Resource) string { return containerHostname(r.(*ContainerResource)) }},
+	{path: "spec.networkMode", value: func(r Resource) string { return r.(*ContainerResource).Spec.NetworkMode }},
+	{path: "spec.securityContext.readOnlyRootFilesystem", value: func(r Resource) string {
+		return formatBool(readOnlyRootFilesystem(r.(*ContainerResource).Spec))
+	}},
+	{path: "spec.securityContext.seccompProfile", value: func(r Resource) string { return seccompProfile(r.(*ContainerResource).Spec) }},
+	{path: "spec.env", value: func(r Resource) string { return formatEnvVars(r.(*ContainerResource).Spec.Env) }},
+	{path: "spec.ports", value: func(r Resource) string { return formatContainerPorts(r.(*ContainerResource).Spec.Ports) }},
+	{path: "spec.volumes", value: func(r Resource) string { return formatVolumeMounts(r.(*ContainerResource).Spec.Volumes) }},
+	{path: "spec.networks", value: func(r Resource) string { return formatNetworkAttachments(r.(*ContainerResource).Spec.Networks) }},
+	{path: "spec.envFrom", value: func(r Resource) string { return formatEnvFromSources(r.(*ContainerResource).Spec.EnvFrom) }},
+	{path: "spec.secrets", value: func(r Resource) string { return formatSecretReferences(r.(*ContainerResource).Spec.Secrets) }},
+	{path: "spec.restartPolicy", value: func(r Resource) string { return r.(*ContainerResource).Spec.RestartPolicy }},
+	{path: "spec.init", value: func(r Resource) string { return formatBool(initEnabled(r.(*ContainerResource).Spec)) }},
+	{path: "spec.autoRemove", value: func(r Resource) string { return formatBool(autoRemove(r.(*ContainerResource).Spec)) }},
+	{path: "spec.stdin", value: func(r Resource) string { return formatBool(stdinOpen(r.(*ContainerResource).Spec)) }},
+	{path: "spec.tty", value: func(r Resource) string { return formatBool(ttyEnabled(r.(*ContainerResource).Spec)) }},
+}
+
+var networkFieldComparisons = []fieldComparison{
+	{path: "spec.driver", value: func(r Resource) string { return r.(*NetworkResource).Spec.Driver }},
+	{path: "spec.subnet", value: func(r Resource) string { return r.(*NetworkResource).Spec.Subnet }},
+	{path: "spec.gateway", value: func(r Resource) string { return r.(*NetworkResource).Spec.Gateway }},
+	{path: "spec.options", value: func(r Resource) string { return formatStringMap(r.(*NetworkResource).Spec.Options) }},
+	{path: "spec.internal", value: func(r Resource) string { return formatBool(r.(*NetworkResource).Spec.Internal) }},
+}
+
+var volumeFieldComparisons = []fieldComparison{
+	{path: "spec.type", value: func(r Resource) string { return string(r.(*VolumeResource).Spec.Type) }},
+	{path: "spec.hostPath", value: func(r Resource) string { return formatHostPath(r.(*VolumeResource).Spec.HostPath) }},
+	{path: "spec.emptyDir", value: func(r Resource) string { return formatEmptyDir(r.(*VolumeResource).Spec.EmptyDir) }},
+	{path: "spec.volume", value: func(r Resource) string { return formatVolumeSource(r.(*VolumeResource).Spec.Volume) }},
+	{path: "spec.securityContext", value: func(r Resource) string {
+		return formatVolumeSecurityContext(r.(*VolumeResource).Spec.SecurityContext)
+	}},
+}
+
+var secretFieldComparisons = []fieldComparison{
+	{path: "spec.type", value: func(r Resource) string { return string(secretType(r.(*SecretResource).Spec)) }},
+}
+
+// DiffResources returns the field-level differences between desired and actual.
+// OldValue holds the actual value and NewValue the desired one.
+func DiffResources(desired, actual Resource) ([]FieldDiff, error) {
+	return diffResources(desired, actual, nil)
+}
+
+// diffResources is DiffResources leaving out the label and annotation keys matching
+// ignoredKeys
+func diffResources(desired, actual Resource, ignoredKeys []string) ([]FieldDiff, error) {
+	if desired.GetType() != actual.GetType() {
+		return nil, fmt.Errorf("resource type mismatch: desired=%s, actual=%s",
+			desired.GetType(), actual.GetType())
+	}
+
+	if fmt.Sprintf("%T", desired) != fmt.Sprintf("%T", actual) {
+		return nil, fmt.Errorf("resource kind mismatch: desired=%T, actual=%T", desired, actual)
+	}
+
+	diffs := make([]FieldDiff, 0)
+	for _, comparison := range fieldComparisonsFor(desired) {
+		oldValue := comparison.format(actual, ignoredKeys)
+		newValue := comparison.format(desired, ignoredKeys)
+		if oldValue != newValue {
+			diffs = append(diffs, FieldDiff{
+				Path:     comparison.path,
+				OldValue: oldValue,
+				NewValue: newValue,
+			})
+		}
+	}
+
+	// Secret data is compared key by key with values masked
+	if desiredSecret, ok := desired.(*SecretResource); ok {
+		diffs = append(diffs, diffSecretData(desiredSecret.Spec.Data, actual.(*SecretResource).Spec.Data)...)
+	}
+
+	return diffs, nil
+}
+
+// DescribeResource returns every non-empty field of a resource as a FieldDiff with
+// only NewValue set, which is how a resource that does not exist yet is rendered
+func DescribeResource(resource Resource) []FieldDiff {
+	fields := make([]FieldDiff, 0)
+	for _, comparison := range fieldComparisonsFor(resource) {
+		if value := comparison.format(resource, nil); value != "" {
+			fields = append(fields, FieldDiff{Path: comparison.path, NewValue: value})
+		}
+	}
+
+	if secret, ok := resource.(*SecretResource); ok {
+		fields = append(fields, diffSecretData(secret.Spec.Data, nil)...)
+	}
+
+	return fields
+}
+
+// fieldComparisonsFor returns the field comparison table for the resource's kind
+func fieldComparisonsFor(resource Resource) []fieldComparison {
+	comparisons := append([]fieldComparison(nil), labelFieldComparisons...)
+	switch resource.(type) {
+	case *ContainerResource:
+		comparisons = append(comparisons, containerFieldComparisons...)
+	case *NetworkResource:
+		comparisons = append(comparisons, networkFieldComparisons...)
+	case *VolumeResource:
+		comparisons = append(comparisons, volumeFieldComparisons...)
+	case *SecretResource:
+		comparisons = append(comparisons, secretFieldComparisons...)
+	}
+	return comparisons
+}
+
+// diffSecretData compares secret data keys and values without exposing values
+func diffSecretData(desired, actual map[string]string) []FieldDiff {
+	keys := make(map[string]bool)
+	for key := range desired {
+		keys[key] = true
+	}
+	for key := range actual {
+		keys[key] = true
+	}
+
+	sortedKeys := make([]string, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Strings(sortedKeys)
+
+	var diffs []FieldDiff
+	for _, key := range sortedKeys {
+		desiredValue, inDesired := desired[key]
+		actualValue, inActual := actual[key]
+		if inDesired && inActual && desiredValue == actualValue {
+			continue
+		}
+
+		diff := FieldDiff{Path: "spec.data." + key}
+		if inActual {
+			diff.OldValue = secretValueMask
+		}
+		if inDesired {
+			diff.NewValue = secretValueMask
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
+// Formatting helpers
+
+func formatStringSlice(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(values, ", ") + "]"
+}
+
+func formatBool(value bool) string {
+	if !value {
+		return ""
+	}
+	return "true"
+}
+
+func formatSortedStrings(values []string) string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return formatStringSlice(sorted)
+}
+
+func formatStringMap(values map[string]string) string {
+	entries := make([]string, 0, len(values))
+	for key, value := range values {
+		entries = append(entries, fmt.Sprintf("%s=%s", key, value))
+	}
+	return formatSortedStrings(entries)
+}
+
+func formatEnvVars(envVars []EnvVar) string {
+	entries := make([]string, 0, len(envVars))
+	for _, env := range envVars {
+		entries = append(entries, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
+	return formatSortedStrings(entries)
+}
+
+func formatContainerPorts(ports []ContainerPort) string {
+	entries := make([]string, 0, len(ports))
+	for _, port := range ports {
+		protocol := port.Protocol
+		if protocol == "" {
+			protocol = "tcp"
+		}
+		entries = append(entries, fmt.Sprintf("%d:%d/%s", port.HostPort, port.ContainerPort, strings.ToLower(protocol)))
+	}
+	return formatSortedStrings(entries)
+}
+
+func formatVolumeMounts(volumes []VolumeMount) string {
+	entries := make([]string, 0, len(volumes))
+	for _, vol := range volumes {
+		mountPath := vol.MountPath
+		if mountPath == "" {
+			mountPath = vol.ContainerPath
+		}
+
+		entry := fmt.Sprintf("%s:%s", vol.Name, mountPath)
+		if vol.SubPath != "" {
+			entry += fmt.Sprintf(" subPath=%s", vol.SubPath)
+		}
+		if vol.ReadOnly {
+			entry += " ro"
+		}
+		if options := formatMountOptions(vol.MountOptions); options != "" {
+			entry += " " + options
+		}
+		entries = append(entries, entry)
+	}
+	return formatSortedStrings(entries)
+}
+
+func formatMountOptions(options *VolumeMountOptions) string {
+	if options == nil {
+		return ""
+	}
+
+	var parts []string
+	if options.SELinuxLabel != "" {
+		parts = append(parts, "selinux="+options.SELinuxLabel)
+	}
+	if options.SELinuxContext != "" {
+		parts = append(parts, "seLinuxContext="+options.SELinuxContext)
+	}
+	if options.UIDMapping != nil {
+		parts = append(parts, "uidmap="+formatUIDGIDMapping(options.UIDMapping))
+	}
+	if options.GIDMapping != nil {
+		parts = append(parts, "gidmap="+formatUIDGIDMapping(options.GIDMapping))
+	}
+	if options.Propagation != "" {
+		parts = append(parts, "propagation="+options.Propagation)
+	}
+	if options.RecursiveReadOnly {
+		parts = append(parts, "rro")
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatUIDGIDMapping(mapping *UIDGIDMapping) string {
+	return fmt.Sprintf("%d:%d:%d", mapping.ContainerID, mapping.HostID, mapping.Size)
+}
+
+func formatNetworkAttachments(networks []NetworkAttachment) string {
+	entries := make([]string, 0, len(networks))
+	for _, network := range networks {
+		entry := network.Name
+		if network.StaticIP != "" {
+			entry += " ip=" + network.StaticIP
+		}
+		if network.StaticMAC != "" {
+			entry += " mac=" + strings.ToLower(network.StaticMAC)
+		}
+		if len(network.Aliases) > 0 {
+			entry += " aliases=" + strings.Join(network.Aliases, ",")
+		}
+		entries = append(entries, entry)
+	}
+	return formatStringSlice(entries)
+}
+
+func formatEnvFromSources(sources []EnvFromSource) string {
+	entries := make([]string, 0, len(sources))
+	for _, source := range sources {
+		entry := source.SecretName
+		if source.Prefix != "" {
+			entry += " prefix=" + source.Prefix
+		}
+		entries = append(entries, entry)
+	}
+	return formatStringSlice(entries)
+}
+
+func formatSecretReferences(secrets []SecretReference) string {
+	entries := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		entry := secret.Name
+		if secret.Env {
+			entry += " env"
+		}
+		if secret.Path != "" {
+			entry += " path=" + secret.Path
+		}
+		for _, item := range secret.Items {
+			entry += fmt.Sprintf(" %s=%s(%04o)", item.Key, item.Path, secretItemMode(item))
+		}
+		entries = append(entries, entry)
+	}
+	return formatSortedStrings(entries)
+}
+
+func formatHostPath(hostPath *HostPathVolumeSource) string {
+	if hostPath == nil {
+		return ""
+	}
+	if hostPath.Type != nil && *hostPath.Type != HostPathUnset {
+		return fmt.Sprintf("%s (%s)", hostPath.Path, *hostPath.Type)
+	}
+	return hostPath.Path
+}
+
+func formatEmptyDir(emptyDir *EmptyDirVolumeSource) string {
+	if emptyDir == nil {
+		return ""
+	}
+
+	parts := []string{"medium=" + string(emptyDir.Medium)}
+	if emptyDir.SizeLimit != nil {
+		parts = append(parts, "sizeLimit="+*emptyDir.SizeLimit)
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatVolumeSource(volume *VolumeVolumeSource) string {
+	if volume == nil {
+		return ""
+	}
+	return fmt.Sprintf("driver=%s options=%s", volume.Driver, formatStringMap(volume.Options))
+}
+
+func formatVolumeSecurityContext(sc *VolumeSecurityContext) string {
+	if sc == nil {
+		return ""
+	}
+
+	var parts []string
+	if sc.SELinuxOptions != nil {
+		parts = append(parts, "seLinuxLevel="+sc.SELinuxOptions.Level)
+		if sc.SELinuxOptions.Context != "" {
+			parts = append(parts, "seLinuxContext="+sc.SELinuxOptions.Context)
+		}
+	}
+	if sc.Owner != nil {
+		if sc.Owner.User != nil {
+			parts = append(parts, fmt.Sprintf("user=%d", *sc.Owner.User))
+		}
+		if sc.Owner.Group != nil {
+			parts = append(parts, fmt.Sprintf("group=%d", *sc.Owner.Group))
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,52 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"sync"
+
+	"github.com/containers/podman/v5/pkg/inspect"
+)
+
+// imageCacheKey is the context key of the image cache set by withImageCache
+type imageCacheKey struct{}
+
+// imageCache remembers the images found during one run, so that containers sharing an
+// image, and the phases looking at it, resolve it once. Missing images are not
+// remembered, since they are about to be pulled.
+type imageCache struct {
+	mu     sync.Mutex
+	images map[string]*inspect.ImageData
+}
+
+// withImageCache returns a context carrying a new image cache, dropping the lookups of
+// any earlier run
+func withImageCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, imageCacheKey{}, &imageCache{images: make(map[string]*inspect.ImageData)})
+}
+
+// lookupImage looks an image up through the image cache of ctx, falling back to the
+// client when ctx carries none
+func lookupImage(ctx context.Context, client podman.PodmanClient, image string) (*inspect.ImageData, error) {
+	cache, ok := ctx.Value(imageCacheKey{}).(*imageCache)
+	if !ok {
+		return client.GetImage(ctx, image)
+	}
+
+	cache.mu.Lock()
+	imageData, found := cache.images[image]
+	cache.mu.Unlock()
+	if found {
+		return imageData, nil
+	}
+
+	imageData, err := client.GetImage(ctx, image)
+	if err != nil || imageData == nil {
+		return imageData, err
+	}
+
+	cache.mu.Lock()
+	cache.images[image] = imageData
+	cache.mu.Unlock()
+	return imageData, nil
+}
@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrorTypeImagePull represents an image that could not be pulled before execution
+const ErrorTypeImagePull ErrorType = "image_pull"
+
+// WithImagePrePull pulls the images of every container about to be created or updated
+// before anything is changed, instead of each one when its container is created. Pulls
+// run concurrently within the pull concurrency limit, and offline mode still never
+// pulls. A missing image then stops the reconcile up front rather than after the
+// containers created before it.
+func WithImagePrePull() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.prePullImages = true
+	}
+}
+
+// PrePullImages pulls the distinct images of the containers that are not present
+// locally, returning the error of every image that could not be pulled. Containers
+// built from a Containerfile are skipped.
+func (cm *ContainerManager) PrePullImages(ctx context.Context, containers []*ContainerResource) (map[string]error, error) {
+	connectedClient := podman.NewConnectedClient(cm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	// The first container of each image reports the pull's progress
+	byImage := make(map[string]*ContainerResource)
+	for _, container := range containers {
+		if container.Spec.Build == nil && byImage[container.Spec.Image] == nil {
+			byImage[container.Spec.Image] = container
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		failed = make(map[string]error)
+	)
+	for image, container := range byImage {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := cm.pullImageIfNeeded(ctx, podmanClient, container); err != nil {
+				mu.Lock()
+				failed[image] = err
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return failed, nil
+}
+
+// prePullContainerImages pulls the images of the containers the diff creates or updates,
+// recording an error for every container whose image could not be pulled. It returns
+// an error when any pull failed, in which case nothing should be changed, unless in
+// best-effort mode, where those containers are dropped from the diff instead.
+func (rc *DefaultReconciliationController) prePullContainerImages(ctx context.Context, result *ReconciliationResult, diff *StateDiff) error {
+	containerManager, ok := rc.managers[ResourceTypeContainer].(*ContainerManager)
+	if !ok {
+		return nil
+	}
+
+	var containers []*ContainerResource
+	for _, resource := range diff.ToCreate {
+		if container, ok := resource.(*ContainerResource); ok {
+			containers = append(containers, container)
+		}
+	}
+	for _, pair := range diff.ToUpdate {
+		if container, ok := pair.Desired.(*ContainerResource); ok {
+			containers = append(containers, container)
+		}
+	}
+	if len(containers) == 0 {
+		return nil
+	}
+
+	failed, err := containerManager.PrePullImages(ctx, containers)
+	if err != nil {
+		return rc.addError(result, ErrorTypePodmanAPI, ResourceReference{},
+			fmt.Sprintf("failed to pre-pull images: %v", err), err, false)
+	}
+	if len(failed) == 0 {
+		return nil
+	}
+
+	// Every container using an image that failed is reported, in a stable order
+	slices.SortFunc(containers, func(a, b *ContainerResource) int {
+		return strings.Compare(a.GetName(), b.GetName())
+	})
+	for _, container := range containers {
+		if err, exists := failed[container.Spec.Image]; exists && container.Spec.Build == nil {
+			rc.addError(result, ErrorTypeImagePull,
+				ResourceReference{Type: ResourceTypeContainer, Name: container.GetName()},
+				fmt.Sprintf("unable to pull image %s: %v", container.Spec.Image, err), err, rc.bestEffort)
+		}
+	}
+	if rc.bestEffort {
+		rc.dropFailedPulls(result, diff, failed)
+		return nil
+	}
+	return fmt.Errorf("failed to pull %d image(s) before reconciling", len(failed))
+}
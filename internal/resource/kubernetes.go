@@ -0,0 +1,806 @@
+package resource
+
+import (
+	"cmp"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/goccy/go-yaml"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// kubernetesNetwork stands in for the cluster network: every imported workload with a
+// single container joins it, and services become aliases on it
+const kubernetesNetwork = "default"
+
+// kubernetesRestartPolicies maps pod restart policies to container restart policies
+var kubernetesRestartPolicies = map[string]string{
+	"Always":    "always",
+	"OnFailure": "on-failure",
+	"Never":     "no",
+}
+
+// The kube* types mirror the subset of the Kubernetes API that is imported. Fields that
+// are only reported as dropped are found on the raw document instead.
+
+type kubeObject struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+}
+
+type kubePod struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              kubePodSpec `json:"spec"`
+}
+
+type kubeDeployment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Replicas *int32 `json:"replicas"`
+		Template struct {
+			Metadata metav1.ObjectMeta `json:"metadata"`
+			Spec     kubePodSpec       `json:"spec"`
+		} `json:"template"`
+	} `json:"spec"`
+}
+
+type kubePodSpec struct {
+	Containers    []kubeContainer `json:"containers"`
+	Volumes       []kubeVolume    `json:"volumes"`
+	RestartPolicy string          `json:"restartPolicy"`
+}
+
+type kubeContainer struct {
+	Name            string               `json:"name"`
+	Image           string               `json:"image"`
+	Command         []string             `json:"command"`
+	Args            []string             `json:"args"`
+	WorkingDir      string               `json:"workingDir"`
+	Env             []kubeEnvVar         `json:"env"`
+	EnvFrom         []kubeEnvFromSource  `json:"envFrom"`
+	Ports           []kubeContainerPort  `json:"ports"`
+	VolumeMounts    []kubeVolumeMount    `json:"volumeMounts"`
+	SecurityContext *kubeSecurityContext `json:"securityContext"`
+	Resources       *kubeResources       `json:"resources"`
+	LivenessProbe   *kubeProbe           `json:"livenessProbe"`
+	ReadinessProbe  *kubeProbe           `json:"readinessProbe"`
+}
+
+type kubeEnvVar struct {
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	ValueFrom any    `json:"valueFrom"`
+}
+
+type kubeEnvFromSource struct {
+	Prefix    string `json:"prefix"`
+	SecretRef *struct {
+		Name string `json:"name"`
+	} `json:"secretRef"`
+}
+
+type kubeContainerPort struct {
+	Name          string `json:"name"`
+	ContainerPort int32  `json:"containerPort"`
+	HostPort      int32  `json:"hostPort"`
+	Protocol      string `json:"protocol"`
+}
+
+type kubeVolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mountPath"`
+	SubPath   string `json:"subPath"`
+	ReadOnly  bool   `json:"readOnly"`
+}
+
+type kubeSecurityContext struct {
+	Privileged   *bool  `json:"privileged"`
+	RunAsUser    *int64 `json:"runAsUser"`
+	RunAsGroup   *int64 `json:"runAsGroup"`
+	Capabilities *struct {
+		Add  []string `json:"add"`
+		Drop []string `json:"drop"`
+	} `json:"capabilities"`
+}
+
+type kubeResources struct {
+	Limits   map[string]any `json:"limits"`
+	Requests map[string]any `json:"requests"`
+}
+
+type kubeProbe struct {
+	Exec *struct {
+		Command []string `json:"command"`
+	} `json:"exec"`
+	HTTPGet *struct {
+		Path   string `json:"path"`
+		Port   any    `json:"port"`
+		Scheme string `json:"scheme"`
+	} `json:"httpGet"`
+	TCPSocket *struct {
+		Port any `json:"port"`
+	} `json:"tcpSocket"`
+	InitialDelaySeconds int32 `json:"initialDelaySeconds"`
+	PeriodSeconds       int32 `json:"periodSeconds"`
+	TimeoutSeconds      int32 `json:"timeoutSeconds"`
+	FailureThreshold    int32 `json:"failureThreshold"`
+}
+
+type kubeVolume struct {
+	Name                  string `json:"name"`
+	PersistentVolumeClaim *struct {
+		ClaimName string `json:"claimName"`
+		ReadOnly  bool   `json:"readOnly"`
+	} `json:"persistentVolumeClaim"`
+	EmptyDir *struct {
+		Medium    string `json:"medium"`
+		SizeLimit any    `json:"sizeLimit"`
+	} `json:"emptyDir"`
+	HostPath *struct {
+		Path string `json:"path"`
+		Type string `json:"type"`
+	} `json:"hostPath"`
+	Secret *struct {
+		SecretName string `json:"secretName"`
+		Items      []struct {
+			Key  string `json:"key"`
+			Path string `json:"path"`
+			Mode *int32 `json:"mode"`
+		} `json:"items"`
+	} `json:"secret"`
+}
+
+type kubeService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Type     string            `json:"type"`
+		Selector map[string]string `json:"selector"`
+		Ports    []struct {
+			Port       int32  `json:"port"`
+			TargetPort any    `json:"targetPort"`
+			NodePort   int32  `json:"nodePort"`
+			Protocol   string `json:"protocol"`
+		} `json:"ports"`
+	} `json:"spec"`
+}
+
+type kubeSecret struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Type              string            `json:"type"`
+	Data              map[string]string `json:"data"`
+	StringData        map[string]string `json:"stringData"`
+}
+
+// kubeWorkload is an imported Pod or Deployment: a single container, or a pod grouping
+// several
+type kubeWorkload struct {
+	name       string
+	podLabels  map[string]string
+	containers []*ContainerResource
+	pod        *PodResource
+	namedPorts map[string]int32
+}
+
+// kubernetesImporter translates a stream of Kubernetes manifests, collecting warnings as
+// it goes
+type kubernetesImporter struct {
+	resources map[string]Resource
+	workloads []*kubeWorkload
+	services  []kubeService
+	warnings  []string
+}
+
+// ImportKubernetes translates Kubernetes manifests into cutepod resources, as a starting
+// point for moving workloads off a cluster. Pods and Deployments become a container named
+// after them, or a pod of containers named "<workload>-<container>" when they run
+// several. Services become aliases on a shared network and, for NodePort and
+// LoadBalancer services, published ports. PersistentVolumeClaims become named volumes
+// and Secrets become secrets. Kinds and fields Podman has no equivalent for are dropped
+// and reported in the returned warnings; malformed documents and undefined references
+// are errors. Resources are ordered like ExportState orders them.
+func ImportKubernetes(reader io.Reader) ([]Resource, []string, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	importer := &kubernetesImporter{
+		resources: make(map[string]Resource),
+	}
+	for index, doc := range splitDocuments(content) {
+		if len(doc) == 0 {
+			continue
+		}
+		if err := importer.importDocument(doc); err != nil {
+			return nil, nil, fmt.Errorf("document %d: %w", index, err)
+		}
+	}
+
+	// Services may come before the workloads they select
+	for _, service := range importer.services {
+		if err := importer.importService(service); err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", service.Name, err)
+		}
+	}
+
+	resources := slices.Collect(maps.Values(importer.resources))
+	slices.SortFunc(resources, func(a, b Resource) int {
+		return cmp.Or(
+			cmp.Compare(slices.Index(exportOrder, a.GetType()), slices.Index(exportOrder, b.GetType())),
+			cmp.Compare(a.GetName(), b.GetName()),
+		)
+	})
+
+	return resources, importer.warnings, nil
+}
+
+// warnf records a warning about a dropped or approximated Kubernetes feature
+func (ki *kubernetesImporter) warnf(format string, args ...any) {
+	ki.warnings = append(ki.warnings, fmt.Sprintf(format, args...))
+}
+
+// warnUnsupportedFields warns about every field of raw that is not listed as supported
+func (ki *kubernetesImporter) warnUnsupportedFields(subject string, raw any, supported ...string) {
+	fields, _ := raw.(map[string]any)
+	for _, key := range slices.Sorted(maps.Keys(fields)) {
+		if !slices.Contains(supported, key) {
+			ki.warnf("%s: %s is not supported and was dropped", subject, key)
+		}
+	}
+}
+
+// add records an imported resource, rejecting duplicates
+func (ki *kubernetesImporter) add(resource Resource) error {
+	key := fmt.Sprintf("%s/%s", resource.GetType(), resource.GetName())
+	if _, exists := ki.resources[key]; exists {
+		return fmt.Errorf("duplicate resource %s", key)
+	}
+	ki.resources[key] = resource
+	return nil
+}
+
+// importDocument dispatches a document on its kind
+func (ki *kubernetesImporter) importDocument(doc []byte) error {
+	var raw map[string]any
+	if err := yaml.Unmarshal(doc, &raw); err != nil {
+		return fmt.Errorf("failed to parse YAML: %w", err)
+	}
+
+	var object kubeObject
+	if err := yaml.Unmarshal(doc, &object); err != nil {
+		return fmt.Errorf("failed to parse YAML metadata: %w", err)
+	}
+	if object.Kind == "" {
+		return nil
+	}
+
+	switch object.Kind {
+	case "Pod":
+		var pod kubePod
+		if err := yaml.Unmarshal(doc, &pod); err != nil {
+			return fmt.Errorf("failed to parse Pod: %w", err)
+		}
+		spec, _ := raw["spec"].(map[string]any)
+		return ki.importWorkload(pod.Name, pod.Labels, pod.Labels, pod.Spec, spec)
+	case "Deployment":
+		var deployment kubeDeployment
+		if err := yaml.Unmarshal(doc, &deployment); err != nil {
+			return fmt.Errorf("failed to parse Deployment: %w", err)
+		}
+		subject := "deployment " + deployment.Name
+		spec, _ := raw["spec"].(map[string]any)
+		ki.warnUnsupportedFields(subject, spec, "replicas", "selector", "template")
+		if replicas := deployment.Spec.Replicas; replicas != nil && *replicas != 1 {
+			ki.warnf("%s: %d replicas were requested; a single instance is imported", subject, *replicas)
+		}
+		template, _ := spec["template"].(map[string]any)
+		podSpec, _ := template["spec"].(map[string]any)
+		return ki.importWorkload(deployment.Name, deployment.Labels, deployment.Spec.Template.Metadata.Labels, deployment.Spec.Template.Spec, podSpec)
+	case "Service":
+		var service kubeService
+		if err := yaml.Unmarshal(doc, &service); err != nil {
+			return fmt.Errorf("failed to parse Service: %w", err)
+		}
+		ki.services = append(ki.services, service)
+		return nil
+	case "PersistentVolumeClaim":
+		return ki.importClaim(object, raw["spec"])
+	case "Secret":
+		var secret kubeSecret
+		if err := yaml.Unmarshal(doc, &secret); err != nil {
+			return fmt.Errorf("failed to parse Secret: %w", err)
+		}
+		return ki.importSecret(secret)
+	case "ConfigMap":
+		ki.warnf("configmap %s was skipped; cutepod has no ConfigMap resource", object.Name)
+		return nil
+	default:
+		ki.warnf("%s %s was skipped; the kind is not supported", object.Kind, object.Name)
+		return nil
+	}
+}
+
+// importWorkload translates the pod spec of a Pod or Deployment
+func (ki *kubernetesImporter) importWorkload(name string, workloadLabels, podLabels map[string]string, spec kubePodSpec, rawSpec map[string]any) error {
+	subject := "workload " + name
+	if len(spec.Containers) == 0 {
+		return fmt.Errorf("%s has no containers", subject)
+	}
+	ki.warnUnsupportedFields(subject, rawSpec, "containers", "volumes", "restartPolicy")
+
+	restartPolicy := "always"
+	if spec.RestartPolicy != "" {
+		restartPolicy = kubernetesRestartPolicies[spec.RestartPolicy]
+		if restartPolicy == "" {
+			return fmt.Errorf("%s: invalid restartPolicy %s", subject, spec.RestartPolicy)
+		}
+	}
+
+	workload := &kubeWorkload{
+		name:       name,
+		podLabels:  podLabels,
+		namedPorts: make(map[string]int32),
+	}
+
+	volumes, err := ki.importPodVolumes(name, spec.Volumes)
+	if err != nil {
+		return fmt.Errorf("%s: %w", subject, err)
+	}
+
+	rawContainers, _ := rawSpec["containers"].([]any)
+	for index, kubeContainer := range spec.Containers {
+		containerName := name
+		if len(spec.Containers) > 1 {
+			containerName = name + "-" + kubeContainer.Name
+		}
+
+		container := NewContainerResource()
+		container.ObjectMeta.Name = containerName
+		container.SetLabels(workloadLabels)
+		container.Spec.RestartPolicy = restartPolicy
+
+		var rawContainer any
+		if index < len(rawContainers) {
+			rawContainer = rawContainers[index]
+		}
+		if err := ki.importContainer(container, kubeContainer, rawContainer, name, volumes, workload.namedPorts); err != nil {
+			return fmt.Errorf("%s: container %s: %w", subject, kubeContainer.Name, err)
+		}
+		workload.containers = append(workload.containers, container)
+	}
+
+	if len(workload.containers) == 1 {
+		container := workload.containers[0]
+		container.Spec.Networks = []NetworkAttachment{{Name: kubernetesNetwork}}
+		if err := ki.ensureNetwork(); err != nil {
+			return err
+		}
+		ki.workloads = append(ki.workloads, workload)
+		return ki.add(container)
+	}
+
+	// Containers of one pod share a network namespace, so their host ports are published
+	// on the pod
+	workload.pod = NewPodResource()
+	workload.pod.ObjectMeta.Name = name
+	workload.pod.SetLabels(workloadLabels)
+	for _, container := range workload.containers {
+		workload.pod.Spec.Containers = append(workload.pod.Spec.Containers, container.GetName())
+		workload.pod.Spec.Ports = append(workload.pod.Spec.Ports, container.Spec.Ports...)
+		container.Spec.Ports = nil
+		if err := ki.add(container); err != nil {
+			return err
+		}
+	}
+	ki.warnf("%s: pods of several containers do not join the %s network, so services can only publish their ports", subject, kubernetesNetwork)
+
+	ki.workloads = append(ki.workloads, workload)
+	return ki.add(workload.pod)
+}
+
+// importPodVolumes translates the volumes of a pod spec, returning what each volume
+// name mounts. Secret volumes map to a nil mount and are mounted as secrets instead.
+func (ki *kubernetesImporter) importPodVolumes(workloadName string, kubeVolumes []kubeVolume) (map[string]*kubeVolume, error) {
+	volumes := make(map[string]*kubeVolume)
+
+	for i := range kubeVolumes {
+		kubeVolume := &kubeVolumes[i]
+		volumeName := workloadName + "-" + kubeVolume.Name
+
+		switch {
+		case kubeVolume.PersistentVolumeClaim != nil:
+			// The claim is imported on its own
+		case kubeVolume.EmptyDir != nil:
+			volume := NewVolumeResource()
+			volume.ObjectMeta.Name = volumeName
+			volume.Spec.Type = VolumeTypeEmptyDir
+			volume.Spec.EmptyDir = &EmptyDirVolumeSource{}
+			if kubeVolume.EmptyDir.Medium == "Memory" {
+				volume.Spec.EmptyDir.Medium = StorageMediumMemory
+			}
+			if kubeVolume.EmptyDir.SizeLimit != nil {
+				sizeLimit := fmt.Sprint(kubeVolume.EmptyDir.SizeLimit)
+				volume.Spec.EmptyDir.SizeLimit = &sizeLimit
+			}
+			if err := ki.add(volume); err != nil {
+				return nil, err
+			}
+		case kubeVolume.HostPath != nil:
+			volume := NewVolumeResource()
+			volume.ObjectMeta.Name = volumeName
+			volume.Spec.Type = VolumeTypeHostPath
+			volume.Spec.HostPath = &HostPathVolumeSource{Path: kubeVolume.HostPath.Path}
+			if kubeVolume.HostPath.Type != "" {
+				hostPathType := HostPathType(kubeVolume.HostPath.Type)
+				volume.Spec.HostPath.Type = &hostPathType
+			}
+			if err := ki.add(volume); err != nil {
+				return nil, err
+			}
+		case kubeVolume.Secret != nil:
+		default:
+			ki.warnf("workload %s: volume %s is not an emptyDir, hostPath, persistentVolumeClaim or secret and was dropped", workloadName, kubeVolume.Name)
+			continue
+		}
+
+		volumes[kubeVolume.Name] = kubeVolume
+	}
+
+	return volumes, nil
+}
+
+// importContainer translates a container of the workload's pod spec
+func (ki *kubernetesImporter) importContainer(container *ContainerResource, kubeContainer kubeContainer, raw any, workloadName string, volumes map[string]*kubeVolume, namedPorts map[string]int32) error {
+	subject := "container " + container.GetName()
+	ki.warnUnsupportedFields(subject, raw,
+		"name", "image", "command", "args", "workingDir", "env", "envFrom", "ports", "volumeMounts",
+		"securityContext", "resources", "livenessProbe", "readinessProbe")
+
+	if kubeContainer.Image == "" {
+		return fmt.Errorf("image must not be empty")
+	}
+	container.Spec.Image = kubeContainer.Image
+	container.Spec.Command = kubeContainer.Command
+	container.Spec.Args = kubeContainer.Args
+	container.Spec.WorkingDir = kubeContainer.WorkingDir
+
+	for _, env := range kubeContainer.Env {
+		if env.ValueFrom != nil {
+			ki.warnf("%s: environment variable %s takes its value from another resource and was dropped", subject, env.Name)
+			continue
+		}
+		container.Spec.Env = append(container.Spec.Env, EnvVar{Name: env.Name, Value: env.Value})
+	}
+	for _, source := range kubeContainer.EnvFrom {
+		if source.SecretRef == nil {
+			ki.warnf("%s: envFrom source other than a secret was dropped", subject)
+			continue
+		}
+		container.Spec.EnvFrom = append(container.Spec.EnvFrom, EnvFromSource{SecretName: source.SecretRef.Name, Prefix: source.Prefix})
+	}
+
+	for _, port := range kubeContainer.Ports {
+		if port.Name != "" {
+			namedPorts[port.Name] = port.ContainerPort
+		}
+		// Container ports are informational in Kubernetes; only host ports publish them
+		if port.HostPort != 0 {
+			container.Spec.Ports = append(container.Spec.Ports, ContainerPort{
+				ContainerPort: uint16(port.ContainerPort),
+				HostPort:      uint16(port.HostPort),
+				Protocol:      kubernetesProtocol(port.Protocol),
+			})
+		}
+	}
+
+	for _, mount := range kubeContainer.VolumeMounts {
+		volume, exists := volumes[mount.Name]
+		if !exists {
+			ki.warnf("%s: mount of volume %s was dropped along with the volume", subject, mount.Name)
+			continue
+		}
+
+		switch {
+		case volume.Secret != nil:
+			secretRef := SecretReference{
+				Name: volume.Secret.SecretName,
+				Path: mount.MountPath,
+			}
+			for _, item := range volume.Secret.Items {
+				secretRef.Items = append(secretRef.Items, SecretItem{Key: item.Key, Path: item.Path, Mode: item.Mode})
+			}
+			container.Spec.Secrets = append(container.Spec.Secrets, secretRef)
+		case volume.PersistentVolumeClaim != nil:
+			container.Spec.Volumes = append(container.Spec.Volumes, VolumeMount{
+				Name:      volume.PersistentVolumeClaim.ClaimName,
+				MountPath: mount.MountPath,
+				SubPath:   mount.SubPath,
+				ReadOnly:  mount.ReadOnly || volume.PersistentVolumeClaim.ReadOnly,
+			})
+		default:
+			container.Spec.Volumes = append(container.Spec.Volumes, VolumeMount{
+				Name:      workloadName + "-" + mount.Name,
+				MountPath: mount.MountPath,
+				SubPath:   mount.SubPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+	}
+
+	if securityContext := kubeContainer.SecurityContext; securityContext != nil {
+		rawSecurityContext, _ := raw.(map[string]any)
+		ki.warnUnsupportedFields(subject+" securityContext", rawSecurityContext["securityContext"],
+			"privileged", "runAsUser", "runAsGroup", "capabilities")
+
+		container.Spec.UID = securityContext.RunAsUser
+		container.Spec.GID = securityContext.RunAsGroup
+		if securityContext.Privileged != nil || securityContext.Capabilities != nil {
+			container.Spec.SecurityContext = &SecurityContext{Privileged: securityContext.Privileged}
+			if securityContext.Capabilities != nil {
+				container.Spec.SecurityContext.Capabilities = &Capabilities{
+					Add:  securityContext.Capabilities.Add,
+					Drop: securityContext.Capabilities.Drop,
+				}
+			}
+		}
+	}
+
+	if resources := kubeContainer.Resources; resources != nil {
+		container.Spec.Resources = &ResourceRequirements{
+			Limits:   ki.importResourceList(subject, resources.Limits),
+			Requests: ki.importResourceList(subject, resources.Requests),
+		}
+	}
+
+	if probe := kubeContainer.LivenessProbe; probe != nil {
+		health, err := ki.importLivenessProbe(subject, probe, namedPorts)
+		if err != nil {
+			return fmt.Errorf("livenessProbe: %w", err)
+		}
+		container.Spec.Health = health
+	}
+	if probe := kubeContainer.ReadinessProbe; probe != nil {
+		readiness, err := ki.importReadinessProbe(subject, probe, namedPorts)
+		if err != nil {
+			return fmt.Errorf("readinessProbe: %w", err)
+		}
+		container.Spec.ReadinessProbe = readiness
+	}
+
+	return nil
+}
+
+// importResourceList takes the cpu and memory quantities of a resource list
+func (ki *kubernetesImporter) importResourceList(subject string, quantities map[string]any) ResourceList {
+	var list ResourceList
+	for _, name := range slices.Sorted(maps.Keys(quantities)) {
+		switch name {
+		case "cpu":
+			list.CPU = fmt.Sprint(quantities[name])
+		case "memory":
+			list.Memory = fmt.Sprint(quantities[name])
+		default:
+			ki.warnf("%s: resource %s is not supported and was dropped", subject, name)
+		}
+	}
+	return list
+}
+
+// importLivenessProbe translates a liveness probe into a health check, which Podman
+// runs periodically
+func (ki *kubernetesImporter) importLivenessProbe(subject string, probe *kubeProbe, namedPorts map[string]int32) (*HealthCheck, error) {
+	health := &HealthCheck{
+		IntervalSeconds:    probe.PeriodSeconds,
+		TimeoutSeconds:     probe.TimeoutSeconds,
+		StartPeriodSeconds: probe.InitialDelaySeconds,
+		Retries:            probe.FailureThreshold,
+	}
+
+	switch {
+	case probe.Exec != nil:
+		health.Type = "exec"
+		health.Command = probe.Exec.Command
+	case probe.HTTPGet != nil:
+		port, err := kubernetesPort(probe.HTTPGet.Port, namedPorts)
+		if err != nil {
+			return nil, err
+		}
+		health.Type = "http"
+		health.HTTPGet = &HTTPProbe{Path: probe.HTTPGet.Path, Port: port}
+	default:
+		ki.warnf("%s: livenessProbe is not an exec or httpGet probe and was dropped", subject)
+		return nil, nil
+	}
+
+	return health, nil
+}
+
+// importReadinessProbe translates a readiness probe
+func (ki *kubernetesImporter) importReadinessProbe(subject string, probe *kubeProbe, namedPorts map[string]int32) (*Probe, error) {
+	readiness := &Probe{
+		PeriodSeconds:  probe.PeriodSeconds,
+		TimeoutSeconds: probe.TimeoutSeconds,
+	}
+
+	switch {
+	case probe.Exec != nil:
+		readiness.Type = ProbeTypeExec
+		readiness.Command = probe.Exec.Command
+	case probe.HTTPGet != nil:
+		port, err := kubernetesPort(probe.HTTPGet.Port, namedPorts)
+		if err != nil {
+			return nil, err
+		}
+		readiness.Type = ProbeTypeHTTP
+		readiness.HTTPGet = &HTTPGetAction{Path: probe.HTTPGet.Path, Port: port, Scheme: probe.HTTPGet.Scheme}
+	case probe.TCPSocket != nil:
+		port, err := kubernetesPort(probe.TCPSocket.Port, namedPorts)
+		if err != nil {
+			return nil, err
+		}
+		readiness.Type = ProbeTypeTCP
+		readiness.TCPSocket = &TCPSocketAction{Port: port}
+	default:
+		ki.warnf("%s: readinessProbe is not an exec, httpGet or tcpSocket probe and was dropped", subject)
+		return nil, nil
+	}
+
+	return readiness, nil
+}
+
+// importClaim translates a PersistentVolumeClaim into a named volume. Podman volumes
+// are neither sized nor provisioned by class, so the claim's spec is dropped.
+func (ki *kubernetesImporter) importClaim(object kubeObject, spec any) error {
+	ki.warnUnsupportedFields("persistentvolumeclaim "+object.Name, spec, "accessModes")
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = object.Name
+	volume.SetLabels(object.Labels)
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{}
+	return ki.add(volume)
+}
+
+// importSecret translates a Secret. Its data is base64 encoded like cutepod's, and
+// stringData is encoded the same way.
+func (ki *kubernetesImporter) importSecret(kubeSecret kubeSecret) error {
+	if kubeSecret.Type != "" && kubeSecret.Type != "Opaque" {
+		ki.warnf("secret %s: type %s was imported as an opaque secret", kubeSecret.Name, kubeSecret.Type)
+	}
+
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = kubeSecret.Name
+	secret.SetLabels(kubeSecret.Labels)
+	secret.Spec.Type = SecretTypeOpaque
+	secret.Spec.Data = make(map[string]string, len(kubeSecret.Data)+len(kubeSecret.StringData))
+	maps.Copy(secret.Spec.Data, kubeSecret.Data)
+	for key, value := range kubeSecret.StringData {
+		secret.Spec.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+	}
+
+	return ki.add(secret)
+}
+
+// importService applies a Service to the workloads it selects. Every service becomes an
+// alias on the network; NodePort and LoadBalancer services also publish their ports.
+func (ki *kubernetesImporter) importService(service kubeService) error {
+	subject := "service " + service.Name
+
+	switch service.Spec.Type {
+	case "", "ClusterIP", "NodePort", "LoadBalancer":
+	default:
+		ki.warnf("%s: type %s is not supported and was skipped", subject, service.Spec.Type)
+		return nil
+	}
+	if len(service.Spec.Selector) == 0 {
+		ki.warnf("%s has no selector and was skipped", subject)
+		return nil
+	}
+
+	var selected []*kubeWorkload
+	for _, workload := range ki.workloads {
+		if labelsMatch(workload.podLabels, service.Spec.Selector) {
+			selected = append(selected, workload)
+		}
+	}
+	if len(selected) == 0 {
+		ki.warnf("%s selects no imported workload and was skipped", subject)
+		return nil
+	}
+	if len(selected) > 1 {
+		ki.warnf("%s selects several workloads; only %s is reachable through it", subject, selected[0].name)
+	}
+	workload := selected[0]
+
+	if workload.pod == nil {
+		attachment := &workload.containers[0].Spec.Networks[0]
+		attachment.Aliases = append(attachment.Aliases, service.Name)
+	}
+
+	publish := service.Spec.Type == "NodePort" || service.Spec.Type == "LoadBalancer"
+	for _, servicePort := range service.Spec.Ports {
+		targetPort := servicePort.Port
+		if servicePort.TargetPort != nil {
+			port, err := kubernetesPort(servicePort.TargetPort, workload.namedPorts)
+			if err != nil {
+				return fmt.Errorf("targetPort: %w", err)
+			}
+			targetPort = port
+		}
+
+		if workload.pod == nil && targetPort != servicePort.Port {
+			ki.warnf("%s: aliases do not remap ports; clients must connect to port %d instead of %d", subject, targetPort, servicePort.Port)
+		}
+		if !publish {
+			continue
+		}
+
+		hostPort := servicePort.NodePort
+		if hostPort == 0 {
+			hostPort = servicePort.Port
+		}
+		port := ContainerPort{
+			ContainerPort: uint16(targetPort),
+			HostPort:      uint16(hostPort),
+			Protocol:      kubernetesProtocol(servicePort.Protocol),
+		}
+
+		if workload.pod != nil {
+			workload.pod.Spec.Ports = append(workload.pod.Spec.Ports, port)
+		} else {
+			workload.containers[0].Spec.Ports = append(workload.containers[0].Spec.Ports, port)
+		}
+	}
+
+	return nil
+}
+
+// ensureNetwork adds the shared network on first use
+func (ki *kubernetesImporter) ensureNetwork() error {
+	if _, exists := ki.resources[fmt.Sprintf("%s/%s", ResourceTypeNetwork, kubernetesNetwork)]; exists {
+		return nil
+	}
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = kubernetesNetwork
+	return ki.add(network)
+}
+
+// labelsMatch reports whether labels has every key and value of selector
+func labelsMatch(labels, selector map[string]string) bool {
+	for key, value := range selector {
+		if labels[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// kubernetesPort resolves a port given as a number or as the name of a container port
+func kubernetesPort(value any, namedPorts map[string]int32) (int32, error) {
+	portValue := fmt.Sprint(value)
+	if port, err := strconv.ParseInt(portValue, 10, 32); err == nil {
+		return int32(port), nil
+	}
+	if port, exists := namedPorts[portValue]; exists {
+		return port, nil
+	}
+	return 0, fmt.Errorf("undefined port %s", portValue)
+}
+
+// kubernetesProtocol returns the cutepod protocol of a Kubernetes port protocol, which
+// defaults to TCP
+func kubernetesProtocol(protocol string) string {
+	if protocol == "TCP" {
+		return ""
+	}
+	return protocol
+}
@@ -0,0 +1,298 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const testKubernetesManifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+spec:
+  type: NodePort
+  selector:
+    app: web
+  ports:
+    - port: 80
+      targetPort: http
+      nodePort: 30080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  labels:
+    app: web
+spec:
+  replicas: 3
+  selector:
+    matchLabels:
+      app: web
+  template:
+    metadata:
+      labels:
+        app: web
+    spec:
+      serviceAccountName: web
+      containers:
+        - name: nginx
+          image: nginx:1.25
+          imagePullPolicy: IfNotPresent
+          ports:
+            - name: http
+              containerPort: 8080
+          env:
+            - name: MODE
+              value: production
+            - name: TOKEN
+              valueFrom:
+                secretKeyRef:
+                  name: web-token
+                  key: token
+          envFrom:
+            - secretRef:
+                name: web-token
+              prefix: WEB_
+            - configMapRef:
+                name: web-config
+          volumeMounts:
+            - name: data
+              mountPath: /data
+            - name: cache
+              mountPath: /cache
+            - name: token
+              mountPath: /run/token
+            - name: config
+              mountPath: /etc/web
+          securityContext:
+            runAsUser: 1000
+            readOnlyRootFilesystem: true
+          resources:
+            limits:
+              cpu: 500m
+              memory: 256Mi
+          readinessProbe:
+            httpGet:
+              path: /healthz
+              port: http
+      volumes:
+        - name: data
+          persistentVolumeClaim:
+            claimName: web-data
+        - name: cache
+          emptyDir: {}
+        - name: token
+          secret:
+            secretName: web-token
+            items:
+              - key: token
+                path: api-token
+        - name: config
+          configMap:
+            name: web-config
+---
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: web-data
+spec:
+  accessModes: [ReadWriteOnce]
+  resources:
+    requests:
+      storage: 1Gi
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: web-token
+stringData:
+  token: s3cr3t
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+data:
+  web.conf: ""
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: db
+spec:
+  selector:
+    app: db
+  ports:
+    - port: 5432
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  name: db
+  labels:
+    app: db
+spec:
+  restartPolicy: OnFailure
+  containers:
+    - name: postgres
+      image: postgres:16
+`
+
+func TestImportKubernetes(t *testing.T) {
+	resources, warnings, err := ImportKubernetes(strings.NewReader(testKubernetesManifests))
+	if err != nil {
+		t.Fatalf("ImportKubernetes failed: %v", err)
+	}
+
+	var names []string
+	for _, resource := range resources {
+		names = append(names, string(resource.GetType())+"/"+resource.GetName())
+	}
+	expected := []string{
+		"network/default",
+		"volume/web-cache", "volume/web-data",
+		"secret/web-token",
+		"container/db", "container/web",
+	}
+	if !slices.Equal(names, expected) {
+		t.Fatalf("Expected resources %v, got %v", expected, names)
+	}
+
+	secret := resources[3].(*SecretResource)
+	if secret.Spec.Data["token"] != "czNjcjN0" {
+		t.Errorf("Expected stringData to be base64 encoded, got %v", secret.Spec.Data)
+	}
+
+	db := resources[4].(*ContainerResource)
+	if db.Spec.RestartPolicy != "on-failure" {
+		t.Errorf("Expected restart policy on-failure, got %q", db.Spec.RestartPolicy)
+	}
+	if len(db.Spec.Networks) != 1 || !slices.Equal(db.Spec.Networks[0].Aliases, []string{"db"}) {
+		t.Errorf("Expected the db service to become an alias, got %+v", db.Spec.Networks)
+	}
+
+	web := resources[5].(*ContainerResource)
+	if len(web.Spec.Ports) != 1 || web.Spec.Ports[0] != (ContainerPort{ContainerPort: 8080, HostPort: 30080}) {
+		t.Errorf("Expected the node port to be published on the named target port, got %+v", web.Spec.Ports)
+	}
+	if len(web.Spec.Env) != 1 || web.Spec.Env[0].Name != "MODE" {
+		t.Errorf("Expected only MODE to be imported, got %+v", web.Spec.Env)
+	}
+	expectedMounts := []VolumeMount{{Name: "web-data", MountPath: "/data"}, {Name: "web-cache", MountPath: "/cache"}}
+	if !slices.Equal(web.Spec.Volumes, expectedMounts) {
+		t.Errorf("Expected mounts %+v, got %+v", expectedMounts, web.Spec.Volumes)
+	}
+	expectedSecret := SecretReference{Name: "web-token", Path: "/run/token", Items: []SecretItem{{Key: "token", Path: "api-token"}}}
+	if len(web.Spec.Secrets) != 1 || !slices.EqualFunc(web.Spec.Secrets[0].Items, expectedSecret.Items, sameSecretItem) ||
+		web.Spec.Secrets[0].Name != expectedSecret.Name || web.Spec.Secrets[0].Path != expectedSecret.Path {
+		t.Errorf("Expected the secret volume to be mounted as a secret, got %+v", web.Spec.Secrets)
+	}
+	if !slices.Equal(web.Spec.EnvFrom, []EnvFromSource{{SecretName: "web-token", Prefix: "WEB_"}}) {
+		t.Errorf("Expected the secret envFrom source to be imported, got %+v", web.Spec.EnvFrom)
+	}
+	if web.Spec.UID == nil || *web.Spec.UID != 1000 {
+		t.Errorf("Expected runAsUser to become the uid, got %v", web.Spec.UID)
+	}
+	if web.Spec.Resources == nil || web.Spec.Resources.Limits != (ResourceList{CPU: "500m", Memory: "256Mi"}) {
+		t.Errorf("Expected the limits to be imported, got %+v", web.Spec.Resources)
+	}
+	if web.Spec.ReadinessProbe == nil || web.Spec.ReadinessProbe.HTTPGet == nil || web.Spec.ReadinessProbe.HTTPGet.Port != 8080 {
+		t.Errorf("Expected the readiness probe to resolve the named port, got %+v", web.Spec.ReadinessProbe)
+	}
+
+	for _, fragment := range []string{
+		"3 replicas",
+		"serviceAccountName is not supported",
+		"imagePullPolicy is not supported",
+		"readOnlyRootFilesystem is not supported",
+		"TOKEN takes its value from another resource",
+		"envFrom source other than a secret was dropped",
+		"volume config is not an emptyDir",
+		"configmap web-config was skipped",
+		"persistentvolumeclaim web-data: resources is not supported",
+		"clients must connect to port 8080",
+	} {
+		if !slices.ContainsFunc(warnings, func(warning string) bool { return strings.Contains(warning, fragment) }) {
+			t.Errorf("Expected a warning mentioning %q, got %v", fragment, warnings)
+		}
+	}
+}
+
+func TestImportKubernetes_MultiContainerPod(t *testing.T) {
+	manifests := `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: app
+  labels:
+    app: app
+spec:
+  containers:
+    - name: web
+      image: nginx:1.25
+      ports:
+        - containerPort: 80
+          hostPort: 8080
+    - name: sidecar
+      image: envoy:1.30
+`
+	resources, _, err := ImportKubernetes(strings.NewReader(manifests))
+	if err != nil {
+		t.Fatalf("ImportKubernetes failed: %v", err)
+	}
+	if len(resources) != 3 {
+		t.Fatalf("Expected a pod and two containers, got %d resources", len(resources))
+	}
+
+	pod := resources[0].(*PodResource)
+	if !slices.Equal(pod.Spec.Containers, []string{"app-web", "app-sidecar"}) {
+		t.Errorf("Expected the containers to be named after the pod, got %v", pod.Spec.Containers)
+	}
+	if len(pod.Spec.Ports) != 1 || pod.Spec.Ports[0].HostPort != 8080 {
+		t.Errorf("Expected the host port to be published on the pod, got %+v", pod.Spec.Ports)
+	}
+
+	mockClient := podman.NewMockPodmanClient()
+	result, err := NewReconciliationController(mockClient).Reconcile(context.Background(), resources, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.CreatedResources) != 3 {
+		t.Errorf("Expected the imported pod to reconcile cleanly, got %s %+v", result.Summary, result.Errors)
+	}
+}
+
+func TestImportKubernetes_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		manifests string
+		contains  string
+	}{
+		{name: "no containers", manifests: "kind: Pod\nmetadata:\n  name: web\nspec: {}\n", contains: "has no containers"},
+		{name: "no image", manifests: "kind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n    - name: web\n", contains: "image must not be empty"},
+		{
+			name:      "duplicate workload",
+			manifests: "kind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n    - name: web\n      image: nginx\n---\nkind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n    - name: web\n      image: nginx\n",
+			contains:  "duplicate resource container/web",
+		},
+		{
+			name:      "undefined named port",
+			manifests: "kind: Pod\nmetadata:\n  name: web\nspec:\n  containers:\n    - name: web\n      image: nginx\n      readinessProbe:\n        tcpSocket:\n          port: http\n",
+			contains:  "undefined port http",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ImportKubernetes(strings.NewReader(tt.manifests))
+			if err == nil || !strings.Contains(err.Error(), tt.contains) {
+				t.Errorf("Expected an error containing %q, got %v", tt.contains, err)
+			}
+		})
+	}
+}
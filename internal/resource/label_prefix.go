@@ -0,0 +1,45 @@
+package resource
+
+import (
+	"cutepod/internal/labels"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// WithLabelPrefix sets the prefix of the chart, version and managed-by labels that
+// identify a chart's resources, such as "acme.io/" instead of "cutepod.io/". The labels
+// of the manifests are moved under the prefix, and the actual state is listed by it.
+// Bookkeeping labels and the annotations cutepod reads keep their cutepod.io/ prefix.
+// Resources created under another prefix are not found, so changing the prefix of a
+// deployed chart recreates its resources.
+func WithLabelPrefix(prefix string) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.labelPrefix = prefix
+	}
+}
+
+// labelPrefixSetter is implemented by managers that list a chart's resources by its label
+type labelPrefixSetter interface {
+	SetLabelPrefix(prefix string)
+}
+
+// validateLabelPrefix checks that prefix is a DNS subdomain followed by a slash
+func validateLabelPrefix(prefix string) error {
+	domain, found := strings.CutSuffix(prefix, "/")
+	if !found {
+		return fmt.Errorf("label prefix %q must end with a slash, such as \"acme.io/\"", prefix)
+	}
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("label prefix %q: %s", prefix, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// applyLabelPrefix moves the standard labels of the manifests under the label prefix
+func (rc *DefaultReconciliationController) applyLabelPrefix(manifests []Resource) {
+	for _, manifest := range manifests {
+		manifest.SetLabels(labels.MovePrefix(manifest.GetLabels(), rc.labelPrefix))
+	}
+}
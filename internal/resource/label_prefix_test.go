@@ -0,0 +1,96 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func TestReconcile_CustomLabelPrefixRoundTrips(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithLabelPrefix("acme.io/"))
+	ctx := context.Background()
+
+	manifests := func() []Resource {
+		network := NewNetworkResource()
+		network.ObjectMeta.Name = "backend"
+		network.Spec.Driver = "bridge"
+		network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+		secret := NewSecretResource()
+		secret.ObjectMeta.Name = "token"
+		secret.Spec.Type = SecretTypeOpaque
+		secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte("s3cret"))}
+		secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+		container := newExplainTestContainer("nginx:1.25")
+		container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+		return []Resource{network, secret, container}
+	}
+
+	result, err := controller.Reconcile(ctx, manifests(), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 3 {
+		t.Fatalf("Expected 3 resources to be created, got %+v", result.CreatedResources)
+	}
+
+	// Resources are created under the custom prefix only
+	filters := map[string][]string{"label": {"acme.io/chart=demo"}}
+	containers, _ := mockClient.ListContainers(ctx, filters, true)
+	networks, _ := mockClient.ListNetworks(ctx, filters)
+	secrets, _ := mockClient.ListSecrets(ctx, filters)
+	if len(containers) != 1 || len(networks) != 1 || len(secrets) != 1 {
+		t.Fatalf("Expected one container, network and secret labelled acme.io/chart, got %d, %d and %d",
+			len(containers), len(networks), len(secrets))
+	}
+	if _, exists := containers[0].Labels[labels.LabelChart]; exists {
+		t.Errorf("Expected no %s label, got %v", labels.LabelChart, containers[0].Labels)
+	}
+	if containers[0].Labels["acme.io/managed-by"] != labels.ManagedByValue {
+		t.Errorf("Expected the managed-by label under the prefix, got %v", containers[0].Labels)
+	}
+
+	// Listing by the custom prefix finds them again, so nothing changes
+	result, err = controller.Reconcile(ctx, manifests(), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 0 || len(result.UpdatedResources) != 0 || len(result.DeletedResources) != 0 {
+		t.Errorf("Expected no changes, got %s", result.Summary)
+	}
+
+	// A controller using the default prefix does not see them
+	actual, err := NewContainerManager(mockClient).GetActualState(ctx, "demo")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 0 {
+		t.Errorf("Expected no containers under the default prefix, got %d", len(actual))
+	}
+
+	// Ownership is checked under the custom prefix too
+	result, err = controller.Destroy(ctx, "demo")
+	if err != nil {
+		t.Fatalf("Destroy failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.DeletedResources) != 3 {
+		t.Errorf("Expected the 3 resources to be deleted, got %+v with errors %+v", result.DeletedResources, result.Errors)
+	}
+}
+
+func TestReconcile_RejectsInvalidLabelPrefix(t *testing.T) {
+	for prefix, expected := range map[string]string{
+		"acme.io":  "must end with a slash",
+		"Acme_IO/": "label prefix \"Acme_IO/\"",
+		"/":        "label prefix \"/\"",
+	} {
+		controller := NewReconciliationController(podman.NewMockPodmanClient(), WithLabelPrefix(prefix))
+		_, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected prefix %q to be rejected with %q, got %v", prefix, expected, err)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package resource
+
+import (
+	"io"
+	"log/slog"
+)
+
+// Logger receives log records from the controller and the resource managers. Fields
+// are alternating keys and values, as with log/slog.
+type Logger interface {
+	Debug(msg string, keysAndValues ...any)
+	Info(msg string, keysAndValues ...any)
+	Warn(msg string, keysAndValues ...any)
+	Error(msg string, keysAndValues ...any)
+}
+
+// WithLogger sets the logger used by the controller and its resource managers.
+// Controllers default to passing only warnings and errors to slog's default logger.
+func WithLogger(logger Logger) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if logger != nil {
+			rc.logger = logger
+		}
+	}
+}
+
+// loggerSetter is implemented by managers that log, so the controller can hand them its logger
+type loggerSetter interface {
+	SetLogger(logger Logger)
+}
+
+// slogLogger logs through a slog.Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a logger backed by logger. A nil logger logs through whatever
+// slog's default logger is at the time of each call.
+func NewSlogLogger(logger *slog.Logger) Logger {
+	return &slogLogger{logger: logger}
+}
+
+// NewJSONLogger returns a logger that writes one JSON object per record to w
+func NewJSONLogger(w io.Writer) Logger {
+	return NewSlogLogger(slog.New(slog.NewJSONHandler(w, nil)))
+}
+
+func (l *slogLogger) current() *slog.Logger {
+	if l.logger == nil {
+		return slog.Default()
+	}
+	return l.logger
+}
+
+// Debug implements Logger interface
+func (l *slogLogger) Debug(msg string, keysAndValues ...any) {
+	l.current().Debug(msg, keysAndValues...)
+}
+
+// Info implements Logger interface
+func (l *slogLogger) Info(msg string, keysAndValues ...any) {
+	l.current().Info(msg, keysAndValues...)
+}
+
+// Warn implements Logger interface
+func (l *slogLogger) Warn(msg string, keysAndValues ...any) {
+	l.current().Warn(msg, keysAndValues...)
+}
+
+// Error implements Logger interface
+func (l *slogLogger) Error(msg string, keysAndValues ...any) {
+	l.current().Error(msg, keysAndValues...)
+}
+
+// noopLogger discards all records
+type noopLogger struct{}
+
+// NewNoopLogger returns a logger that discards all records
+func NewNoopLogger() Logger {
+	return noopLogger{}
+}
+
+func (noopLogger) Debug(string, ...any) {}
+func (noopLogger) Info(string, ...any)  {}
+func (noopLogger) Warn(string, ...any)  {}
+func (noopLogger) Error(string, ...any) {}
+
+// warningsOnly drops debug and info records, passing the others on to Logger
+type warningsOnly struct {
+	Logger
+}
+
+func (warningsOnly) Debug(string, ...any) {}
+func (warningsOnly) Info(string, ...any)  {}
+
+// defaultLogger is used by controllers and managers that were not given a logger. The
+// step by step records are left out, so commands only show the warnings they used to.
+var defaultLogger Logger = warningsOnly{NewSlogLogger(nil)}
+
+// loggerOrDefault returns logger, or the default logger if it is nil
+func loggerOrDefault(logger Logger) Logger {
+	if logger == nil {
+		return defaultLogger
+	}
+	return logger
+}
+
+// logAction logs a finished resource action
+func (rc *DefaultReconciliationController) logAction(action *ResourceAction) {
+	fields := []any{
+		"type", action.Type,
+		"name", action.Name,
+		"action", action.Action,
+		"duration", action.Duration,
+	}
+
+	if action.Error != "" {
+		rc.logger.Warn("resource action failed", append(fields, "error", action.Error)...)
+		return
+	}
+	rc.logger.Info("resource action completed", append(fields, "message", action.Message)...)
+}
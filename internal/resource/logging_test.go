@@ -0,0 +1,102 @@
+package resource
+
+import (
+	"bytes"
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"testing"
+)
+
+// recordingLogger keeps every record as "LEVEL msg"
+type recordingLogger struct {
+	records []string
+}
+
+func (l *recordingLogger) record(level, msg string) {
+	l.records = append(l.records, fmt.Sprintf("%s %s", level, msg))
+}
+
+func (l *recordingLogger) Debug(msg string, keysAndValues ...any) { l.record("DEBUG", msg) }
+func (l *recordingLogger) Info(msg string, keysAndValues ...any)  { l.record("INFO", msg) }
+func (l *recordingLogger) Warn(msg string, keysAndValues ...any)  { l.record("WARN", msg) }
+func (l *recordingLogger) Error(msg string, keysAndValues ...any) { l.record("ERROR", msg) }
+
+func TestReconcile_LogsStepsAsJSON(t *testing.T) {
+	var output bytes.Buffer
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithLogger(NewJSONLogger(&output)))
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	if _, err := controller.Reconcile(context.Background(), []Resource{network}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	messages := make([]string, 0)
+	for _, line := range bytes.Split(bytes.TrimSpace(output.Bytes()), []byte("\n")) {
+		var record map[string]any
+		if err := json.Unmarshal(line, &record); err != nil {
+			t.Fatalf("Expected a JSON record, got %q: %v", line, err)
+		}
+		messages = append(messages, record["msg"].(string))
+
+		if record["msg"] == "resource action completed" && (record["name"] != "backend" || record["action"] != "create") {
+			t.Errorf("Expected the network creation to be logged with its fields, got %v", record)
+		}
+	}
+
+	expected := []string{"reconciliation started", "resource action completed", "reconciliation finished"}
+	if fmt.Sprint(messages) != fmt.Sprint(expected) {
+		t.Errorf("Expected records %v, got %v", expected, messages)
+	}
+}
+
+func TestContainerManager_LogsThroughInjectedLogger(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetExecExitCode([]string{"shutdown"}, 1)
+	logger := &recordingLogger{}
+	cm := NewContainerManager(mockClient)
+	cm.SetLogger(logger)
+	ctx := context.Background()
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "app"
+	container.SetLabels(labels.GetStandardLabels("chart-name", "chart-version"))
+	container.Spec.Image = "alpine:3.20"
+	container.Spec.Lifecycle = &LifecycleSpec{PreStop: &ExecAction{Command: []string{"shutdown"}}}
+
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	if err := cm.DeleteResource(ctx, container); err != nil {
+		t.Fatalf("DeleteResource failed: %v", err)
+	}
+
+	if len(logger.records) != 1 || logger.records[0] != "WARN preStop hook failed" {
+		t.Errorf("Expected the failed preStop hook to be logged as a warning, got %v", logger.records)
+	}
+}
+
+func TestReconcile_DefaultLoggerOnlyPassesOnWarnings(t *testing.T) {
+	var output bytes.Buffer
+	previous := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&output, nil)))
+	defer slog.SetDefault(previous)
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	if _, err := NewReconciliationController(podman.NewMockPodmanClient()).Reconcile(context.Background(), []Resource{network}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if output.Len() != 0 {
+		t.Errorf("Expected no records from a plain reconcile, got %s", output.String())
+	}
+
+	defaultLogger.Warn("failed to stop container", "container", "web")
+	if !bytes.Contains(output.Bytes(), []byte("level=WARN msg=\"failed to stop container\"")) {
+		t.Errorf("Expected the warning to reach slog's default logger, got %s", output.String())
+	}
+}
@@ -0,0 +1,73 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// LogOptions selects the log lines GetChartLogs returns for each container
+type LogOptions struct {
+	// Tail is the number of lines to return from the end of each container's logs; zero
+	// or less returns all of them
+	Tail int
+}
+
+// GetChartLogs returns the logs of every container of the chart, stopped ones included,
+// keyed by container name. A container that has not written anything yet gets an empty
+// stream. The caller is expected to close every stream.
+func (rc *DefaultReconciliationController) GetChartLogs(ctx context.Context, chartName string, opts LogOptions) (map[string]io.ReadCloser, error) {
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	containers, err := podmanClient.ListContainers(
+		ctx,
+		map[string][]string{
+			"label": {labels.GetChartLabelValueWithPrefix(chartName, rc.labelPrefix)},
+		},
+		true,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	tail := opts.Tail
+	if tail <= 0 {
+		tail = -1
+	}
+
+	logs := make(map[string]io.ReadCloser, len(containers))
+	for _, container := range containers {
+		name := strings.TrimPrefix(container.Names[0], "/")
+
+		lines, err := podmanClient.ContainerLogs(ctx, name, tail)
+		if err != nil {
+			closeLogs(logs)
+			return nil, fmt.Errorf("unable to get logs for container %s: %w", name, err)
+		}
+
+		var text strings.Builder
+		for _, line := range lines {
+			text.WriteString(strings.TrimSuffix(line, "\n"))
+			text.WriteByte('\n')
+		}
+		logs[name] = io.NopCloser(strings.NewReader(text.String()))
+	}
+
+	return logs, nil
+}
+
+// closeLogs closes the log streams gathered before a later container failed
+func closeLogs(logs map[string]io.ReadCloser) {
+	for _, stream := range logs {
+		stream.Close()
+	}
+}
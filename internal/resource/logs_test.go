@@ -0,0 +1,78 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"io"
+	"testing"
+)
+
+func TestGetChartLogs_StreamsEachContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	web := newExplainTestContainer("nginx:1.25")
+	worker := newExplainTestContainer("busybox:1.36")
+	worker.ObjectMeta.Name = "worker"
+
+	if _, err := controller.Reconcile(ctx, []Resource{web, worker}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	mockClient.SetContainerLogs("web", []string{"starting", "listening on :8080", "GET /"})
+	if err := mockClient.StopContainer(ctx, "web", 0); err != nil {
+		t.Fatalf("StopContainer failed: %v", err)
+	}
+
+	read := func(opts LogOptions) map[string]string {
+		logs, err := controller.GetChartLogs(ctx, "demo", opts)
+		if err != nil {
+			t.Fatalf("GetChartLogs failed: %v", err)
+		}
+		texts := make(map[string]string, len(logs))
+		for name, stream := range logs {
+			text, err := io.ReadAll(stream)
+			if err != nil {
+				t.Fatalf("Reading the logs of %s failed: %v", name, err)
+			}
+			if err := stream.Close(); err != nil {
+				t.Errorf("Closing the logs of %s failed: %v", name, err)
+			}
+			texts[name] = string(text)
+		}
+		return texts
+	}
+
+	// Stopped containers are included, and one without logs gets an empty stream
+	logs := read(LogOptions{})
+	if len(logs) != 2 {
+		t.Fatalf("Expected logs for 2 containers, got %v", logs)
+	}
+	if logs["web"] != "starting\nlistening on :8080\nGET /\n" {
+		t.Errorf("Expected all of web's lines, got %q", logs["web"])
+	}
+	if text, exists := logs["worker"]; !exists || text != "" {
+		t.Errorf("Expected an empty stream for worker, got %q (present: %v)", text, exists)
+	}
+
+	if tail := read(LogOptions{Tail: 1})["web"]; tail != "GET /\n" {
+		t.Errorf("Expected only the last line, got %q", tail)
+	}
+}
+
+func TestGetChartLogs_ReportsFailingContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	mockClient.SetShouldFailOperation("ListContainers", true)
+
+	if _, err := controller.GetChartLogs(ctx, "demo", LogOptions{}); err == nil {
+		t.Error("Expected an error when the chart's containers cannot be listed")
+	}
+}
@@ -0,0 +1,51 @@
+package resource
+
+import (
+	"time"
+)
+
+// MetricsCollector receives reconciliation metrics. Implementations typically back
+// these with Prometheus counters and histograms so a long-running cutepod process
+// can expose them on /metrics.
+type MetricsCollector interface {
+	// IncReconciliations counts a finished reconciliation, labeled by chart and status
+	IncReconciliations(chartName, status string)
+
+	// IncResourceOperations counts a create, update or delete attempt on a resource
+	IncResourceOperations(resourceType ResourceType, action ActionType)
+
+	// ObserveOperationDuration records how long a resource operation took, retries included
+	ObserveOperationDuration(resourceType ResourceType, action ActionType, duration time.Duration)
+
+	// IncRetries counts a retried resource operation
+	IncRetries(resourceType ResourceType, action ActionType)
+}
+
+// WithMetrics sets the collector that receives reconciliation metrics.
+// Controllers default to a collector that discards everything.
+func WithMetrics(metrics MetricsCollector) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if metrics != nil {
+			rc.metrics = metrics
+		}
+	}
+}
+
+// noopMetricsCollector is the default collector; it discards all metrics
+type noopMetricsCollector struct{}
+
+// NewNoopMetricsCollector returns a collector that discards all metrics
+func NewNoopMetricsCollector() MetricsCollector {
+	return noopMetricsCollector{}
+}
+
+func (noopMetricsCollector) IncReconciliations(string, string)                                {}
+func (noopMetricsCollector) IncResourceOperations(ResourceType, ActionType)                   {}
+func (noopMetricsCollector) ObserveOperationDuration(ResourceType, ActionType, time.Duration) {}
+func (noopMetricsCollector) IncRetries(ResourceType, ActionType)                              {}
+
+// observeAction records the operation count and duration of a finished resource action
+func (rc *DefaultReconciliationController) observeAction(action *ResourceAction) {
+	rc.metrics.IncResourceOperations(action.Type, action.Action)
+	rc.metrics.ObserveOperationDuration(action.Type, action.Action, action.Duration)
+}
@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMetricsCollector counts every metric it receives
+type fakeMetricsCollector struct {
+	mu              sync.Mutex
+	reconciliations map[string]int
+	operations      map[string]int
+	durations       map[string]int
+	retries         map[string]int
+}
+
+func newFakeMetricsCollector() *fakeMetricsCollector {
+	return &fakeMetricsCollector{
+		reconciliations: make(map[string]int),
+		operations:      make(map[string]int),
+		durations:       make(map[string]int),
+		retries:         make(map[string]int),
+	}
+}
+
+func (f *fakeMetricsCollector) IncReconciliations(chartName, status string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reconciliations[chartName+"/"+status]++
+}
+
+func (f *fakeMetricsCollector) IncResourceOperations(resourceType ResourceType, action ActionType) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.operations[string(resourceType)+"/"+string(action)]++
+}
+
+func (f *fakeMetricsCollector) ObserveOperationDuration(resourceType ResourceType, action ActionType, duration time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.durations[string(resourceType)+"/"+string(action)]++
+}
+
+func (f *fakeMetricsCollector) IncRetries(resourceType ResourceType, action ActionType) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.retries[string(resourceType)+"/"+string(action)]++
+}
+
+func TestReconcile_RecordsMetrics(t *testing.T) {
+	metrics := newFakeMetricsCollector()
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithMetrics(metrics))
+
+	container := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if metrics.reconciliations["demo/healthy"] != 1 {
+		t.Errorf("Expected 1 healthy reconciliation for demo, got %v", metrics.reconciliations)
+	}
+	if metrics.operations["container/create"] != 1 {
+		t.Errorf("Expected 1 container create operation, got %v", metrics.operations)
+	}
+	if metrics.durations["container/create"] != 1 {
+		t.Errorf("Expected 1 container create duration observation, got %v", metrics.durations)
+	}
+	if len(metrics.retries) != 0 {
+		t.Errorf("Expected no retries, got %v", metrics.retries)
+	}
+}
+
+func TestReconcile_RecordsRetryMetrics(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("CreateNetwork", true)
+
+	metrics := newFakeMetricsCollector()
+	controller := NewReconciliationController(mockClient, WithMetrics(metrics))
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	if _, err := controller.Reconcile(context.Background(), []Resource{network}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if metrics.retries["network/create"] != 2 {
+		t.Errorf("Expected 2 network create retries, got %v", metrics.retries)
+	}
+	if metrics.operations["network/create"] != 1 {
+		t.Errorf("Expected 1 network create operation, got %v", metrics.operations)
+	}
+	if metrics.reconciliations["demo/degraded"] != 1 {
+		t.Errorf("Expected 1 degraded reconciliation for demo, got %v", metrics.reconciliations)
+	}
+}
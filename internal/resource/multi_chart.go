@@ -0,0 +1,214 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// ReconcileMulti reconciles several charts at once, so that a resource of one chart can
+// reference a resource of another, such as a container joining a network another chart
+// defines. The charts' manifests are validated and ordered as a single dependency graph,
+// and every dependency level is applied across all charts before the next one. Actual
+// state, ownership and status are still tracked per chart, and the returned results are
+// keyed by chart name. Dangling images are not pruned.
+func (rc *DefaultReconciliationController) ReconcileMulti(ctx context.Context, manifestsByChart map[string][]Resource, dryRun bool) (map[string]*ReconciliationResult, error) {
+	ctx, span := rc.tracer.Start(ctx, "reconcile_multi",
+		Attribute(SpanAttributeDryRun, strconv.FormatBool(dryRun)))
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+	ctx = withImageCache(ctx)
+
+	rc.logger.Info("multi-chart reconciliation started", "charts", len(manifestsByChart), "dryRun", dryRun)
+
+	rc.clearComparisonCache()
+	results, err := rc.reconcileMulti(ctx, manifestsByChart, dryRun)
+	endSpan(span, err)
+
+	if err != nil {
+		rc.logger.Error("multi-chart reconciliation failed", "error", err)
+	} else {
+		for chartName, result := range results {
+			rc.logger.Info("reconciliation finished", "chart", chartName,
+				"created", len(result.CreatedResources),
+				"updated", len(result.UpdatedResources),
+				"deleted", len(result.DeletedResources),
+				"errors", len(result.Errors),
+				"duration", result.Duration)
+		}
+	}
+
+	return results, err
+}
+
+// reconcileMulti runs the phases of reconcile over the combined manifests of every chart
+func (rc *DefaultReconciliationController) reconcileMulti(ctx context.Context, manifestsByChart map[string][]Resource, dryRun bool) (map[string]*ReconciliationResult, error) {
+	startTime := time.Now()
+
+	charts := slices.Sorted(maps.Keys(manifestsByChart))
+	results := make(map[string]*ReconciliationResult, len(charts))
+	for _, chartName := range charts {
+		results[chartName] = &ReconciliationResult{
+			CreatedResources: make([]ResourceAction, 0),
+			UpdatedResources: make([]ResourceAction, 0),
+			DeletedResources: make([]ResourceAction, 0),
+			Errors:           make([]*ReconciliationError, 0),
+			ChartName:        chartName,
+		}
+	}
+
+	// An error that concerns the charts together is recorded on each of them
+	failAll := func(errorType ErrorType, message string, cause error) error {
+		var err error
+		for _, chartName := range charts {
+			err = rc.addError(results[chartName], errorType, ResourceReference{}, message, cause, false)
+		}
+		return err
+	}
+
+	// Podman names are not scoped by chart, so two charts cannot define the same resource
+	ownerByKey := make(map[string]string)
+	var combined []Resource
+	for _, chartName := range charts {
+		for _, manifest := range manifestsByChart[chartName] {
+			key := fmt.Sprintf("%s/%s", manifest.GetType(), manifest.GetName())
+			if owner, exists := ownerByKey[key]; exists {
+				err := fmt.Errorf("%s is defined by both chart '%s' and chart '%s'", key, owner, chartName)
+				return results, failAll(ErrorTypeValidation, fmt.Sprintf("manifest validation failed: %v", err), err)
+			}
+			ownerByKey[key] = chartName
+			combined = append(combined, manifest)
+		}
+	}
+
+	// References are resolved across charts, so one chart may use another's resources
+	if err := rc.validateManifests(combined); err != nil {
+		return results, failAll(ErrorTypeValidation, fmt.Sprintf("manifest validation failed: %v", err), err)
+	}
+	assignPodMembers(combined)
+	rc.applyLabelPrefix(combined)
+
+	// A label selector narrows the manifests the same way it narrows the actual state
+	combined = selectManifests(ctx, combined)
+	selectedByChart := make(map[string][]Resource, len(charts))
+	for _, chartName := range charts {
+		selectedByChart[chartName] = selectManifests(ctx, manifestsByChart[chartName])
+	}
+
+	dependencyGraph, err := rc.dependencyResolver.BuildDependencyGraph(combined)
+	if err != nil {
+		return results, failAll(ErrorTypeDependency, fmt.Sprintf("failed to build dependency graph: %v", err), err)
+	}
+	creationOrder, err := rc.dependencyResolver.GetCreationOrder(dependencyGraph)
+	if err != nil {
+		return results, failAll(ErrorTypeDependency, fmt.Sprintf("failed to determine creation order: %v", err), err)
+	}
+	deletionOrder, err := rc.dependencyResolver.GetDeletionOrder(dependencyGraph)
+	if err != nil {
+		return results, failAll(ErrorTypeDependency, fmt.Sprintf("failed to determine deletion order: %v", err), err)
+	}
+
+	// Each chart compares against its own actual state, which only holds its own resources
+	diffs := make(map[string]*StateDiff, len(charts))
+	for _, chartName := range charts {
+		result := results[chartName]
+		result.CreationLevels = resourceLevels(chartLevels(creationOrder, ownerByKey, chartName))
+		result.DeletionLevels = resourceLevels(chartLevels(deletionOrder, ownerByKey, chartName))
+
+		actualStateByType, err := rc.getCurrentStateWithRetry(ctx, chartName, result)
+		if err != nil {
+			return results, err
+		}
+
+		diffs[chartName], err = rc.compareAllStatesWithValidation(selectedByChart[chartName], actualStateByType, result)
+		if err != nil {
+			return results, err
+		}
+		rc.warnUnrecordedDeleteProtection(selectedByChart[chartName], actualStateByType)
+	}
+
+	if dryRun {
+		planned := plannedCreations(slices.Collect(maps.Values(diffs))...)
+		for _, chartName := range charts {
+			rc.populateDryRunResult(results[chartName], diffs[chartName])
+			rc.dryRunValidate(ctx, results[chartName], diffs[chartName], planned)
+		}
+	} else {
+		// Orphans are not in the manifests, so they are ordered by their own references
+		var orphans []Resource
+		for _, chartName := range charts {
+			orphans = append(orphans, diffs[chartName].ToDelete...)
+		}
+		orphanOrder, err := rc.liveDeletionOrder(orphans)
+		if err != nil {
+			return results, failAll(ErrorTypeDependency, fmt.Sprintf("failed to determine deletion order of orphaned resources: %v", err), err)
+		}
+
+		rc.executeMultiChart(ctx, charts, results, diffs, creationOrder, orphanOrder)
+	}
+
+	for _, chartName := range charts {
+		result := results[chartName]
+		rc.updateReconciliationStatus(chartName, result, startTime)
+		result.Duration = time.Since(startTime)
+		result.PerTypeDurations = result.durationsByType()
+		result.Summary = rc.generateSummary(result)
+	}
+
+	return results, nil
+}
+
+// executeMultiChart applies the charts' diffs one dependency level at a time, handling
+// every chart's resources of a level before moving on to the next
+func (rc *DefaultReconciliationController) executeMultiChart(ctx context.Context, charts []string, results map[string]*ReconciliationResult, diffs map[string]*StateDiff, creationOrder, deletionOrder [][]Resource) {
+	cancelled := func() bool {
+		if ctx.Err() == nil {
+			return false
+		}
+		for _, chartName := range charts {
+			rc.addError(results[chartName], ErrorTypeConfiguration, ResourceReference{},
+				"reconciliation cancelled by context", ctx.Err(), false)
+		}
+		return true
+	}
+
+	for levelIndex, level := range creationOrder {
+		for _, chartName := range charts {
+			rc.executeCreationLevel(ctx, results[chartName], level, diffs[chartName].ToCreate, levelIndex)
+		}
+		if cancelled() {
+			return
+		}
+	}
+
+	for _, chartName := range charts {
+		rc.executeUpdatesWithRecovery(ctx, results[chartName], diffs[chartName], creationOrder)
+	}
+
+	for levelIndex, level := range deletionOrder {
+		for _, chartName := range charts {
+			rc.executeDeletionLevel(ctx, results[chartName], level, diffs[chartName].ToDelete, levelIndex)
+		}
+		if cancelled() {
+			return
+		}
+	}
+}
+
+// chartLevels keeps the resources of chartName in each level of a combined order
+func chartLevels(order [][]Resource, ownerByKey map[string]string, chartName string) [][]Resource {
+	levels := make([][]Resource, len(order))
+	for i, level := range order {
+		levels[i] = make([]Resource, 0, len(level))
+		for _, resource := range level {
+			if ownerByKey[fmt.Sprintf("%s/%s", resource.GetType(), resource.GetName())] == chartName {
+				levels[i] = append(levels[i], resource)
+			}
+		}
+	}
+	return levels
+}
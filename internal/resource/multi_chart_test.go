@@ -0,0 +1,158 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newMultiChartManifests returns an "infra" chart defining a network and an "app" chart
+// whose container joins it
+func newMultiChartManifests() map[string][]Resource {
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "shared"
+	network.Spec.Driver = "bridge"
+	network.SetLabels(labels.GetStandardLabels("infra", "1.0.0"))
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.SetLabels(labels.GetStandardLabels("app", "1.0.0"))
+	container.Spec.Networks = []NetworkAttachment{{Name: "shared"}}
+
+	return map[string][]Resource{
+		"app":   {container},
+		"infra": {network},
+	}
+}
+
+func TestReconcileMulti_ResolvesReferencesAcrossCharts(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+	manifestsByChart := newMultiChartManifests()
+
+	// On its own the app chart references a network it does not define
+	if _, err := controller.Reconcile(ctx, manifestsByChart["app"], "app", true); err == nil || !strings.Contains(err.Error(), "references missing network 'shared'") {
+		t.Fatalf("Expected the app chart alone to fail validation, got %v", err)
+	}
+
+	results, err := controller.ReconcileMulti(ctx, manifestsByChart, false)
+	if err != nil {
+		t.Fatalf("ReconcileMulti failed: %v", err)
+	}
+
+	for chartName, expected := range map[string]ResourceReference{
+		"infra": {Type: ResourceTypeNetwork, Name: "shared"},
+		"app":   {Type: ResourceTypeContainer, Name: "web"},
+	} {
+		result := results[chartName]
+		if result == nil || result.ChartName != chartName {
+			t.Fatalf("Expected a result for chart %s, got %+v", chartName, result)
+		}
+		if len(result.Errors) != 0 || len(result.CreatedResources) != 1 {
+			t.Fatalf("Expected chart %s to create one resource without errors, got %+v and %+v", chartName, result.CreatedResources, result.Errors)
+		}
+		if created := result.CreatedResources[0]; created.Type != expected.Type || created.Name != expected.Name {
+			t.Errorf("Expected chart %s to create %+v, got %+v", chartName, expected, created)
+		}
+
+		status, err := controller.GetStatus(chartName)
+		if err != nil || status.Status != "healthy" {
+			t.Errorf("Expected chart %s to be tracked as healthy, got %+v (err: %v)", chartName, status, err)
+		}
+	}
+
+	// The network of one chart is created a level before the container of the other
+	if levels := results["infra"].CreationLevels; len(levels) != 2 || len(levels[0]) != 1 || len(levels[1]) != 0 {
+		t.Errorf("Expected the network at level 0, got %v", levels)
+	}
+	if levels := results["app"].CreationLevels; len(levels) != 2 || len(levels[0]) != 0 || len(levels[1]) != 1 {
+		t.Errorf("Expected the container at level 1, got %v", levels)
+	}
+
+	// Reconciling again finds both charts up to date
+	results, err = controller.ReconcileMulti(ctx, newMultiChartManifests(), false)
+	if err != nil {
+		t.Fatalf("ReconcileMulti failed: %v", err)
+	}
+	for chartName, result := range results {
+		if len(result.CreatedResources)+len(result.UpdatedResources)+len(result.DeletedResources) != 0 {
+			t.Errorf("Expected chart %s to be up to date, got %+v", chartName, result)
+		}
+	}
+}
+
+func TestReconcileMulti_DryRunPlansAcrossCharts(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+
+	results, err := controller.ReconcileMulti(context.Background(), newMultiChartManifests(), true)
+	if err != nil {
+		t.Fatalf("ReconcileMulti failed: %v", err)
+	}
+
+	// The network the app chart joins is planned by the infra chart, so it is not missing
+	if len(results["app"].Errors) != 0 {
+		t.Errorf("Expected no warnings for the app chart, got %+v", results["app"].Errors)
+	}
+	if mockClient.GetCallCount("CreateNetwork") != 0 || mockClient.GetCallCount("CreateContainer") != 0 {
+		t.Error("Expected the dry run not to create anything")
+	}
+}
+
+func TestReconcileMulti_PlacesMembersInTheirPod(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	web := newExplainTestContainer("nginx:1.25")
+	sidecar := newExplainTestContainer("envoy:1.30")
+	sidecar.ObjectMeta.Name = "sidecar"
+	manifestsByChart := map[string][]Resource{
+		"demo":  {web, sidecar, newTestPod("web", "sidecar")},
+		"infra": newMultiChartManifests()["infra"],
+	}
+
+	results, err := controller.ReconcileMulti(ctx, manifestsByChart, false)
+	if err != nil {
+		t.Fatalf("ReconcileMulti failed: %v", err)
+	}
+	if len(results["demo"].Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", results["demo"].Errors)
+	}
+
+	pods, err := mockClient.ListPods(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPods failed: %v", err)
+	}
+	if len(pods) != 1 || !slices.Equal(pods[0].Containers, []string{"sidecar", "web"}) {
+		t.Fatalf("Expected both containers in the pod, got %+v", pods)
+	}
+	for _, container := range []*ContainerResource{web, sidecar} {
+		if container.Spec.Pod != "app" {
+			t.Errorf("Expected %s to be placed in pod 'app', got %q", container.GetName(), container.Spec.Pod)
+		}
+	}
+}
+
+func TestReconcileMulti_RejectsResourceDefinedByTwoCharts(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	manifestsByChart := newMultiChartManifests()
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "shared"
+	network.SetLabels(labels.GetStandardLabels("app", "1.0.0"))
+	manifestsByChart["app"] = append(manifestsByChart["app"], network)
+
+	results, err := controller.ReconcileMulti(context.Background(), manifestsByChart, false)
+	if err == nil || !strings.Contains(err.Error(), "network/shared is defined by both chart 'app' and chart 'infra'") {
+		t.Fatalf("Expected the duplicate network to be rejected, got %v", err)
+	}
+	for chartName, result := range results {
+		if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeValidation {
+			t.Errorf("Expected chart %s to record the validation error, got %+v", chartName, result.Errors)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/containers/podman/v5/pkg/specgen"
+)
+
+// namespaceContainerPrefix starts a namespace mode that joins another container's namespace
+const namespaceContainerPrefix = "container:"
+
+// namespaceMode is one of the namespaces a container can share, with its manifest field
+type namespaceMode struct {
+	field string
+	mode  string
+	// modes are the keywords accepted besides container:<name>
+	modes []string
+}
+
+// namespaceModes returns the IPC, PID, UTS and network namespace modes of the spec
+func namespaceModes(spec CuteContainerSpec) []namespaceMode {
+	return []namespaceMode{
+		{field: "ipcMode", mode: spec.IpcMode, modes: []string{"host", "private", "shareable", "none", "pod"}},
+		{field: "pidMode", mode: spec.PidMode, modes: []string{"host", "private", "pod"}},
+		{field: "utsMode", mode: spec.UtsMode, modes: []string{"host", "private", "pod"}},
+		{field: "networkMode", mode: spec.NetworkMode, modes: []string{"host", "none"}},
+	}
+}
+
+// namespaceTarget returns the container whose namespace a container:<name> mode joins
+func namespaceTarget(mode string) (string, bool) {
+	return strings.CutPrefix(mode, namespaceContainerPrefix)
+}
+
+// validateNamespaceMode returns why a namespace mode is invalid, or an empty string
+func validateNamespaceMode(ns namespaceMode, container *ContainerResource) string {
+	if ns.mode == "" {
+		return ""
+	}
+	if slices.Contains(ns.modes, ns.mode) {
+		if ns.mode == "pod" && container.Spec.Pod == "" {
+			return fmt.Sprintf("%s 'pod' requires the container to be in a pod", ns.field)
+		}
+		return ""
+	}
+
+	if target, ok := namespaceTarget(ns.mode); ok {
+		switch strings.TrimSpace(target) {
+		case "":
+			return fmt.Sprintf("%s must name a container after '%s'", ns.field, namespaceContainerPrefix)
+		case container.GetName():
+			return fmt.Sprintf("%s must not refer to the container itself", ns.field)
+		}
+		return ""
+	}
+
+	return fmt.Sprintf("%s must be one of %s or container:<name>", ns.field, strings.Join(ns.modes, ", "))
+}
+
+// applyNamespaceModes sets the IPC, PID, UTS and network namespaces of the spec, leaving
+// the ones without a mode to Podman's defaults
+func applyNamespaceModes(spec *specgen.SpecGenerator, container CuteContainerSpec) error {
+	if container.IpcMode != "" {
+		ns, err := specgen.ParseIPCNamespace(container.IpcMode)
+		if err != nil {
+			return fmt.Errorf("invalid ipcMode: %w", err)
+		}
+		spec.IpcNS = ns
+	}
+	if container.PidMode != "" {
+		ns, err := specgen.ParseNamespace(container.PidMode)
+		if err != nil {
+			return fmt.Errorf("invalid pidMode: %w", err)
+		}
+		spec.PidNS = ns
+	}
+	if container.UtsMode != "" {
+		ns, err := specgen.ParseNamespace(container.UtsMode)
+		if err != nil {
+			return fmt.Errorf("invalid utsMode: %w", err)
+		}
+		spec.UtsNS = ns
+	}
+
+	switch mode := container.NetworkMode; mode {
+	case "":
+	case "host":
+		spec.NetNS = specgen.Namespace{NSMode: specgen.Host}
+	case "none":
+		spec.NetNS = specgen.Namespace{NSMode: specgen.NoNetwork}
+	default:
+		target, ok := namespaceTarget(mode)
+		if !ok {
+			return fmt.Errorf("invalid networkMode: %s", mode)
+		}
+		spec.NetNS = specgen.Namespace{NSMode: specgen.FromContainer, Value: target}
+	}
+	return nil
+}
+
+// namespaceModeFromInspect converts a namespace mode reported by inspect back into its
+// manifest form. Podman reports the default modes explicitly and names the containers
+// whose namespaces are joined by ID.
+func namespaceModeFromInspect(ctx context.Context, client podman.PodmanClient, mode string) string {
+	if isDefaultNamespaceMode(mode) {
+		return ""
+	}
+
+	target, ok := namespaceTarget(mode)
+	if !ok {
+		return mode
+	}
+	inspect, err := client.InspectContainer(ctx, target)
+	if err != nil {
+		return mode
+	}
+	// Pod members share the namespaces of the pod's infra container unless told otherwise
+	if inspect.IsInfra {
+		return ""
+	}
+	return namespaceContainerPrefix + strings.TrimPrefix(inspect.Name, "/")
+}
+
+// defaultNamespaceModes are the modes a container gets when none is set: its own
+// namespace, or its pod's. Podman reports the network namespace of a container on its
+// own networks by the driver that sets it up.
+var defaultNamespaceModes = []string{"", "private", "shareable", "pod", "bridge", "slirp4netns", "pasta"}
+
+// isDefaultNamespaceMode reports whether a mode is what a container gets when none is set
+func isDefaultNamespaceMode(mode string) bool {
+	return slices.Contains(defaultNamespaceModes, mode)
+}
+
+// sameNamespaceMode compares namespace modes, treating the default modes as equal
+func sameNamespaceMode(desired, actual string) bool {
+	if isDefaultNamespaceMode(desired) && isDefaultNamespaceMode(actual) {
+		return true
+	}
+	return desired == actual
+}
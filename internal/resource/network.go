@@ -32,6 +32,13 @@ type CuteNetworkSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Pattern="^([0-9]{1,3}\\.){3}[0-9]{1,3}$"
 	Gateway string `json:"gateway,omitempty"`
+	// IPRange is the CIDR within the subnet that container addresses are allocated from
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern="^([0-9]{1,3}\\.){3}[0-9]{1,3}/[0-9]{1,2}$"
+	IPRange string `json:"ipRange,omitempty"`
+	// Internal networks have no external connectivity
+	// +kubebuilder:validation:Optional
+	Internal bool `json:"internal,omitempty"`
 }
 
 // NewNetworkResource creates a new NetworkResource
@@ -2,14 +2,19 @@ package resource
 
 import (
 	"context"
-	"cutepod/internal/labels"
 	"cutepod/internal/podman"
 	"fmt"
+	"net"
+	"strings"
+
+	nettypes "github.com/containers/common/libnetwork/types"
+	"github.com/containers/podman/v5/libpod/define"
 )
 
 // NetworkManager implements ResourceManager for network resources
 type NetworkManager struct {
-	client podman.PodmanClient
+	client      podman.PodmanClient
+	labelPrefix string
 }
 
 // NewNetworkManager creates a new NetworkManager
@@ -19,6 +24,11 @@ func NewNetworkManager(client podman.PodmanClient) *NetworkManager {
 	}
 }
 
+// SetLabelPrefix makes the manager list networks by the chart label under prefix
+func (nm *NetworkManager) SetLabelPrefix(prefix string) {
+	nm.labelPrefix = prefix
+}
+
 // GetResourceType returns the resource type this manager handles
 func (nm *NetworkManager) GetResourceType() ResourceType {
 	return ResourceTypeNetwork
@@ -47,12 +57,7 @@ func (nm *NetworkManager) GetActualState(ctx context.Context, chartName string)
 		return nil, fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	networks, err := podmanClient.ListNetworks(
-		ctx,
-		map[string][]string{
-			"label": {labels.GetChartLabelValue(chartName)},
-		},
-	)
+	networks, err := podmanClient.ListNetworks(ctx, chartFilters(ctx, nm.labelPrefix, chartName))
 	if err != nil {
 		return nil, fmt.Errorf("unable to list networks: %w", err)
 	}
@@ -96,6 +101,26 @@ func (nm *NetworkManager) CreateResource(ctx context.Context, resource Resource)
 
 // UpdateResource updates an existing network resource
 func (nm *NetworkManager) UpdateResource(ctx context.Context, desired, actual Resource) error {
+	desiredNetwork, ok := desired.(*NetworkResource)
+	if !ok {
+		return fmt.Errorf("expected NetworkResource for desired, got %T", desired)
+	}
+
+	connectedClient := podman.NewConnectedClient(nm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	// Podman refuses to remove a network that still has containers attached,
+	// so detach them first and reattach them once the network is recreated
+	attached, err := nm.disconnectContainers(ctx, podmanClient, actual.GetName(), desiredNetwork.Spec.Subnet)
+	if err != nil {
+		return fmt.Errorf("unable to disconnect containers for update: %w", err)
+	}
+
 	// For networks, update typically means recreate
 	// First remove the existing network, then create the new one
 	if err := nm.DeleteResource(ctx, actual); err != nil {
@@ -106,6 +131,12 @@ func (nm *NetworkManager) UpdateResource(ctx context.Context, desired, actual Re
 		return fmt.Errorf("unable to create updated network: %w", err)
 	}
 
+	for containerName, options := range attached {
+		if err := podmanClient.ConnectContainerToNetwork(ctx, containerName, desired.GetName(), options); err != nil {
+			return fmt.Errorf("unable to reconnect container %s to network: %w", containerName, err)
+		}
+	}
+
 	return nil
 }
 
@@ -148,7 +179,16 @@ func (nm *NetworkManager) CompareResources(desired, actual Resource) (bool, erro
 		return false, nil
 	}
 
-	if desiredNetwork.Spec.Gateway != actualNetwork.Spec.Gateway {
+	// Podman picks a gateway when none is given, so only a requested one is compared
+	if desiredNetwork.Spec.Gateway != "" && desiredNetwork.Spec.Gateway != actualNetwork.Spec.Gateway {
+		return false, nil
+	}
+
+	if desiredNetwork.Spec.IPRange != actualNetwork.Spec.IPRange {
+		return false, nil
+	}
+
+	if desiredNetwork.Spec.Internal != actualNetwork.Spec.Internal {
 		return false, nil
 	}
 
@@ -162,6 +202,61 @@ func (nm *NetworkManager) CompareResources(desired, actual Resource) (bool, erro
 
 // Helper methods
 
+// disconnectContainers detaches every container from the network and returns the
+// options to reattach each one with, keyed by container name. Addresses are kept only
+// when the subnet the network is recreated with still holds them.
+func (nm *NetworkManager) disconnectContainers(ctx context.Context, client podman.PodmanClient, networkName, subnet string) (map[string]nettypes.PerNetworkOptions, error) {
+	containers, err := client.ListContainers(ctx, map[string][]string{
+		"network": {networkName},
+	}, true)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list containers on network %s: %w", networkName, err)
+	}
+
+	attached := make(map[string]nettypes.PerNetworkOptions, len(containers))
+	for _, container := range containers {
+		containerName := strings.TrimPrefix(container.Names[0], "/")
+
+		inspect, err := client.InspectContainer(ctx, containerName)
+		if err != nil {
+			return nil, fmt.Errorf("unable to inspect container %s: %w", containerName, err)
+		}
+
+		var options nettypes.PerNetworkOptions
+		if inspect.NetworkSettings != nil {
+			if network, exists := inspect.NetworkSettings.Networks[networkName]; exists && network != nil {
+				options = reattachOptions(network, subnet)
+			}
+		}
+
+		if err := client.DisconnectContainerFromNetwork(ctx, containerName, networkName); err != nil {
+			return nil, fmt.Errorf("unable to disconnect container %s: %w", containerName, err)
+		}
+		attached[containerName] = options
+	}
+
+	return attached, nil
+}
+
+// reattachOptions returns the aliases, MAC address and IP address a container had on a
+// network. The IP address is dropped when it is outside subnet, or when no subnet is set
+// and Podman picks one for the recreated network.
+func reattachOptions(network *define.InspectAdditionalNetwork, subnet string) nettypes.PerNetworkOptions {
+	options := nettypes.PerNetworkOptions{Aliases: network.Aliases}
+
+	if mac, err := net.ParseMAC(network.MacAddress); err == nil {
+		options.StaticMAC = nettypes.HardwareAddr(mac)
+	}
+
+	if _, ipNet, err := net.ParseCIDR(subnet); err == nil {
+		if ip := net.ParseIP(network.IPAddress); ip != nil && ipNet.Contains(ip) {
+			options.StaticIPs = []net.IP{ip}
+		}
+	}
+
+	return options
+}
+
 func (nm *NetworkManager) convertPodmanNetworkToResource(network podman.NetworkInfo) *NetworkResource {
 	resource := NewNetworkResource()
 	resource.ObjectMeta.Name = network.Name
@@ -171,17 +266,23 @@ func (nm *NetworkManager) convertPodmanNetworkToResource(network podman.NetworkI
 	resource.Spec.Driver = network.Driver
 	resource.Spec.Options = network.Options
 	resource.Spec.Subnet = network.Subnet
+	resource.Spec.Gateway = network.Gateway
+	resource.Spec.IPRange = network.IPRange
+	resource.Spec.Internal = network.Internal
 
 	return resource
 }
 
 func (nm *NetworkManager) buildNetworkSpec(network *NetworkResource) podman.NetworkSpec {
 	spec := podman.NetworkSpec{
-		Name:    network.GetName(),
-		Driver:  network.Spec.Driver,
-		Options: network.Spec.Options,
-		Subnet:  network.Spec.Subnet,
-		Labels:  network.GetLabels(),
+		Name:     network.GetName(),
+		Driver:   network.Spec.Driver,
+		Options:  network.Spec.Options,
+		Subnet:   network.Spec.Subnet,
+		Gateway:  network.Spec.Gateway,
+		IPRange:  network.Spec.IPRange,
+		Labels:   network.GetLabels(),
+		Internal: network.Spec.Internal,
 	}
 
 	// Set default driver if not specified
@@ -225,6 +225,123 @@ func TestNetworkManager_UpdateResource(t *testing.T) {
 	}
 }
 
+func TestNetworkManager_UpdateInternalReconnectsContainers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	nm := NewNetworkManager(mockClient)
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	original := NewNetworkResource()
+	original.ObjectMeta.Name = "backend"
+	original.Spec.Driver = "bridge"
+
+	if err := nm.CreateResource(ctx, original); err != nil {
+		t.Fatalf("Failed to create original network: %v", err)
+	}
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "db"
+	container.Spec.Image = "postgres:16"
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend", Aliases: []string{"database"}}}
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+
+	updated := NewNetworkResource()
+	updated.ObjectMeta.Name = "backend"
+	updated.Spec.Driver = "bridge"
+	updated.Spec.Internal = true
+
+	match, err := nm.CompareResources(updated, original)
+	if err != nil {
+		t.Fatalf("CompareResources failed: %v", err)
+	}
+	if match {
+		t.Fatal("Expected an internal flag change to be detected")
+	}
+
+	if err := nm.UpdateResource(ctx, updated, original); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	if mockClient.GetCallCount("DisconnectContainerFromNetwork") != 1 {
+		t.Errorf("Expected the container to be disconnected once, got %d", mockClient.GetCallCount("DisconnectContainerFromNetwork"))
+	}
+
+	aliases, connected := mockClient.GetNetworkAliases("db", "backend")
+	if !connected {
+		t.Fatal("Expected the container to be reconnected to the recreated network")
+	}
+	if len(aliases) != 1 || aliases[0] != "database" {
+		t.Errorf("Expected aliases [database] to be preserved, got %v", aliases)
+	}
+
+	network, err := mockClient.InspectNetwork(ctx, "backend")
+	if err != nil {
+		t.Fatalf("InspectNetwork failed: %v", err)
+	}
+	if !network.Internal {
+		t.Error("Expected the recreated network to be internal")
+	}
+}
+
+func TestNetworkManager_UpdateKeepsStaticAddressesOfReconnectedContainers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	nm := NewNetworkManager(mockClient)
+	cm := NewContainerManager(mockClient)
+	ctx := context.Background()
+
+	original := NewNetworkResource()
+	original.ObjectMeta.Name = "backend"
+	original.Spec.Subnet = "10.89.0.0/24"
+	if err := nm.CreateResource(ctx, original); err != nil {
+		t.Fatalf("Failed to create original network: %v", err)
+	}
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "db"
+	container.Spec.Image = "postgres:16"
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend", StaticIP: "10.89.0.5", StaticMAC: "92:d0:c6:0a:29:33"}}
+	if err := cm.CreateResource(ctx, container); err != nil {
+		t.Fatalf("Failed to create container: %v", err)
+	}
+
+	updated := NewNetworkResource()
+	updated.ObjectMeta.Name = "backend"
+	updated.Spec.Subnet = "10.89.0.0/24"
+	updated.Spec.Internal = true
+	if err := nm.UpdateResource(ctx, updated, original); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	inspect, err := mockClient.InspectContainer(ctx, "db")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	network, connected := inspect.NetworkSettings.Networks["backend"]
+	if !connected {
+		t.Fatal("Expected the container to be reconnected to the recreated network")
+	}
+	if network.IPAddress != "10.89.0.5" || network.MacAddress != "92:d0:c6:0a:29:33" {
+		t.Errorf("Expected the static IP and MAC to be kept, got %s and %s", network.IPAddress, network.MacAddress)
+	}
+
+	// An address outside the new subnet cannot be kept
+	moved := NewNetworkResource()
+	moved.ObjectMeta.Name = "backend"
+	moved.Spec.Subnet = "10.90.0.0/24"
+	if err := nm.UpdateResource(ctx, moved, updated); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+	inspect, err = mockClient.InspectContainer(ctx, "db")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if network := inspect.NetworkSettings.Networks["backend"]; network.IPAddress != "" || network.MacAddress != "92:d0:c6:0a:29:33" {
+		t.Errorf("Expected only the MAC to be kept, got %s and %s", network.IPAddress, network.MacAddress)
+	}
+}
+
 func TestNetworkManager_DeleteResource(t *testing.T) {
 	mockClient := podman.NewMockPodmanClient()
 	nm := NewNetworkManager(mockClient)
@@ -323,6 +440,36 @@ func TestNetworkManager_BuildNetworkSpec(t *testing.T) {
 	}
 }
 
+func TestNetworkManager_InternalRoundTrip(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	nm := NewNetworkManager(mockClient)
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "isolated"
+	network.Spec.Internal = true
+	network.SetLabels(labels.GetStandardLabels("test-name", "test-version"))
+
+	if spec := nm.buildNetworkSpec(network); !spec.Internal {
+		t.Error("Expected internal flag to be set on the network spec")
+	}
+
+	if err := nm.CreateResource(context.Background(), network); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	actual, err := nm.GetActualState(context.Background(), "test-name")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("Expected 1 network, got %d", len(actual))
+	}
+
+	if !actual[0].(*NetworkResource).Spec.Internal {
+		t.Error("Expected internal flag to be read back")
+	}
+}
+
 func TestNetworkManager_BuildNetworkSpec_Defaults(t *testing.T) {
 	mockClient := podman.NewMockPodmanClient()
 	nm := NewNetworkManager(mockClient)
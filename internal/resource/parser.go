@@ -1,8 +1,11 @@
 package resource
 
 import (
+	"bufio"
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/goccy/go-yaml"
@@ -23,11 +26,7 @@ func NewManifestParser() *ManifestParser {
 
 // ParseManifest parses a single YAML manifest and adds it to the registry
 func (p *ManifestParser) ParseManifest(content []byte) error {
-	// Split multi-document YAML
-	documents := bytes.Split(content, []byte("---"))
-
-	for _, doc := range documents {
-		doc = bytes.TrimSpace(doc)
+	for _, doc := range splitDocuments(content) {
 		if len(doc) == 0 {
 			continue
 		}
@@ -47,6 +46,125 @@ func (p *ManifestParser) ParseManifest(content []byte) error {
 	return nil
 }
 
+// ParseManifests reads multi-document YAML and returns its resources in document order.
+// Each document is dispatched on its kind and validated like chart templates are;
+// errors name the zero-based index of the offending document.
+func ParseManifests(reader io.Reader) ([]Resource, error) {
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifests: %w", err)
+	}
+
+	parser := NewManifestParser()
+	resources := make([]Resource, 0)
+	for index, doc := range splitDocuments(content) {
+		if len(doc) == 0 {
+			continue
+		}
+
+		resource, err := parser.parseDocument(doc)
+		if err != nil {
+			return nil, fmt.Errorf("document %d: %w", index, err)
+		}
+
+		if resource != nil {
+			resources = append(resources, resource)
+		}
+	}
+
+	return resources, nil
+}
+
+// MarshalManifests renders resources as multi-document YAML that ParseManifests reads
+// back. apiVersion and kind are filled in on resources that do not set them.
+func MarshalManifests(resources []Resource) ([]byte, error) {
+	var buf bytes.Buffer
+
+	for index, resource := range resources {
+		typeMeta := typeMetaOf(resource)
+		if typeMeta == nil {
+			return nil, fmt.Errorf("resource %d: unsupported resource type: %s", index, resource.GetType())
+		}
+		if typeMeta.Kind == "" {
+			typeMeta.APIVersion = GroupVersion.String()
+			typeMeta.Kind = kindByType[resource.GetType()]
+		}
+
+		// Going through JSON honours the omitempty tags of the Kubernetes metadata types
+		jsonBytes, err := json.Marshal(resource)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d (%s): failed to marshal: %w", index, resource.GetName(), err)
+		}
+
+		yamlBytes, err := yaml.JSONToYAML(jsonBytes)
+		if err != nil {
+			return nil, fmt.Errorf("resource %d (%s): failed to convert to YAML: %w", index, resource.GetName(), err)
+		}
+
+		if index > 0 {
+			buf.WriteString("---\n")
+		}
+		buf.Write(yamlBytes)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// kindByType maps resource types to the kind used in manifests
+var kindByType = map[ResourceType]string{
+	ResourceTypeContainer: "CuteContainer",
+	ResourceTypeNetwork:   "CuteNetwork",
+	ResourceTypeVolume:    "CuteVolume",
+	ResourceTypeSecret:    "CuteSecret",
+	ResourceTypePod:       "CutePod",
+}
+
+// typeMetaOf returns the type metadata of a resource, or nil for unknown implementations
+func typeMetaOf(resource Resource) *metav1.TypeMeta {
+	switch r := resource.(type) {
+	case *ContainerResource:
+		return &r.TypeMeta
+	case *NetworkResource:
+		return &r.TypeMeta
+	case *VolumeResource:
+		return &r.TypeMeta
+	case *SecretResource:
+		return &r.TypeMeta
+	case *PodResource:
+		return &r.TypeMeta
+	default:
+		return nil
+	}
+}
+
+// splitDocuments splits multi-document YAML on "---" separator lines. Documents are
+// trimmed, so blank ones come back empty but still take up an index.
+func splitDocuments(content []byte) [][]byte {
+	documents := make([][]byte, 0)
+	var current bytes.Buffer
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(content)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if isDocumentSeparator(line) {
+			documents = append(documents, bytes.TrimSpace(bytes.Clone(current.Bytes())))
+			current.Reset()
+			continue
+		}
+		current.Write(line)
+		current.WriteByte('\n')
+	}
+
+	return append(documents, bytes.TrimSpace(current.Bytes()))
+}
+
+// isDocumentSeparator reports whether line is a YAML document separator
+func isDocumentSeparator(line []byte) bool {
+	trimmed := bytes.TrimRight(line, " \t\r")
+	return bytes.Equal(trimmed, []byte("---")) || bytes.HasPrefix(trimmed, []byte("--- "))
+}
+
 // parseDocument parses a single YAML document into a resource
 func (p *ManifestParser) parseDocument(content []byte) (Resource, error) {
 	// First, parse the basic metadata to determine the resource type
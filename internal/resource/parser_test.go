@@ -1,7 +1,16 @@
 package resource
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
 	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/validate"
+	"github.com/goccy/go-yaml"
 )
 
 func TestManifestParser_ParseNetwork(t *testing.T) {
@@ -273,11 +282,142 @@ spec:
 
 	// Verify the container references the network
 	container := containers[0].(*ContainerResource)
-	if len(container.Spec.Networks) != 1 || container.Spec.Networks[0] != "web-network" {
+	if len(container.Spec.Networks) != 1 || container.Spec.Networks[0].Name != "web-network" {
 		t.Error("Container should reference web-network")
 	}
 }
 
+func TestManifestParser_ParseNetworkAttachments(t *testing.T) {
+	parser := NewManifestParser()
+
+	containerYAML := `
+apiVersion: cutepod/v1alpha0
+kind: CuteContainer
+metadata:
+  name: web-server
+spec:
+  image: nginx:latest
+  networks:
+    - web-network
+    - name: db-network
+      staticIP: 172.21.0.10
+      staticMAC: "02:42:ac:15:00:0a"
+      aliases:
+        - web
+`
+
+	if err := parser.ParseManifest([]byte(containerYAML)); err != nil {
+		t.Fatalf("Failed to parse container manifest: %v", err)
+	}
+
+	containers := parser.GetRegistry().GetResourcesByType(ResourceTypeContainer)
+	if len(containers) != 1 {
+		t.Fatalf("Expected 1 container resource, got %d", len(containers))
+	}
+
+	networks := containers[0].(*ContainerResource).Spec.Networks
+	if len(networks) != 2 {
+		t.Fatalf("Expected 2 network attachments, got %d", len(networks))
+	}
+
+	if networks[0].Name != "web-network" || networks[0].StaticIP != "" {
+		t.Errorf("Expected bare name attachment for web-network, got %+v", networks[0])
+	}
+
+	attachment := networks[1]
+	if attachment.Name != "db-network" {
+		t.Errorf("Expected network name 'db-network', got '%s'", attachment.Name)
+	}
+	if attachment.StaticIP != "172.21.0.10" {
+		t.Errorf("Expected static IP '172.21.0.10', got '%s'", attachment.StaticIP)
+	}
+	if attachment.StaticMAC != "02:42:ac:15:00:0a" {
+		t.Errorf("Expected static MAC '02:42:ac:15:00:0a', got '%s'", attachment.StaticMAC)
+	}
+	if len(attachment.Aliases) != 1 || attachment.Aliases[0] != "web" {
+		t.Errorf("Expected aliases [web], got %v", attachment.Aliases)
+	}
+}
+
+func TestContainerCRD_AcceptsNetworkShorthand(t *testing.T) {
+	crdYAML, err := os.ReadFile("../../crds/cutepod_containerresources.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read CRD: %v", err)
+	}
+	crdJSON, err := yaml.YAMLToJSON(crdYAML)
+	if err != nil {
+		t.Fatalf("Failed to convert CRD: %v", err)
+	}
+	var crd struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema spec.Schema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}
+	if err := json.Unmarshal(crdJSON, &crd); err != nil || len(crd.Spec.Versions) == 0 {
+		t.Fatalf("Failed to decode CRD schema: %v", err)
+	}
+	schema := &crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+
+	validateManifest := func(manifest []byte) error {
+		manifestJSON, err := yaml.YAMLToJSON(manifest)
+		if err != nil {
+			t.Fatalf("Failed to convert manifest: %v", err)
+		}
+		var document any
+		if err := json.Unmarshal(manifestJSON, &document); err != nil {
+			t.Fatalf("Failed to decode manifest: %v", err)
+		}
+		return validate.AgainstSchema(schema, document, strfmt.Default)
+	}
+
+	manifest, err := os.ReadFile("testdata/container_network_shorthand.yaml")
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	if err := validateManifest(manifest); err != nil {
+		t.Errorf("Expected the CRD to accept bare network names, got %v", err)
+	}
+
+	parser := NewManifestParser()
+	if err := parser.ParseManifest(manifest); err != nil {
+		t.Fatalf("Failed to parse fixture: %v", err)
+	}
+	networks := parser.GetRegistry().GetResourcesByType(ResourceTypeContainer)[0].(*ContainerResource).Spec.Networks
+	if len(networks) != 2 || networks[0].Name != "frontend" || networks[1].StaticIP != "172.21.0.10" {
+		t.Errorf("Expected both attachment forms to be parsed, got %+v", networks)
+	}
+
+	// The object form is still checked
+	withoutName := bytes.Replace(manifest, []byte("- name: backend\n     "), []byte("-"), 1)
+	if err := validateManifest(withoutName); err == nil {
+		t.Error("Expected the CRD to reject an attachment without a name")
+	}
+}
+
+func TestManifestParser_InvalidStaticIP(t *testing.T) {
+	parser := NewManifestParser()
+
+	containerYAML := `
+apiVersion: cutepod/v1alpha0
+kind: CuteContainer
+metadata:
+  name: web-server
+spec:
+  image: nginx:latest
+  networks:
+    - name: db-network
+      staticIP: not-an-ip
+`
+
+	if err := parser.ParseManifest([]byte(containerYAML)); err == nil {
+		t.Error("Expected an error for an invalid static IP")
+	}
+}
+
 func TestManifestParser_NetworkValidation(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -355,3 +495,85 @@ func contains(s, substr string) bool {
 				return false
 			}())))
 }
+
+func TestParseManifests_RoundTrip(t *testing.T) {
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	network.Spec.Driver = "bridge"
+
+	// Resources read back from Podman may lack type metadata
+	container := &ContainerResource{}
+	container.ObjectMeta.Name = "web"
+	container.SetLabels(map[string]string{"tier": "web"})
+	container.Spec.Image = "nginx:1.25"
+	container.Spec.Env = []EnvVar{{Name: "MOTD", Value: "--- not a separator"}}
+	container.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"}}
+
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "credentials"
+	secret.Spec.Type = SecretTypeOpaque
+	secret.Spec.Data = map[string]string{"password": "c2VjcmV0"}
+
+	content, err := MarshalManifests([]Resource{network, container, secret})
+	if err != nil {
+		t.Fatalf("MarshalManifests failed: %v", err)
+	}
+
+	resources, err := ParseManifests(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("ParseManifests failed: %v\n%s", err, content)
+	}
+
+	if len(resources) != 3 {
+		t.Fatalf("Expected 3 resources, got %d:\n%s", len(resources), content)
+	}
+
+	parsedNetwork, ok := resources[0].(*NetworkResource)
+	if !ok || parsedNetwork.GetName() != "backend" || parsedNetwork.Spec.Driver != "bridge" {
+		t.Errorf("Expected the backend network first, got %+v", resources[0])
+	}
+
+	parsedContainer, ok := resources[1].(*ContainerResource)
+	if !ok {
+		t.Fatalf("Expected a container second, got %T", resources[1])
+	}
+	if parsedContainer.Kind != "CuteContainer" || parsedContainer.APIVersion != GroupVersion.String() {
+		t.Errorf("Expected type metadata to be filled in, got %+v", parsedContainer.TypeMeta)
+	}
+	if parsedContainer.Spec.Image != "nginx:1.25" || parsedContainer.GetLabels()["tier"] != "web" {
+		t.Errorf("Expected image and labels to survive, got %+v", parsedContainer)
+	}
+	if len(parsedContainer.Spec.Env) != 1 || parsedContainer.Spec.Env[0].Value != "--- not a separator" {
+		t.Errorf("Expected env to survive, got %v", parsedContainer.Spec.Env)
+	}
+	if len(parsedContainer.Spec.Ports) != 1 || parsedContainer.Spec.Ports[0].HostPort != 8080 {
+		t.Errorf("Expected ports to survive, got %v", parsedContainer.Spec.Ports)
+	}
+
+	parsedSecret, ok := resources[2].(*SecretResource)
+	if !ok || parsedSecret.Spec.Data["password"] != "c2VjcmV0" {
+		t.Errorf("Expected the secret data to survive, got %+v", resources[2])
+	}
+}
+
+func TestParseManifests_UnknownKindNamesDocument(t *testing.T) {
+	manifests := `
+apiVersion: cutepod/v1alpha1
+kind: CuteNetwork
+metadata:
+  name: backend
+---
+apiVersion: cutepod/v1alpha1
+kind: CuteDeployment
+metadata:
+  name: web
+`
+
+	_, err := ParseManifests(strings.NewReader(manifests))
+	if err == nil {
+		t.Fatal("Expected an error for an unknown kind")
+	}
+	if !strings.Contains(err.Error(), "document 1") || !strings.Contains(err.Error(), "CuteDeployment") {
+		t.Errorf("Expected the error to name document 1 and the kind, got %v", err)
+	}
+}
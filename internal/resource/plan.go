@@ -0,0 +1,110 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"fmt"
+	"time"
+)
+
+// Plan computes the resources reconciliation would create, update and delete, with
+// their field-level diffs, without changing anything on the system. Unlike a dry-run
+// Reconcile it reads the actual state once, without retries, and skips the execute
+// phase, so it returns quickly enough to gate CI. Validation and dependency
+// errors are reported the same way Reconcile reports them.
+func (rc *DefaultReconciliationController) Plan(ctx context.Context, manifests []Resource, chartName string) (*ReconciliationResult, error) {
+	ctx, span := rc.tracer.Start(ctx, "plan", Attribute(SpanAttributeChart, chartName))
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+	ctx = withImageCache(ctx)
+
+	rc.clearComparisonCache()
+	result, err := rc.plan(ctx, manifests, chartName)
+	endSpan(span, err)
+
+	return result, err
+}
+
+// plan runs the read-only phases of reconcile
+func (rc *DefaultReconciliationController) plan(ctx context.Context, manifests []Resource, chartName string) (*ReconciliationResult, error) {
+	startTime := time.Now()
+
+	result := &ReconciliationResult{
+		CreatedResources: make([]ResourceAction, 0),
+		UpdatedResources: make([]ResourceAction, 0),
+		DeletedResources: make([]ResourceAction, 0),
+		Errors:           make([]*ReconciliationError, 0),
+		ChartName:        chartName,
+	}
+
+	if err := rc.validateManifests(manifests); err != nil {
+		return result, rc.addValidationError(result, err)
+	}
+	assignPodMembers(manifests)
+	rc.applyLabelPrefix(manifests)
+	manifests = selectManifests(ctx, manifests)
+
+	dependencyGraph, err := rc.dependencyResolver.BuildDependencyGraph(manifests)
+	if err != nil {
+		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+			fmt.Sprintf("failed to build dependency graph: %v", err), err, false)
+	}
+
+	creationOrder, err := rc.dependencyResolver.GetCreationOrder(dependencyGraph)
+	if err != nil {
+		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+			fmt.Sprintf("failed to determine creation order: %v", err), err, false)
+	}
+
+	deletionOrder, err := rc.dependencyResolver.GetDeletionOrder(dependencyGraph)
+	if err != nil {
+		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+			fmt.Sprintf("failed to determine deletion order: %v", err), err, false)
+	}
+	result.CreationLevels = resourceLevels(creationOrder)
+	result.DeletionLevels = resourceLevels(deletionOrder)
+
+	actualStateByType, err := rc.getCurrentState(ctx, chartName, result)
+	if err != nil {
+		return result, err
+	}
+
+	stateDiff, err := rc.compareAllStatesWithValidation(manifests, actualStateByType, result)
+	if err != nil {
+		return result, err
+	}
+
+	rc.populateDryRunResult(result, stateDiff)
+	rc.dryRunValidate(ctx, result, stateDiff, plannedCreations(stateDiff))
+
+	result.Duration = time.Since(startTime)
+	result.PerTypeDurations = result.durationsByType()
+	result.Summary = rc.generateSummary(result)
+
+	return result, nil
+}
+
+// getCurrentState reads the actual state of every resource type once. A type that
+// cannot be listed is reported and treated as empty, as in getCurrentStateWithRetry.
+func (rc *DefaultReconciliationController) getCurrentState(ctx context.Context, chartName string, result *ReconciliationResult) (map[ResourceType][]Resource, error) {
+	actualStateByType := make(map[ResourceType][]Resource)
+
+	for resourceType, manager := range rc.managers {
+		actualResources, err := manager.GetActualState(ctx, chartName)
+		if err != nil {
+			if podman.IsConnectionError(err) {
+				return nil, rc.addError(result, ErrorTypePodmanAPI, ResourceReference{},
+					fmt.Sprintf("Podman socket not found at %s; is Podman running?", rc.podmanURI()), err, false)
+			}
+
+			rc.addError(result, ErrorTypePodmanAPI, ResourceReference{Type: resourceType},
+				fmt.Sprintf("failed to get actual state for %s: %v", resourceType, err), err, true)
+			actualResources = make([]Resource, 0)
+		}
+
+		actualStateByType[resourceType] = actualResources
+	}
+
+	return actualStateByType, nil
+}
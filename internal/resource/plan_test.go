@@ -0,0 +1,76 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"strings"
+	"testing"
+)
+
+func TestPlan_ReportsChangesWithoutMutating(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+
+	result, err := controller.Plan(ctx, []Resource{network, newExplainTestContainer("nginx:1.26")}, "demo")
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if len(result.CreatedResources) != 1 || result.CreatedResources[0].Name != "backend" {
+		t.Errorf("Expected the network to be planned for creation, got %+v", result.CreatedResources)
+	}
+	if len(result.UpdatedResources) != 1 || result.UpdatedResources[0].Name != "web" {
+		t.Fatalf("Expected the container to be planned for update, got %+v", result.UpdatedResources)
+	}
+	if len(result.UpdatedResources[0].Diffs) == 0 {
+		t.Errorf("Expected the update to carry field diffs")
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %+v", result.Errors)
+	}
+
+	if mockClient.GetCallCount("CreateContainer") != 1 || mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Errorf("Expected Plan not to touch containers")
+	}
+	if mockClient.GetCallCount("CreateNetwork") != 0 {
+		t.Errorf("Expected Plan not to create the network")
+	}
+}
+
+func TestPlan_DoesNotRetryFailedListing(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("ListContainers", true)
+	controller := NewReconciliationController(mockClient)
+
+	result, err := controller.Plan(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo")
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "failed to get actual state for container") {
+		t.Errorf("Expected a single listing error, got %+v", result.Errors)
+	}
+}
+
+func TestPlan_ReportsValidationErrors(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{{Name: "cache", MountPath: "/cache"}}
+
+	result, err := controller.Plan(context.Background(), []Resource{container}, "demo")
+	if err == nil {
+		t.Fatal("Expected Plan to fail validation")
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeValidation {
+		t.Errorf("Expected a validation error in the result, got %+v", result.Errors)
+	}
+}
@@ -2,8 +2,6 @@ package resource
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // +kubebuilder:object:root=true
@@ -16,6 +14,10 @@ type PodResource struct {
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
 	Spec CutePodSpec `json:"spec"`
+
+	// members are the manifests of the containers the pod lists, set by assignPodMembers
+	// so that recreating the pod can recreate them too
+	members []*ContainerResource
 }
 
 // +kubebuilder:object:generate=true
@@ -29,6 +31,20 @@ type CutePodSpec struct {
 	// +kubebuilder:validation:Enum=Always;OnFailure;Never
 	// +kubebuilder:default:="Always"
 	RestartPolicy string `json:"restartPolicy,omitempty"` // Always, OnFailure, Never
+	// SharedNamespaces lists the namespaces member containers share; defaults to ipc, net and uts
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=cgroup;ipc;net;pid;uts
+	SharedNamespaces []string `json:"sharedNamespaces,omitempty"`
+	// Ports are published on the pod's infra container and reach every member sharing
+	// the net namespace
+	// +kubebuilder:validation:Optional
+	Ports []ContainerPort `json:"ports,omitempty"`
+	// Volumes are mounted into the pod's infra container and inherited by every member
+	// +kubebuilder:validation:Optional
+	Volumes []VolumeMount `json:"volumes,omitempty"`
+	// Priority orders the pod's creation within its dependency level, highest first
+	// +kubebuilder:validation:Optional
+	Priority int `json:"priority,omitempty"`
 }
 
 // NewPodResource creates a new PodResource
@@ -64,17 +80,23 @@ func (p *PodResource) SetLabels(labels map[string]string) {
 	p.ObjectMeta.Labels = labels
 }
 
-// GetDependencies returns the resources this pod depends on
+// GetPriority returns the pod's creation priority within its dependency level
+func (p *PodResource) GetPriority() int {
+	return p.Spec.Priority
+}
+
+// GetDependencies returns the resources this pod depends on. A pod only depends on the
+// volumes it mounts: its member containers are created inside it, so they depend on the
+// pod instead.
 func (p *PodResource) GetDependencies() []ResourceReference {
 	var deps []ResourceReference
-
-	// Add container dependencies
-	for _, containerName := range p.Spec.Containers {
-		deps = append(deps, ResourceReference{
-			Type: ResourceTypeContainer,
-			Name: containerName,
-		})
+	for _, volume := range p.Spec.Volumes {
+		if volume.Name != "" {
+			deps = append(deps, ResourceReference{
+				Type: ResourceTypeVolume,
+				Name: volume.Name,
+			})
+		}
 	}
-
 	return deps
 }
@@ -0,0 +1,355 @@
+package resource
+
+import (
+	"context"
+	"crypto/sha256"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// defaultPodSharedNamespaces are the namespaces pod members share unless the pod lists
+// its own, matching what containers of a Kubernetes pod share
+var defaultPodSharedNamespaces = []string{"ipc", "net", "uts"}
+
+// PodManager implements ResourceManager for pod resources. It manages the pod and its
+// infra container, which holds the ports and volumes shared by the members; member
+// containers are created inside it by the ContainerManager.
+type PodManager struct {
+	client      podman.PodmanClient
+	containers  *ContainerManager
+	labelPrefix string
+}
+
+// NewPodManager creates a new PodManager
+func NewPodManager(client podman.PodmanClient) *PodManager {
+	return NewPodManagerWithContainerManager(client, NewContainerManager(client))
+}
+
+// NewPodManagerWithContainerManager creates a new PodManager that resolves pod volumes
+// and recreates member containers through the given ContainerManager
+func NewPodManagerWithContainerManager(client podman.PodmanClient, containers *ContainerManager) *PodManager {
+	return &PodManager{
+		client:     client,
+		containers: containers,
+	}
+}
+
+// SetLabelPrefix makes the manager list pods by the chart label under prefix
+func (pm *PodManager) SetLabelPrefix(prefix string) {
+	pm.labelPrefix = prefix
+}
+
+// GetResourceType returns the resource type this manager handles
+func (pm *PodManager) GetResourceType() ResourceType {
+	return ResourceTypePod
+}
+
+// GetDesiredState extracts pod resources from manifests
+func (pm *PodManager) GetDesiredState(manifests []Resource) ([]Resource, error) {
+	var pods []Resource
+
+	for _, manifest := range manifests {
+		if manifest.GetType() == ResourceTypePod {
+			pods = append(pods, manifest)
+		}
+	}
+
+	return pods, nil
+}
+
+// GetActualState retrieves current pod resources from Podman
+func (pm *PodManager) GetActualState(ctx context.Context, chartName string) ([]Resource, error) {
+	connectedClient := podman.NewConnectedClient(pm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	pods, err := podmanClient.ListPods(ctx, chartFilters(ctx, pm.labelPrefix, chartName))
+	if err != nil {
+		return nil, fmt.Errorf("unable to list pods: %w", err)
+	}
+
+	var resources []Resource
+	for _, pod := range pods {
+		resources = append(resources, pm.convertPodmanPodToResource(pod))
+	}
+
+	return resources, nil
+}
+
+// CreateResource creates a new pod resource
+func (pm *PodManager) CreateResource(ctx context.Context, resource Resource) error {
+	pod, ok := resource.(*PodResource)
+	if !ok {
+		return fmt.Errorf("expected PodResource, got %T", resource)
+	}
+
+	connectedClient := podman.NewConnectedClient(pm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	mounts, err := pm.containers.podMounts(pod.Spec.Volumes)
+	if err != nil {
+		return err
+	}
+
+	specHash, err := computePodSpecHash(pod.Spec)
+	if err != nil {
+		return fmt.Errorf("failed to hash pod spec: %w", err)
+	}
+
+	_, err = podmanClient.CreatePod(ctx, podman.PodSpec{
+		Name:             pod.GetName(),
+		Labels:           labels.MergeLabels(pod.GetLabels(), map[string]string{labels.LabelSpecHash: specHash}),
+		SharedNamespaces: podSharedNamespaces(pod),
+		PortMappings:     pm.containers.convertPortMappings(pod.Spec.Ports),
+		Mounts:           mounts,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create pod: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateResource updates an existing pod resource. Membership changes need nothing from
+// the pod, since they change the spec of the containers joining or leaving it, which are
+// recreated by the ContainerManager. Shared namespaces, ports and volumes are fixed when
+// the infra container is created, so changing them recreates the pod; Podman removes
+// the members with it, so they are recreated here as well.
+func (pm *PodManager) UpdateResource(ctx context.Context, desired, actual Resource) error {
+	desiredPod, ok := desired.(*PodResource)
+	if !ok {
+		return fmt.Errorf("expected PodResource for desired, got %T", desired)
+	}
+	actualPod, ok := actual.(*PodResource)
+	if !ok {
+		return fmt.Errorf("expected PodResource for actual, got %T", actual)
+	}
+
+	changed, err := pm.podInfraChanged(desiredPod, actualPod)
+	if err != nil {
+		return err
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := pm.DeleteResource(ctx, actual); err != nil {
+		return fmt.Errorf("unable to remove existing pod for update: %w", err)
+	}
+
+	if err := pm.CreateResource(ctx, desired); err != nil {
+		return fmt.Errorf("unable to create updated pod: %w", err)
+	}
+
+	for _, member := range desiredPod.members {
+		if err := pm.containers.CreateResource(ctx, member); err != nil {
+			return fmt.Errorf("unable to recreate container %s in pod: %w", member.GetName(), err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteResource deletes a pod resource
+func (pm *PodManager) DeleteResource(ctx context.Context, resource Resource) error {
+	pod, ok := resource.(*PodResource)
+	if !ok {
+		return fmt.Errorf("expected PodResource, got %T", resource)
+	}
+
+	connectedClient := podman.NewConnectedClient(pm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	return podmanClient.RemovePod(ctx, pod.GetName())
+}
+
+// CompareResources compares desired vs actual pod resource
+func (pm *PodManager) CompareResources(desired, actual Resource) (bool, error) {
+	desiredPod, ok := desired.(*PodResource)
+	if !ok {
+		return false, fmt.Errorf("expected PodResource for desired, got %T", desired)
+	}
+
+	actualPod, ok := actual.(*PodResource)
+	if !ok {
+		return false, fmt.Errorf("expected PodResource for actual, got %T", actual)
+	}
+
+	changed, err := pm.podInfraChanged(desiredPod, actualPod)
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		return false, nil
+	}
+
+	if !slices.Equal(podMembers(desiredPod), podMembers(actualPod)) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// comparisonKey summarises what CompareResources reads from a pod that carries a spec
+// hash: its annotations and members
+func (pm *PodManager) comparisonKey(desired, actual Resource) (string, bool) {
+	actualPod, ok := actual.(*PodResource)
+	if !ok || actualPod.GetAnnotations()[labels.LabelSpecHash] == "" {
+		return "", false
+	}
+
+	var key strings.Builder
+	writeComparisonKey(&key, actualPod.GetAnnotations())
+	key.WriteString(strings.Join(podMembers(actualPod), ","))
+	return key.String(), true
+}
+
+// Helper methods
+
+func (pm *PodManager) convertPodmanPodToResource(pod podman.PodInfo) *PodResource {
+	resource := NewPodResource()
+	resource.ObjectMeta.Name = pod.Name
+
+	// The spec hash is bookkeeping rather than a user label
+	podLabels := make(map[string]string, len(pod.Labels))
+	for key, value := range pod.Labels {
+		if key == labels.LabelSpecHash {
+			resource.SetAnnotations(map[string]string{key: value})
+			continue
+		}
+		podLabels[key] = value
+	}
+	resource.SetLabels(podLabels)
+
+	resource.Spec.Containers = slices.Clone(pod.Containers)
+	resource.Spec.SharedNamespaces = slices.Clone(pod.SharedNamespaces)
+
+	for _, mapping := range pod.PortMappings {
+		resource.Spec.Ports = append(resource.Spec.Ports, ContainerPort{
+			ContainerPort: mapping.ContainerPort,
+			HostPort:      mapping.HostPort,
+			Protocol:      strings.ToUpper(mapping.Protocol),
+		})
+	}
+	for _, mount := range pod.Mounts {
+		resource.Spec.Volumes = append(resource.Spec.Volumes, VolumeMount{
+			Name:      mount.Source,
+			MountPath: mount.Destination,
+			ReadOnly:  slices.Contains(mount.Options, "ro"),
+		})
+	}
+
+	return resource
+}
+
+// podInfraChanged reports whether the desired pod needs a new infra container, because
+// its shared namespaces, ports or volumes differ from the actual pod
+func (pm *PodManager) podInfraChanged(desired, actual *PodResource) (bool, error) {
+	// Pods created by cutepod carry a hash of their spec, which catches any change
+	if actualHash := actual.GetAnnotations()[labels.LabelSpecHash]; actualHash != "" {
+		desiredHash, err := computePodSpecHash(desired.Spec)
+		if err != nil {
+			return false, fmt.Errorf("unable to hash desired pod spec: %w", err)
+		}
+		return desiredHash != actualHash, nil
+	}
+
+	if !slices.Equal(podSharedNamespaces(desired), podSharedNamespaces(actual)) {
+		return true, nil
+	}
+
+	if !pm.containers.comparePorts(desired.Spec.Ports, actual.Spec.Ports) {
+		return true, nil
+	}
+
+	return !pm.containers.compareVolumes(desired.Spec.Volumes, actual.Spec.Volumes), nil
+}
+
+// computePodSpecHash returns a stable hash of the parts of a pod spec that shape its
+// infra container. Members are left out, since joining or leaving a pod does not
+// change the pod itself.
+func computePodSpecHash(spec CutePodSpec) (string, error) {
+	normalized := CutePodSpec{
+		SharedNamespaces: podSharedNamespaces(&PodResource{Spec: spec}),
+		Ports:            slices.Clone(spec.Ports),
+		Volumes:          slices.Clone(spec.Volumes),
+	}
+	slices.SortStableFunc(normalized.Ports, func(a, b ContainerPort) int {
+		return strings.Compare(fmt.Sprintf("%05d/%05d/%s", a.HostPort, a.ContainerPort, strings.ToLower(a.Protocol)),
+			fmt.Sprintf("%05d/%05d/%s", b.HostPort, b.ContainerPort, strings.ToLower(b.Protocol)))
+	})
+	slices.SortStableFunc(normalized.Volumes, func(a, b VolumeMount) int {
+		return strings.Compare(a.Name+":"+a.MountPath, b.Name+":"+b.MountPath)
+	})
+
+	data, err := json.Marshal(normalized)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// podSharedNamespaces returns the sorted namespaces the pod's members share
+func podSharedNamespaces(pod *PodResource) []string {
+	if len(pod.Spec.SharedNamespaces) == 0 {
+		return defaultPodSharedNamespaces
+	}
+
+	namespaces := slices.Clone(pod.Spec.SharedNamespaces)
+	slices.Sort(namespaces)
+	return slices.Compact(namespaces)
+}
+
+// podMembers returns the sorted names of the pod's member containers
+func podMembers(pod *PodResource) []string {
+	members := slices.Clone(pod.Spec.Containers)
+	slices.Sort(members)
+	return slices.Compact(members)
+}
+
+// assignPodMembers places the containers a pod lists into that pod, so the
+// ContainerManager creates them inside it, and records them on the pod so that
+// recreating the pod recreates them too
+func assignPodMembers(manifests []Resource) {
+	podByContainer := make(map[string]*PodResource)
+	for _, manifest := range manifests {
+		if pod, ok := manifest.(*PodResource); ok {
+			pod.members = nil
+			for _, containerName := range pod.Spec.Containers {
+				podByContainer[containerName] = pod
+			}
+		}
+	}
+
+	for _, manifest := range manifests {
+		container, ok := manifest.(*ContainerResource)
+		if !ok {
+			continue
+		}
+		if pod, exists := podByContainer[container.GetName()]; exists {
+			container.Spec.Pod = pod.GetName()
+			pod.members = append(pod.members, container)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPodManager_ImplementsResourceManager(t *testing.T) {
+	var _ ResourceManager = &PodManager{}
+}
+
+// newTestPod creates a pod "app" of the "demo" chart with the given members
+func newTestPod(members ...string) *PodResource {
+	pod := NewPodResource()
+	pod.ObjectMeta.Name = "app"
+	pod.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	pod.Spec.Containers = members
+	return pod
+}
+
+func TestReconcile_CreatesPodBeforeItsMembers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	web := newExplainTestContainer("nginx:1.25")
+	sidecar := newExplainTestContainer("envoy:1.30")
+	sidecar.ObjectMeta.Name = "sidecar"
+	manifests := []Resource{web, sidecar, newTestPod("web", "sidecar")}
+
+	result, err := controller.Reconcile(ctx, manifests, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+	if len(result.CreatedResources) != 3 || result.CreatedResources[0].Type != ResourceTypePod {
+		t.Fatalf("Expected the pod to be created first, got %+v", result.CreatedResources)
+	}
+
+	pods, err := mockClient.ListPods(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPods failed: %v", err)
+	}
+	if len(pods) != 1 || !slices.Equal(pods[0].Containers, []string{"sidecar", "web"}) {
+		t.Fatalf("Expected both containers in the pod, got %+v", pods)
+	}
+	if !slices.Equal(pods[0].SharedNamespaces, defaultPodSharedNamespaces) {
+		t.Errorf("Expected the default shared namespaces, got %v", pods[0].SharedNamespaces)
+	}
+
+	result, err = controller.Reconcile(ctx, manifests, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources)+len(result.DeletedResources) != 0 {
+		t.Errorf("Expected the second reconcile to change nothing, got %s", result.Summary)
+	}
+}
+
+func TestPodManager_CompareResources(t *testing.T) {
+	pm := NewPodManager(podman.NewMockPodmanClient())
+	actual := newTestPod("sidecar", "web")
+	actual.Spec.SharedNamespaces = []string{"ipc", "net", "uts"}
+
+	tests := []struct {
+		name    string
+		desired *PodResource
+		equal   bool
+	}{
+		{name: "same members in another order", desired: newTestPod("web", "sidecar"), equal: true},
+		{name: "member added", desired: newTestPod("web", "sidecar", "worker"), equal: false},
+		{name: "member removed", desired: newTestPod("web"), equal: false},
+		{
+			name: "port published",
+			desired: func() *PodResource {
+				pod := newTestPod("web", "sidecar")
+				pod.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+				return pod
+			}(),
+			equal: false,
+		},
+		{
+			name: "volume added",
+			desired: func() *PodResource {
+				pod := newTestPod("web", "sidecar")
+				pod.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/data"}}
+				return pod
+			}(),
+			equal: false,
+		},
+		{
+			name: "shared namespaces changed",
+			desired: func() *PodResource {
+				pod := newTestPod("web", "sidecar")
+				pod.Spec.SharedNamespaces = []string{"net"}
+				return pod
+			}(),
+			equal: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			equal, err := pm.CompareResources(tt.desired, actual)
+			if err != nil {
+				t.Fatalf("CompareResources failed: %v", err)
+			}
+			if equal != tt.equal {
+				t.Errorf("Expected equal=%v, got %v", tt.equal, equal)
+			}
+		})
+	}
+}
+
+func TestPodManager_UpdateRecreatesPodWhenNamespacesChange(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	pm := NewPodManager(mockClient)
+	ctx := context.Background()
+
+	actual := newTestPod("web")
+	if err := pm.CreateResource(ctx, actual); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	// A membership change alone leaves the pod alone
+	if err := pm.UpdateResource(ctx, newTestPod("web", "sidecar"), actual); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+	if calls := mockClient.GetCallCount("RemovePod"); calls != 0 {
+		t.Errorf("Expected the pod not to be recreated, got %d removals", calls)
+	}
+
+	desired := newTestPod("web")
+	desired.Spec.SharedNamespaces = []string{"net", "pid"}
+	if err := pm.UpdateResource(ctx, desired, actual); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	pods, err := pm.GetActualState(ctx, "demo")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	if len(pods) != 1 || !slices.Equal(pods[0].(*PodResource).Spec.SharedNamespaces, []string{"net", "pid"}) {
+		t.Errorf("Expected the pod to be recreated with the new namespaces, got %+v", pods)
+	}
+}
+
+func TestReconcile_PodPortsChangeRecreatesPodWithMembers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	manifests := func(hostPort uint16) []Resource {
+		pod := newTestPod("web")
+		pod.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: hostPort}}
+		return []Resource{newExplainTestContainer("nginx:1.25"), pod}
+	}
+
+	result, err := controller.Reconcile(ctx, manifests(8080), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	result, err = controller.Reconcile(ctx, manifests(9090), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+	if len(result.UpdatedResources) != 1 || result.UpdatedResources[0].Type != ResourceTypePod {
+		t.Fatalf("Expected only the pod to be updated, got %+v", result.UpdatedResources)
+	}
+
+	pods, err := mockClient.ListPods(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPods failed: %v", err)
+	}
+	if len(pods) != 1 || len(pods[0].PortMappings) != 1 || pods[0].PortMappings[0].HostPort != 9090 {
+		t.Fatalf("Expected the pod to publish the new port, got %+v", pods)
+	}
+	if !slices.Equal(pods[0].Containers, []string{"web"}) {
+		t.Errorf("Expected the member to be recreated inside the new pod, got %v", pods[0].Containers)
+	}
+
+	result, err = controller.Reconcile(ctx, manifests(9090), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources)+len(result.DeletedResources) != 0 {
+		t.Errorf("Expected the third reconcile to change nothing, got %s", result.Summary)
+	}
+}
+
+func TestValidateManifests_PodMembership(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient()).(*DefaultReconciliationController)
+
+	err := controller.validateManifests([]Resource{newExplainTestContainer("nginx:1.25"), newTestPod("web", "worker")})
+	if err == nil || !strings.Contains(err.Error(), "pod 'app' references missing container 'worker'") {
+		t.Errorf("Expected the missing member to be reported, got %v", err)
+	}
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Pod = "other"
+	err = controller.validateManifests([]Resource{container})
+	if err == nil || !strings.Contains(err.Error(), "container 'web' references missing pod 'other'") {
+		t.Errorf("Expected the missing pod to be reported, got %v", err)
+	}
+
+	container = newExplainTestContainer("nginx:1.25")
+	container.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+	err = controller.validateManifests([]Resource{container, newTestPod("web")})
+	if err == nil || !strings.Contains(err.Error(), "declare them on the pod") {
+		t.Errorf("Expected member ports to be rejected, got %v", err)
+	}
+
+	container = newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+	backend := NewNetworkResource()
+	backend.ObjectMeta.Name = "backend"
+	err = controller.validateManifests([]Resource{container, backend, newTestPod("web")})
+	if err == nil || !strings.Contains(err.Error(), "container 'web' joins networks but shares the network namespace of pod 'app'") {
+		t.Errorf("Expected member networks to be rejected, got %v", err)
+	}
+
+	pod := newTestPod("web")
+	pod.Spec.SharedNamespaces = []string{"ipc"}
+	pod.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+	err = controller.validateManifests([]Resource{newExplainTestContainer("nginx:1.25"), pod})
+	if err == nil || !strings.Contains(err.Error(), "does not share the net namespace") {
+		t.Errorf("Expected pod ports without a shared network to be rejected, got %v", err)
+	}
+}
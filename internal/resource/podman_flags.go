@@ -0,0 +1,116 @@
+package resource
+
+import (
+	"cutepod/internal/labels"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/containers/podman/v5/pkg/specgen"
+	"github.com/docker/go-units"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+// podmanFlags are the Podman create flags that can be set through a
+// cutepod.io/podman-flag/<flag> annotation, for settings cutepod does not model yet.
+// Each one sets the matching SpecGenerator field from the annotation's value.
+var podmanFlags = map[string]func(spec *specgen.SpecGenerator, value string) error{
+	"cpu-shares": func(spec *specgen.SpecGenerator, value string) error {
+		shares, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return errors.New("must be a positive number")
+		}
+		limits := resourceLimits(spec)
+		if limits.CPU == nil {
+			limits.CPU = &specs.LinuxCPU{}
+		}
+		limits.CPU.Shares = &shares
+		return nil
+	},
+	"pids-limit": func(spec *specgen.SpecGenerator, value string) error {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return errors.New("must be a number, or -1 for no limit")
+		}
+		resourceLimits(spec).Pids = &specs.LinuxPids{Limit: limit}
+		return nil
+	},
+	"shm-size": func(spec *specgen.SpecGenerator, value string) error {
+		size, err := units.RAMInBytes(value)
+		if err != nil || size <= 0 {
+			return errors.New("must be a size such as 64m")
+		}
+		spec.ShmSize = &size
+		return nil
+	},
+	"oom-score-adj": func(spec *specgen.SpecGenerator, value string) error {
+		score, err := strconv.Atoi(value)
+		if err != nil || score < -1000 || score > 1000 {
+			return errors.New("must be a number between -1000 and 1000")
+		}
+		spec.OOMScoreAdj = &score
+		return nil
+	},
+}
+
+// resourceLimits returns the spec's resource limits, creating them when unset
+func resourceLimits(spec *specgen.SpecGenerator) *specs.LinuxResources {
+	if spec.ResourceLimits == nil {
+		spec.ResourceLimits = &specs.LinuxResources{}
+	}
+	return spec.ResourceLimits
+}
+
+// podmanFlagAnnotations returns the Podman flags a container sets through annotations,
+// by flag name
+func podmanFlagAnnotations(container *ContainerResource) map[string]string {
+	flags := make(map[string]string)
+	for key, value := range container.GetAnnotations() {
+		if flag, ok := strings.CutPrefix(key, labels.AnnotationPodmanFlagPrefix); ok {
+			flags[flag] = value
+		}
+	}
+	return flags
+}
+
+// validatePodmanFlags returns why each of the container's Podman flag annotations is
+// rejected, in flag order
+func validatePodmanFlags(container *ContainerResource) []string {
+	flags := podmanFlagAnnotations(container)
+
+	var problems []string
+	for _, flag := range slices.Sorted(maps.Keys(flags)) {
+		apply, supported := podmanFlags[flag]
+		if !supported {
+			problems = append(problems, fmt.Sprintf("unsupported Podman flag %q, supported flags are %s",
+				flag, strings.Join(slices.Sorted(maps.Keys(podmanFlags)), ", ")))
+			continue
+		}
+		if err := apply(&specgen.SpecGenerator{}, flags[flag]); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid value %q for Podman flag %s: %v", flags[flag], flag, err))
+		}
+	}
+	return problems
+}
+
+// applyPodmanFlags sets the Podman flags of the container's annotations on the spec
+func applyPodmanFlags(spec *specgen.SpecGenerator, container *ContainerResource) error {
+	if problems := validatePodmanFlags(container); len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	for flag, value := range podmanFlagAnnotations(container) {
+		if err := podmanFlags[flag](spec, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// samePodmanFlags reports whether a container was created with the Podman flags its
+// manifest sets. The annotations are passed on to Podman, so they read back with it.
+func samePodmanFlags(desired, actual *ContainerResource) bool {
+	return maps.Equal(podmanFlagAnnotations(desired), podmanFlagAnnotations(actual))
+}
@@ -0,0 +1,177 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultHookTimeout bounds a post-reconcile hook that does not set its own timeout
+const defaultHookTimeout = 30 * time.Second
+
+// Hook is a chart-level action run once a reconcile has applied the chart, such as
+// warming a cache or notifying a webhook. Exactly one of Exec and HTTP is set.
+type Hook struct {
+	Name    string
+	Exec    *ExecHook
+	HTTP    *HTTPHook
+	Timeout time.Duration // Defaults to 30 seconds
+}
+
+// ExecHook runs a command inside one of the chart's containers; a non-zero exit fails it
+type ExecHook struct {
+	Container string
+	Command   []string
+}
+
+// HTTPHook sends a request; a status of 400 or above fails it
+type HTTPHook struct {
+	Method  string // Defaults to POST
+	URL     string
+	Headers map[string]string
+	Body    string
+}
+
+// HookResult records the outcome of a post-reconcile hook
+type HookResult struct {
+	Name     string        `json:"name"`
+	Skipped  bool          `json:"skipped,omitempty"`
+	Message  string        `json:"message,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// WithPostReconcileHooks sets hooks run in order at the end of every reconcile that is
+// not a dry run. They are skipped when the reconcile has a non-recoverable error; a
+// failing hook does not stop the ones after it, and its failure is only recorded in
+// the result's hook results, since the chart itself was applied.
+func WithPostReconcileHooks(hooks ...Hook) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.postReconcileHooks = append(rc.postReconcileHooks, hooks...)
+	}
+}
+
+// validatePostReconcileHooks checks the hooks, and that exec hooks name a container of
+// the manifests
+func (rc *DefaultReconciliationController) validatePostReconcileHooks(manifests []Resource) error {
+	for i, hook := range rc.postReconcileHooks {
+		name := hook.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i)
+		}
+
+		switch {
+		case (hook.Exec == nil) == (hook.HTTP == nil):
+			return fmt.Errorf("post-reconcile hook '%s' must set exactly one of exec and http", name)
+		case hook.Exec != nil:
+			if len(hook.Exec.Command) == 0 {
+				return fmt.Errorf("post-reconcile hook '%s' has no command", name)
+			}
+			if !slices.ContainsFunc(manifests, func(manifest Resource) bool {
+				return manifest.GetType() == ResourceTypeContainer && manifest.GetName() == hook.Exec.Container
+			}) {
+				return fmt.Errorf("post-reconcile hook '%s' references missing container '%s'", name, hook.Exec.Container)
+			}
+		default:
+			target, err := url.Parse(hook.HTTP.URL)
+			if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+				return fmt.Errorf("post-reconcile hook '%s' needs an http or https URL, got %q", name, hook.HTTP.URL)
+			}
+		}
+	}
+	return nil
+}
+
+// runPostReconcileHooks runs the hooks once the chart was applied, or records them as
+// skipped when a non-recoverable error means it was not
+func (rc *DefaultReconciliationController) runPostReconcileHooks(ctx context.Context, result *ReconciliationResult) {
+	var blocking *ReconciliationError
+	for _, reconcileErr := range result.Errors {
+		if !reconcileErr.Recoverable {
+			blocking = reconcileErr
+			break
+		}
+	}
+
+	for i, hook := range rc.postReconcileHooks {
+		hookResult := HookResult{Name: hook.Name}
+		if hookResult.Name == "" {
+			hookResult.Name = fmt.Sprintf("#%d", i)
+		}
+
+		if blocking != nil {
+			hookResult.Skipped = true
+			hookResult.Message = fmt.Sprintf("skipped: reconcile failed with %s", blocking.Message)
+			result.Hooks = append(result.Hooks, hookResult)
+			continue
+		}
+
+		startTime := time.Now()
+		timeout := hook.Timeout
+		if timeout <= 0 {
+			timeout = defaultHookTimeout
+		}
+		hookCtx, cancel := context.WithTimeout(ctx, timeout)
+		err := rc.runPostReconcileHook(hookCtx, hook)
+		cancel()
+
+		hookResult.Duration = time.Since(startTime)
+		if err != nil {
+			hookResult.Error = err.Error()
+			rc.logger.Warn("post-reconcile hook failed", "chart", result.ChartName, "hook", hookResult.Name, "error", err)
+		} else {
+			hookResult.Message = "completed successfully"
+		}
+		result.Hooks = append(result.Hooks, hookResult)
+	}
+}
+
+// runPostReconcileHook runs a single hook
+func (rc *DefaultReconciliationController) runPostReconcileHook(ctx context.Context, hook Hook) error {
+	if hook.Exec != nil {
+		connectedClient := podman.NewConnectedClient(rc.podmanClient)
+		defer connectedClient.Close()
+
+		podmanClient, err := connectedClient.GetClient(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to connect to podman: %w", err)
+		}
+
+		exitCode, err := podmanClient.ExecContainer(ctx, hook.Exec.Container, hook.Exec.Command)
+		if err != nil {
+			return fmt.Errorf("unable to run command in container %s: %w", hook.Exec.Container, err)
+		}
+		if exitCode != 0 {
+			return fmt.Errorf("command exited with code %d", exitCode)
+		}
+		return nil
+	}
+
+	method := hook.HTTP.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	request, err := http.NewRequestWithContext(ctx, method, hook.HTTP.URL, strings.NewReader(hook.HTTP.Body))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+	for key, value := range hook.HTTP.Headers {
+		request.Header.Set(key, value)
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("%s %s returned %s", method, hook.HTTP.URL, strings.TrimSpace(response.Status))
+	}
+	return nil
+}
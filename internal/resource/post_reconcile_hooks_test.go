@@ -0,0 +1,98 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReconcile_RunsPostReconcileHooks(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Method+" "+r.Header.Get("X-Chart")+" "+string(body))
+	}))
+	defer server.Close()
+
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithPostReconcileHooks(
+		Hook{Name: "warm-cache", Exec: &ExecHook{Container: "web", Command: []string{"warm", "--all"}}},
+		Hook{Name: "notify", HTTP: &HTTPHook{URL: server.URL, Headers: map[string]string{"X-Chart": "demo"}, Body: "deployed"}},
+	))
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.Hooks) != 2 {
+		t.Fatalf("Expected 2 hook results, got %+v", result.Hooks)
+	}
+	for _, hook := range result.Hooks {
+		if hook.Skipped || hook.Error != "" {
+			t.Errorf("Expected hook %s to succeed, got %+v", hook.Name, hook)
+		}
+	}
+	execs := mockClient.GetExecs()
+	if len(execs) != 1 || execs[0].Container != "web" || strings.Join(execs[0].Command, " ") != "warm --all" {
+		t.Errorf("Expected the command to run in web, got %+v", execs)
+	}
+	if len(received) != 1 || received[0] != "POST demo deployed" {
+		t.Errorf("Expected the webhook to be notified once, got %v", received)
+	}
+
+	// A failing hook is recorded without stopping the next one
+	mockClient.SetExecExitCode([]string{"warm", "--all"}, 1)
+	result, err = controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Hooks) != 2 || !strings.Contains(result.Hooks[0].Error, "exited with code 1") || result.Hooks[1].Error != "" {
+		t.Errorf("Expected only the exec hook to fail, got %+v", result.Hooks)
+	}
+	if len(result.Errors) != 0 || len(received) != 2 {
+		t.Errorf("Expected the hook failure not to fail the reconcile, got %+v", result.Errors)
+	}
+}
+
+func TestReconcile_SkipsPostReconcileHooksAfterFailure(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetOperationError("CreateContainer", errors.New("invalid config provided"))
+	controller := NewReconciliationController(mockClient, WithPostReconcileHooks(
+		Hook{Name: "notify", HTTP: &HTTPHook{URL: server.URL}},
+	))
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if called {
+		t.Error("Expected the webhook not to be called")
+	}
+	if len(result.Hooks) != 1 || !result.Hooks[0].Skipped || !strings.Contains(result.Hooks[0].Message, "failed to create resource") {
+		t.Errorf("Expected the hook to be recorded as skipped, got %+v", result.Hooks)
+	}
+}
+
+func TestReconcile_RejectsPostReconcileHookOnMissingContainer(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithPostReconcileHooks(
+		Hook{Name: "warm-cache", Exec: &ExecHook{Container: "cache", Command: []string{"warm"}}},
+	))
+
+	_, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err == nil || !strings.Contains(err.Error(), "references missing container 'cache'") {
+		t.Errorf("Expected the missing container to be reported, got %v", err)
+	}
+}
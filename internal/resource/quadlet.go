@@ -0,0 +1,437 @@
+package resource
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// quadletRestartPolicies maps container restart policies to systemd's Restart= values.
+// systemd leaves stopped units stopped, so unless-stopped behaves like always.
+var quadletRestartPolicies = map[string]string{
+	"no":             "no",
+	"Never":          "no",
+	"on-failure":     "on-failure",
+	"OnFailure":      "on-failure",
+	"always":         "always",
+	"Always":         "always",
+	"unless-stopped": "always",
+}
+
+// quadletUnit accumulates the sections of a unit file in the order they are first used
+type quadletUnit struct {
+	sections []string
+	entries  map[string][]string
+}
+
+// add appends a "key=value" entry to the section for every non-empty value
+func (u *quadletUnit) add(section, key string, values ...string) {
+	if u.entries == nil {
+		u.entries = make(map[string][]string)
+	}
+	for _, value := range values {
+		if value == "" {
+			continue
+		}
+		if _, exists := u.entries[section]; !exists {
+			u.sections = append(u.sections, section)
+		}
+		u.entries[section] = append(u.entries[section], key+"="+value)
+	}
+}
+
+// String renders the unit file
+func (u *quadletUnit) String() string {
+	var b strings.Builder
+	for index, section := range u.sections {
+		if index > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[%s]\n", section)
+		for _, entry := range u.entries[section] {
+			b.WriteString(entry)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// GenerateQuadlet renders resources as Podman Quadlet units, so that systemd rather than
+// cutepod owns their lifecycle. Units are keyed by file name: containers, networks, named
+// volumes and pods become "<name>.container", "<name>.network", "<name>.volume" and
+// "<name>.pod". hostPath volumes are bind mounted and emptyDir volumes become anonymous
+// volumes, or tmpfs mounts when backed by memory, so neither has a unit of its own.
+// Secrets have no Quadlet unit either and must be created with "podman secret create"
+// before the units start. Dependencies on other containers become Requires= and After=;
+// Quadlet orders units after the networks, volumes and pods they reference by itself.
+// Readiness probes and lifecycle hooks are run by cutepod and are left out.
+func GenerateQuadlet(resources []Resource) (map[string]string, error) {
+	volumes := make(map[string]*VolumeResource)
+	networks := make(map[string]bool)
+	podByContainer := make(map[string]string)
+	for _, resource := range resources {
+		switch r := resource.(type) {
+		case *VolumeResource:
+			volumes[r.GetName()] = r
+		case *NetworkResource:
+			networks[r.GetName()] = true
+		case *PodResource:
+			for _, containerName := range r.Spec.Containers {
+				podByContainer[containerName] = r.GetName()
+			}
+		}
+	}
+
+	units := make(map[string]string)
+	for _, resource := range resources {
+		var fileName string
+		var unit *quadletUnit
+		var err error
+
+		switch r := resource.(type) {
+		case *ContainerResource:
+			fileName = r.GetName() + ".container"
+			unit, err = quadletContainer(r, podByContainer, volumes, networks)
+		case *NetworkResource:
+			fileName = r.GetName() + ".network"
+			unit = quadletNetwork(r)
+		case *VolumeResource:
+			if r.Spec.Type != VolumeTypeVolume {
+				continue
+			}
+			fileName = r.GetName() + ".volume"
+			unit, err = quadletVolume(r)
+		case *PodResource:
+			fileName = r.GetName() + ".pod"
+			unit, err = quadletPod(r, volumes)
+		case *SecretResource:
+			continue
+		default:
+			return nil, fmt.Errorf("unsupported resource type: %s", resource.GetType())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", resource.GetType(), resource.GetName(), err)
+		}
+
+		if _, exists := units[fileName]; exists {
+			return nil, fmt.Errorf("duplicate unit %s", fileName)
+		}
+		units[fileName] = unit.String()
+	}
+
+	return units, nil
+}
+
+// quadletContainer renders a container unit
+func quadletContainer(container *ContainerResource, podByContainer map[string]string, volumes map[string]*VolumeResource, networks map[string]bool) (*quadletUnit, error) {
+	unit := &quadletUnit{}
+	spec := container.Spec
+
+	unit.add("Unit", "Description", "cutepod container "+container.GetName())
+	for _, dependency := range spec.DependsOn {
+		unit.add("Unit", "Requires", dependency+".service")
+		unit.add("Unit", "After", dependency+".service")
+	}
+
+	unit.add("Container", "ContainerName", container.GetName())
+	unit.add("Container", "Image", spec.Image)
+	command, err := containerCommand(spec)
+	if err != nil {
+		return nil, err
+	}
+	unit.add("Container", "Exec", quadletCommand(append(slices.Clone(command), spec.Args...)))
+	// Pod members usually share the pod's hostname, so they only get one they set
+	hostname := containerHostname(container)
+	if podName := cmp.Or(spec.Pod, podByContainer[container.GetName()]); podName != "" {
+		unit.add("Container", "Pod", podName+".pod")
+		hostname = spec.Hostname
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(container.GetLabels())) {
+		unit.add("Container", "Label", quadletQuote(key+"="+container.GetLabels()[key]))
+	}
+	for _, env := range spec.Env {
+		unit.add("Container", "Environment", quadletQuote(env.Name+"="+env.Value))
+	}
+	// Expanding the keys here would write the secret values into the unit file
+	if len(spec.EnvFrom) > 0 {
+		return nil, fmt.Errorf("envFrom has no Quadlet equivalent")
+	}
+	unit.add("Container", "EnvironmentFile", spec.EnvFile)
+	unit.add("Container", "WorkingDir", spec.WorkingDir)
+	unit.add("Container", "User", containerUser(spec))
+	unit.add("Container", "GroupAdd", spec.GroupAdd...)
+
+	for _, port := range spec.Ports {
+		unit.add("Container", "PublishPort", quadletPort(port))
+	}
+
+	for _, attachment := range spec.Networks {
+		network := attachment.Name
+		if networks[network] {
+			network += ".network"
+		}
+		var options []string
+		for _, alias := range attachment.Aliases {
+			options = append(options, "alias="+alias)
+		}
+		if attachment.StaticIP != "" {
+			options = append(options, "ip="+attachment.StaticIP)
+		}
+		if attachment.StaticMAC != "" {
+			options = append(options, "mac="+attachment.StaticMAC)
+		}
+		if len(options) > 0 {
+			network += ":" + strings.Join(options, ",")
+		}
+		unit.add("Container", "Network", network)
+	}
+
+	if err := addQuadletMounts(unit, "Container", spec.Volumes, volumes); err != nil {
+		return nil, err
+	}
+
+	for _, secret := range spec.Secrets {
+		if secret.Env {
+			unit.add("Container", "Secret", secret.Name+",type=env")
+		}
+		if secret.Path != "" && len(secret.Items) == 0 {
+			unit.add("Container", "Secret", secret.Name+",target="+secret.Path)
+		}
+		for _, item := range secret.Items {
+			unit.add("Container", "Secret", fmt.Sprintf("%s,target=%s,mode=%04o", secret.Name, path.Join(secret.Path, item.Path), secretItemMode(item)))
+		}
+	}
+
+	if securityContext := spec.SecurityContext; securityContext != nil {
+		capAdd, capDrop, err := resolveCapabilities(securityContext)
+		if err != nil {
+			return nil, err
+		}
+		unit.add("Container", "AddCapability", capAdd...)
+		unit.add("Container", "DropCapability", capDrop...)
+		if readOnlyRootFilesystem(spec) {
+			unit.add("Container", "ReadOnly", "true")
+		}
+		unit.add("Container", "SeccompProfile", securityContext.SeccompProfile)
+		// Quadlet has no key for privileged containers
+		if securityContext.Privileged != nil && *securityContext.Privileged {
+			unit.add("Container", "PodmanArgs", "--privileged")
+		}
+	}
+	// Quadlet only has a key for the network namespace, so the modes all go through PodmanArgs
+	for _, ns := range namespaceModes(spec) {
+		if ns.mode != "" {
+			unit.add("Container", "PodmanArgs", fmt.Sprintf("--%s=%s", strings.TrimSuffix(ns.field, "Mode"), ns.mode))
+		}
+	}
+	unit.add("Container", "HostName", hostname)
+	if spec.DomainName != "" {
+		unit.add("Container", "Sysctl", "kernel.domainname="+spec.DomainName)
+	}
+	// Quadlet has no key for an init process, stdin or a terminal, and always removes
+	// containers once they exit
+	if initEnabled(spec) {
+		unit.add("Container", "PodmanArgs", "--init")
+	}
+	if stdinOpen(spec) {
+		unit.add("Container", "PodmanArgs", "--interactive")
+	}
+	if ttyEnabled(spec) {
+		unit.add("Container", "PodmanArgs", "--tty")
+	}
+	for _, key := range slices.Sorted(maps.Keys(spec.Sysctl)) {
+		unit.add("Container", "Sysctl", key+"="+spec.Sysctl[key])
+	}
+
+	if health := spec.Health; health != nil && health.Type == "exec" {
+		unit.add("Container", "HealthCmd", quadletCommand(health.Command))
+		unit.add("Container", "HealthInterval", quadletSeconds(health.IntervalSeconds))
+		unit.add("Container", "HealthTimeout", quadletSeconds(health.TimeoutSeconds))
+		unit.add("Container", "HealthStartPeriod", quadletSeconds(health.StartPeriodSeconds))
+		if health.Retries > 0 {
+			unit.add("Container", "HealthRetries", strconv.Itoa(int(health.Retries)))
+		}
+	}
+
+	if spec.RestartPolicy != "" {
+		restart, ok := quadletRestartPolicies[spec.RestartPolicy]
+		if !ok {
+			return nil, fmt.Errorf("invalid restartPolicy %s", spec.RestartPolicy)
+		}
+		unit.add("Service", "Restart", restart)
+	}
+
+	unit.add("Install", "WantedBy", "default.target")
+	return unit, nil
+}
+
+// quadletNetwork renders a network unit
+func quadletNetwork(network *NetworkResource) *quadletUnit {
+	unit := &quadletUnit{}
+
+	unit.add("Network", "NetworkName", network.GetName())
+	unit.add("Network", "Driver", network.Spec.Driver)
+	unit.add("Network", "Subnet", network.Spec.Subnet)
+	unit.add("Network", "Gateway", network.Spec.Gateway)
+	if network.Spec.Internal {
+		unit.add("Network", "Internal", "true")
+	}
+	for _, key := range slices.Sorted(maps.Keys(network.Spec.Options)) {
+		unit.add("Network", "Options", key+"="+network.Spec.Options[key])
+	}
+	for _, key := range slices.Sorted(maps.Keys(network.GetLabels())) {
+		unit.add("Network", "Label", quadletQuote(key+"="+network.GetLabels()[key]))
+	}
+
+	return unit
+}
+
+// quadletVolume renders a named volume unit. Quadlet only knows the options of the local
+// driver, which mounts a device of a given type with mount options.
+func quadletVolume(volume *VolumeResource) (*quadletUnit, error) {
+	unit := &quadletUnit{}
+
+	unit.add("Volume", "VolumeName", volume.GetName())
+	if source := volume.Spec.Volume; source != nil {
+		unit.add("Volume", "Driver", source.Driver)
+		for _, key := range slices.Sorted(maps.Keys(source.Options)) {
+			switch key {
+			case "type":
+				unit.add("Volume", "Type", source.Options[key])
+			case "device":
+				unit.add("Volume", "Device", source.Options[key])
+			case "o":
+				unit.add("Volume", "Options", source.Options[key])
+			default:
+				return nil, fmt.Errorf("volume option %s has no Quadlet equivalent", key)
+			}
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(volume.GetLabels())) {
+		unit.add("Volume", "Label", quadletQuote(key+"="+volume.GetLabels()[key]))
+	}
+
+	return unit, nil
+}
+
+// quadletPod renders a pod unit holding the ports and volumes its members share
+func quadletPod(pod *PodResource, volumes map[string]*VolumeResource) (*quadletUnit, error) {
+	unit := &quadletUnit{}
+
+	unit.add("Pod", "PodName", pod.GetName())
+	for _, port := range pod.Spec.Ports {
+		unit.add("Pod", "PublishPort", quadletPort(port))
+	}
+	if err := addQuadletMounts(unit, "Pod", pod.Spec.Volumes, volumes); err != nil {
+		return nil, err
+	}
+
+	unit.add("Install", "WantedBy", "default.target")
+	return unit, nil
+}
+
+// addQuadletMounts adds the volume mounts to the section of the unit
+func addQuadletMounts(unit *quadletUnit, section string, mounts []VolumeMount, volumes map[string]*VolumeResource) error {
+	for _, mount := range mounts {
+		volume, exists := volumes[mount.Name]
+		if !exists {
+			return fmt.Errorf("references missing volume '%s'", mount.Name)
+		}
+
+		mountPath := cmp.Or(mount.MountPath, mount.ContainerPath)
+		var options []string
+		if mount.ReadOnly {
+			options = append(options, "ro")
+		}
+		if mount.MountOptions != nil && mount.MountOptions.SELinuxContext != "" {
+			options = append(options, seLinuxContextOption(mount.MountOptions.SELinuxContext))
+		} else if mount.MountOptions != nil && mount.MountOptions.SELinuxLabel != "" {
+			options = append(options, mount.MountOptions.SELinuxLabel)
+		}
+
+		switch volume.Spec.Type {
+		case VolumeTypeHostPath:
+			source := volume.Spec.HostPath.Path
+			if mount.SubPath != "" {
+				source = path.Join(source, mount.SubPath)
+			}
+			unit.add(section, "Volume", quadletVolumeEntry(source, mountPath, options))
+		case VolumeTypeEmptyDir:
+			if volume.Spec.EmptyDir != nil && volume.Spec.EmptyDir.Medium == StorageMediumMemory {
+				unit.add(section, "Tmpfs", mountPath)
+				continue
+			}
+			unit.add(section, "Volume", quadletVolumeEntry("", mountPath, options))
+		default:
+			if mount.SubPath != "" {
+				entry := fmt.Sprintf("type=volume,source=%s.volume,destination=%s,subpath=%s", volume.GetName(), mountPath, mount.SubPath)
+				if mount.ReadOnly {
+					entry += ",ro=true"
+				}
+				unit.add(section, "Mount", entry)
+				continue
+			}
+			unit.add(section, "Volume", quadletVolumeEntry(volume.GetName()+".volume", mountPath, options))
+		}
+	}
+
+	return nil
+}
+
+// quadletVolumeEntry formats a Volume= value; an empty source makes an anonymous volume
+func quadletVolumeEntry(source, mountPath string, options []string) string {
+	entry := mountPath
+	if source != "" {
+		entry = source + ":" + mountPath
+	}
+	if len(options) > 0 {
+		entry += ":" + strings.Join(options, ",")
+	}
+	return entry
+}
+
+// quadletPort formats a PublishPort= value
+func quadletPort(port ContainerPort) string {
+	entry := strconv.Itoa(int(port.ContainerPort))
+	if port.HostPort != 0 {
+		entry = strconv.Itoa(int(port.HostPort)) + ":" + entry
+	}
+	if port.Protocol != "" {
+		entry += "/" + strings.ToLower(port.Protocol)
+	}
+	return entry
+}
+
+// quadletSeconds formats a number of seconds as a duration, leaving zero unset
+func quadletSeconds(seconds int32) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return strconv.Itoa(int(seconds)) + "s"
+}
+
+// quadletCommand formats a command line, quoting the arguments that need it
+func quadletCommand(command []string) string {
+	quoted := make([]string, 0, len(command))
+	for _, arg := range command {
+		quoted = append(quoted, quadletQuote(arg))
+	}
+	return strings.Join(quoted, " ")
+}
+
+// quadletQuote escapes systemd specifiers in a value, and quotes it when it contains
+// whitespace or quotes
+func quadletQuote(value string) string {
+	value = strings.ReplaceAll(value, "%", "%%")
+	if value != "" && !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	value = strings.ReplaceAll(value, `"`, `\"`)
+	return `"` + value + `"`
+}
@@ -0,0 +1,117 @@
+package resource
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestGenerateQuadlet(t *testing.T) {
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "front"
+	network.Spec.Subnet = "10.89.0.0/24"
+
+	data := NewVolumeResource()
+	data.ObjectMeta.Name = "data"
+	data.Spec.Type = VolumeTypeVolume
+
+	site := NewVolumeResource()
+	site.ObjectMeta.Name = "site"
+	site.Spec.Type = VolumeTypeHostPath
+	site.Spec.HostPath = &HostPathVolumeSource{Path: "/srv/site"}
+
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "token"
+
+	db := newExplainTestContainer("postgres:16")
+	db.ObjectMeta.Name = "db"
+	db.SetLabels(nil)
+	db.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql/data"}}
+
+	web := newExplainTestContainer("nginx:1.25")
+	web.SetLabels(nil)
+	web.Spec.Command = []string{"nginx", "-g", "daemon off;"}
+	web.Spec.Env = []EnvVar{{Name: "GREETING", Value: "100% ready"}}
+	web.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}, {ContainerPort: 53, Protocol: "UDP"}}
+	web.Spec.Networks = []NetworkAttachment{{Name: "front", Aliases: []string{"www"}}}
+	web.Spec.Volumes = []VolumeMount{{Name: "site", MountPath: "/usr/share/nginx/html", ReadOnly: true}}
+	web.Spec.Secrets = []SecretReference{{Name: "token", Path: "/run/token"}}
+	web.Spec.DependsOn = []string{"db"}
+	web.Spec.RestartPolicy = "unless-stopped"
+
+	units, err := GenerateQuadlet([]Resource{network, data, site, secret, db, web})
+	if err != nil {
+		t.Fatalf("GenerateQuadlet failed: %v", err)
+	}
+
+	var fileNames []string
+	for fileName := range units {
+		fileNames = append(fileNames, fileName)
+	}
+	slices.Sort(fileNames)
+	expectedFiles := []string{"data.volume", "db.container", "front.network", "web.container"}
+	if !slices.Equal(fileNames, expectedFiles) {
+		t.Fatalf("Expected units %v, got %v", expectedFiles, fileNames)
+	}
+
+	expectedWeb := `[Unit]
+Description=cutepod container web
+Requires=db.service
+After=db.service
+
+[Container]
+ContainerName=web
+Image=nginx:1.25
+Exec=nginx -g "daemon off;"
+Environment="GREETING=100%% ready"
+PublishPort=8080:80
+PublishPort=53/udp
+Network=front.network:alias=www
+Volume=/srv/site:/usr/share/nginx/html:ro
+Secret=token,target=/run/token
+HostName=web
+
+[Service]
+Restart=always
+
+[Install]
+WantedBy=default.target
+`
+	if units["web.container"] != expectedWeb {
+		t.Errorf("Unexpected web.container:\n%s", units["web.container"])
+	}
+
+	if !strings.Contains(units["db.container"], "Volume=data.volume:/var/lib/postgresql/data\n") {
+		t.Errorf("Expected db to mount the data volume unit, got:\n%s", units["db.container"])
+	}
+	if !strings.Contains(units["front.network"], "NetworkName=front\nSubnet=10.89.0.0/24\n") {
+		t.Errorf("Unexpected front.network:\n%s", units["front.network"])
+	}
+}
+
+func TestGenerateQuadlet_Pod(t *testing.T) {
+	pod := newTestPod("web")
+	pod.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+
+	units, err := GenerateQuadlet([]Resource{newExplainTestContainer("nginx:1.25"), pod})
+	if err != nil {
+		t.Fatalf("GenerateQuadlet failed: %v", err)
+	}
+
+	if !strings.Contains(units["web.container"], "Pod=app.pod\n") {
+		t.Errorf("Expected the member to join the pod unit, got:\n%s", units["web.container"])
+	}
+	if !strings.Contains(units["app.pod"], "[Pod]\nPodName=app\nPublishPort=8080:80\n") {
+		t.Errorf("Unexpected app.pod:\n%s", units["app.pod"])
+	}
+}
+
+func TestGenerateQuadlet_MissingVolume(t *testing.T) {
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/data"}}
+
+	_, err := GenerateQuadlet([]Resource{container})
+	if err == nil || !strings.Contains(err.Error(), "references missing volume 'data'") {
+		t.Errorf("Expected the missing volume to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,283 @@
+package resource
+
+import (
+	"context"
+	"crypto/tls"
+	"cutepod/internal/podman"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProbeType identifies how a probe checks a container
+type ProbeType string
+
+const (
+	ProbeTypeExec ProbeType = "exec"
+	ProbeTypeTCP  ProbeType = "tcp"
+	ProbeTypeHTTP ProbeType = "http"
+)
+
+const (
+	defaultProbePeriod  = time.Second
+	defaultProbeTimeout = 30 * time.Second
+	defaultProbeHost    = "localhost"
+)
+
+// ReadinessChecker defines the interface for checking readiness with one type of probe
+type ReadinessChecker interface {
+	// Check runs the probe once against the container, returning nil if it passed or an
+	// error describing why it did not
+	Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error
+
+	// SupportsType returns true if this checker handles the given probe type
+	SupportsType(probeType ProbeType) bool
+}
+
+// ReadinessCheckerRegistry manages readiness checkers for different probe types
+type ReadinessCheckerRegistry struct {
+	checkers []ReadinessChecker
+}
+
+// NewReadinessCheckerRegistry creates a new registry with the exec, tcp and http checkers
+func NewReadinessCheckerRegistry() *ReadinessCheckerRegistry {
+	return &ReadinessCheckerRegistry{
+		checkers: []ReadinessChecker{
+			NewExecReadinessChecker(),
+			NewTCPReadinessChecker(),
+			NewHTTPReadinessChecker(),
+		},
+	}
+}
+
+// Register adds a checker; it takes precedence over checkers registered before it
+func (r *ReadinessCheckerRegistry) Register(checker ReadinessChecker) {
+	r.checkers = append([]ReadinessChecker{checker}, r.checkers...)
+}
+
+// GetChecker returns the appropriate checker for the given probe type
+func (r *ReadinessCheckerRegistry) GetChecker(probeType ProbeType) (ReadinessChecker, error) {
+	for _, checker := range r.checkers {
+		if checker.SupportsType(probeType) {
+			return checker, nil
+		}
+	}
+	return nil, fmt.Errorf("no readiness checker found for probe type: %s", probeType)
+}
+
+// builtinReadinessCheckers backs Probe.Check, which only knows the built-in probe types
+var builtinReadinessCheckers = NewReadinessCheckerRegistry()
+
+// Check runs the probe once against a container using the exec, tcp or http checker
+func (p *Probe) Check(ctx context.Context, container *ContainerResource, client podman.PodmanClient) error {
+	checker, err := builtinReadinessCheckers.GetChecker(p.Type)
+	if err != nil {
+		return err
+	}
+	return checker.Check(ctx, client, container, p)
+}
+
+// WithReadinessChecker registers a checker for a custom probe type, or replaces the
+// built-in checker of a type it also supports
+func WithReadinessChecker(checker ReadinessChecker) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if checker != nil {
+			rc.readinessCheckers.Register(checker)
+		}
+	}
+}
+
+// waitForReady polls the container's readiness probe until it passes or the probe
+// timeout elapses, and returns how long it waited
+func (rc *DefaultReconciliationController) waitForReady(ctx context.Context, container *ContainerResource) (time.Duration, error) {
+	startTime := time.Now()
+	probe := container.Spec.ReadinessProbe
+
+	checker, err := rc.readinessCheckers.GetChecker(probe.Type)
+	if err != nil {
+		return 0, err
+	}
+
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	period := defaultProbePeriod
+	if probe.PeriodSeconds > 0 {
+		period = time.Duration(probe.PeriodSeconds) * time.Second
+	}
+	timeout := defaultProbeTimeout
+	if probe.TimeoutSeconds > 0 {
+		timeout = time.Duration(probe.TimeoutSeconds) * time.Second
+	}
+	deadline := time.After(timeout)
+
+	for {
+		err := checker.Check(ctx, podmanClient, container, probe)
+		if err == nil {
+			return time.Since(startTime), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return time.Since(startTime), ctx.Err()
+		case <-deadline:
+			err = fmt.Errorf("not ready after %s: %w", timeout, err)
+			return time.Since(startTime), withContainerLogs(ctx, podmanClient, container.GetName(), rc.failureLogLines, err)
+		case <-time.After(period):
+		}
+	}
+}
+
+// ExecReadinessChecker runs the probe command inside the container; exit code 0 means ready
+type ExecReadinessChecker struct{}
+
+// NewExecReadinessChecker creates a new exec readiness checker
+func NewExecReadinessChecker() *ExecReadinessChecker {
+	return &ExecReadinessChecker{}
+}
+
+// SupportsType implements ReadinessChecker
+func (c *ExecReadinessChecker) SupportsType(probeType ProbeType) bool {
+	return probeType == ProbeTypeExec
+}
+
+// Check implements ReadinessChecker
+func (c *ExecReadinessChecker) Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error {
+	exitCode, err := client.ExecContainer(ctx, container.GetName(), probe.Command)
+	if err != nil {
+		return fmt.Errorf("unable to run probe command: %w", err)
+	}
+	if exitCode != 0 {
+		return fmt.Errorf("probe command exited with code %d", exitCode)
+	}
+	return nil
+}
+
+// TCPReadinessChecker considers the container ready once its port accepts connections
+type TCPReadinessChecker struct {
+	dialer net.Dialer
+}
+
+// NewTCPReadinessChecker creates a new tcp readiness checker
+func NewTCPReadinessChecker() *TCPReadinessChecker {
+	return &TCPReadinessChecker{dialer: net.Dialer{Timeout: time.Second}}
+}
+
+// SupportsType implements ReadinessChecker
+func (c *TCPReadinessChecker) SupportsType(probeType ProbeType) bool {
+	return probeType == ProbeTypeTCP
+}
+
+// Check implements ReadinessChecker
+func (c *TCPReadinessChecker) Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error {
+	if probe.TCPSocket == nil {
+		return fmt.Errorf("tcp probe requires tcpSocket")
+	}
+
+	address, err := probeAddress(ctx, client, container, probe, probe.TCPSocket.Port)
+	if err != nil {
+		return err
+	}
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return fmt.Errorf("unable to connect to %s: %w", address, err)
+	}
+	return conn.Close()
+}
+
+// HTTPReadinessChecker considers the container ready once a GET returns a 2xx or 3xx status
+type HTTPReadinessChecker struct {
+	client *http.Client
+}
+
+// NewHTTPReadinessChecker creates a new http readiness checker. Like Kubernetes probes,
+// HTTPS probes do not verify the certificate, which is typically self-signed.
+func NewHTTPReadinessChecker() *HTTPReadinessChecker {
+	return &HTTPReadinessChecker{
+		client: &http.Client{
+			Timeout: time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+			},
+			// Redirects count as success rather than being followed
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// SupportsType implements ReadinessChecker
+func (c *HTTPReadinessChecker) SupportsType(probeType ProbeType) bool {
+	return probeType == ProbeTypeHTTP
+}
+
+// Check implements ReadinessChecker
+func (c *HTTPReadinessChecker) Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error {
+	httpGet := probe.HTTPGet
+	if httpGet == nil {
+		return fmt.Errorf("http probe requires httpGet")
+	}
+
+	address, err := probeAddress(ctx, client, container, probe, httpGet.Port)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if strings.EqualFold(httpGet.Scheme, "https") {
+		scheme = "https"
+	}
+	url := fmt.Sprintf("%s://%s/%s", scheme, address, strings.TrimPrefix(httpGet.Path, "/"))
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid probe URL %s: %w", url, err)
+	}
+	for name, value := range httpGet.Headers {
+		request.Header.Set(name, value)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("unable to reach %s: %w", url, err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 400 {
+		return fmt.Errorf("GET %s returned status %d", url, response.StatusCode)
+	}
+	return nil
+}
+
+// probeAddress returns the host address a container port is actually published on,
+// which also covers host ports Podman picked itself
+func probeAddress(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe, port int32) (string, error) {
+	inspect, err := client.InspectContainer(ctx, container.GetName())
+	if err != nil {
+		return "", fmt.Errorf("unable to inspect container %s: %w", container.GetName(), err)
+	}
+
+	host := probe.Host
+	if host == "" {
+		host = defaultProbeHost
+	}
+
+	for _, published := range convertPortBindings(inspect) {
+		if int32(published.ContainerPort) != port || published.HostPort == 0 || published.Protocol != "TCP" {
+			continue
+		}
+		return net.JoinHostPort(host, strconv.Itoa(int(published.HostPort))), nil
+	}
+
+	return "", fmt.Errorf("container port %d is not published on a host port", port)
+}
@@ -0,0 +1,205 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// flakyReadinessChecker reports not ready for a number of checks before succeeding
+type flakyReadinessChecker struct {
+	failures int
+	checks   int
+}
+
+func (c *flakyReadinessChecker) SupportsType(probeType ProbeType) bool {
+	return probeType == "flaky"
+}
+
+func (c *flakyReadinessChecker) Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error {
+	c.checks++
+	if c.checks <= c.failures {
+		return errors.New("still starting")
+	}
+	return nil
+}
+
+func TestReconcile_WaitsForReadinessProbe(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	checker := &flakyReadinessChecker{failures: 1}
+	controller := NewReconciliationController(mockClient, WithReadinessChecker(checker))
+
+	container := newExplainTestContainer("postgres:16")
+	container.Spec.ReadinessProbe = &Probe{Type: "flaky", PeriodSeconds: 1}
+
+	result, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if checker.checks != 2 {
+		t.Errorf("Expected the probe to be checked until it passed, got %d checks", checker.checks)
+	}
+	if len(result.CreatedResources) != 1 {
+		t.Fatalf("Expected 1 created resource, got %d", len(result.CreatedResources))
+	}
+	action := result.CreatedResources[0]
+	if action.Error != "" || action.ReadinessWait < defaultProbePeriod {
+		t.Errorf("Expected the readiness wait to be recorded, got %+v", action)
+	}
+}
+
+func TestReconcile_ReportsContainerThatNeverBecomesReady(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetExecExitCode([]string{"pg_isready"}, 2)
+	controller := NewReconciliationController(mockClient)
+
+	container := newExplainTestContainer("postgres:16")
+	container.Spec.ReadinessProbe = &Probe{
+		Type:           ProbeTypeExec,
+		Command:        []string{"pg_isready"},
+		PeriodSeconds:  1,
+		TimeoutSeconds: 1,
+	}
+
+	result, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.CreatedResources) != 1 || !strings.Contains(result.CreatedResources[0].Error, "exited with code 2") {
+		t.Fatalf("Expected the create action to report the failed probe, got %+v", result.CreatedResources)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeReadiness {
+		t.Errorf("Expected a readiness error, got %+v", result.Errors)
+	}
+}
+
+// newProbedContainer creates a running mock container whose container port is published
+// on the given host port
+func newProbedContainer(t *testing.T, mockClient *podman.MockPodmanClient, containerPort, hostPort uint16) *ContainerResource {
+	t.Helper()
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Ports = []ContainerPort{{ContainerPort: containerPort, HostPort: hostPort}}
+	if err := NewContainerManager(mockClient).CreateResource(context.Background(), container); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	return container
+}
+
+// listenerPort returns the local port a test listener is bound to
+func listenerPort(t *testing.T, address string) uint16 {
+	t.Helper()
+
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		t.Fatalf("Invalid listener address %s: %v", address, err)
+	}
+	parsed, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		t.Fatalf("Invalid listener port %s: %v", port, err)
+	}
+	return uint16(parsed)
+}
+
+func TestProbe_ExecAction(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	container := newProbedContainer(t, mockClient, 80, 8080)
+	ctx := context.Background()
+
+	probe := &Probe{Type: ProbeTypeExec, Command: []string{"test", "-f", "/ready"}}
+	if err := probe.Check(ctx, container, mockClient); err != nil {
+		t.Errorf("Expected the command to pass, got %v", err)
+	}
+
+	mockClient.SetExecExitCode([]string{"test", "-f", "/ready"}, 1)
+	if err := probe.Check(ctx, container, mockClient); err == nil || !strings.Contains(err.Error(), "exited with code 1") {
+		t.Errorf("Expected the exit code in the error, got %v", err)
+	}
+}
+
+func TestProbe_TCPSocketAction(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+
+	mockClient := podman.NewMockPodmanClient()
+	container := newProbedContainer(t, mockClient, 5432, listenerPort(t, listener.Addr().String()))
+	ctx := context.Background()
+
+	probe := &Probe{Type: ProbeTypeTCP, TCPSocket: &TCPSocketAction{Port: 5432}, Host: "127.0.0.1"}
+	if err := probe.Check(ctx, container, mockClient); err != nil {
+		t.Errorf("Expected the listening port to pass, got %v", err)
+	}
+
+	unpublished := &Probe{Type: ProbeTypeTCP, TCPSocket: &TCPSocketAction{Port: 8080}, Host: "127.0.0.1"}
+	if err := unpublished.Check(ctx, container, mockClient); err == nil || !strings.Contains(err.Error(), "not published") {
+		t.Errorf("Expected an unpublished port to be reported, got %v", err)
+	}
+
+	listener.Close()
+	if err := probe.Check(ctx, container, mockClient); err == nil || !strings.Contains(err.Error(), "unable to connect") {
+		t.Errorf("Expected the dial error once the port is closed, got %v", err)
+	}
+}
+
+func TestProbe_HTTPGetAction(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/healthz":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Header.Get("X-Probe") != "cutepod":
+			w.WriteHeader(http.StatusBadRequest)
+		default:
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	mockClient := podman.NewMockPodmanClient()
+	container := newProbedContainer(t, mockClient, 80, listenerPort(t, server.Listener.Addr().String()))
+	ctx := context.Background()
+
+	probe := &Probe{
+		Type:    ProbeTypeHTTP,
+		HTTPGet: &HTTPGetAction{Path: "/healthz", Port: 80, Headers: map[string]string{"X-Probe": "cutepod"}},
+		Host:    "127.0.0.1",
+	}
+	if err := probe.Check(ctx, container, mockClient); err != nil {
+		t.Errorf("Expected the probe to pass, got %v", err)
+	}
+
+	probe.HTTPGet.Path = "/missing"
+	if err := probe.Check(ctx, container, mockClient); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("Expected the status code in the error, got %v", err)
+	}
+}
+
+func TestProbe_HTTPGetActionOverHTTPS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockClient := podman.NewMockPodmanClient()
+	container := newProbedContainer(t, mockClient, 443, listenerPort(t, server.Listener.Addr().String()))
+
+	// The test server's certificate is self-signed, which probes accept
+	probe := &Probe{
+		Type:    ProbeTypeHTTP,
+		HTTPGet: &HTTPGetAction{Port: 443, Scheme: "HTTPS"},
+		Host:    "127.0.0.1",
+	}
+	if err := probe.Check(context.Background(), container, mockClient); err != nil {
+		t.Errorf("Expected the HTTPS probe to pass, got %v", err)
+	}
+}
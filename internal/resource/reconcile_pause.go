@@ -0,0 +1,81 @@
+package resource
+
+import (
+	"cutepod/internal/labels"
+	"fmt"
+	"time"
+)
+
+// reconcilePaused is the value of the reconcile annotation that pauses a resource
+const reconcilePaused = "paused"
+
+// isReconcilePaused reports whether the resource's manifest pauses its reconciliation
+func isReconcilePaused(resource Resource) bool {
+	annotated, ok := resource.(interface{ GetAnnotations() map[string]string })
+	return ok && annotated.GetAnnotations()[labels.AnnotationReconcile] == reconcilePaused
+}
+
+// chartPaused reports whether every resource of the chart is paused, in which case its
+// orphans are left alone too
+func chartPaused(manifests []Resource) bool {
+	for _, manifest := range manifests {
+		if !isReconcilePaused(manifest) {
+			return false
+		}
+	}
+	return len(manifests) > 0
+}
+
+// skipPausedResources drops the changes to paused resources from the diff, recording
+// each as skipped, and lists every paused resource in the result whether or not it has
+// drifted. When the whole chart is paused, deleting its orphans is skipped as well.
+func (rc *DefaultReconciliationController) skipPausedResources(result *ReconciliationResult, diff *StateDiff, manifests []Resource) {
+	for _, manifest := range manifests {
+		if isReconcilePaused(manifest) {
+			result.Paused = append(result.Paused, ResourceReference{Type: manifest.GetType(), Name: manifest.GetName()})
+		}
+	}
+	if len(result.Paused) == 0 {
+		return
+	}
+
+	skip := func(resource Resource, diffs []FieldDiff) ResourceAction {
+		action := ResourceAction{
+			Type:      resource.GetType(),
+			Name:      resource.GetName(),
+			Action:    ActionSkip,
+			Message:   fmt.Sprintf("paused by the %s annotation", labels.AnnotationReconcile),
+			Diffs:     diffs,
+			Timestamp: time.Now(),
+		}
+		rc.logAction(&action)
+		return action
+	}
+
+	toCreate := make([]Resource, 0, len(diff.ToCreate))
+	for _, resource := range diff.ToCreate {
+		if isReconcilePaused(resource) {
+			result.CreatedResources = append(result.CreatedResources, skip(resource, nil))
+			continue
+		}
+		toCreate = append(toCreate, resource)
+	}
+	diff.ToCreate = toCreate
+
+	toUpdate := make([]ResourcePair, 0, len(diff.ToUpdate))
+	for _, pair := range diff.ToUpdate {
+		if isReconcilePaused(pair.Desired) {
+			result.UpdatedResources = append(result.UpdatedResources, skip(pair.Desired, pair.Diffs))
+			continue
+		}
+		toUpdate = append(toUpdate, pair)
+	}
+	diff.ToUpdate = toUpdate
+
+	if chartPaused(manifests) {
+		for _, resource := range diff.ToDelete {
+			result.DeletedResources = append(result.DeletedResources, skip(resource, nil))
+		}
+		diff.ToDelete = nil
+	}
+}
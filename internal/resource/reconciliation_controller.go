@@ -2,8 +2,16 @@ package resource
 
 import (
 	"context"
+	"cutepod/internal/labels"
 	"cutepod/internal/podman"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -13,8 +21,45 @@ type ReconciliationController interface {
 	// Reconcile performs the full reconciliation workflow: parse → resolve → compare → execute
 	Reconcile(ctx context.Context, manifests []Resource, chartName string, dryRun bool) (*ReconciliationResult, error)
 
-	// GetStatus returns the current reconciliation status for a chartName
+	// ReconcileMulti reconciles several charts whose resources may reference each other,
+	// ordering them as one dependency graph; results are keyed by chart name
+	ReconcileMulti(ctx context.Context, manifestsByChart map[string][]Resource, dryRun bool) (map[string]*ReconciliationResult, error)
+
+	// Destroy deletes every resource cutepod manages for chartName in reverse dependency order
+	Destroy(ctx context.Context, chartName string) (*ReconciliationResult, error)
+
+	// Plan computes the create, update and delete sets with their diffs without retries or mutations
+	Plan(ctx context.Context, manifests []Resource, chartName string) (*ReconciliationResult, error)
+
+	// GetStatus returns the last recorded reconciliation status for a chartName without contacting Podman
 	GetStatus(chartName string) (*ReconciliationStatus, error)
+
+	// RefreshStatus recounts the chartName's actual resources in Podman and records them in its status
+	RefreshStatus(ctx context.Context, chartName string) (*ReconciliationStatus, error)
+
+	// Explain reports why reconciliation would act on a single resource, down to the differing fields
+	Explain(ctx context.Context, manifests []Resource, chartName string, resourceRef ResourceReference) (*ExplainResult, error)
+
+	// Watch re-reconciles on every interval when the actual state drifts, until ctx is cancelled
+	Watch(ctx context.Context, manifests []Resource, chartName string, interval time.Duration) error
+
+	// GetResourceStats samples resource usage of the chart's running containers
+	GetResourceStats(ctx context.Context, chartName string) (*ResourceStats, error)
+
+	// GetChartLogs returns a log stream per container of the chart, keyed by container name
+	GetChartLogs(ctx context.Context, chartName string, opts LogOptions) (map[string]io.ReadCloser, error)
+
+	// ExportState reads the chart's actual resources back as manifests
+	ExportState(ctx context.Context, chartName string) ([]Resource, error)
+
+	// GetVolumeUsage measures the size of each volume declared in the manifests
+	GetVolumeUsage(ctx context.Context, manifests []Resource, chartName string) (*ChartVolumeUsage, error)
+
+	// BackupVolume archives the contents of a volume declared in the manifests to destPath
+	BackupVolume(ctx context.Context, manifests []Resource, chartName, volumeName, destPath string) error
+
+	// RestoreVolume replaces the contents of a volume declared in the manifests with an archive
+	RestoreVolume(ctx context.Context, manifests []Resource, chartName, volumeName, srcPath string) error
 }
 
 // ReconciliationResult contains the results of a reconciliation operation
@@ -22,10 +67,38 @@ type ReconciliationResult struct {
 	CreatedResources []ResourceAction       `json:"created_resources"`
 	UpdatedResources []ResourceAction       `json:"updated_resources"`
 	DeletedResources []ResourceAction       `json:"deleted_resources"`
+	PrunedImages     []string               `json:"pruned_images,omitempty"`
+	CreationLevels   [][]ResourceReference  `json:"creation_levels,omitempty"`
+	DeletionLevels   [][]ResourceReference  `json:"deletion_levels,omitempty"`
 	Errors           []*ReconciliationError `json:"errors"`
 	Summary          string                 `json:"summary"`
 	Duration         time.Duration          `json:"duration"`
 	ChartName        string                 `json:"chart_name"`
+
+	// PerTypeDurations sums how long the actions on each resource type took
+	PerTypeDurations map[ResourceType]time.Duration `json:"per_type_durations,omitempty"`
+
+	// Paused lists the resources left alone because of their reconcile annotation
+	Paused []ResourceReference `json:"paused,omitempty"`
+
+	// Hooks records the outcome of each post-reconcile hook
+	Hooks []HookResult `json:"hooks,omitempty"`
+}
+
+// ToJSON serializes the result for machine consumption, such as recording timings in CI
+func (r *ReconciliationResult) ToJSON() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// durationsByType sums the durations of the created, updated and deleted resources by type
+func (r *ReconciliationResult) durationsByType() map[ResourceType]time.Duration {
+	durations := make(map[ResourceType]time.Duration)
+	for _, actions := range [][]ResourceAction{r.CreatedResources, r.UpdatedResources, r.DeletedResources} {
+		for _, action := range actions {
+			durations[action.Type] += action.Duration
+		}
+	}
+	return durations
 }
 
 // ReconciliationStatus represents the current status of reconciliation for a chart name
@@ -44,8 +117,12 @@ type ResourceAction struct {
 	Action    ActionType    `json:"action"`
 	Message   string        `json:"message,omitempty"`
 	Error     string        `json:"error,omitempty"`
+	Diffs     []FieldDiff   `json:"diffs,omitempty"`
 	Duration  time.Duration `json:"duration"`
 	Timestamp time.Time     `json:"timestamp"`
+
+	// ReadinessWait is how long a created container took to pass its readiness probe
+	ReadinessWait time.Duration `json:"readinessWait,omitempty"`
 }
 
 // ActionType represents the type of action taken on a resource
@@ -61,64 +138,356 @@ const (
 // ErrorTypeComparison represents comparison-related errors
 const ErrorTypeComparison ErrorType = "comparison"
 
+// ErrorTypeOwnership represents a refusal to touch a resource owned by something else
+const ErrorTypeOwnership ErrorType = "ownership"
+
+// ErrorTypeReadiness represents a created container that did not become ready in time
+const ErrorTypeReadiness ErrorType = "readiness"
+
+// ErrorTypeTimeout represents a resource operation that exceeded the resource timeout
+const ErrorTypeTimeout ErrorType = "timeout"
+
+// errResourceTimeout is returned when a resource operation exceeds the resource timeout
+var errResourceTimeout = errors.New("resource operation timed out")
+
 // DefaultReconciliationController implements ReconciliationController
 type DefaultReconciliationController struct {
-	managers           map[ResourceType]ResourceManager
-	stateComparator    StateComparator
-	dependencyResolver DependencyResolver
-	podmanClient       podman.PodmanClient
-	mu                 sync.RWMutex // Protects concurrent access to status
-	lastStatus         map[string]*ReconciliationStatus
+	managers                   map[ResourceType]ResourceManager
+	stateComparator            StateComparator
+	dependencyResolver         DependencyResolver
+	podmanClient               podman.PodmanClient
+	mu                         sync.RWMutex // Protects concurrent access to watch cycles
+	stateStore                 StateStore
+	watchCycles                map[string]bool
+	eventHook                  EventHook
+	tracer                     Tracer
+	metrics                    MetricsCollector
+	readinessCheckers          *ReadinessCheckerRegistry
+	admissionCheckers          []AdmissionChecker
+	pauseOnDelete              bool
+	pruneImages                bool
+	recreateOnAnnotationChange bool
+	forceDelete                bool
+	offlineMode                bool
+	volumeHelperImage          string
+	pullConcurrency            int
+	prePullImages              bool
+	bestEffort                 bool
+	failureLogLines            int
+	resourceTimeout            time.Duration
+	hostPathPrefixes           []string
+	labelPrefix                string
+	labelPrefixErr             error
+	maxUnavailable             int
+	volumeBaseDir              string
+	volumeBaseDirErr           error
+	logger                     Logger
+	postReconcileHooks         []Hook
+}
+
+// resourcePauser is implemented by managers that can pause a resource instead of deleting it
+type resourcePauser interface {
+	PauseResource(ctx context.Context, resource Resource) error
+}
+
+// ControllerOption configures optional behaviour of a DefaultReconciliationController
+type ControllerOption func(*DefaultReconciliationController)
+
+// WithTracer sets the tracer used to create spans around reconciliation phases and
+// per-resource operations. Controllers default to a no-op tracer.
+func WithTracer(tracer Tracer) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if tracer != nil {
+			rc.tracer = tracer
+		}
+	}
+}
+
+// WithPauseOnDelete makes reconciliation pause containers slated for removal instead of
+// deleting them, so they can still be inspected. Re-adding such a container with an
+// unchanged spec resumes it rather than recreating it. Containers left paused this way
+// are not reported again, and the networks and volumes they use are kept.
+func WithPauseOnDelete() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.pauseOnDelete = true
+	}
+}
+
+// WithPruneImagesAfterReconcile removes dangling images once a reconcile has applied
+// every change without errors. Images used by containers are never removed.
+func WithPruneImagesAfterReconcile() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.pruneImages = true
+	}
+}
+
+// WithRecreateOnAnnotationChange makes containers whose annotations drifted from their
+// manifest be recreated. Without it, annotation drift only shows up in diffs, because
+// Podman cannot change the annotations of an existing container.
+func WithRecreateOnAnnotationChange() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.recreateOnAnnotationChange = true
+	}
+}
+
+// WithForceDelete deletes volumes and secrets even when they carry the delete-protect
+// annotation. Without it, protected resources are skipped instead of deleted.
+func WithForceDelete() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.forceDelete = true
+	}
+}
+
+// WithOfflineMode makes reconciliation never pull images, for air-gapped hosts whose
+// images are side-loaded. Creating a container whose image is not present locally
+// fails instead.
+func WithOfflineMode() ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.offlineMode = true
+	}
+}
+
+// WithVolumeHelperImage sets the image of the helper containers that back up and
+// restore named volumes, instead of docker.io/library/busybox:latest. The image needs a
+// shell with tail, rm and mv, and is pulled when it is missing.
+func WithVolumeHelperImage(image string) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.volumeHelperImage = image
+	}
+}
+
+// WithPullConcurrency limits how many images are pulled at once while creating
+// containers, so that large pulls do not saturate the network. Images that are already
+// present never wait. The default is 2.
+func WithPullConcurrency(concurrency int) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.pullConcurrency = concurrency
+	}
+}
+
+// WithStateStore sets where the status of each chart's last reconcile is kept, such as a
+// FileStateStore that survives restarts. Controllers default to a MemoryStateStore.
+func WithStateStore(store StateStore) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if store != nil {
+			rc.stateStore = store
+		}
+	}
+}
+
+// WithResourceTimeout bounds every create, update and delete of a single resource, so
+// a hung Podman call cannot stall the rest of the reconcile. A resource that times out
+// is reported as a recoverable error and is not retried. Zero means no limit, which is
+// the default.
+func WithResourceTimeout(timeout time.Duration) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.resourceTimeout = timeout
+	}
+}
+
+// WithHostPathPrefixes restricts hostPath volumes to paths under the given prefixes,
+// such as "/srv" and "/data". Manifests with a hostPath outside of them fail validation.
+// By default any absolute path is allowed.
+func WithHostPathPrefixes(prefixes ...string) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.hostPathPrefixes = prefixes
+	}
+}
+
+// WithVolumeBaseDir sets the directory emptyDir volumes are created in, as
+// <dir>/emptydir/<volume>, instead of /tmp/cutepod-volumes. The directory must be an
+// absolute path the controller can write to; otherwise every reconcile fails validation.
+func WithVolumeBaseDir(dir string) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.volumeBaseDir = dir
+	}
+}
+
+// hostPathPrefixSetter is implemented by managers that resolve hostPath volumes
+type hostPathPrefixSetter interface {
+	SetHostPathPrefixes(prefixes []string)
+}
+
+// WithIgnoredLabelKeys makes comparisons ignore label and annotation keys matching the
+// given patterns, such as "ci.example.com/*", on top of cutepod's own bookkeeping keys
+func WithIgnoredLabelKeys(keys ...string) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if comparator, ok := rc.stateComparator.(*DefaultStateComparator); ok {
+			comparator.SetIgnoredLabelKeys(keys)
+		}
+	}
 }
 
 // NewReconciliationController creates a new reconciliation controller
-func NewReconciliationController(podmanClient podman.PodmanClient) ReconciliationController {
-	return NewReconciliationControllerWithRegistry(podmanClient, nil)
+func NewReconciliationController(podmanClient podman.PodmanClient, opts ...ControllerOption) ReconciliationController {
+	return NewReconciliationControllerWithRegistry(podmanClient, nil, opts...)
 }
 
 // NewReconciliationControllerWithRegistry creates a new reconciliation controller with a registry
-func NewReconciliationControllerWithRegistry(podmanClient podman.PodmanClient, registry *ManifestRegistry) ReconciliationController {
+func NewReconciliationControllerWithRegistry(podmanClient podman.PodmanClient, registry *ManifestRegistry, opts ...ControllerOption) ReconciliationController {
 	controller := &DefaultReconciliationController{
 		managers:           make(map[ResourceType]ResourceManager),
 		stateComparator:    NewStateComparator(),
 		dependencyResolver: NewDependencyResolver(),
 		podmanClient:       podmanClient,
-		lastStatus:         make(map[string]*ReconciliationStatus),
+		stateStore:         NewMemoryStateStore(),
+		watchCycles:        make(map[string]bool),
+		tracer:             NewNoopTracer(),
+		metrics:            NewNoopMetricsCollector(),
+		readinessCheckers:  NewReadinessCheckerRegistry(),
+		failureLogLines:    defaultFailureLogLines,
+		logger:             defaultLogger,
 	}
 
+	for _, opt := range opts {
+		opt(controller)
+	}
+
+	if controller.volumeBaseDir != "" {
+		controller.volumeBaseDirErr = validateTempDirBase(controller.volumeBaseDir)
+	}
+	if controller.labelPrefix != "" {
+		controller.labelPrefixErr = validateLabelPrefix(controller.labelPrefix)
+	}
+
+	// The container and volume managers share one path manager so that they agree on
+	// where emptyDir volumes live
+	pathManager := NewVolumePathManager(controller.volumeBaseDir)
+
 	// Register resource managers
+	var containerManager *ContainerManager
 	if registry != nil {
-		controller.managers[ResourceTypeContainer] = NewContainerManagerWithRegistry(podmanClient, registry)
+		containerManager = NewContainerManagerWithRegistry(podmanClient, registry)
 	} else {
-		controller.managers[ResourceTypeContainer] = NewContainerManager(podmanClient)
+		containerManager = NewContainerManager(podmanClient)
+	}
+	containerManager.pathManager = pathManager
+	containerManager.recreateOnAnnotationChange = controller.recreateOnAnnotationChange
+	if comparator, ok := controller.stateComparator.(*DefaultStateComparator); ok {
+		containerManager.ignoredLabelKeys = comparator.IgnoredLabelKeys
 	}
+	containerManager.offlineMode = controller.offlineMode
+	containerManager.failureLogLines = controller.failureLogLines
+	if controller.pullConcurrency > 0 {
+		containerManager.SetPullConcurrency(controller.pullConcurrency)
+	}
+	if controller.eventHook != nil {
+		containerManager.pullProgress = func(chartName string, progress podman.PullProgress) {
+			controller.emitEvent(WatchEvent{ChartName: chartName, PullProgress: &progress, Timestamp: time.Now()})
+		}
+	}
+	controller.managers[ResourceTypeContainer] = containerManager
 	controller.managers[ResourceTypeNetwork] = NewNetworkManager(podmanClient)
-	controller.managers[ResourceTypeVolume] = NewVolumeManager(podmanClient)
+	volumeManager := NewVolumeManagerWithPathManager(podmanClient, pathManager)
+	volumeManager.helperImage = controller.volumeHelperImage
+	controller.managers[ResourceTypeVolume] = volumeManager
 	controller.managers[ResourceTypeSecret] = NewSecretManager(podmanClient)
+	controller.managers[ResourceTypePod] = NewPodManagerWithContainerManager(podmanClient, containerManager)
 
 	// Set up state comparator with resource managers
-	stateComparator := controller.stateComparator.(*DefaultStateComparator)
 	for resourceType, manager := range controller.managers {
-		stateComparator.SetResourceManager(resourceType, manager)
+		controller.wireManager(resourceType, manager)
 	}
 
 	return controller
 }
 
+// RegisterManager adds a manager for a resource type of the caller's own, or replaces
+// the manager of a built-in type. Resources of the type then pass validation and are
+// compared, created, updated and deleted like the built-in ones, in the order their
+// dependencies require. The manager gets the controller's logger, hostPath prefixes and
+// label prefix if it has setters for them. Managers must be registered before the
+// controller is used.
+func (rc *DefaultReconciliationController) RegisterManager(resourceType ResourceType, manager ResourceManager) {
+	rc.managers[resourceType] = manager
+	rc.wireManager(resourceType, manager)
+}
+
+// wireManager hands a manager to the state comparator and configures it with the
+// controller's options
+func (rc *DefaultReconciliationController) wireManager(resourceType ResourceType, manager ResourceManager) {
+	rc.stateComparator.SetResourceManager(resourceType, manager)
+	if setter, ok := manager.(loggerSetter); ok {
+		setter.SetLogger(rc.logger)
+	}
+	if setter, ok := manager.(hostPathPrefixSetter); ok && len(rc.hostPathPrefixes) > 0 {
+		setter.SetHostPathPrefixes(rc.hostPathPrefixes)
+	}
+	if setter, ok := manager.(labelPrefixSetter); ok && rc.labelPrefix != "" {
+		setter.SetLabelPrefix(rc.labelPrefix)
+	}
+}
+
 // NewReconciliationControllerWithURI creates a new reconciliation controller with a Podman URI
-func NewReconciliationControllerWithURI(podmanURI string) ReconciliationController {
-	adapter := podman.NewPodmanAdapter()
-	return NewReconciliationController(adapter)
+func NewReconciliationControllerWithURI(podmanURI string, opts ...ControllerOption) ReconciliationController {
+	adapter := podman.NewPodmanAdapterWithURI(podmanURI, GetPodmanIdentity())
+	return NewReconciliationController(adapter, opts...)
 }
 
 // NewReconciliationControllerWithURIAndRegistry creates a new reconciliation controller with a Podman URI and registry
-func NewReconciliationControllerWithURIAndRegistry(podmanURI string, registry *ManifestRegistry) ReconciliationController {
-	adapter := podman.NewPodmanAdapter()
-	return NewReconciliationControllerWithRegistry(adapter, registry)
+func NewReconciliationControllerWithURIAndRegistry(podmanURI string, registry *ManifestRegistry, opts ...ControllerOption) ReconciliationController {
+	adapter := podman.NewPodmanAdapterWithURI(podmanURI, GetPodmanIdentity())
+	return NewReconciliationControllerWithRegistry(adapter, registry, opts...)
 }
 
 // Reconcile performs the complete reconciliation workflow: parse → resolve → compare → execute
 func (rc *DefaultReconciliationController) Reconcile(ctx context.Context, manifests []Resource, chartName string, dryRun bool) (*ReconciliationResult, error) {
+	ctx, span := rc.tracer.Start(ctx, "reconcile",
+		Attribute(SpanAttributeChart, chartName),
+		Attribute(SpanAttributeDryRun, strconv.FormatBool(dryRun)))
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+	ctx = withImageCache(ctx)
+
+	rc.logger.Info("reconciliation started", "chart", chartName, "dryRun", dryRun, "resources", len(manifests))
+
+	rc.clearComparisonCache()
+	result, err := rc.reconcile(ctx, manifests, chartName, dryRun)
+	endSpan(span, err)
+
+	if err != nil {
+		rc.logger.Error("reconciliation failed", "chart", chartName, "error", err)
+	} else {
+		rc.logger.Info("reconciliation finished", "chart", chartName,
+			"created", len(result.CreatedResources),
+			"updated", len(result.UpdatedResources),
+			"deleted", len(result.DeletedResources),
+			"errors", len(result.Errors),
+			"duration", result.Duration)
+	}
+
+	return result, err
+}
+
+// clearComparisonCache forgets the comparator's unchanged verdicts. The manifests given
+// to Reconcile and Plan may have been changed in place since the last call; only Watch
+// compares the same manifests cycle after cycle.
+func (rc *DefaultReconciliationController) clearComparisonCache() {
+	if comparator, ok := rc.stateComparator.(*DefaultStateComparator); ok {
+		comparator.ClearComparisonCache()
+	}
+}
+
+// withSharedConnection opens one Podman connection that every manager call under the
+// returned context reuses, instead of connecting and disconnecting per operation
+func (rc *DefaultReconciliationController) withSharedConnection(ctx context.Context) (context.Context, func()) {
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	return podman.WithSharedConnection(ctx, connectedClient), func() {
+		connectedClient.Close()
+	}
+}
+
+// podmanURI returns the URI of the controller's Podman client, if it exposes one
+func (rc *DefaultReconciliationController) podmanURI() string {
+	if client, ok := rc.podmanClient.(interface{ URI() string }); ok {
+		return client.URI()
+	}
+	return "the configured URI"
+}
+
+// reconcile runs each reconciliation phase in its own span
+func (rc *DefaultReconciliationController) reconcile(ctx context.Context, manifests []Resource, chartName string, dryRun bool) (*ReconciliationResult, error) {
 	startTime := time.Now()
 
 	result := &ReconciliationResult{
@@ -129,133 +498,158 @@ func (rc *DefaultReconciliationController) Reconcile(ctx context.Context, manife
 		ChartName:        chartName,
 	}
 
-	// Validate input parameters
-	if len(manifests) == 0 {
-		result.Duration = time.Since(startTime)
-		result.Summary = "No resources to reconcile"
-		return result, nil
-	}
-
 	// Step 1: Parse and validate manifests
-	if err := rc.validateManifests(manifests); err != nil {
-		return result, rc.addError(result, ErrorTypeValidation, ResourceReference{},
-			fmt.Sprintf("manifest validation failed: %v", err), err, false)
+	_, span := rc.tracer.Start(ctx, "reconcile.validate")
+	err := rc.validateManifests(manifests)
+	endSpan(span, err)
+	if err != nil {
+		return result, rc.addValidationError(result, err)
 	}
+	assignPodMembers(manifests)
+	rc.applyLabelPrefix(manifests)
+
+	// A label selector narrows the manifests the same way it narrows the actual state
+	manifests = selectManifests(ctx, manifests)
 
 	// Step 2: Build dependency graph with error recovery
-	dependencyGraph, err := rc.buildDependencyGraphWithRetry(ctx, manifests, result)
+	graphCtx, span := rc.tracer.Start(ctx, "reconcile.build_graph")
+	dependencyGraph, err := rc.buildDependencyGraphWithRetry(graphCtx, manifests, result)
 	if err != nil {
+		endSpan(span, err)
 		return result, err
 	}
 
 	// Step 3: Get creation and deletion order
 	creationOrder, err := rc.dependencyResolver.GetCreationOrder(dependencyGraph)
 	if err != nil {
+		endSpan(span, err)
 		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
 			fmt.Sprintf("failed to determine creation order: %v", err), err, false)
 	}
 
 	deletionOrder, err := rc.dependencyResolver.GetDeletionOrder(dependencyGraph)
+	endSpan(span, err)
 	if err != nil {
 		return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
 			fmt.Sprintf("failed to determine deletion order: %v", err), err, false)
 	}
+	result.CreationLevels = resourceLevels(creationOrder)
+	result.DeletionLevels = resourceLevels(deletionOrder)
 
 	// Step 4: Get current state with error recovery
-	actualStateByType, err := rc.getCurrentStateWithRetry(ctx, chartName, result)
+	stateCtx, span := rc.tracer.Start(ctx, "reconcile.get_state")
+	actualStateByType, err := rc.getCurrentStateWithRetry(stateCtx, chartName, result)
+	endSpan(span, err)
 	if err != nil {
 		return result, err
 	}
 
 	// Step 5: Compare states and determine actions
+	_, span = rc.tracer.Start(ctx, "reconcile.compare")
 	stateDiff, err := rc.compareAllStatesWithValidation(manifests, actualStateByType, result)
+	endSpan(span, err)
 	if err != nil {
 		return result, err
 	}
+	rc.warnUnrecordedDeleteProtection(manifests, actualStateByType)
 
 	// Step 6: Execute changes with comprehensive error handling
+	executeCtx, span := rc.tracer.Start(ctx, "reconcile.execute")
 	if dryRun {
 		rc.populateDryRunResult(result, stateDiff)
+		rc.dryRunValidate(executeCtx, result, stateDiff, plannedCreations(stateDiff))
 	} else {
-		rc.executeReconciliationWithRecovery(ctx, result, stateDiff, creationOrder, deletionOrder)
+		// Orphans are not in the manifests, so they are ordered by their own references
+		orphanOrder, err := rc.liveDeletionOrder(stateDiff.ToDelete)
+		if err != nil {
+			endSpan(span, err)
+			return result, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+				fmt.Sprintf("failed to determine deletion order of orphaned resources: %v", err), err, false)
+		}
+		if rc.prePullImages {
+			pullCtx, pullSpan := rc.tracer.Start(executeCtx, "reconcile.pre_pull")
+			err := rc.prePullContainerImages(pullCtx, result, stateDiff)
+			endSpan(pullSpan, err)
+			if err != nil {
+				endSpan(span, err)
+				return result, err
+			}
+		}
+		rc.executeReconciliationWithRecovery(executeCtx, result, stateDiff, creationOrder, orphanOrder)
+	}
+	span.End()
+
+	// Step 7: Prune dangling images, but only after a fully successful reconcile
+	if !dryRun && rc.pruneImages && len(result.Errors) == 0 {
+		pruneCtx, span := rc.tracer.Start(ctx, "reconcile.prune_images")
+		err := rc.pruneDanglingImages(pruneCtx, result)
+		endSpan(span, err)
 	}
 
-	// Step 7: Clean up orphaned resources with error handling
-	if !dryRun {
-		rc.cleanupOrphanedResourcesWithRecovery(ctx, result, manifests, actualStateByType, deletionOrder)
+	// Step 8: Run the post-reconcile hooks once the chart is applied
+	if !dryRun && len(rc.postReconcileHooks) > 0 {
+		hooksCtx, span := rc.tracer.Start(ctx, "reconcile.post_hooks")
+		rc.runPostReconcileHooks(hooksCtx, result)
+		span.End()
 	}
 
-	// Step 8: Update status and generate summary
+	// Step 9: Update status and generate summary
 	rc.updateReconciliationStatus(chartName, result, startTime)
 	result.Duration = time.Since(startTime)
+	result.PerTypeDurations = result.durationsByType()
 	result.Summary = rc.generateSummary(result)
 
 	return result, nil
 }
 
-// GetStatus returns the current reconciliation status for a chart name
+// GetStatus returns the status recorded by the chart's last reconcile or refresh without
+// contacting Podman, so it is cheap and keeps working while Podman is unreachable. The
+// counts are as stale as that reconcile or refresh; call RefreshStatus for live ones.
+// A chart that was never reconciled has status "unknown".
 func (rc *DefaultReconciliationController) GetStatus(chartName string) (*ReconciliationStatus, error) {
-	rc.mu.RLock()
-	cachedStatus, exists := rc.lastStatus[chartName]
-	rc.mu.RUnlock()
-
-	// If we have cached status, return it with current resource counts
-	if exists {
-		// Update with current resource counts
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+	cachedStatus, err := rc.stateStore.Load(chartName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load status: %w", err)
+	}
 
-		currentStatus := &ReconciliationStatus{
+	if cachedStatus == nil {
+		return &ReconciliationStatus{
 			ChartName:      chartName,
-			LastReconciled: cachedStatus.LastReconciled,
 			ResourceCounts: make(map[string]int),
-			Status:         cachedStatus.Status,
-			Errors:         cachedStatus.Errors,
-		}
-
-		// Get current resource counts for each type
-		for resourceType, manager := range rc.managers {
-			resources, err := manager.GetActualState(ctx, chartName)
-			if err != nil {
-				currentStatus.Errors = append(currentStatus.Errors, NewPodmanAPIError(
-					ResourceReference{Type: resourceType},
-					fmt.Sprintf("failed to get current status for %s: %v", resourceType, err),
-					err,
-					true,
-				))
-				continue
-			}
-			currentStatus.ResourceCounts[string(resourceType)] = len(resources)
-		}
-
-		// Update overall status based on current errors
-		if len(currentStatus.Errors) == 0 {
-			currentStatus.Status = "healthy"
-		} else {
-			currentStatus.Status = "degraded"
-		}
-
-		return currentStatus, nil
+			Status:         "unknown",
+			LastReconciled: time.Time{}, // Zero time indicates never reconciled
+		}, nil
 	}
 
-	// No cached status, create a fresh one
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
+	status := *cachedStatus
+	status.ResourceCounts = maps.Clone(cachedStatus.ResourceCounts)
+	if status.ResourceCounts == nil {
+		status.ResourceCounts = make(map[string]int)
+	}
+	status.Errors = slices.Clone(cachedStatus.Errors)
+	return &status, nil
+}
 
-	status := &ReconciliationStatus{
-		ChartName:      chartName,
-		ResourceCounts: make(map[string]int),
-		Status:         "unknown",
-		LastReconciled: time.Time{}, // Zero time indicates never reconciled
+// RefreshStatus counts the chart's actual resources of each type in Podman and returns
+// the status with those counts. A complete refresh is saved, so that later GetStatus
+// calls return its counts; one that failed to list some type is returned as degraded
+// but not saved.
+func (rc *DefaultReconciliationController) RefreshStatus(ctx context.Context, chartName string) (*ReconciliationStatus, error) {
+	status, err := rc.GetStatus(chartName)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get current resource counts for each type
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	var listErrors []*ReconciliationError
 	for resourceType, manager := range rc.managers {
 		resources, err := manager.GetActualState(ctx, chartName)
 		if err != nil {
-			status.Errors = append(status.Errors, NewPodmanAPIError(
+			listErrors = append(listErrors, NewPodmanAPIError(
 				ResourceReference{Type: resourceType},
-				fmt.Sprintf("failed to get status for %s: %v", resourceType, err),
+				fmt.Sprintf("failed to get current status for %s: %v", resourceType, err),
 				err,
 				true,
 			))
@@ -264,11 +658,17 @@ func (rc *DefaultReconciliationController) GetStatus(chartName string) (*Reconci
 		status.ResourceCounts[string(resourceType)] = len(resources)
 	}
 
-	// Determine overall status
-	if len(status.Errors) == 0 {
-		status.Status = "healthy"
-	} else {
+	if len(listErrors) > 0 {
+		status.Errors = append(status.Errors, listErrors...)
 		status.Status = "degraded"
+		return status, nil
+	}
+
+	if status.Status == "unknown" {
+		status.Status = "healthy"
+	}
+	if err := rc.stateStore.Save(chartName, status); err != nil {
+		return nil, fmt.Errorf("failed to save status: %w", err)
 	}
 
 	return status, nil
@@ -285,6 +685,7 @@ func (rc *DefaultReconciliationController) populateDryRunResult(result *Reconcil
 			Name:      resource.GetName(),
 			Action:    ActionCreate,
 			Message:   "would be created",
+			Diffs:     DescribeResource(resource),
 			Timestamp: now,
 		})
 	}
@@ -296,22 +697,46 @@ func (rc *DefaultReconciliationController) populateDryRunResult(result *Reconcil
 			Name:      pair.Desired.GetName(),
 			Action:    ActionUpdate,
 			Message:   "would be updated",
+			Diffs:     pair.Diffs,
 			Timestamp: now,
 		})
 	}
 
 	// Add delete actions
 	for _, resource := range diff.ToDelete {
+		actionType := ActionDelete
+		message := "would be deleted"
+		if _, ok := rc.pauserFor(resource); ok {
+			message = "would be paused instead of deleted"
+		}
+		if rc.deleteProtected(resource) {
+			actionType = ActionSkip
+			message = fmt.Sprintf("would be kept: protected by the %s annotation", labels.AnnotationDeleteProtect)
+		}
+
 		result.DeletedResources = append(result.DeletedResources, ResourceAction{
 			Type:      resource.GetType(),
 			Name:      resource.GetName(),
-			Action:    ActionDelete,
-			Message:   "would be deleted",
+			Action:    actionType,
+			Message:   message,
 			Timestamp: now,
 		})
 	}
 }
 
+// resourceLevels converts a creation or deletion order into references, one slice per
+// level of resources that are handled in parallel
+func resourceLevels(order [][]Resource) [][]ResourceReference {
+	levels := make([][]ResourceReference, len(order))
+	for i, level := range order {
+		levels[i] = make([]ResourceReference, len(level))
+		for j, resource := range level {
+			levels[i][j] = ResourceReference{Type: resource.GetType(), Name: resource.GetName()}
+		}
+	}
+	return levels
+}
+
 func (rc *DefaultReconciliationController) shouldCreate(resource Resource, toCreate []Resource) bool {
 	for _, createResource := range toCreate {
 		if createResource.GetName() == resource.GetName() && createResource.GetType() == resource.GetType() {
@@ -332,8 +757,31 @@ func (rc *DefaultReconciliationController) addError(result *ReconciliationResult
 	return nil
 }
 
+// addValidationError records a failed manifest validation. Errors that name their own
+// resource, such as rejected admission checks, are recorded as they are.
+func (rc *DefaultReconciliationController) addValidationError(result *ReconciliationResult, err error) error {
+	var reconciliationError *ReconciliationError
+	if errors.As(err, &reconciliationError) {
+		result.Errors = append(result.Errors, reconciliationError)
+		return err
+	}
+
+	return rc.addError(result, ErrorTypeValidation, ResourceReference{},
+		fmt.Sprintf("manifest validation failed: %v", err), err, false)
+}
+
 // validateManifests performs comprehensive validation of input manifests
 func (rc *DefaultReconciliationController) validateManifests(manifests []Resource) error {
+	if rc.volumeBaseDirErr != nil {
+		return rc.volumeBaseDirErr
+	}
+	if rc.labelPrefixErr != nil {
+		return rc.labelPrefixErr
+	}
+	if err := rc.validatePostReconcileHooks(manifests); err != nil {
+		return err
+	}
+
 	resourceNames := make(map[string]bool)
 
 	for _, manifest := range manifests {
@@ -353,11 +801,165 @@ func (rc *DefaultReconciliationController) validateManifests(manifests []Resourc
 		if _, exists := rc.managers[manifest.GetType()]; !exists {
 			return fmt.Errorf("unsupported resource type: %s", manifest.GetType())
 		}
+
+		// Check hostPath volumes against the allowed prefixes before anything is created
+		if volume, ok := manifest.(*VolumeResource); ok && volume.Spec.HostPath != nil && len(rc.hostPathPrefixes) > 0 {
+			validator := &HostPathValidator{allowedPrefixes: rc.hostPathPrefixes}
+			if err := validator.validateHostPath(volume.Spec.HostPath.Path); err != nil {
+				return fmt.Errorf("volume '%s': %w", volume.GetName(), err)
+			}
+		}
+
+		if err := rc.checkAdmission(manifest); err != nil {
+			return err
+		}
+	}
+
+	// Check that every container reference resolves within the manifest set
+	var dangling []string
+	for _, manifest := range manifests {
+		container, ok := manifest.(*ContainerResource)
+		if !ok {
+			continue
+		}
+
+		missing := func(resourceType ResourceType, name string) {
+			if !resourceNames[fmt.Sprintf("%s/%s", resourceType, name)] {
+				dangling = append(dangling, fmt.Sprintf("container '%s' references missing %s '%s'", container.GetName(), resourceType, name))
+			}
+		}
+
+		for _, volume := range container.Spec.Volumes {
+			missing(ResourceTypeVolume, volume.Name)
+		}
+		for _, secret := range container.Spec.Secrets {
+			missing(ResourceTypeSecret, secret.Name)
+		}
+		for _, network := range container.Spec.Networks {
+			// Podman's default network always exists, and external ones are not in the chart
+			if network.Name != defaultPodmanNetwork && !network.External {
+				missing(ResourceTypeNetwork, network.Name)
+			}
+		}
+		if container.Spec.Pod != "" {
+			missing(ResourceTypePod, container.Spec.Pod)
+		}
+		for _, name := range container.Spec.DependsOn {
+			missing(ResourceTypeContainer, name)
+		}
+		if !container.Spec.ExternalNamespaces {
+			for _, ns := range namespaceModes(container.Spec) {
+				if target, ok := namespaceTarget(ns.mode); ok {
+					missing(ResourceTypeContainer, target)
+				}
+			}
+		}
+	}
+
+	// Check that pod members exist and belong to a single pod
+	podByContainer := make(map[string]string)
+	podsByName := make(map[string]*PodResource)
+	for _, manifest := range manifests {
+		pod, ok := manifest.(*PodResource)
+		if !ok {
+			continue
+		}
+		podsByName[pod.GetName()] = pod
+
+		for _, volume := range pod.Spec.Volumes {
+			if !resourceNames[fmt.Sprintf("%s/%s", ResourceTypeVolume, volume.Name)] {
+				dangling = append(dangling, fmt.Sprintf("pod '%s' references missing volume '%s'", pod.GetName(), volume.Name))
+			}
+		}
+		if len(pod.Spec.Ports) > 0 && !slices.Contains(podSharedNamespaces(pod), "net") {
+			return fmt.Errorf("pod '%s' publishes ports but does not share the net namespace", pod.GetName())
+		}
+
+		for _, containerName := range pod.Spec.Containers {
+			if !resourceNames[fmt.Sprintf("%s/%s", ResourceTypeContainer, containerName)] {
+				dangling = append(dangling, fmt.Sprintf("pod '%s' references missing container '%s'", pod.GetName(), containerName))
+				continue
+			}
+			if other, exists := podByContainer[containerName]; exists && other != pod.GetName() {
+				return fmt.Errorf("container '%s' is listed by both pod '%s' and pod '%s'", containerName, other, pod.GetName())
+			}
+			podByContainer[containerName] = pod.GetName()
+		}
+	}
+	for _, manifest := range manifests {
+		container, ok := manifest.(*ContainerResource)
+		if !ok {
+			continue
+		}
+		podName, listed := podByContainer[container.GetName()]
+		if container.Spec.Pod != "" {
+			if listed && podName != container.Spec.Pod {
+				return fmt.Errorf("container '%s' is listed by pod '%s' but its spec names pod '%s'", container.GetName(), podName, container.Spec.Pod)
+			}
+			podName = container.Spec.Pod
+		}
+
+		// Members sharing the pod's network namespace reach the host through its ports,
+		// and Podman refuses to attach them to networks of their own
+		if pod, exists := podsByName[podName]; exists && slices.Contains(podSharedNamespaces(pod), "net") {
+			if len(container.Spec.Ports) > 0 {
+				return fmt.Errorf("container '%s' publishes ports but shares the network namespace of pod '%s'; declare them on the pod", container.GetName(), podName)
+			}
+			if len(container.Spec.Networks) > 0 {
+				return fmt.Errorf("container '%s' joins networks but shares the network namespace of pod '%s'; remove them or stop sharing the net namespace", container.GetName(), podName)
+			}
+		}
+	}
+
+	if err := checkHostPortConflicts(manifests); err != nil {
+		return err
+	}
+
+	if len(dangling) > 0 {
+		return fmt.Errorf("unresolved references: %s", strings.Join(dangling, "; "))
 	}
 
 	return nil
 }
 
+// checkHostPortConflicts returns an error naming the first two containers or pods that
+// publish the same host port and protocol, which would otherwise only fail once the
+// second one starts. Ports without a protocol are TCP.
+func checkHostPortConflicts(manifests []Resource) error {
+	publishers := make(map[string]string)
+	publish := func(owner string, ports []ContainerPort) error {
+		for _, port := range ports {
+			if port.HostPort == 0 {
+				continue
+			}
+			protocol := strings.ToLower(port.Protocol)
+			if protocol == "" {
+				protocol = "tcp"
+			}
+			key := fmt.Sprintf("%d/%s", port.HostPort, protocol)
+			if other, exists := publishers[key]; exists {
+				return fmt.Errorf("host port %s is published by both %s and %s", key, other, owner)
+			}
+			publishers[key] = owner
+		}
+		return nil
+	}
+
+	for _, manifest := range manifests {
+		var err error
+		switch resource := manifest.(type) {
+		case *ContainerResource:
+			err = publish(fmt.Sprintf("container '%s'", resource.GetName()), resource.Spec.Ports)
+		case *PodResource:
+			err = publish(fmt.Sprintf("pod '%s'", resource.GetName()), resource.Spec.Ports)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // buildDependencyGraphWithRetry builds dependency graph with retry logic
 func (rc *DefaultReconciliationController) buildDependencyGraphWithRetry(ctx context.Context, manifests []Resource, result *ReconciliationResult) (*DependencyGraph, error) {
 	const maxRetries = 3
@@ -369,6 +971,13 @@ func (rc *DefaultReconciliationController) buildDependencyGraphWithRetry(ctx con
 			return dependencyGraph, nil
 		}
 
+		// A cycle is in the manifests themselves, so building again cannot resolve it
+		var cycleErr *CircularDependencyError
+		if errors.As(err, &cycleErr) {
+			return nil, rc.addError(result, ErrorTypeDependency, ResourceReference{},
+				fmt.Sprintf("failed to build dependency graph: %v", err), err, false)
+		}
+
 		lastErr = err
 		if attempt < maxRetries {
 			// Add a warning for retry attempts
@@ -404,6 +1013,13 @@ func (rc *DefaultReconciliationController) getCurrentStateWithRetry(ctx context.
 				break
 			}
 
+			// A missing socket will not come back within the retry delays, and every other
+			// resource type would fail the same way
+			if podman.IsConnectionError(err) {
+				return nil, rc.addError(result, ErrorTypePodmanAPI, ResourceReference{},
+					fmt.Sprintf("Podman socket not found at %s; is Podman running?", rc.podmanURI()), err, false)
+			}
+
 			lastErr = err
 			if attempt < maxRetries {
 				rc.addError(result, ErrorTypePodmanAPI, ResourceReference{Type: resourceType},
@@ -466,6 +1082,11 @@ func (rc *DefaultReconciliationController) compareAllStatesWithValidation(manife
 		allDiff.Unchanged = append(allDiff.Unchanged, diff.Unchanged...)
 	}
 
+	recreateSecretDependents(allDiff, actualStateByType)
+	rc.skipAutoRemovedContainers(allDiff)
+	rc.keepPausedOrphans(allDiff)
+	rc.skipPausedResources(result, allDiff, manifests)
+
 	return allDiff, nil
 }
 
@@ -484,7 +1105,7 @@ func (rc *DefaultReconciliationController) executeReconciliationWithRecovery(ctx
 	}
 
 	// Execute updates with parallel processing where safe
-	rc.executeUpdatesWithRecovery(ctx, result, diff.ToUpdate)
+	rc.executeUpdatesWithRecovery(ctx, result, diff, creationOrder)
 
 	// Execute deletes in reverse dependency order
 	for levelIndex, level := range deletionOrder {
@@ -502,7 +1123,7 @@ func (rc *DefaultReconciliationController) executeReconciliationWithRecovery(ctx
 // executeCreationLevel executes creation for a single dependency level
 func (rc *DefaultReconciliationController) executeCreationLevel(ctx context.Context, result *ReconciliationResult, level []Resource, toCreate []Resource, levelIndex int) {
 	for _, resource := range level {
-		if rc.shouldCreate(resource, toCreate) {
+		if rc.shouldCreate(resource, toCreate) && !rc.skipForFailedDependency(result, resource, ActionCreate) {
 			rc.executeCreateWithRetry(ctx, result, resource, levelIndex)
 		}
 	}
@@ -517,11 +1138,28 @@ func (rc *DefaultReconciliationController) executeDeletionLevel(ctx context.Cont
 	}
 }
 
-// executeUpdatesWithRecovery executes updates with error recovery
-func (rc *DefaultReconciliationController) executeUpdatesWithRecovery(ctx context.Context, result *ReconciliationResult, toUpdate []ResourcePair) {
-	for _, pair := range toUpdate {
-		rc.executeUpdateWithRetry(ctx, result, pair.Desired, pair.Actual)
+// executeUpdatesWithRecovery executes updates in dependency order with error recovery.
+// Containers depending on a resource that is replaced are stopped before any update and
+// started again once all of them are done.
+func (rc *DefaultReconciliationController) executeUpdatesWithRecovery(ctx context.Context, result *ReconciliationResult, diff *StateDiff, creationOrder [][]Resource) {
+	drained := rc.drainDependents(ctx, result, diff, creationOrder)
+	ordered := updatesInDependencyOrder(diff.ToUpdate, creationOrder)
+	groups := rc.replicaGroups(ordered)
+	rolled := make(map[string]bool)
+	for _, pair := range ordered {
+		// The replicas of a component are rolled together, where the first one is due
+		if component, ok := replicaComponent(pair.Desired); ok && len(groups[component]) > 0 {
+			if !rolled[component] {
+				rolled[component] = true
+				rc.executeRollingUpdate(ctx, result, groups[component])
+			}
+			continue
+		}
+		if !rc.skipForFailedDependency(result, pair.Desired, ActionUpdate) {
+			rc.executeUpdateWithRetry(ctx, result, pair)
+		}
 	}
+	rc.restartDrained(ctx, result, drained)
 }
 
 // executeCreateWithRetry creates a resource with retry logic
@@ -536,6 +1174,11 @@ func (rc *DefaultReconciliationController) executeCreateWithRetry(ctx context.Co
 		Timestamp: startTime,
 	}
 
+	ctx, span := rc.tracer.Start(ctx, "reconcile.create", resourceSpanAttributes(resource)...)
+	defer endActionSpan(span, &action)
+	defer rc.observeAction(&action)
+	defer rc.logAction(&action)
+
 	manager, exists := rc.managers[resource.GetType()]
 	if !exists {
 		action.Error = fmt.Sprintf("no manager found for resource type %s", resource.GetType())
@@ -549,16 +1192,62 @@ func (rc *DefaultReconciliationController) executeCreateWithRetry(ctx context.Co
 
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := manager.CreateResource(ctx, resource)
+		err := rc.runWithResourceTimeout(ctx, func(ctx context.Context) error {
+			return manager.CreateResource(ctx, resource)
+		})
 		if err == nil {
+			// Dependents are created in later levels, so they only start once this container is ready
+			if container, ok := resource.(*ContainerResource); ok && container.Spec.ReadinessProbe != nil {
+				waited, err := rc.waitForReady(ctx, container)
+				action.ReadinessWait = waited
+				if err != nil {
+					action.Error = fmt.Sprintf("created but not ready: %v", err)
+					action.Duration = time.Since(startTime)
+					result.CreatedResources = append(result.CreatedResources, action)
+					rc.addError(result, ErrorTypeReadiness,
+						ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+						action.Error, err, true)
+					attachContainerLogs(result, err)
+					return
+				}
+			}
+
 			action.Duration = time.Since(startTime)
 			action.Message = fmt.Sprintf("created successfully (level %d)", levelIndex)
+			if action.ReadinessWait > 0 {
+				action.Message = fmt.Sprintf("created and ready after %s (level %d)", action.ReadinessWait.Round(time.Millisecond), levelIndex)
+			}
 			result.CreatedResources = append(result.CreatedResources, action)
 			return
 		}
 
+		if errors.Is(err, errResourceTimeout) {
+			action.Error = err.Error()
+			action.Duration = time.Since(startTime)
+			result.CreatedResources = append(result.CreatedResources, action)
+			rc.addError(result, ErrorTypeTimeout,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to create resource: %v", err), err, true)
+			attachContainerLogs(result, err)
+			return
+		}
+
+		// Retrying cannot fix a missing image or a spec Podman rejects
+		if !podman.IsRetryable(err) {
+			action.Error = fmt.Sprintf("failed without retrying: %v", err)
+			action.Duration = time.Since(startTime)
+			result.CreatedResources = append(result.CreatedResources, action)
+			rc.addError(result, ErrorTypePodmanAPI,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to create resource: %v", err), err, false)
+			attachContainerLogs(result, err)
+			return
+		}
+
 		lastErr = err
 		if attempt < maxRetries {
+			rc.metrics.IncRetries(action.Type, action.Action)
+
 			// Brief delay before retry
 			select {
 			case <-ctx.Done():
@@ -577,20 +1266,57 @@ func (rc *DefaultReconciliationController) executeCreateWithRetry(ctx context.Co
 	rc.addError(result, ErrorTypePodmanAPI,
 		ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
 		fmt.Sprintf("failed to create resource: %v", lastErr), lastErr, true)
+	attachContainerLogs(result, lastErr)
+}
+
+// runWithResourceTimeout runs a single resource operation under the resource timeout, if
+// one is set. The operation is waited for, since Podman calls end once their context
+// expires, so a resource is not reported as timed out while it is still being changed.
+func (rc *DefaultReconciliationController) runWithResourceTimeout(ctx context.Context, operation func(ctx context.Context) error) error {
+	if rc.resourceTimeout <= 0 {
+		return operation(ctx)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, rc.resourceTimeout)
+	defer cancel()
+
+	err := operation(timeoutCtx)
+	if err != nil && errors.Is(timeoutCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+		return fmt.Errorf("%w after %s: %v", errResourceTimeout, rc.resourceTimeout, err)
+	}
+	return err
 }
 
 // executeUpdateWithRetry updates a resource with retry logic
-func (rc *DefaultReconciliationController) executeUpdateWithRetry(ctx context.Context, result *ReconciliationResult, desired, actual Resource) {
+func (rc *DefaultReconciliationController) executeUpdateWithRetry(ctx context.Context, result *ReconciliationResult, pair ResourcePair) {
 	const maxRetries = 3
 	startTime := time.Now()
+	desired, actual := pair.Desired, pair.Actual
 
 	action := ResourceAction{
 		Type:      desired.GetType(),
 		Name:      desired.GetName(),
 		Action:    ActionUpdate,
+		Diffs:     pair.Diffs,
 		Timestamp: startTime,
 	}
 
+	ctx, span := rc.tracer.Start(ctx, "reconcile.update", resourceSpanAttributes(desired)...)
+	defer endActionSpan(span, &action)
+	defer rc.observeAction(&action)
+	defer rc.logAction(&action)
+
+	// Updates may recreate the resource, so the actual one must be ours to delete
+	if err := assertOwnership(actual, result.ChartName, rc.labelPrefix); err != nil {
+		action.Error = fmt.Sprintf("refused to update: %v", err)
+		action.Duration = time.Since(startTime)
+		result.UpdatedResources = append(result.UpdatedResources, action)
+		rc.addError(result, ErrorTypeOwnership,
+			ResourceReference{Type: actual.GetType(), Name: actual.GetName()},
+			action.Error, err, false)
+		return
+	}
+
 	manager, exists := rc.managers[desired.GetType()]
 	if !exists {
 		action.Error = fmt.Sprintf("no manager found for resource type %s", desired.GetType())
@@ -604,7 +1330,12 @@ func (rc *DefaultReconciliationController) executeUpdateWithRetry(ctx context.Co
 
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := manager.UpdateResource(ctx, desired, actual)
+		err := rc.runWithResourceTimeout(ctx, func(ctx context.Context) error {
+			if pair.Recreate {
+				return recreateResource(ctx, manager, desired, actual)
+			}
+			return manager.UpdateResource(ctx, desired, actual)
+		})
 		if err == nil {
 			action.Duration = time.Since(startTime)
 			action.Message = "updated successfully"
@@ -612,8 +1343,31 @@ func (rc *DefaultReconciliationController) executeUpdateWithRetry(ctx context.Co
 			return
 		}
 
+		if errors.Is(err, errResourceTimeout) {
+			action.Error = err.Error()
+			action.Duration = time.Since(startTime)
+			result.UpdatedResources = append(result.UpdatedResources, action)
+			rc.addError(result, ErrorTypeTimeout,
+				ResourceReference{Type: desired.GetType(), Name: desired.GetName()},
+				fmt.Sprintf("failed to update resource: %v", err), err, true)
+			return
+		}
+
+		// Retrying cannot fix a missing image or a spec Podman rejects
+		if !podman.IsRetryable(err) {
+			action.Error = fmt.Sprintf("failed without retrying: %v", err)
+			action.Duration = time.Since(startTime)
+			result.UpdatedResources = append(result.UpdatedResources, action)
+			rc.addError(result, ErrorTypePodmanAPI,
+				ResourceReference{Type: desired.GetType(), Name: desired.GetName()},
+				fmt.Sprintf("failed to update resource: %v", err), err, false)
+			return
+		}
+
 		lastErr = err
 		if attempt < maxRetries {
+			rc.metrics.IncRetries(action.Type, action.Action)
+
 			// Brief delay before retry
 			select {
 			case <-ctx.Done():
@@ -634,6 +1388,14 @@ func (rc *DefaultReconciliationController) executeUpdateWithRetry(ctx context.Co
 		fmt.Sprintf("failed to update resource: %v", lastErr), lastErr, true)
 }
 
+// recreateResource deletes the actual resource and creates the desired one in its place
+func recreateResource(ctx context.Context, manager ResourceManager, desired, actual Resource) error {
+	if err := manager.DeleteResource(ctx, actual); err != nil {
+		return fmt.Errorf("unable to remove resource for recreation: %w", err)
+	}
+	return manager.CreateResource(ctx, desired)
+}
+
 // executeDeleteWithRetry deletes a resource with retry logic
 func (rc *DefaultReconciliationController) executeDeleteWithRetry(ctx context.Context, result *ReconciliationResult, resource Resource, levelIndex int) {
 	const maxRetries = 3
@@ -646,6 +1408,29 @@ func (rc *DefaultReconciliationController) executeDeleteWithRetry(ctx context.Co
 		Timestamp: startTime,
 	}
 
+	ctx, span := rc.tracer.Start(ctx, "reconcile.delete", resourceSpanAttributes(resource)...)
+	defer endActionSpan(span, &action)
+	defer rc.observeAction(&action)
+	defer rc.logAction(&action)
+
+	if err := assertOwnership(resource, result.ChartName, rc.labelPrefix); err != nil {
+		action.Error = fmt.Sprintf("refused to delete: %v", err)
+		action.Duration = time.Since(startTime)
+		result.DeletedResources = append(result.DeletedResources, action)
+		rc.addError(result, ErrorTypeOwnership,
+			ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+			action.Error, err, false)
+		return
+	}
+
+	if rc.deleteProtected(resource) {
+		action.Action = ActionSkip
+		action.Message = fmt.Sprintf("kept instead of deleted: protected by the %s annotation", labels.AnnotationDeleteProtect)
+		action.Duration = time.Since(startTime)
+		result.DeletedResources = append(result.DeletedResources, action)
+		return
+	}
+
 	manager, exists := rc.managers[resource.GetType()]
 	if !exists {
 		action.Error = fmt.Sprintf("no manager found for resource type %s", resource.GetType())
@@ -657,18 +1442,50 @@ func (rc *DefaultReconciliationController) executeDeleteWithRetry(ctx context.Co
 		return
 	}
 
+	deleteResource := manager.DeleteResource
+	successMessage := fmt.Sprintf("deleted successfully (level %d)", levelIndex)
+	if pauser, ok := rc.pauserFor(resource); ok {
+		deleteResource = pauser.PauseResource
+		successMessage = fmt.Sprintf("paused instead of deleted (level %d)", levelIndex)
+	}
+
 	var lastErr error
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := manager.DeleteResource(ctx, resource)
+		err := rc.runWithResourceTimeout(ctx, func(ctx context.Context) error {
+			return deleteResource(ctx, resource)
+		})
 		if err == nil {
 			action.Duration = time.Since(startTime)
-			action.Message = fmt.Sprintf("deleted successfully (level %d)", levelIndex)
+			action.Message = successMessage
 			result.DeletedResources = append(result.DeletedResources, action)
 			return
 		}
 
+		if errors.Is(err, errResourceTimeout) {
+			action.Error = err.Error()
+			action.Duration = time.Since(startTime)
+			result.DeletedResources = append(result.DeletedResources, action)
+			rc.addError(result, ErrorTypeTimeout,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to delete resource: %v", err), err, true)
+			return
+		}
+
+		// Retrying cannot fix a missing image or a spec Podman rejects
+		if !podman.IsRetryable(err) {
+			action.Error = fmt.Sprintf("failed without retrying: %v", err)
+			action.Duration = time.Since(startTime)
+			result.DeletedResources = append(result.DeletedResources, action)
+			rc.addError(result, ErrorTypePodmanAPI,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to delete resource: %v", err), err, false)
+			return
+		}
+
 		lastErr = err
 		if attempt < maxRetries {
+			rc.metrics.IncRetries(action.Type, action.Action)
+
 			// Brief delay before retry
 			select {
 			case <-ctx.Done():
@@ -689,59 +1506,29 @@ func (rc *DefaultReconciliationController) executeDeleteWithRetry(ctx context.Co
 		fmt.Sprintf("failed to delete resource: %v", lastErr), lastErr, true)
 }
 
-// cleanupOrphanedResourcesWithRecovery removes orphaned resources with error handling
-func (rc *DefaultReconciliationController) cleanupOrphanedResourcesWithRecovery(ctx context.Context, result *ReconciliationResult, manifests []Resource, actualStateByType map[ResourceType][]Resource, deletionOrder [][]Resource) {
-	// Create a set of desired resource names by type
-	desiredByType := make(map[ResourceType]map[string]bool)
-	for _, manifest := range manifests {
-		resourceType := manifest.GetType()
-		if desiredByType[resourceType] == nil {
-			desiredByType[resourceType] = make(map[string]bool)
-		}
-		desiredByType[resourceType][manifest.GetName()] = true
+// pruneDanglingImages removes dangling images and records their IDs on the result.
+// A failed prune is reported as a recoverable error since every change was already applied.
+func (rc *DefaultReconciliationController) pruneDanglingImages(ctx context.Context, result *ReconciliationResult) error {
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err == nil {
+		var pruned []string
+		pruned, err = podmanClient.PruneImages(ctx, map[string][]string{"dangling": {"true"}})
+		result.PrunedImages = append(result.PrunedImages, pruned...)
 	}
 
-	// Find orphaned resources and organize by dependency levels
-	orphanedByLevel := make([][]Resource, len(deletionOrder))
-
-	for levelIndex, level := range deletionOrder {
-		for _, levelResource := range level {
-			resourceType := levelResource.GetType()
-			actualResources := actualStateByType[resourceType]
-			desired := desiredByType[resourceType]
-			if desired == nil {
-				desired = make(map[string]bool)
-			}
-
-			for _, actualResource := range actualResources {
-				if !desired[actualResource.GetName()] {
-					// Check if this resource matches the current level
-					if rc.resourceMatchesLevel(actualResource, level) {
-						orphanedByLevel[levelIndex] = append(orphanedByLevel[levelIndex], actualResource)
-					}
-				}
-			}
-		}
+	if err != nil {
+		return rc.addError(result, ErrorTypePodmanAPI, ResourceReference{},
+			fmt.Sprintf("failed to prune images: %v", err), err, true)
 	}
 
-	// Delete orphaned resources in proper dependency order
-	for levelIndex, orphanedResources := range orphanedByLevel {
-		for _, orphanedResource := range orphanedResources {
-			rc.executeDeleteWithRetry(ctx, result, orphanedResource, levelIndex)
-
-			// Check if context was cancelled
-			if ctx.Err() != nil {
-				return
-			}
-		}
-	}
+	return nil
 }
 
 // updateReconciliationStatus updates the internal status tracking
 func (rc *DefaultReconciliationController) updateReconciliationStatus(chartName string, result *ReconciliationResult, startTime time.Time) {
-	rc.mu.Lock()
-	defer rc.mu.Unlock()
-
 	status := &ReconciliationStatus{
 		ChartName:      chartName,
 		LastReconciled: startTime,
@@ -755,19 +1542,19 @@ func (rc *DefaultReconciliationController) updateReconciliationStatus(chartName
 	successfulDeletes := 0
 
 	for _, action := range result.CreatedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulCreates++
 		}
 	}
 
 	for _, action := range result.UpdatedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulUpdates++
 		}
 	}
 
 	for _, action := range result.DeletedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulDeletes++
 		}
 	}
@@ -775,6 +1562,9 @@ func (rc *DefaultReconciliationController) updateReconciliationStatus(chartName
 	status.ResourceCounts["created"] = successfulCreates
 	status.ResourceCounts["updated"] = successfulUpdates
 	status.ResourceCounts["deleted"] = successfulDeletes
+	if len(result.Paused) > 0 {
+		status.ResourceCounts["paused"] = len(result.Paused)
+	}
 
 	// Determine overall status
 	if len(result.Errors) == 0 {
@@ -793,23 +1583,125 @@ func (rc *DefaultReconciliationController) updateReconciliationStatus(chartName
 		}
 	}
 
-	rc.lastStatus[chartName] = status
+	if err := rc.stateStore.Save(chartName, status); err != nil {
+		rc.logger.Warn("failed to save reconciliation status", "chart", chartName, "error", err)
+	}
+	rc.metrics.IncReconciliations(chartName, status.Status)
 }
 
 // Helper methods
 
-func (rc *DefaultReconciliationController) shouldDelete(resource Resource, toDelete []Resource) bool {
-	for _, deleteResource := range toDelete {
-		if deleteResource.GetName() == resource.GetName() && deleteResource.GetType() == resource.GetType() {
-			return true
+// assertOwnership verifies that a resource is managed by cutepod for chartName, going by
+// the standard labels under labelPrefix, so that a chart never deletes resources it did
+// not create even if the chart label filter is wrong
+func assertOwnership(resource Resource, chartName, labelPrefix string) error {
+	resourceLabels := resource.GetLabels()
+
+	managedByKey := labels.WithPrefix(labels.LabelManagedBy, labelPrefix)
+	if managedBy := resourceLabels[managedByKey]; managedBy != labels.ManagedByValue {
+		return fmt.Errorf("%s %s is not managed by cutepod (%s=%q)",
+			resource.GetType(), resource.GetName(), managedByKey, managedBy)
+	}
+
+	if owner := resourceLabels[labels.WithPrefix(labels.LabelChart, labelPrefix)]; owner != chartName {
+		return fmt.Errorf("%s %s belongs to chart %q, not %q",
+			resource.GetType(), resource.GetName(), owner, chartName)
+	}
+
+	return nil
+}
+
+// deleteProtected reports whether resource has to be kept instead of deleted, unless
+// the controller was told to force deletion
+func (rc *DefaultReconciliationController) deleteProtected(resource Resource) bool {
+	return !rc.forceDelete && isDeleteProtected(resource)
+}
+
+// isDeleteProtected reports whether a volume or secret carries the delete-protect annotation
+func isDeleteProtected(resource Resource) bool {
+	if resource.GetType() != ResourceTypeVolume && resource.GetType() != ResourceTypeSecret {
+		return false
+	}
+	annotated, ok := resource.(interface{ GetAnnotations() map[string]string })
+	return ok && annotated.GetAnnotations()[labels.AnnotationDeleteProtect] == "true"
+}
+
+// withDeleteProtection returns the labels to create a volume or secret with, adding the
+// delete-protect annotation as a label so that it can be read back once the resource is
+// gone from the chart
+func withDeleteProtection(resource Resource, objectLabels map[string]string) map[string]string {
+	if !isDeleteProtected(resource) {
+		return objectLabels
+	}
+	return labels.MergeLabels(objectLabels, map[string]string{labels.AnnotationDeleteProtect: "true"})
+}
+
+// warnUnrecordedDeleteProtection warns about volumes and secrets that are protected in
+// their manifest but were created without the protection label. Podman cannot relabel
+// them, so they are still deleted once they leave the chart unless they are recreated.
+func (rc *DefaultReconciliationController) warnUnrecordedDeleteProtection(manifests []Resource, actualStateByType map[ResourceType][]Resource) {
+	for _, manifest := range manifests {
+		if !isDeleteProtected(manifest) {
+			continue
+		}
+		for _, actual := range actualStateByType[manifest.GetType()] {
+			if actual.GetName() == manifest.GetName() && !isDeleteProtected(actual) {
+				rc.logger.Warn("delete protection only applies to resources created with it; recreate the resource to protect it",
+					"type", manifest.GetType(), "name", manifest.GetName(), "annotation", labels.AnnotationDeleteProtect)
+			}
 		}
 	}
-	return false
 }
 
-func (rc *DefaultReconciliationController) resourceMatchesLevel(resource Resource, level []Resource) bool {
-	for _, levelResource := range level {
-		if resource.GetName() == levelResource.GetName() && resource.GetType() == levelResource.GetType() {
+// pauserFor returns the manager that should pause resource instead of deleting it, if any
+func (rc *DefaultReconciliationController) pauserFor(resource Resource) (resourcePauser, bool) {
+	if !rc.pauseOnDelete {
+		return nil, false
+	}
+	pauser, ok := rc.managers[resource.GetType()].(resourcePauser)
+	return pauser, ok
+}
+
+// keepPausedOrphans drops containers that were already paused instead of deleted from
+// the deletions, so they are not paused and reported again on every reconcile. The
+// networks, volumes and other resources those containers use are kept too, since they
+// cannot be removed while a paused container still holds them.
+func (rc *DefaultReconciliationController) keepPausedOrphans(diff *StateDiff) {
+	inUse := make(map[ResourceReference]bool)
+	for _, resource := range diff.ToDelete {
+		container, ok := resource.(*ContainerResource)
+		if !ok {
+			continue
+		}
+		if _, ok := rc.pauserFor(container); !ok {
+			continue
+		}
+		for _, dep := range container.GetDependencies() {
+			if dep.Type != ResourceTypeContainer {
+				inUse[dep] = true
+			}
+		}
+	}
+
+	toDelete := make([]Resource, 0, len(diff.ToDelete))
+	for _, resource := range diff.ToDelete {
+		if container, ok := resource.(*ContainerResource); ok && isContainerPaused(container) {
+			if _, ok := rc.pauserFor(container); ok {
+				continue
+			}
+		}
+		if inUse[ResourceReference{Type: resource.GetType(), Name: resource.GetName()}] {
+			rc.logger.Debug("keeping resource used by a paused container", "type", resource.GetType(), "name", resource.GetName())
+			continue
+		}
+		toDelete = append(toDelete, resource)
+	}
+	diff.ToDelete = toDelete
+}
+
+func (rc *DefaultReconciliationController) shouldDelete(resource Resource, toDelete []Resource) bool {
+	for _, deleteResource := range toDelete {
+		if deleteResource.GetName() == resource.GetName() && deleteResource.GetType() == resource.GetType() {
 			return true
 		}
 	}
@@ -828,28 +1720,59 @@ func (rc *DefaultReconciliationController) generateSummary(result *Reconciliatio
 	successfulDeletes := 0
 
 	for _, action := range result.CreatedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulCreates++
 		}
 	}
 
 	for _, action := range result.UpdatedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulUpdates++
 		}
 	}
 
 	for _, action := range result.DeletedResources {
-		if action.Error == "" {
+		if action.Error == "" && action.Action != ActionSkip {
 			successfulDeletes++
 		}
 	}
 
+	pruned := ""
+	if len(result.PrunedImages) > 0 {
+		shortIDs := make([]string, len(result.PrunedImages))
+		for i, id := range result.PrunedImages {
+			shortIDs[i] = shortImageID(id)
+		}
+		pruned = fmt.Sprintf(", %d images pruned (%s)", len(shortIDs), strings.Join(shortIDs, ", "))
+	}
+
+	// Creates and updates are skipped for paused resources, and in best-effort mode for
+	// failed dependencies
+	skipped := ""
+	skippedChanges := 0
+	for _, action := range slices.Concat(result.CreatedResources, result.UpdatedResources) {
+		if action.Action == ActionSkip {
+			skippedChanges++
+		}
+	}
+	if skippedChanges > 0 {
+		skipped = fmt.Sprintf(", %d skipped", skippedChanges)
+	}
+
 	if errors > 0 {
-		return fmt.Sprintf("Reconciliation completed with errors: %d/%d created, %d/%d updated, %d/%d deleted, %d errors",
-			successfulCreates, created, successfulUpdates, updated, successfulDeletes, deleted, errors)
+		return fmt.Sprintf("Reconciliation completed with errors: %d/%d created, %d/%d updated, %d/%d deleted, %d errors%s%s",
+			successfulCreates, created, successfulUpdates, updated, successfulDeletes, deleted, errors, skipped, pruned)
 	}
 
-	return fmt.Sprintf("Reconciliation completed successfully: %d created, %d updated, %d deleted",
-		created, updated, deleted)
+	return fmt.Sprintf("Reconciliation completed successfully: %d created, %d updated, %d deleted%s%s",
+		successfulCreates, successfulUpdates, successfulDeletes, skipped, pruned)
+}
+
+// shortImageID truncates an image ID to the 12 characters Podman displays
+func shortImageID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
 }
@@ -1,8 +1,22 @@
 package resource
 
 import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/containers/podman/v5/pkg/inspect"
 )
 
 func TestReconciliationResult_Summary(t *testing.T) {
@@ -51,3 +65,1731 @@ func TestReconciliationResult_SummaryWithErrors(t *testing.T) {
 		t.Errorf("Expected summary '%s', got '%s'", expected, summary)
 	}
 }
+
+func TestReconciliationResult_ToJSONRoundTrip(t *testing.T) {
+	timestamp := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	result := &ReconciliationResult{
+		CreatedResources: []ResourceAction{
+			{Type: ResourceTypeContainer, Name: "web", Action: ActionCreate, Duration: 300 * time.Millisecond, Timestamp: timestamp},
+			{Type: ResourceTypeVolume, Name: "data", Action: ActionCreate, Duration: 20 * time.Millisecond, Timestamp: timestamp},
+		},
+		UpdatedResources: []ResourceAction{
+			{Type: ResourceTypeContainer, Name: "api", Action: ActionUpdate, Duration: 200 * time.Millisecond, Timestamp: timestamp,
+				Diffs: []FieldDiff{{Path: "spec.image", OldValue: "nginx:1.25", NewValue: "nginx:1.26"}}},
+		},
+		DeletedResources: []ResourceAction{},
+		Errors: []*ReconciliationError{
+			NewPodmanAPIError(ResourceReference{Type: ResourceTypeContainer, Name: "api"}, "failed to update resource",
+				errors.New("connection reset"), true),
+		},
+		Summary:   "Reconciliation completed with errors",
+		Duration:  time.Second,
+		ChartName: "demo",
+	}
+	result.Errors[0].Logs = []string{"starting", "panic: boom"}
+	result.PerTypeDurations = result.durationsByType()
+
+	if result.PerTypeDurations[ResourceTypeContainer] != 500*time.Millisecond ||
+		result.PerTypeDurations[ResourceTypeVolume] != 20*time.Millisecond {
+		t.Fatalf("Expected durations to be summed by type, got %v", result.PerTypeDurations)
+	}
+
+	data, err := result.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"cause":"connection reset"`) {
+		t.Errorf("Expected the cause to serialize as its message, got %s", data)
+	}
+
+	var decoded ReconciliationResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Errors[0].Cause == nil || decoded.Errors[0].Cause.Error() != "connection reset" {
+		t.Errorf("Expected the cause to round-trip, got %v", decoded.Errors[0].Cause)
+	}
+
+	// Causes come back as plain errors, so compare the rest field by field
+	decoded.Errors[0].Cause, result.Errors[0].Cause = nil, nil
+	if !reflect.DeepEqual(&decoded, result) {
+		t.Errorf("Expected the result to round-trip\nwant: %+v\ngot:  %+v", result, &decoded)
+	}
+}
+
+func TestReconcile_ReusesSingleConnection(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	container := newExplainTestContainer("nginx:1.25")
+
+	if _, err := controller.Reconcile(context.Background(), []Resource{network, container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if mockClient.GetCallCount("Connect") != 1 {
+		t.Errorf("Expected a single connection per reconcile, got %d", mockClient.GetCallCount("Connect"))
+	}
+	if mockClient.GetCallCount("Close") != 1 {
+		t.Errorf("Expected the connection to be closed once, got %d", mockClient.GetCallCount("Close"))
+	}
+}
+
+func TestReconcile_PauseOnDeleteAndResume(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithPauseOnDelete()).(*DefaultReconciliationController)
+	ctx := context.Background()
+
+	container := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(ctx, []Resource{container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	actual, err := controller.managers[ResourceTypeContainer].GetActualState(ctx, "demo")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+
+	result := &ReconciliationResult{ChartName: "demo"}
+	controller.executeDeleteWithRetry(ctx, result, actual[0], 0)
+
+	if len(result.DeletedResources) != 1 || result.DeletedResources[0].Error != "" {
+		t.Fatalf("Expected a successful delete action, got %+v", result.DeletedResources)
+	}
+	if result.DeletedResources[0].Message != "paused instead of deleted (level 0)" {
+		t.Errorf("Expected pause message, got '%s'", result.DeletedResources[0].Message)
+	}
+	if mockClient.GetCallCount("PauseContainer") != 1 || mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Errorf("Expected the container to be paused rather than removed")
+	}
+
+	// Re-adding the unchanged container resumes it instead of recreating it
+	result, err = controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected 1 updated resource, got %d", len(result.UpdatedResources))
+	}
+	if mockClient.GetCallCount("UnpauseContainer") != 1 {
+		t.Errorf("Expected the container to be unpaused once, got %d", mockClient.GetCallCount("UnpauseContainer"))
+	}
+	if mockClient.GetCallCount("CreateContainer") != 1 {
+		t.Errorf("Expected no recreation, got %d creates", mockClient.GetCallCount("CreateContainer"))
+	}
+}
+
+func TestReconcile_PauseOnDeleteLeavesPausedOrphansAlone(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithPauseOnDelete())
+	ctx := context.Background()
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+	if _, err := controller.Reconcile(ctx, []Resource{network, container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// The network is still used by the paused container, so it is kept
+	result, err := controller.Reconcile(ctx, nil, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.DeletedResources) != 1 || result.DeletedResources[0].Name != "web" {
+		t.Fatalf("Expected only the container to be paused, got %+v", result.DeletedResources)
+	}
+	if mockClient.GetCallCount("RemoveNetwork") != 0 {
+		t.Errorf("Expected the network of the paused container to be kept")
+	}
+
+	// Once paused, the container is left alone instead of being paused again
+	result, err = controller.Reconcile(ctx, nil, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.DeletedResources) != 0 || len(result.Errors) != 0 {
+		t.Errorf("Expected nothing to do, got %+v (errors: %+v)", result.DeletedResources, result.Errors)
+	}
+	if mockClient.GetCallCount("PauseContainer") != 1 {
+		t.Errorf("Expected the container to be paused once, got %d", mockClient.GetCallCount("PauseContainer"))
+	}
+}
+
+func TestReconcile_EmptyManifestsDeleteOrphans(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// An empty desired state means nothing of the chart should be left
+	result, err := controller.Reconcile(ctx, nil, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.DeletedResources) != 1 || result.DeletedResources[0].Name != "web" || result.DeletedResources[0].Action != ActionDelete {
+		t.Fatalf("Expected the container to be deleted, got %+v", result.DeletedResources)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %+v", result.Errors)
+	}
+	if mockClient.GetCallCount("RemoveContainer") != 1 {
+		t.Errorf("Expected the container to be removed once, got %d", mockClient.GetCallCount("RemoveContainer"))
+	}
+}
+
+func TestReconcile_PrunesDanglingImagesAfterSuccess(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.AddMockImage("<none>", &inspect.ImageData{ID: "sha256:0123456789abcdef0123456789abcdef"})
+	controller := NewReconciliationController(mockClient, WithPruneImagesAfterReconcile())
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.PrunedImages) != 1 || result.PrunedImages[0] != "sha256:0123456789abcdef0123456789abcdef" {
+		t.Fatalf("Expected the dangling image to be pruned, got %v", result.PrunedImages)
+	}
+	if !strings.Contains(result.Summary, "1 images pruned (0123456789ab)") {
+		t.Errorf("Expected pruned images in summary, got '%s'", result.Summary)
+	}
+	if _, err := mockClient.GetImage(context.Background(), "nginx:1.25"); err != nil {
+		t.Error("Expected the image used by the container to be kept")
+	}
+}
+
+func TestReconcile_DoesNotPruneAfterFailure(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("CreateNetwork", true)
+	controller := NewReconciliationController(mockClient, WithPruneImagesAfterReconcile())
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	result, err := controller.Reconcile(context.Background(), []Resource{network}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.Errors) == 0 {
+		t.Fatal("Expected the reconcile to record errors")
+	}
+	if mockClient.GetCallCount("PruneImages") != 0 {
+		t.Error("Expected no pruning after a failed reconcile")
+	}
+}
+
+func TestReconcile_ReportsPullProgressToEventHook(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	var progress []podman.PullProgress
+	controller := NewReconciliationController(mockClient, WithEventHook(func(event WatchEvent) {
+		if event.PullProgress != nil && event.ChartName == "demo" {
+			progress = append(progress, *event.PullProgress)
+		}
+	}))
+
+	if _, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(progress) < 2 || progress[0].Image != "nginx:1.25" || progress[0].Message == "" {
+		t.Fatalf("Expected progress reports for the pull, got %+v", progress)
+	}
+	if !progress[len(progress)-1].Done {
+		t.Errorf("Expected the last report to mark the pull as done, got %+v", progress[len(progress)-1])
+	}
+	if mockClient.GetCallCount("PullImageWithProgress") != 1 {
+		t.Errorf("Expected the image to be pulled with progress once, got %d", mockClient.GetCallCount("PullImageWithProgress"))
+	}
+}
+
+func TestReconcile_AttachesLogsOfContainerFailingToStart(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("StartContainer", true)
+	var logs []string
+	for i := 1; i <= 30; i++ {
+		logs = append(logs, fmt.Sprintf("line %d", i))
+	}
+	mockClient.SetContainerLogs("web", logs)
+	controller := NewReconciliationController(mockClient, WithFailureLogLines(5))
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("Expected 1 error, got %+v", result.Errors)
+	}
+	if !reflect.DeepEqual(result.Errors[0].Logs, logs[25:]) {
+		t.Errorf("Expected the last 5 log lines, got %v", result.Errors[0].Logs)
+	}
+}
+
+func TestWithFailureLogLines_CapsTail(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithFailureLogLines(100000)).(*DefaultReconciliationController)
+
+	if controller.failureLogLines != maxFailureLogLines {
+		t.Errorf("Expected the tail to be capped at %d, got %d", maxFailureLogLines, controller.failureLogLines)
+	}
+}
+
+func TestReconcile_OfflineModeUsesLocalImages(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.AddMockImage("nginx:1.25", &inspect.ImageData{ID: "sha256:0123456789abcdef0123456789abcdef"})
+	controller := NewReconciliationController(mockClient, WithOfflineMode())
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.CreatedResources) != 1 {
+		t.Fatalf("Expected the container to be created from the local image, got %s", result.Summary)
+	}
+	if calls := mockClient.GetCallCount("PullImage"); calls != 0 {
+		t.Errorf("Expected no pulls in offline mode, got %d", calls)
+	}
+
+	// Without the image present, nothing is pulled either
+	mockClient = podman.NewMockPodmanClient()
+	controller = NewReconciliationController(mockClient, WithOfflineMode())
+
+	result, err = controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) == 0 || !strings.Contains(result.Errors[0].Error(), "not present locally and pulling is disabled") {
+		t.Errorf("Expected the missing image to be reported, got %+v", result.Errors)
+	}
+	if calls := mockClient.GetCallCount("PullImage"); calls != 0 {
+		t.Errorf("Expected no pulls in offline mode, got %d", calls)
+	}
+}
+
+func TestGetStatus_DoesNotContactPodman(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	refreshed, err := controller.RefreshStatus(ctx, "demo")
+	if err != nil {
+		t.Fatalf("RefreshStatus failed: %v", err)
+	}
+	if refreshed.ResourceCounts[string(ResourceTypeContainer)] != 1 {
+		t.Fatalf("Expected the live container count, got %v", refreshed.ResourceCounts)
+	}
+
+	for _, operation := range []string{"ListContainers", "ListNetworks", "ListVolumes", "ListSecrets", "ListPods"} {
+		mockClient.SetShouldFailOperation(operation, true)
+	}
+	mockClient.SetShouldFailConnect(true)
+
+	status, err := controller.GetStatus("demo")
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if status.Status != "healthy" || len(status.Errors) != 0 {
+		t.Errorf("Expected the cached healthy status, got %s with %v", status.Status, status.Errors)
+	}
+	if status.ResourceCounts[string(ResourceTypeContainer)] != 1 || status.ResourceCounts["created"] != 1 {
+		t.Errorf("Expected the cached counts, got %v", status.ResourceCounts)
+	}
+
+	refreshed, err = controller.RefreshStatus(ctx, "demo")
+	if err != nil {
+		t.Fatalf("RefreshStatus failed: %v", err)
+	}
+	if refreshed.Status != "degraded" {
+		t.Errorf("Expected a failed refresh to be degraded, got %s", refreshed.Status)
+	}
+	if status, _ := controller.GetStatus("demo"); status.Status != "healthy" {
+		t.Errorf("Expected a failed refresh not to be saved, got %s", status.Status)
+	}
+}
+
+func TestAssertOwnership(t *testing.T) {
+	owned := newExplainTestContainer("nginx:1.25")
+	if err := assertOwnership(owned, "demo", ""); err != nil {
+		t.Errorf("Expected the container to be owned by demo, got %v", err)
+	}
+
+	if err := assertOwnership(owned, "other", ""); err == nil {
+		t.Error("Expected a chart mismatch to be rejected")
+	}
+
+	unmanaged := newExplainTestContainer("nginx:1.25")
+	unmanaged.SetLabels(map[string]string{labels.LabelChart: "demo"})
+	if err := assertOwnership(unmanaged, "demo", ""); err == nil {
+		t.Error("Expected a resource without the managed-by label to be rejected")
+	}
+}
+
+func TestExecuteDelete_RefusesForeignResource(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient).(*DefaultReconciliationController)
+	ctx := context.Background()
+
+	// A container with the same name that belongs to another chart
+	foreign := newExplainTestContainer("nginx:1.25")
+	foreign.SetLabels(labels.GetStandardLabels("other", "1.0.0"))
+	if err := controller.managers[ResourceTypeContainer].CreateResource(ctx, foreign); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	result := &ReconciliationResult{ChartName: "demo"}
+	controller.executeDeleteWithRetry(ctx, result, foreign, 0)
+
+	if mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Error("Expected the foreign container not to be removed")
+	}
+	if len(result.DeletedResources) != 1 || result.DeletedResources[0].Error == "" {
+		t.Fatalf("Expected a failed delete action, got %+v", result.DeletedResources)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeOwnership || result.Errors[0].Recoverable {
+		t.Errorf("Expected a non-recoverable ownership error, got %+v", result.Errors)
+	}
+}
+
+// newDeleteProtectTestManifests returns a protected volume and an unprotected secret
+func newDeleteProtectTestManifests() []Resource {
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{}
+	volume.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	volume.SetAnnotations(map[string]string{labels.AnnotationDeleteProtect: "true"})
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "token"
+	secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	secret.SetData(map[string][]byte{"token": []byte("s3cret")})
+	return []Resource{volume, secret}
+}
+
+func TestReconcile_KeepsOrphanedDeleteProtectedVolume(t *testing.T) {
+	for _, force := range []bool{false, true} {
+		mockClient := podman.NewMockPodmanClient()
+		var opts []ControllerOption
+		if force {
+			opts = append(opts, WithForceDelete())
+		}
+		controller := NewReconciliationController(mockClient, opts...)
+		ctx := context.Background()
+
+		if _, err := controller.Reconcile(ctx, newDeleteProtectTestManifests(), "demo", false); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+
+		// Neither is in the manifests anymore, so both are orphaned
+		result, err := controller.Reconcile(ctx, nil, "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+
+		if mockClient.GetCallCount("RemoveSecret") != 1 {
+			t.Errorf("Expected the unprotected secret to be deleted (force=%v)", force)
+		}
+		if len(result.DeletedResources) != 2 || len(result.Errors) != 0 {
+			t.Fatalf("Expected 2 delete actions and no errors, got %+v and %+v", result.DeletedResources, result.Errors)
+		}
+		volumeIndex := slices.IndexFunc(result.DeletedResources, func(action ResourceAction) bool {
+			return action.Type == ResourceTypeVolume
+		})
+		if volumeIndex < 0 {
+			t.Fatalf("Expected a delete action for the volume, got %+v", result.DeletedResources)
+		}
+		volumeAction := result.DeletedResources[volumeIndex]
+		if force {
+			if mockClient.GetCallCount("RemoveVolume") != 1 || volumeAction.Action != ActionDelete {
+				t.Errorf("Expected WithForceDelete to delete the protected volume, got %+v", volumeAction)
+			}
+			continue
+		}
+		if mockClient.GetCallCount("RemoveVolume") != 0 {
+			t.Error("Expected the protected volume to survive the orphan deletion")
+		}
+		if volumeAction.Action != ActionSkip || !strings.Contains(volumeAction.Message, labels.AnnotationDeleteProtect) {
+			t.Errorf("Expected a skip action naming the annotation, got %+v", volumeAction)
+		}
+	}
+}
+
+func TestReconcile_WarnsWhenProtectingExistingVolume(t *testing.T) {
+	logger := &recordingLogger{}
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithLogger(logger))
+	ctx := context.Background()
+
+	manifests := newDeleteProtectTestManifests()
+	manifests[0].(*VolumeResource).SetAnnotations(nil)
+	if _, err := controller.Reconcile(ctx, manifests, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if slices.ContainsFunc(logger.records, func(record string) bool { return strings.HasPrefix(record, "WARN delete protection") }) {
+		t.Fatalf("Expected no warning for an unprotected volume, got %v", logger.records)
+	}
+
+	// The existing volume has no protection label, so it would still be deleted
+	if _, err := controller.Reconcile(ctx, newDeleteProtectTestManifests(), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if !slices.ContainsFunc(logger.records, func(record string) bool { return strings.HasPrefix(record, "WARN delete protection") }) {
+		t.Errorf("Expected a warning that the volume is not protected, got %v", logger.records)
+	}
+}
+
+func TestValidateManifests_MissingVolumeReference(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient()).(*DefaultReconciliationController)
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{
+		{Name: "data", MountPath: "/data"},
+		{Name: "cache", MountPath: "/cache"},
+	}
+
+	err := controller.validateManifests([]Resource{volume, container})
+	if err == nil {
+		t.Fatal("Expected an error for the missing volume")
+	}
+	if !strings.Contains(err.Error(), "container 'web' references missing volume 'cache'") {
+		t.Errorf("Expected the missing volume to be named, got %v", err)
+	}
+	if strings.Contains(err.Error(), "'data'") {
+		t.Errorf("Expected the declared volume not to be reported, got %v", err)
+	}
+}
+
+func TestValidateManifests_HostPortConflict(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient()).(*DefaultReconciliationController)
+
+	web := newExplainTestContainer("nginx:1.25")
+	web.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080, Protocol: "TCP"}}
+	admin := newExplainTestContainer("nginx:1.25")
+	admin.ObjectMeta.Name = "admin"
+	admin.Spec.Ports = []ContainerPort{{ContainerPort: 8000, HostPort: 8080}}
+
+	err := controller.validateManifests([]Resource{web, admin})
+	if err == nil {
+		t.Fatal("Expected an error for the conflicting host port")
+	}
+	if !strings.Contains(err.Error(), "host port 8080/tcp is published by both container 'web' and container 'admin'") {
+		t.Errorf("Expected the port and both containers to be named, got %v", err)
+	}
+
+	// The same port over another protocol does not conflict
+	admin.Spec.Ports[0].Protocol = "udp"
+	if err := controller.validateManifests([]Resource{web, admin}); err != nil {
+		t.Errorf("Expected no conflict between TCP and UDP, got %v", err)
+	}
+}
+
+func TestValidateManifests_MissingNetworkReference(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "podman"}, {Name: "backnd"}}
+	container.Spec.Secrets = []SecretReference{{Name: "credentials", Env: true}}
+
+	result, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false)
+	if err == nil {
+		t.Fatal("Expected reconciliation to fail on the missing references")
+	}
+	for _, expected := range []string{
+		"container 'web' references missing network 'backnd'",
+		"container 'web' references missing secret 'credentials'",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %v", expected, err)
+		}
+	}
+	if strings.Contains(err.Error(), "'podman'") {
+		t.Errorf("Expected the default network not to be reported, got %v", err)
+	}
+	if len(result.CreatedResources) != 0 || mockClient.GetCallCount("CreateContainer") != 0 {
+		t.Error("Expected nothing to be created")
+	}
+}
+
+func TestReconcile_ExternalNetworkAttachment(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	// The network exists in Podman but is not part of any chart
+	if _, err := mockClient.CreateNetwork(ctx, podman.NetworkSpec{Name: "shared", Driver: "bridge"}); err != nil {
+		t.Fatalf("CreateNetwork failed: %v", err)
+	}
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "shared", External: true}}
+	if len(container.GetDependencies()) != 0 {
+		t.Errorf("Expected no dependency on the external network, got %+v", container.GetDependencies())
+	}
+
+	result, err := controller.Reconcile(ctx, []Resource{container}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.CreatedResources) != 1 {
+		t.Fatalf("Expected only the container to be created, got %+v (errors: %+v)", result.CreatedResources, result.Errors)
+	}
+
+	inspect, err := mockClient.InspectContainer(ctx, "web")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if _, attached := inspect.NetworkSettings.Networks["shared"]; !attached {
+		t.Errorf("Expected web to be attached to the external network, got %+v", inspect.NetworkSettings.Networks)
+	}
+
+	// The attachment reads back without drift, and the network is left alone
+	result, err = controller.Reconcile(ctx, []Resource{container}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources)+len(result.DeletedResources) != 0 {
+		t.Errorf("Expected nothing to change, got %+v", result)
+	}
+	if mockClient.GetCallCount("RemoveNetwork") != 0 {
+		t.Error("Expected the external network not to be removed")
+	}
+}
+
+func TestReconcile_ImagePrePull(t *testing.T) {
+	ctx := context.Background()
+	newContainers := func() []Resource {
+		web := newExplainTestContainer("nginx:1.25")
+		worker := newExplainTestContainer("nginx:1.25")
+		worker.ObjectMeta.Name = "worker"
+		cache := newExplainTestContainer("redis:7")
+		cache.ObjectMeta.Name = "cache"
+		return []Resource{web, worker, cache}
+	}
+
+	t.Run("pulls each image once", func(t *testing.T) {
+		mockClient := podman.NewMockPodmanClient()
+		controller := NewReconciliationController(mockClient, WithImagePrePull())
+
+		result, err := controller.Reconcile(ctx, newContainers(), "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.Errors) != 0 || len(result.CreatedResources) != 3 {
+			t.Fatalf("Expected all containers to be created, got %+v (errors: %+v)", result.CreatedResources, result.Errors)
+		}
+		if pulls := mockClient.GetCallCount("PullImage"); pulls != 2 {
+			t.Errorf("Expected 2 pulls, got %d", pulls)
+		}
+	})
+
+	t.Run("looks each present image up once", func(t *testing.T) {
+		mockClient := podman.NewMockPodmanClient()
+		for _, image := range []string{"nginx:1.25", "redis:7"} {
+			if err := mockClient.PullImage(ctx, image); err != nil {
+				t.Fatalf("PullImage failed: %v", err)
+			}
+		}
+		controller := NewReconciliationController(mockClient, WithImagePrePull())
+
+		result, err := controller.Reconcile(ctx, newContainers(), "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.Errors) != 0 || len(result.CreatedResources) != 3 {
+			t.Fatalf("Expected all containers to be created, got %+v (errors: %+v)", result.CreatedResources, result.Errors)
+		}
+		if lookups := mockClient.GetCallCount("GetImage"); lookups != 2 {
+			t.Errorf("Expected 2 image lookups, got %d", lookups)
+		}
+		if pulls := mockClient.GetCallCount("PullImage"); pulls != 2 {
+			t.Errorf("Expected no pulls beyond the 2 seeding ones, got %d", pulls)
+		}
+	})
+
+	t.Run("stops before changing anything when a pull fails", func(t *testing.T) {
+		mockClient := podman.NewMockPodmanClient()
+		mockClient.SetShouldFailOperation("PullImage", true)
+		controller := NewReconciliationController(mockClient, WithImagePrePull())
+
+		result, err := controller.Reconcile(ctx, newContainers(), "demo", false)
+		if err == nil {
+			t.Fatal("Expected the reconcile to fail")
+		}
+		if mockClient.GetCallCount("CreateContainer") != 0 {
+			t.Error("Expected no container to be created")
+		}
+
+		var failed []string
+		for _, recErr := range result.Errors {
+			if recErr.Type != ErrorTypeImagePull {
+				t.Errorf("Expected only image pull errors, got %+v", recErr)
+			}
+			failed = append(failed, recErr.Resource.Name)
+		}
+		if !reflect.DeepEqual(failed, []string{"cache", "web", "worker"}) {
+			t.Errorf("Expected an error per container, got %v", failed)
+		}
+	})
+}
+
+// orderRecordingClient records the container and volume operations in the order they run
+type orderRecordingClient struct {
+	*podman.MockPodmanClient
+	operations []string
+}
+
+func (c *orderRecordingClient) StopContainer(ctx context.Context, name string, timeout uint) error {
+	c.operations = append(c.operations, "stop "+name)
+	return c.MockPodmanClient.StopContainer(ctx, name, timeout)
+}
+
+func (c *orderRecordingClient) StartContainer(ctx context.Context, id string) error {
+	c.operations = append(c.operations, "start "+id)
+	return c.MockPodmanClient.StartContainer(ctx, id)
+}
+
+func (c *orderRecordingClient) RemoveVolume(ctx context.Context, name string) error {
+	c.operations = append(c.operations, "remove volume "+name)
+	return c.MockPodmanClient.RemoveVolume(ctx, name)
+}
+
+func (c *orderRecordingClient) CreateVolume(ctx context.Context, spec podman.VolumeSpec) (*podman.VolumeInfo, error) {
+	c.operations = append(c.operations, "create volume "+spec.Name)
+	return c.MockPodmanClient.CreateVolume(ctx, spec)
+}
+
+func TestReconcile_UpdatingVolumeDrainsDependentContainer(t *testing.T) {
+	ctx := context.Background()
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{Driver: "local"}
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/data"}}
+
+	registry := NewManifestRegistry()
+	if err := registry.AddResource(volume); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	client := &orderRecordingClient{MockPodmanClient: podman.NewMockPodmanClient()}
+	controller := NewReconciliationControllerWithRegistry(client, registry)
+
+	if _, err := controller.Reconcile(ctx, []Resource{container, volume}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	client.operations = nil
+
+	// Changing the volume's options replaces it
+	volume.Spec.Volume.Options = map[string]string{"o": "size=1g"}
+	result, err := controller.Reconcile(ctx, []Resource{container, volume}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected only the volume to be updated, got %+v (errors: %+v)", result.UpdatedResources, result.Errors)
+	}
+
+	expected := []string{"stop web", "remove volume data", "create volume data", "start web"}
+	if !reflect.DeepEqual(client.operations, expected) {
+		t.Errorf("Expected operations %v, got %v", expected, client.operations)
+	}
+}
+
+func TestReplacedByUpdate(t *testing.T) {
+	newSecret := func(token string) *SecretResource {
+		secret := NewSecretResource()
+		secret.ObjectMeta.Name = "creds"
+		secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte(token))}
+		return secret
+	}
+	actualSecret := NewSecretResource()
+	actualSecret.ObjectMeta.Name = "creds"
+	decodedData, err := newSecret("v1").ResolveData()
+	if err != nil {
+		t.Fatalf("ResolveData failed: %v", err)
+	}
+	actualSecret.SetAnnotations(map[string]string{labels.LabelSecretHash: newSecretDataHash(decodedData)})
+
+	named := NewVolumeResource()
+	named.Spec.Type = VolumeTypeVolume
+	hostPath := NewVolumeResource()
+	hostPath.Spec.Type = VolumeTypeHostPath
+	emptyDir := NewVolumeResource()
+	emptyDir.Spec.Type = VolumeTypeEmptyDir
+
+	for name, test := range map[string]struct {
+		pair     ResourcePair
+		expected bool
+	}{
+		"rotated secret":   {ResourcePair{Desired: newSecret("v2"), Actual: actualSecret}, true},
+		"unchanged secret": {ResourcePair{Desired: newSecret("v1"), Actual: actualSecret}, false},
+		"network":          {ResourcePair{Desired: NewNetworkResource(), Actual: NewNetworkResource()}, true},
+		"named volume":     {ResourcePair{Desired: named, Actual: named}, true},
+		"hostPath volume":  {ResourcePair{Desired: hostPath, Actual: hostPath}, false},
+		"emptyDir volume":  {ResourcePair{Desired: emptyDir, Actual: emptyDir}, false},
+	} {
+		if replaced := replacedByUpdate(test.pair); replaced != test.expected {
+			t.Errorf("%s: expected replaced to be %v, got %v", name, test.expected, replaced)
+		}
+	}
+}
+
+func TestUpdatesInDependencyOrder(t *testing.T) {
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/data"}}
+
+	ordered := updatesInDependencyOrder(
+		[]ResourcePair{{Desired: container}, {Desired: volume}},
+		[][]Resource{{volume}, {container}},
+	)
+	if ordered[0].Desired != volume || ordered[1].Desired != container {
+		t.Errorf("Expected the volume to be updated before the container, got %s then %s",
+			ordered[0].Desired.GetName(), ordered[1].Desired.GetName())
+	}
+}
+
+func TestReconcile_AutoRemovedContainerIsNotRecreated(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	enabled := true
+	job := newExplainTestContainer("busybox:1.36")
+	job.ObjectMeta.Name = "job"
+	job.Spec.RestartPolicy = "no"
+	job.Spec.Init = &enabled
+	job.Spec.AutoRemove = &enabled
+
+	if _, err := controller.Reconcile(ctx, []Resource{job}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	inspect, err := mockClient.InspectContainer(ctx, "job")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if !inspect.HostConfig.Init || !inspect.HostConfig.AutoRemove {
+		t.Errorf("Expected the container to run an init process and be removed on exit, got %+v", inspect.HostConfig)
+	}
+
+	// While it runs, it reads back without drift
+	result, err := controller.Reconcile(ctx, []Resource{job}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources) != 0 {
+		t.Fatalf("Expected nothing to change, got %+v", result)
+	}
+
+	// Once it has exited and removed itself, it is not run again
+	if err := mockClient.RemoveContainer(ctx, "job"); err != nil {
+		t.Fatalf("RemoveContainer failed: %v", err)
+	}
+	result, err = controller.Reconcile(ctx, []Resource{job}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 0 {
+		t.Errorf("Expected the removed container not to be recreated, got %+v", result.CreatedResources)
+	}
+
+	// A changed spec runs it again
+	job.Spec.Args = []string{"--verbose"}
+	result, err = controller.Reconcile(ctx, []Resource{job}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 1 {
+		t.Errorf("Expected the changed container to run again, got %+v", result.CreatedResources)
+	}
+}
+
+func TestReconcile_InteractiveContainerFlags(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	enabled := true
+	toolbox := newExplainTestContainer("fedora-toolbox:40")
+	toolbox.ObjectMeta.Name = "toolbox"
+	toolbox.Spec.Stdin = &enabled
+	toolbox.Spec.StdinOnce = &enabled
+	toolbox.Spec.TTY = &enabled
+
+	if _, err := controller.Reconcile(ctx, []Resource{toolbox}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	inspect, err := mockClient.InspectContainer(ctx, "toolbox")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if !inspect.Config.OpenStdin || !inspect.Config.Tty {
+		t.Errorf("Expected the container to keep stdin open with a terminal, got %+v", inspect.Config)
+	}
+
+	// stdinOnce is not reported by Podman, which must not read as drift
+	result, err := controller.Reconcile(ctx, []Resource{toolbox}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources) != 0 {
+		t.Fatalf("Expected nothing to change, got %+v", result)
+	}
+
+	// Dropping the terminal recreates the container
+	toolbox.Spec.TTY = nil
+	result, err = controller.Reconcile(ctx, []Resource{toolbox}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected the container to be updated, got %+v", result)
+	}
+	if inspect, _ := mockClient.InspectContainer(ctx, "toolbox"); inspect.Config.Tty {
+		t.Errorf("Expected the recreated container to have no terminal")
+	}
+
+	// stdinOnce has no effect without stdin
+	toolbox.Spec.Stdin = nil
+	yml := `
+apiVersion: v1
+kind: CuteContainer
+metadata:
+  name: toolbox
+spec:
+  image: fedora-toolbox:40
+  stdinOnce: true
+`
+	if errs := toolbox.Validate(yml); len(errs) != 1 || !strings.Contains(errs[0].Error(), "stdinOnce requires stdin") {
+		t.Errorf("Expected stdinOnce without stdin to be rejected, got %v", errs)
+	}
+}
+
+func TestValidateManifests_NamespaceTargetReference(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient()).(*DefaultReconciliationController)
+
+	monitor := newExplainTestContainer("nginx:1.25")
+	monitor.ObjectMeta.Name = "monitor"
+	monitor.Spec.PidMode = "container:app"
+
+	err := controller.validateManifests([]Resource{monitor})
+	if err == nil || !strings.Contains(err.Error(), "container 'monitor' references missing container 'app'") {
+		t.Errorf("Expected the missing namespace target to be reported, got %v", err)
+	}
+
+	// The target may live outside the chart when marked external
+	monitor.Spec.ExternalNamespaces = true
+	if err := controller.validateManifests([]Resource{monitor}); err != nil {
+		t.Errorf("Expected an external namespace target to be accepted, got %v", err)
+	}
+	if len(monitor.GetDependencies()) != 0 {
+		t.Errorf("Expected no dependency on the external container, got %+v", monitor.GetDependencies())
+	}
+}
+
+func TestValidateManifests_HostPathOutsideAllowedPrefixes(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient(),
+		WithHostPathPrefixes("/srv", "/data")).(*DefaultReconciliationController)
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "logs"
+	volume.Spec.Type = VolumeTypeHostPath
+	volume.Spec.HostPath = &HostPathVolumeSource{Path: "/srv/app/logs"}
+	if err := controller.validateManifests([]Resource{volume}); err != nil {
+		t.Fatalf("Expected a hostPath under /srv to be allowed, got %v", err)
+	}
+
+	volume.Spec.HostPath.Path = "/etc/app"
+	err := controller.validateManifests([]Resource{volume})
+	if err == nil || !strings.Contains(err.Error(), "volume 'logs': hostPath /etc/app is not within allowed prefixes") {
+		t.Errorf("Expected the hostPath to be rejected, got %v", err)
+	}
+
+	// The managers enforce the same allow-list when they resolve paths themselves
+	for _, resourceType := range []ResourceType{ResourceTypeContainer, ResourceTypeVolume} {
+		var pathManager *VolumePathManager
+		switch manager := controller.managers[resourceType].(type) {
+		case *ContainerManager:
+			pathManager = manager.pathManager
+		case *VolumeManager:
+			pathManager = manager.pathManager
+		}
+		if _, err := pathManager.ResolveVolumePath(volume, &VolumeMount{Name: "logs"}); err == nil {
+			t.Errorf("Expected the %s manager to reject the hostPath", resourceType)
+		}
+	}
+}
+
+func TestNewReconciliationController_SharesVolumeBaseDir(t *testing.T) {
+	baseDir := filepath.Join(t.TempDir(), "volumes")
+	controller := NewReconciliationController(podman.NewMockPodmanClient(),
+		WithVolumeBaseDir(baseDir)).(*DefaultReconciliationController)
+
+	containerManager := controller.managers[ResourceTypeContainer].(*ContainerManager)
+	volumeManager := controller.managers[ResourceTypeVolume].(*VolumeManager)
+	if containerManager.pathManager != volumeManager.pathManager {
+		t.Fatal("Expected the container and volume managers to share a path manager")
+	}
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "scratch"
+	volume.Spec.Type = VolumeTypeEmptyDir
+	volume.Spec.EmptyDir = &EmptyDirVolumeSource{}
+
+	if err := volumeManager.CreateResource(context.Background(), volume); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+	pathInfo, err := containerManager.pathManager.ResolveVolumePath(volume, &VolumeMount{Name: "scratch"})
+	if err != nil {
+		t.Fatalf("ResolveVolumePath failed: %v", err)
+	}
+	if expected := filepath.Join(baseDir, "emptydir", "scratch"); pathInfo.SourcePath != expected {
+		t.Errorf("Expected the emptyDir under %s, got %s", expected, pathInfo.SourcePath)
+	}
+	if _, err := os.Stat(pathInfo.SourcePath); err != nil {
+		t.Errorf("Expected containers to mount the directory the volume manager created, got %v", err)
+	}
+}
+
+func TestReconcile_SharedEmptyDir(t *testing.T) {
+	ctx := context.Background()
+	registry := NewManifestRegistry()
+	controller := NewReconciliationControllerWithRegistry(podman.NewMockPodmanClient(), registry,
+		WithVolumeBaseDir(filepath.Join(t.TempDir(), "volumes"))).(*DefaultReconciliationController)
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "scratch"
+	volume.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	volume.Spec.Type = VolumeTypeEmptyDir
+	volume.Spec.EmptyDir = &EmptyDirVolumeSource{}
+
+	newContainer := func(name, mountPath string) *ContainerResource {
+		container := newExplainTestContainer("nginx:1.25")
+		container.ObjectMeta.Name = name
+		container.Spec.Volumes = []VolumeMount{{Name: "scratch", MountPath: mountPath}}
+		return container
+	}
+	writer := newContainer("writer", "/var/log/app")
+	reader := newContainer("reader", "/logs")
+	for _, resource := range []Resource{volume, writer, reader} {
+		if err := registry.AddResource(resource); err != nil {
+			t.Fatalf("AddResource failed: %v", err)
+		}
+	}
+
+	result, err := controller.Reconcile(ctx, []Resource{volume, writer, reader}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("Expected no reconciliation errors, got %v", result.Errors)
+	}
+
+	containerManager := controller.managers[ResourceTypeContainer].(*ContainerManager)
+	var sources []string
+	for _, container := range []*ContainerResource{writer, reader} {
+		spec, err := containerManager.buildContainerSpec(container)
+		if err != nil {
+			t.Fatalf("buildContainerSpec failed: %v", err)
+		}
+		if spec.Labels[labels.LabelEmptyDirs] != "scratch" {
+			t.Errorf("Expected %s to be labelled with the emptyDir it mounts, got %q", container.GetName(), spec.Labels[labels.LabelEmptyDirs])
+		}
+		sources = append(sources, spec.Mounts[0].Source)
+	}
+	if sources[0] != sources[1] {
+		t.Fatalf("Expected both containers to bind the same directory, got %v", sources)
+	}
+	if _, err := os.Stat(sources[0]); err != nil {
+		t.Fatalf("Expected the shared emptyDir to exist, got %v", err)
+	}
+
+	// Deleting the volume, as an update of its spec does, keeps the directory while any
+	// container still mounts it
+	volumeManager := controller.managers[ResourceTypeVolume].(*VolumeManager)
+	for _, container := range []*ContainerResource{writer, reader} {
+		if err := volumeManager.DeleteResource(ctx, volume); err != nil {
+			t.Fatalf("DeleteResource failed: %v", err)
+		}
+		if _, err := os.Stat(sources[0]); err != nil {
+			t.Fatalf("Expected the emptyDir to be kept while %s mounts it, got %v", container.GetName(), err)
+		}
+		if err := containerManager.DeleteResource(ctx, container); err != nil {
+			t.Fatalf("DeleteResource failed: %v", err)
+		}
+	}
+
+	if err := volumeManager.DeleteResource(ctx, volume); err != nil {
+		t.Fatalf("DeleteResource failed: %v", err)
+	}
+	if _, err := os.Stat(sources[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the emptyDir to be removed once no container mounts it, got %v", err)
+	}
+}
+
+func TestValidateManifests_RejectsRelativeVolumeBaseDir(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient(),
+		WithVolumeBaseDir("volumes")).(*DefaultReconciliationController)
+
+	err := controller.validateManifests([]Resource{newExplainTestContainer("nginx:1.25")})
+	if err == nil || !strings.Contains(err.Error(), "must be an absolute path") {
+		t.Errorf("Expected the relative base directory to be rejected, got %v", err)
+	}
+}
+
+func TestReconcile_FailsFastWithoutPodmanSocket(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailConnect(true)
+	controller := NewReconciliationController(mockClient)
+
+	result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+	if err == nil {
+		t.Fatal("Expected reconciliation to fail without a Podman socket")
+	}
+	if !strings.Contains(err.Error(), "Podman socket not found at unix:///run/podman/podman.sock; is Podman running?") {
+		t.Errorf("Expected an actionable error, got %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Recoverable {
+		t.Errorf("Expected a single non-recoverable error, got %+v", result.Errors)
+	}
+	if calls := mockClient.GetCallCount("Connect"); calls != 1 {
+		t.Errorf("Expected no retries, got %d connection attempts", calls)
+	}
+}
+
+func TestNewReconciliationControllerWithURI_UsesURI(t *testing.T) {
+	const uri = "ssh://core@10.0.0.5/run/podman/podman.sock"
+
+	controllers := map[string]ReconciliationController{
+		"without registry": NewReconciliationControllerWithURI(uri),
+		"with registry":    NewReconciliationControllerWithURIAndRegistry(uri, NewManifestRegistry()),
+	}
+	for name, controller := range controllers {
+		if got := controller.(*DefaultReconciliationController).podmanURI(); got != uri {
+			t.Errorf("%s: expected the controller to connect to %s, got %s", name, uri, got)
+		}
+	}
+}
+
+// blockingResourceManager blocks creating one resource until its context expires, like
+// a hung Podman call would
+type blockingResourceManager struct {
+	ResourceManager
+	blocked string
+}
+
+func (m *blockingResourceManager) CreateResource(ctx context.Context, resource Resource) error {
+	if resource.GetName() == m.blocked {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return m.ResourceManager.CreateResource(ctx, resource)
+}
+
+func TestReconcile_ResourceTimeoutMovesOnFromHungResource(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithResourceTimeout(50*time.Millisecond)).(*DefaultReconciliationController)
+
+	controller.managers[ResourceTypeNetwork] = &blockingResourceManager{
+		ResourceManager: controller.managers[ResourceTypeNetwork],
+		blocked:         "hung",
+	}
+
+	hung := NewNetworkResource()
+	hung.ObjectMeta.Name = "hung"
+	backend := NewNetworkResource()
+	backend.ObjectMeta.Name = "backend"
+
+	result, err := controller.Reconcile(context.Background(), []Resource{hung, backend}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	actions := make(map[string]ResourceAction, len(result.CreatedResources))
+	for _, action := range result.CreatedResources {
+		actions[action.Name] = action
+	}
+	if !strings.Contains(actions["hung"].Error, "timed out after 50ms") {
+		t.Errorf("Expected the hung network to time out, got %+v", actions["hung"])
+	}
+	if actions["backend"].Error != "" {
+		t.Errorf("Expected the other network to be created, got %+v", actions["backend"])
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeTimeout || !result.Errors[0].Recoverable {
+		t.Errorf("Expected a single recoverable timeout error, got %+v", result.Errors)
+	}
+}
+
+// slowResourceManager takes delay to create a resource, whatever its context
+type slowResourceManager struct {
+	ResourceManager
+	delay time.Duration
+}
+
+func (m *slowResourceManager) CreateResource(ctx context.Context, resource Resource) error {
+	time.Sleep(m.delay)
+	return m.ResourceManager.CreateResource(ctx, resource)
+}
+
+func TestReconcile_ResourceTimeoutWaitsForRunningOperation(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithResourceTimeout(20*time.Millisecond)).(*DefaultReconciliationController)
+	controller.managers[ResourceTypeNetwork] = &slowResourceManager{
+		ResourceManager: controller.managers[ResourceTypeNetwork],
+		delay:           100 * time.Millisecond,
+	}
+
+	backend := NewNetworkResource()
+	backend.ObjectMeta.Name = "backend"
+
+	result, err := controller.Reconcile(context.Background(), []Resource{backend}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 1 || result.CreatedResources[0].Error != "" {
+		t.Errorf("Expected the network to be reported as created once its operation returned, got %+v", result.CreatedResources)
+	}
+	if len(result.Errors) != 0 {
+		t.Errorf("Expected no errors, got %+v", result.Errors)
+	}
+}
+
+func TestReconcile_RestartsManuallyStoppedContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	web := newExplainTestContainer("nginx:1.25")
+	job := newExplainTestContainer("busybox:1.36")
+	job.ObjectMeta.Name = "job"
+	job.Spec.RestartPolicy = "no"
+	manifests := []Resource{web, job}
+
+	if _, err := controller.Reconcile(ctx, manifests, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	for _, name := range []string{"web", "job"} {
+		if err := mockClient.StopContainer(ctx, name, 0); err != nil {
+			t.Fatalf("StopContainer failed: %v", err)
+		}
+	}
+
+	result, err := controller.Reconcile(ctx, manifests, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if len(result.UpdatedResources) != 1 || result.UpdatedResources[0].Name != "web" {
+		t.Fatalf("Expected only the always-restarted container to be updated, got %+v", result.UpdatedResources)
+	}
+	if calls := mockClient.GetCallCount("CreateContainer"); calls != 2 {
+		t.Errorf("Expected the container to be started rather than recreated, got %d creates", calls)
+	}
+
+	running, err := mockClient.ListContainers(ctx, nil, false)
+	if err != nil {
+		t.Fatalf("ListContainers failed: %v", err)
+	}
+	if len(running) != 1 || running[0].Names[0] != "web" {
+		t.Errorf("Expected only web to be running again, got %+v", running)
+	}
+}
+
+func TestReconcile_UpdatesRestartPolicyInPlace(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	repolicied := newExplainTestContainer("nginx:1.25")
+	repolicied.Spec.RestartPolicy = "on-failure"
+	result, err := controller.Reconcile(ctx, []Resource{repolicied}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 || len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected the container to be updated, got %s", result.Summary)
+	}
+
+	if calls := mockClient.GetCallCount("RemoveContainer"); calls != 0 {
+		t.Errorf("Expected no container to be recreated, got %d removals", calls)
+	}
+	if calls := mockClient.GetCallCount("UpdateContainer"); calls != 1 {
+		t.Errorf("Expected the restart policy to be updated in place, got %d updates", calls)
+	}
+
+	inspect, err := mockClient.InspectContainer(ctx, "web")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if inspect.HostConfig.RestartPolicy.Name != "on-failure" {
+		t.Errorf("Expected the new restart policy, got %q", inspect.HostConfig.RestartPolicy.Name)
+	}
+
+	result, err = controller.Reconcile(ctx, []Resource{repolicied}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.UpdatedResources) != 0 {
+		t.Errorf("Expected the next reconcile to change nothing, got %s", result.Summary)
+	}
+}
+
+func TestSpecHashMatches_AcceptsHashIncludingRestartPolicy(t *testing.T) {
+	spec := newExplainTestContainer("nginx:1.25").Spec
+
+	// Hashes from before the restart policy was left out hashed the spec as is
+	normalized, err := normalizeContainerSpec(spec)
+	if err != nil {
+		t.Fatalf("normalizeContainerSpec failed: %v", err)
+	}
+	legacyHash, err := hashContainerSpec(normalized)
+	if err != nil {
+		t.Fatalf("hashContainerSpec failed: %v", err)
+	}
+
+	if matches, err := specHashMatches(spec, legacyHash); err != nil || !matches {
+		t.Errorf("Expected the hash including the restart policy to match, got %v (err: %v)", matches, err)
+	}
+
+	spec.Image = "nginx:1.26"
+	if matches, err := specHashMatches(spec, legacyHash); err != nil || matches {
+		t.Errorf("Expected a changed spec not to match, got %v (err: %v)", matches, err)
+	}
+}
+
+func TestReconcile_ReportsCreationAndDeletionLevels(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+
+	result, err := controller.Reconcile(context.Background(), []Resource{container, network}, "demo", true)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	networkRef := ResourceReference{Type: ResourceTypeNetwork, Name: "backend"}
+	containerRef := ResourceReference{Type: ResourceTypeContainer, Name: "web"}
+	expectedCreation := [][]ResourceReference{{networkRef}, {containerRef}}
+	if !reflect.DeepEqual(result.CreationLevels, expectedCreation) {
+		t.Errorf("Expected creation levels %v, got %v", expectedCreation, result.CreationLevels)
+	}
+	expectedDeletion := [][]ResourceReference{{containerRef}, {networkRef}}
+	if !reflect.DeepEqual(result.DeletionLevels, expectedDeletion) {
+		t.Errorf("Expected deletion levels %v, got %v", expectedDeletion, result.DeletionLevels)
+	}
+
+	planned, err := controller.Plan(context.Background(), []Resource{container, network}, "demo")
+	if err != nil {
+		t.Fatalf("Plan failed: %v", err)
+	}
+	if !reflect.DeepEqual(planned.CreationLevels, expectedCreation) {
+		t.Errorf("Expected Plan to report creation levels %v, got %v", expectedCreation, planned.CreationLevels)
+	}
+}
+
+func TestReconcile_RetriesOnlyTransientFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   string
+		err         error
+		calls       int
+		recoverable bool
+	}{
+		{name: "image not found", operation: "PullImage", err: errors.New("reading manifest 1.25 in docker.io/library/nginx: manifest unknown"), calls: 1},
+		{name: "invalid port", operation: "CreateContainer", err: errors.New("invalid config provided: invalid port number 70000"), calls: 1},
+		{name: "connection reset", operation: "CreateContainer", err: errors.New("read: connection reset by peer"), calls: 3, recoverable: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := podman.NewMockPodmanClient()
+			mockClient.SetOperationError(tt.operation, tt.err)
+			controller := NewReconciliationController(mockClient)
+
+			result, err := controller.Reconcile(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false)
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			if mockClient.GetCallCount(tt.operation) != tt.calls {
+				t.Errorf("Expected %d %s calls, got %d", tt.calls, tt.operation, mockClient.GetCallCount(tt.operation))
+			}
+			if len(result.Errors) != 1 || result.Errors[0].Recoverable != tt.recoverable {
+				t.Errorf("Expected a single error with recoverable=%v, got %+v", tt.recoverable, result.Errors)
+			}
+		})
+	}
+}
+
+func TestReconcile_UntypedSecretIsNotUpdatedAgain(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	// No type, which Podman reads back as opaque
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "creds"
+	secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte("v1"))}
+	secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Secrets = []SecretReference{{Name: "creds", Env: true}}
+
+	if _, err := controller.Reconcile(ctx, []Resource{secret, container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	for range 2 {
+		result, err := controller.Reconcile(ctx, []Resource{secret, container}, "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.UpdatedResources) != 0 {
+			t.Fatalf("Expected nothing to change, got %+v", result.UpdatedResources)
+		}
+	}
+	if calls := mockClient.GetCallCount("StopContainer"); calls != 0 {
+		t.Errorf("Expected the container to keep running, got %d stops", calls)
+	}
+}
+
+func TestReconcile_SecretRotationRecreatesDependentContainers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	newSecret := func(token string) *SecretResource {
+		secret := NewSecretResource()
+		secret.ObjectMeta.Name = "creds"
+		secret.Spec.Type = SecretTypeOpaque
+		secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte(token))}
+		secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+		return secret
+	}
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Secrets = []SecretReference{{Name: "creds", Env: true}}
+	other := newComponentContainer("api", "nginx:1.25", "backend")
+
+	if _, err := controller.Reconcile(ctx, []Resource{newSecret("v1"), container, other}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	created := mockClient.GetCallCount("CreateContainer")
+
+	// Reconciling the same data leaves the secret and its container alone
+	result, err := controller.Reconcile(ctx, []Resource{newSecret("v1"), container, other}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.UpdatedResources) != 0 || mockClient.GetCallCount("RemoveContainer") != 0 {
+		t.Fatalf("Expected nothing to change, got %+v", result.UpdatedResources)
+	}
+
+	result, err = controller.Reconcile(ctx, []Resource{newSecret("v2"), container, other}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	// The secret is rotated first, then only the container using it is recreated
+	if len(result.UpdatedResources) != 2 {
+		t.Fatalf("Expected the secret and one container to be updated, got %+v", result.UpdatedResources)
+	}
+	if result.UpdatedResources[0].Type != ResourceTypeSecret || result.UpdatedResources[1].Name != "web" {
+		t.Errorf("Expected the secret to be rotated before web is recreated, got %+v", result.UpdatedResources)
+	}
+	if mockClient.GetCallCount("RemoveContainer") != 1 || mockClient.GetCallCount("CreateContainer") != created+1 {
+		t.Errorf("Expected web to be removed and created again, got %d removals and %d creations",
+			mockClient.GetCallCount("RemoveContainer"), mockClient.GetCallCount("CreateContainer")-created)
+	}
+}
+
+// rejectingNetworkClient fails every network creation with an error retrying cannot fix
+type rejectingNetworkClient struct {
+	*podman.MockPodmanClient
+}
+
+func (c *rejectingNetworkClient) CreateNetwork(ctx context.Context, spec podman.NetworkSpec) (*podman.NetworkInfo, error) {
+	return nil, errors.New("invalid argument: subnet overlaps")
+}
+
+func TestReconcile_BestEffort(t *testing.T) {
+	ctx := context.Background()
+	newManifests := func() []Resource {
+		network := NewNetworkResource()
+		network.ObjectMeta.Name = "backend"
+		network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+
+		web := newExplainTestContainer("nginx:1.25")
+		web.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+		cache := newExplainTestContainer("redis:7")
+		cache.ObjectMeta.Name = "cache"
+		return []Resource{network, web, cache}
+	}
+	names := func(actions []ResourceAction) []string {
+		var names []string
+		for _, action := range actions {
+			names = append(names, action.Name)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	t.Run("skips dependents of a failed resource", func(t *testing.T) {
+		client := &rejectingNetworkClient{MockPodmanClient: podman.NewMockPodmanClient()}
+		controller := NewReconciliationController(client, WithBestEffort())
+
+		result, err := controller.Reconcile(ctx, newManifests(), "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if failed := names(result.Failed()); !reflect.DeepEqual(failed, []string{"backend"}) {
+			t.Errorf("Expected the network to fail, got %v", failed)
+		}
+		if skipped := names(result.Skipped()); !reflect.DeepEqual(skipped, []string{"web"}) {
+			t.Errorf("Expected web to be skipped, got %v", skipped)
+		}
+		if succeeded := names(result.Succeeded()); !reflect.DeepEqual(succeeded, []string{"cache"}) {
+			t.Errorf("Expected cache to be created, got %v", succeeded)
+		}
+		if client.GetCallCount("CreateContainer") != 1 {
+			t.Errorf("Expected only cache to be created, got %d creations", client.GetCallCount("CreateContainer"))
+		}
+		if !strings.Contains(result.Summary, "1 skipped") {
+			t.Errorf("Expected the summary to count the skip, got %q", result.Summary)
+		}
+	})
+
+	t.Run("attempts dependents without best effort", func(t *testing.T) {
+		client := &rejectingNetworkClient{MockPodmanClient: podman.NewMockPodmanClient()}
+		controller := NewReconciliationController(client)
+
+		result, err := controller.Reconcile(ctx, newManifests(), "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if len(result.Skipped()) != 0 || client.GetCallCount("CreateContainer") != 2 {
+			t.Errorf("Expected both containers to be attempted, got %d creations and skips %+v",
+				client.GetCallCount("CreateContainer"), result.Skipped())
+		}
+	})
+
+	t.Run("applies the rest when a pre-pull fails", func(t *testing.T) {
+		// Only the image of web is missing, and pulling it fails
+		mockClient := podman.NewMockPodmanClient()
+		if err := mockClient.PullImage(ctx, "redis:7"); err != nil {
+			t.Fatalf("PullImage failed: %v", err)
+		}
+		mockClient.SetShouldFailOperation("PullImage", true)
+		controller := NewReconciliationController(mockClient, WithImagePrePull(), WithBestEffort())
+
+		result, err := controller.Reconcile(ctx, newManifests(), "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if failed := names(result.Failed()); !reflect.DeepEqual(failed, []string{"web"}) {
+			t.Errorf("Expected web to fail, got %v", failed)
+		}
+		if succeeded := names(result.Succeeded()); !reflect.DeepEqual(succeeded, []string{"backend", "cache"}) {
+			t.Errorf("Expected the network and cache to be created, got %v", succeeded)
+		}
+	})
+}
+
+func TestReconcile_PausedResources(t *testing.T) {
+	ctx := context.Background()
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+
+	cache := newExplainTestContainer("redis:7")
+	cache.ObjectMeta.Name = "cache"
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25"), cache}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	created := mockClient.GetCallCount("CreateContainer")
+
+	paused := newExplainTestContainer("nginx:1.26")
+	paused.SetAnnotations(map[string]string{labels.AnnotationReconcile: "paused"})
+
+	t.Run("leaves a drifted paused container alone", func(t *testing.T) {
+		updatedCache := newExplainTestContainer("redis:7.2")
+		updatedCache.ObjectMeta.Name = "cache"
+
+		result, err := controller.Reconcile(ctx, []Resource{paused, updatedCache}, "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if mockClient.GetCallCount("CreateContainer") != created+1 {
+			t.Errorf("Expected only cache to be recreated, got %d creations", mockClient.GetCallCount("CreateContainer")-created)
+		}
+		skipped := result.Skipped()
+		if len(skipped) != 1 || skipped[0].Name != "web" || !strings.Contains(skipped[0].Message, "paused") {
+			t.Fatalf("Expected web to be skipped as paused, got %+v", skipped)
+		}
+		if len(skipped[0].Diffs) == 0 {
+			t.Error("Expected the skipped update to report its pending diffs")
+		}
+
+		status, err := controller.GetStatus("demo")
+		if err != nil || status.ResourceCounts["paused"] != 1 || status.ResourceCounts["updated"] != 1 {
+			t.Errorf("Expected 1 paused and 1 updated resource in status, got %+v (err: %v)", status, err)
+		}
+	})
+
+	t.Run("changes nothing when the whole chart is paused", func(t *testing.T) {
+		before := mockClient.GetCallCount("CreateContainer")
+
+		// cache is gone from the chart, but a paused chart keeps its orphans
+		result, err := controller.Reconcile(ctx, []Resource{paused}, "demo", false)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		if mockClient.GetCallCount("RemoveContainer") != 1 || mockClient.GetCallCount("CreateContainer") != before {
+			t.Errorf("Expected no container to be removed or created, got %d removals and %d creations",
+				mockClient.GetCallCount("RemoveContainer")-1, mockClient.GetCallCount("CreateContainer")-before)
+		}
+		if len(result.Succeeded()) != 0 || len(result.Skipped()) != 2 {
+			t.Errorf("Expected web and the orphaned cache to be skipped, got %+v", result.Skipped())
+		}
+	})
+}
+
+// resourceTypeDNSRecord is a resource type cutepod does not manage itself
+const resourceTypeDNSRecord ResourceType = "dnsRecord"
+
+// dnsRecordResource points a name at an address of a network
+type dnsRecordResource struct {
+	BaseResource
+	Network string
+	Address string
+}
+
+func newDNSRecord(name, network, address string) *dnsRecordResource {
+	record := &dnsRecordResource{Network: network, Address: address}
+	record.ResourceType = resourceTypeDNSRecord
+	record.ObjectMeta.Name = name
+	record.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	return record
+}
+
+func (r *dnsRecordResource) GetDependencies() []ResourceReference {
+	return []ResourceReference{{Type: ResourceTypeNetwork, Name: r.Network}}
+}
+
+// dnsRecordManager keeps DNS records in memory
+type dnsRecordManager struct {
+	records map[string]*dnsRecordResource
+}
+
+func (m *dnsRecordManager) GetDesiredState(manifests []Resource) ([]Resource, error) {
+	var desired []Resource
+	for _, manifest := range manifests {
+		if manifest.GetType() == resourceTypeDNSRecord {
+			desired = append(desired, manifest)
+		}
+	}
+	return desired, nil
+}
+
+func (m *dnsRecordManager) GetActualState(ctx context.Context, chartName string) ([]Resource, error) {
+	var actual []Resource
+	for _, record := range m.records {
+		if record.GetLabels()[labels.LabelChart] == chartName {
+			actual = append(actual, record)
+		}
+	}
+	return actual, nil
+}
+
+func (m *dnsRecordManager) CreateResource(ctx context.Context, resource Resource) error {
+	record := *resource.(*dnsRecordResource)
+	m.records[record.GetName()] = &record
+	return nil
+}
+
+func (m *dnsRecordManager) UpdateResource(ctx context.Context, desired, actual Resource) error {
+	return m.CreateResource(ctx, desired)
+}
+
+func (m *dnsRecordManager) DeleteResource(ctx context.Context, resource Resource) error {
+	delete(m.records, resource.GetName())
+	return nil
+}
+
+func (m *dnsRecordManager) CompareResources(desired, actual Resource) (bool, error) {
+	return desired.(*dnsRecordResource).Address == actual.(*dnsRecordResource).Address, nil
+}
+
+func (m *dnsRecordManager) GetResourceType() ResourceType {
+	return resourceTypeDNSRecord
+}
+
+func TestRegisterManager_ReconcilesCustomResourceType(t *testing.T) {
+	ctx := context.Background()
+	controller := NewReconciliationController(podman.NewMockPodmanClient()).(*DefaultReconciliationController)
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "backend"
+	network.Spec.Driver = "bridge"
+	network.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+
+	if _, err := controller.Reconcile(ctx, []Resource{network, newDNSRecord("api", "backend", "10.89.0.10")}, "demo", false); err == nil ||
+		!strings.Contains(err.Error(), "unsupported resource type: dnsRecord") {
+		t.Fatalf("Expected the unknown type to be rejected before it is registered, got %v", err)
+	}
+
+	manager := &dnsRecordManager{records: make(map[string]*dnsRecordResource)}
+	controller.RegisterManager(resourceTypeDNSRecord, manager)
+
+	result, err := controller.Reconcile(ctx, []Resource{network, newDNSRecord("api", "backend", "10.89.0.10")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.CreatedResources) != 2 || manager.records["api"] == nil {
+		t.Fatalf("Expected the network and the record to be created, got %+v", result.CreatedResources)
+	}
+	if result.CreatedResources[1].Type != resourceTypeDNSRecord {
+		t.Errorf("Expected the record to be created after the network it depends on, got %+v", result.CreatedResources)
+	}
+
+	// Unchanged records are left alone and changed ones are updated
+	result, err = controller.Reconcile(ctx, []Resource{network, newDNSRecord("api", "backend", "10.89.0.10")}, "demo", false)
+	if err != nil || len(result.CreatedResources)+len(result.UpdatedResources)+len(result.DeletedResources) != 0 {
+		t.Fatalf("Expected no changes, got %s (%v)", result.Summary, err)
+	}
+	result, err = controller.Reconcile(ctx, []Resource{network, newDNSRecord("api", "backend", "10.89.0.20")}, "demo", false)
+	if err != nil || len(result.UpdatedResources) != 1 || manager.records["api"].Address != "10.89.0.20" {
+		t.Fatalf("Expected the record to be updated, got %s (%v)", result.Summary, err)
+	}
+
+	// Records gone from the chart are deleted
+	result, err = controller.Reconcile(ctx, []Resource{network}, "demo", false)
+	if err != nil || len(result.DeletedResources) != 1 || len(manager.records) != 0 {
+		t.Fatalf("Expected the record to be deleted, got %s (%v)", result.Summary, err)
+	}
+}
@@ -2,15 +2,27 @@ package resource
 
 import (
 	"fmt"
+	"slices"
 	"sort"
 )
 
-// ManifestRegistry manages parsed resources and their dependencies
+// ManifestRegistry manages parsed resources and their dependencies. Resources and
+// dependencies are keyed by type-qualified name ("volume/data"), so resources of
+// different types may share a name.
 type ManifestRegistry struct {
 	Resources    map[string]Resource
 	Dependencies map[string][]string
 }
 
+// lookupOrder is the order in which GetResource tries types when a name is shared
+var lookupOrder = []ResourceType{
+	ResourceTypeNetwork,
+	ResourceTypeVolume,
+	ResourceTypeSecret,
+	ResourceTypePod,
+	ResourceTypeContainer,
+}
+
 // NewManifestRegistry creates a new empty registry
 func NewManifestRegistry() *ManifestRegistry {
 	return &ManifestRegistry{
@@ -19,6 +31,11 @@ func NewManifestRegistry() *ManifestRegistry {
 	}
 }
 
+// registryKey generates the type-qualified key of a resource
+func registryKey(resourceType ResourceType, name string) string {
+	return fmt.Sprintf("%s/%s", resourceType, name)
+}
+
 // AddResource adds a resource to the registry and builds its dependency graph
 func (r *ManifestRegistry) AddResource(resource Resource) error {
 	name := resource.GetName()
@@ -26,30 +43,43 @@ func (r *ManifestRegistry) AddResource(resource Resource) error {
 		return fmt.Errorf("resource name cannot be empty")
 	}
 
-	// Check for duplicate names
-	if _, exists := r.Resources[name]; exists {
-		return fmt.Errorf("resource with name '%s' already exists", name)
+	// Check for duplicate names within the resource type
+	key := registryKey(resource.GetType(), name)
+	if _, exists := r.Resources[key]; exists {
+		return fmt.Errorf("%s with name '%s' already exists", resource.GetType(), name)
 	}
 
 	// Add the resource
-	r.Resources[name] = resource
+	r.Resources[key] = resource
 
 	// Build dependency list
 	var deps []string
 	for _, dep := range resource.GetDependencies() {
-		deps = append(deps, dep.Name)
+		deps = append(deps, registryKey(dep.Type, dep.Name))
 	}
-	r.Dependencies[name] = deps
+	r.Dependencies[key] = deps
 
 	return nil
 }
 
-// GetResource retrieves a resource by name
-func (r *ManifestRegistry) GetResource(name string) (Resource, bool) {
-	resource, exists := r.Resources[name]
+// GetResourceByTypeName retrieves a resource by type and name
+func (r *ManifestRegistry) GetResourceByTypeName(resourceType ResourceType, name string) (Resource, bool) {
+	resource, exists := r.Resources[registryKey(resourceType, name)]
 	return resource, exists
 }
 
+// GetResource retrieves a resource by name. The preferred types are tried first; when
+// none matches, any resource with the name is returned, trying types in lookupOrder.
+// Callers that know the type should use GetResourceByTypeName.
+func (r *ManifestRegistry) GetResource(name string, preferredTypes ...ResourceType) (Resource, bool) {
+	for _, resourceType := range slices.Concat(preferredTypes, lookupOrder) {
+		if resource, exists := r.GetResourceByTypeName(resourceType, name); exists {
+			return resource, true
+		}
+	}
+	return nil, false
+}
+
 // GetResourcesByType returns all resources of a specific type
 func (r *ManifestRegistry) GetResourcesByType(resourceType ResourceType) []Resource {
 	var resources []Resource
@@ -214,7 +244,7 @@ type ObjectReference struct {
 
 // ResolveReference resolves an object reference to the actual resource
 func (r *ManifestRegistry) ResolveReference(ref ObjectReference) (Resource, error) {
-	resource, exists := r.GetResource(ref.Name)
+	resource, exists := r.GetResource(ref.Name, ref.Type)
 	if !exists {
 		return nil, fmt.Errorf("referenced resource '%s' not found", ref.Name)
 	}
@@ -1,6 +1,7 @@
 package resource
 
 import (
+	"cutepod/internal/podman"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -24,7 +25,7 @@ func TestManifestRegistry_DependencyResolution(t *testing.T) {
 	container := NewContainerResource()
 	container.ObjectMeta.Name = "web-server"
 	container.Spec.Image = "nginx:latest"
-	container.Spec.Networks = []string{"web-network"}
+	container.Spec.Networks = []NetworkAttachment{{Name: "web-network"}}
 	container.Spec.Volumes = []VolumeMount{
 		{
 			Name:      "web-data",
@@ -109,7 +110,7 @@ func TestManifestRegistry_MissingDependency(t *testing.T) {
 	container := NewContainerResource()
 	container.ObjectMeta.Name = "web-server"
 	container.Spec.Image = "nginx:latest"
-	container.Spec.Networks = []string{"missing-network"}
+	container.Spec.Networks = []NetworkAttachment{{Name: "missing-network"}}
 
 	require.NoError(t, registry.AddResource(container))
 
@@ -118,3 +119,76 @@ func TestManifestRegistry_MissingDependency(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "missing-network")
 }
+
+func TestManifestRegistry_NameSharedAcrossTypes(t *testing.T) {
+	registry := NewManifestRegistry()
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "data"
+	network.Spec.Driver = "bridge"
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "web-server"
+	container.Spec.Image = "nginx:latest"
+	container.Spec.Networks = []NetworkAttachment{{Name: "data"}}
+	container.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/data"}}
+
+	require.NoError(t, registry.AddResource(network))
+	require.NoError(t, registry.AddResource(volume))
+	require.NoError(t, registry.AddResource(container))
+	require.NoError(t, registry.ValidateDependencies())
+
+	resolved, exists := registry.GetResourceByTypeName(ResourceTypeVolume, "data")
+	require.True(t, exists)
+	assert.Same(t, volume, resolved)
+
+	resolved, exists = registry.GetResourceByTypeName(ResourceTypeNetwork, "data")
+	require.True(t, exists)
+	assert.Same(t, network, resolved)
+
+	_, exists = registry.GetResourceByTypeName(ResourceTypeSecret, "data")
+	assert.False(t, exists)
+
+	// The preferred type wins over the lookup order
+	resolved, exists = registry.GetResource("data", ResourceTypeVolume)
+	require.True(t, exists)
+	assert.Same(t, volume, resolved)
+
+	resolved, err := registry.ResolveReference(ObjectReference{Name: "data", Type: ResourceTypeVolume})
+	require.NoError(t, err)
+	assert.Same(t, volume, resolved)
+
+	// A duplicate within the same type is still rejected
+	duplicate := NewVolumeResource()
+	duplicate.ObjectMeta.Name = "data"
+	assert.Error(t, registry.AddResource(duplicate))
+
+	order, err := registry.GetCreationOrder()
+	require.NoError(t, err)
+	require.Len(t, order, 2)
+	assert.Len(t, order[0], 2)
+	assert.Same(t, container, order[1][0])
+}
+
+func TestContainerManager_ResolvesVolumeSharingNameWithNetwork(t *testing.T) {
+	registry := NewManifestRegistry()
+
+	network := NewNetworkResource()
+	network.ObjectMeta.Name = "data"
+	require.NoError(t, registry.AddResource(network))
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+	require.NoError(t, registry.AddResource(volume))
+
+	cm := NewContainerManagerWithRegistry(podman.NewMockPodmanClient(), registry)
+
+	resolved, err := cm.resolveVolumeReference("data")
+	require.NoError(t, err)
+	assert.Same(t, volume, resolved)
+}
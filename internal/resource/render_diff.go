@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RenderDiff renders the planned changes as a unified +/- text diff, grouped by
+// resource type and sorted by name. Updates list the changed fields, creates the
+// full spec and deletes only the resource identity. Skipped resources change nothing
+// and are left out, so it returns an empty string when there is nothing to change.
+func (r *ReconciliationResult) RenderDiff() string {
+	actionsByType := make(map[ResourceType][]ResourceAction)
+	for _, actions := range [][]ResourceAction{r.CreatedResources, r.UpdatedResources, r.DeletedResources} {
+		for _, action := range actions {
+			if action.Action == ActionSkip {
+				continue
+			}
+			actionsByType[action.Type] = append(actionsByType[action.Type], action)
+		}
+	}
+
+	resourceTypes := make([]string, 0, len(actionsByType))
+	for resourceType := range actionsByType {
+		resourceTypes = append(resourceTypes, string(resourceType))
+	}
+	sort.Strings(resourceTypes)
+
+	var b strings.Builder
+	for i, resourceType := range resourceTypes {
+		actions := actionsByType[ResourceType(resourceType)]
+		sort.SliceStable(actions, func(i, j int) bool {
+			return actions[i].Name < actions[j].Name
+		})
+
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "# %s\n", resourceType)
+
+		for _, action := range actions {
+			renderResourceAction(&b, action)
+		}
+	}
+
+	return b.String()
+}
+
+// renderResourceAction writes the header and field lines for a single resource
+func renderResourceAction(b *strings.Builder, action ResourceAction) {
+	identity := fmt.Sprintf("%s/%s", action.Type, action.Name)
+
+	switch action.Action {
+	case ActionCreate:
+		fmt.Fprintf(b, "+ %s\n", identity)
+	case ActionUpdate:
+		fmt.Fprintf(b, "~ %s\n", identity)
+	case ActionDelete:
+		fmt.Fprintf(b, "- %s\n", identity)
+		return
+	default:
+		return
+	}
+
+	for _, diff := range action.Diffs {
+		if diff.OldValue != "" {
+			fmt.Fprintf(b, "-   %s: %s\n", diff.Path, diff.OldValue)
+		}
+		if diff.NewValue != "" {
+			fmt.Fprintf(b, "+   %s: %s\n", diff.Path, diff.NewValue)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"testing"
+)
+
+func TestReconciliationResult_RenderDiff(t *testing.T) {
+	result := &ReconciliationResult{
+		CreatedResources: []ResourceAction{
+			{Type: ResourceTypeNetwork, Name: "backend", Action: ActionCreate, Diffs: []FieldDiff{
+				{Path: "spec.driver", NewValue: "bridge"},
+			}},
+		},
+		UpdatedResources: []ResourceAction{
+			{Type: ResourceTypeContainer, Name: "web", Action: ActionUpdate, Diffs: []FieldDiff{
+				{Path: "spec.image", OldValue: "nginx:1.25", NewValue: "nginx:1.26"},
+				{Path: "spec.ports", NewValue: "[8080:80/tcp]"},
+			}},
+			{Type: ResourceTypeContainer, Name: "cache", Action: ActionSkip, Message: "paused by the cutepod.io/reconcile annotation"},
+			{Type: ResourceTypeVolume, Name: "data", Action: ActionSkip, Message: "paused by the cutepod.io/reconcile annotation"},
+		},
+		DeletedResources: []ResourceAction{
+			{Type: ResourceTypeContainer, Name: "old", Action: ActionDelete},
+		},
+	}
+
+	expected := "# container\n" +
+		"- container/old\n" +
+		"~ container/web\n" +
+		"-   spec.image: nginx:1.25\n" +
+		"+   spec.image: nginx:1.26\n" +
+		"+   spec.ports: [8080:80/tcp]\n" +
+		"\n" +
+		"# network\n" +
+		"+ network/backend\n" +
+		"+   spec.driver: bridge\n"
+
+	if rendered := result.RenderDiff(); rendered != expected {
+		t.Errorf("Unexpected diff output:\n%s\nexpected:\n%s", rendered, expected)
+	}
+}
+
+func TestReconciliationResult_RenderDiffNoChanges(t *testing.T) {
+	result := &ReconciliationResult{
+		UpdatedResources: []ResourceAction{
+			{Type: ResourceTypeVolume, Name: "data", Action: ActionSkip, Message: "paused by the cutepod.io/reconcile annotation"},
+		},
+	}
+
+	if rendered := result.RenderDiff(); rendered != "" {
+		t.Errorf("Expected empty diff, got:\n%s", rendered)
+	}
+}
+
+func TestDescribeResource_ContainerCreate(t *testing.T) {
+	container := NewContainerResource()
+	container.ObjectMeta.Name = "web"
+	container.Spec.Image = "nginx:1.26"
+
+	fields := DescribeResource(container)
+
+	found := false
+	for _, field := range fields {
+		if field.OldValue != "" {
+			t.Errorf("Expected no old values when describing a resource, got %+v", field)
+		}
+		if field.Path == "spec.image" && field.NewValue == "nginx:1.26" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected spec.image to be described, got %+v", fields)
+	}
+}
@@ -0,0 +1,125 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// WithRollingUpdate replaces the replicas of a component, the containers sharing a
+// cutepod.io/component label, at most maxUnavailable at a time. Each batch is updated,
+// then the replicas of the batch that have a readiness probe are waited for before the
+// next batch starts. A replica that fails to update or to become ready halts the
+// rollout, leaving the remaining replicas on their previous spec. Zero or less updates
+// every replica at once, the default.
+func WithRollingUpdate(maxUnavailable int) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		rc.maxUnavailable = maxUnavailable
+	}
+}
+
+// replicaComponent returns the component a container is a replica of
+func replicaComponent(resource Resource) (string, bool) {
+	if _, ok := resource.(*ContainerResource); !ok {
+		return "", false
+	}
+	component := resource.GetLabels()[labels.LabelComponent]
+	return component, component != ""
+}
+
+// replicaGroups groups the container updates by component when rolling updates are
+// enabled, ordering the replicas of each component by name
+func (rc *DefaultReconciliationController) replicaGroups(updates []ResourcePair) map[string][]ResourcePair {
+	groups := make(map[string][]ResourcePair)
+	if rc.maxUnavailable <= 0 {
+		return groups
+	}
+	for _, pair := range updates {
+		if component, ok := replicaComponent(pair.Desired); ok {
+			groups[component] = append(groups[component], pair)
+		}
+	}
+	for _, replicas := range groups {
+		slices.SortFunc(replicas, func(a, b ResourcePair) int {
+			return strings.Compare(a.Desired.GetName(), b.Desired.GetName())
+		})
+	}
+	return groups
+}
+
+// executeRollingUpdate updates the replicas of a component in batches of maxUnavailable,
+// gating each batch on the readiness of its replicas
+func (rc *DefaultReconciliationController) executeRollingUpdate(ctx context.Context, result *ReconciliationResult, replicas []ResourcePair) {
+	for start := 0; start < len(replicas); start += rc.maxUnavailable {
+		end := min(start+rc.maxUnavailable, len(replicas))
+		batch := replicas[start:end]
+
+		for _, pair := range batch {
+			if !rc.skipForFailedDependency(result, pair.Desired, ActionUpdate) {
+				rc.executeUpdateWithRetry(ctx, result, pair)
+			}
+		}
+
+		for _, pair := range batch {
+			if halted := rc.waitForReplica(ctx, result, pair.Desired); halted != "" {
+				rc.haltRollingUpdate(result, replicas[end:], halted)
+				return
+			}
+		}
+	}
+}
+
+// waitForReplica waits for an updated replica to pass its readiness probe, recording
+// the wait on its update. It returns why the rollout has to stop, or an empty string.
+func (rc *DefaultReconciliationController) waitForReplica(ctx context.Context, result *ReconciliationResult, resource Resource) string {
+	var action *ResourceAction
+	for i := len(result.UpdatedResources) - 1; i >= 0; i-- {
+		if result.UpdatedResources[i].Type == resource.GetType() && result.UpdatedResources[i].Name == resource.GetName() {
+			action = &result.UpdatedResources[i]
+			break
+		}
+	}
+	if action == nil {
+		return ""
+	}
+	if action.Error != "" || action.Action == ActionSkip {
+		return fmt.Sprintf("replica '%s' was not updated", resource.GetName())
+	}
+
+	container := resource.(*ContainerResource)
+	if container.Spec.ReadinessProbe == nil {
+		return ""
+	}
+
+	waited, err := rc.waitForReady(ctx, container)
+	action.ReadinessWait = waited
+	action.Duration += waited
+	if err != nil {
+		action.Error = fmt.Sprintf("updated but not ready: %v", err)
+		rc.addError(result, ErrorTypeReadiness,
+			ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+			action.Error, err, true)
+		attachContainerLogs(result, err)
+		return fmt.Sprintf("replica '%s' did not become ready", resource.GetName())
+	}
+	action.Message = fmt.Sprintf("updated and ready after %s", waited.Round(time.Millisecond))
+	return ""
+}
+
+// haltRollingUpdate records the replicas left after a failed one as skipped
+func (rc *DefaultReconciliationController) haltRollingUpdate(result *ReconciliationResult, remaining []ResourcePair, reason string) {
+	for _, pair := range remaining {
+		action := ResourceAction{
+			Type:      pair.Desired.GetType(),
+			Name:      pair.Desired.GetName(),
+			Action:    ActionSkip,
+			Message:   fmt.Sprintf("skipped update: rolling update halted, %s", reason),
+			Timestamp: time.Now(),
+		}
+		result.UpdatedResources = append(result.UpdatedResources, action)
+		rc.logAction(&action)
+	}
+}
@@ -0,0 +1,171 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// rolloutReadinessChecker records, for each check, how many replicas already run the
+// new image, and fails the checks of the replicas listed in notReady
+type rolloutReadinessChecker struct {
+	image    string
+	notReady map[string]bool
+	checks   []string
+}
+
+func (c *rolloutReadinessChecker) SupportsType(probeType ProbeType) bool {
+	return probeType == "rollout"
+}
+
+func (c *rolloutReadinessChecker) Check(ctx context.Context, client podman.PodmanClient, container *ContainerResource, probe *Probe) error {
+	replicas, err := client.ListContainers(ctx, map[string][]string{"label": {labels.LabelComponent + "=worker"}}, true)
+	if err != nil {
+		return err
+	}
+	updated := 0
+	for _, replica := range replicas {
+		if replica.Image == c.image {
+			updated++
+		}
+	}
+	c.checks = append(c.checks, fmt.Sprintf("%s:%d", container.GetName(), updated))
+	if c.notReady[container.GetName()] {
+		return errors.New("still starting")
+	}
+	return nil
+}
+
+func newWorkerReplicas(image string) []Resource {
+	var replicas []Resource
+	for i := range 3 {
+		container := newExplainTestContainer(image)
+		container.ObjectMeta.Name = fmt.Sprintf("worker-%d", i)
+		container.ObjectMeta.Labels[labels.LabelComponent] = "worker"
+		container.Spec.ReadinessProbe = &Probe{Type: "rollout", PeriodSeconds: 1, TimeoutSeconds: 1}
+		replicas = append(replicas, container)
+	}
+	return replicas
+}
+
+func TestReconcile_RollingUpdateReplacesReplicasOneAtATime(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	checker := &rolloutReadinessChecker{image: "worker:2"}
+	controller := NewReconciliationController(mockClient, WithReadinessChecker(checker), WithRollingUpdate(1))
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, newWorkerReplicas("worker:1"), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	checker.checks = nil
+
+	result, err := controller.Reconcile(ctx, newWorkerReplicas("worker:2"), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	// Each replica is ready before the next one is replaced
+	expected := []string{"worker-0:1", "worker-1:2", "worker-2:3"}
+	if fmt.Sprint(checker.checks) != fmt.Sprint(expected) {
+		t.Errorf("Expected checks %v, got %v", expected, checker.checks)
+	}
+	if len(result.UpdatedResources) != 3 {
+		t.Fatalf("Expected 3 updates, got %+v", result.UpdatedResources)
+	}
+	for i, action := range result.UpdatedResources {
+		if action.Name != fmt.Sprintf("worker-%d", i) || action.Error != "" {
+			t.Errorf("Expected worker-%d to be updated in turn, got %+v", i, action)
+		}
+	}
+}
+
+func TestReconcile_RollingUpdateHaltsOnReplicaThatIsNotReady(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	checker := &rolloutReadinessChecker{image: "worker:2"}
+	controller := NewReconciliationController(mockClient, WithReadinessChecker(checker), WithRollingUpdate(1))
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, newWorkerReplicas("worker:1"), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	checker.notReady = map[string]bool{"worker-1": true}
+
+	result, err := controller.Reconcile(ctx, newWorkerReplicas("worker:2"), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Type != ErrorTypeReadiness {
+		t.Fatalf("Expected a readiness error, got %+v", result.Errors)
+	}
+
+	last := result.UpdatedResources[len(result.UpdatedResources)-1]
+	if last.Name != "worker-2" || last.Action != ActionSkip {
+		t.Errorf("Expected worker-2 to be skipped, got %+v", last)
+	}
+	inspect, err := mockClient.InspectContainer(ctx, "worker-2")
+	if err != nil {
+		t.Fatalf("InspectContainer failed: %v", err)
+	}
+	if inspect.Config.Image != "worker:1" {
+		t.Errorf("Expected worker-2 to keep its previous image, got %s", inspect.Config.Image)
+	}
+}
+
+func TestReconcile_RollingUpdateRotatesSharedSecretOneReplicaAtATime(t *testing.T) {
+	client := &orderRecordingClient{MockPodmanClient: podman.NewMockPodmanClient()}
+	controller := NewReconciliationController(client, WithRollingUpdate(1))
+	ctx := context.Background()
+
+	manifests := func(token string) []Resource {
+		secret := NewSecretResource()
+		secret.ObjectMeta.Name = "creds"
+		secret.Spec.Data = map[string]string{"token": base64.StdEncoding.EncodeToString([]byte(token))}
+		secret.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+
+		resources := []Resource{secret}
+		for _, replica := range newWorkerReplicas("worker:1") {
+			replica.(*ContainerResource).Spec.ReadinessProbe = nil
+			replica.(*ContainerResource).Spec.Secrets = []SecretReference{{Name: "creds", Env: true}}
+			resources = append(resources, replica)
+		}
+		return resources
+	}
+
+	if _, err := controller.Reconcile(ctx, manifests("v1"), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	client.operations = nil
+
+	result, err := controller.Reconcile(ctx, manifests("v2"), "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	// Each replica is started again before the next one goes down
+	down := 0
+	for _, operation := range client.operations {
+		switch {
+		case strings.HasPrefix(operation, "stop "):
+			down++
+		case strings.HasPrefix(operation, "start "):
+			down--
+		}
+		if down > 1 {
+			t.Fatalf("Expected at most one replica down at a time, got %v", client.operations)
+		}
+	}
+	if len(result.UpdatedResources) != 4 {
+		t.Errorf("Expected the secret and the 3 replicas to be updated, got %+v", result.UpdatedResources)
+	}
+}
@@ -2,7 +2,11 @@ package resource
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
+	"slices"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -26,8 +30,19 @@ type CuteSecretSpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default:="opaque"
 	Type SecretType `json:"type,omitempty"`
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	Data map[string]string `json:"data,omitempty"` // Base64 encoded data
+	// +kubebuilder:validation:Optional
+	Source *SecretSource `json:"source,omitempty"`
+}
+
+// SecretSource reads secret values from the host when the secret is created, so they
+// do not have to be embedded in the manifest
+type SecretSource struct {
+	// FromFile maps secret keys to host paths whose content becomes the value
+	FromFile map[string]string `json:"fromFile,omitempty"`
+	// FromEnv maps secret keys to environment variables whose value becomes the value
+	FromEnv map[string]string `json:"fromEnv,omitempty"`
 }
 
 // SecretType represents the type of secret
@@ -37,6 +52,14 @@ const (
 	SecretTypeOpaque SecretType = "opaque"
 )
 
+// secretType returns the type of the secret, which defaults to opaque
+func secretType(spec CuteSecretSpec) SecretType {
+	if spec.Type == "" {
+		return SecretTypeOpaque
+	}
+	return spec.Type
+}
+
 // NewSecretResource creates a new SecretResource
 func NewSecretResource() *SecretResource {
 	return &SecretResource{
@@ -91,6 +114,65 @@ func (s *SecretResource) GetDecodedData() (map[string][]byte, error) {
 	return decoded, nil
 }
 
+// ResolveData returns the decoded secret data merged with the values read from the
+// files and environment variables of the secret's source. Every missing file, unset
+// variable or key defined more than once is reported.
+func (s *SecretResource) ResolveData() (map[string][]byte, error) {
+	data, err := s.GetDecodedData()
+	if err != nil {
+		return nil, err
+	}
+	if s.Spec.Source == nil {
+		return data, nil
+	}
+
+	var errs []error
+	setValue := func(key string, value []byte) {
+		if _, exists := data[key]; exists {
+			errs = append(errs, fmt.Errorf("key '%s' is defined more than once", key))
+			return
+		}
+		data[key] = value
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.Spec.Source.FromFile)) {
+		path := s.Spec.Source.FromFile[key]
+		content, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to read file for key '%s': %w", key, err))
+			continue
+		}
+		setValue(key, content)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(s.Spec.Source.FromEnv)) {
+		name := s.Spec.Source.FromEnv[key]
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			errs = append(errs, fmt.Errorf("environment variable %s for key '%s' is not set", name, key))
+			continue
+		}
+		setValue(key, []byte(value))
+	}
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("secret '%s': %w", s.GetName(), errors.Join(errs...))
+	}
+	return data, nil
+}
+
+// Keys returns the sorted keys of the secret, including those of its source, without
+// reading any values
+func (s *SecretResource) Keys() []string {
+	keys := slices.Collect(maps.Keys(s.Spec.Data))
+	if s.Spec.Source != nil {
+		keys = slices.AppendSeq(keys, maps.Keys(s.Spec.Source.FromFile))
+		keys = slices.AppendSeq(keys, maps.Keys(s.Spec.Source.FromEnv))
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
 // SetData sets the secret data with base64 encoding
 func (s *SecretResource) SetData(data map[string][]byte) {
 	if s.Spec.Data == nil {
@@ -2,14 +2,23 @@ package resource
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
 	"cutepod/internal/labels"
 	"cutepod/internal/podman"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"maps"
+	"slices"
+	"strings"
 )
 
 // SecretManager implements ResourceManager for secret resources
 type SecretManager struct {
-	client podman.PodmanClient
+	client      podman.PodmanClient
+	labelPrefix string
 }
 
 // NewSecretManager creates a new SecretManager
@@ -19,6 +28,11 @@ func NewSecretManager(client podman.PodmanClient) *SecretManager {
 	}
 }
 
+// SetLabelPrefix makes the manager list secrets by the chart label under prefix
+func (sm *SecretManager) SetLabelPrefix(prefix string) {
+	sm.labelPrefix = prefix
+}
+
 // GetResourceType returns the resource type this manager handles
 func (sm *SecretManager) GetResourceType() ResourceType {
 	return ResourceTypeSecret
@@ -47,12 +61,7 @@ func (sm *SecretManager) GetActualState(ctx context.Context, chartName string) (
 		return nil, fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	secrets, err := podmanClient.ListSecrets(
-		ctx,
-		map[string][]string{
-			"label": {labels.GetChartLabelValue(chartName)},
-		},
-	)
+	secrets, err := podmanClient.ListSecrets(ctx, chartFilters(ctx, sm.labelPrefix, chartName))
 	if err != nil {
 		return nil, fmt.Errorf("unable to list secrets: %w", err)
 	}
@@ -82,10 +91,10 @@ func (sm *SecretManager) CreateResource(ctx context.Context, resource Resource)
 		return fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	// Get decoded data from the secret
-	decodedData, err := secret.GetDecodedData()
+	// Get decoded data from the secret, including values read from its source
+	decodedData, err := secret.ResolveData()
 	if err != nil {
-		return fmt.Errorf("unable to decode secret data: %w", err)
+		return fmt.Errorf("unable to resolve secret data: %w", err)
 	}
 
 	// Create secret spec for each key-value pair
@@ -117,10 +126,19 @@ func (sm *SecretManager) UpdateResource(ctx context.Context, desired, actual Res
 		return fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	// Get decoded data from the desired secret
-	decodedData, err := desiredSecret.GetDecodedData()
+	// Get decoded data from the desired secret, including values read from its source
+	decodedData, err := desiredSecret.ResolveData()
 	if err != nil {
-		return fmt.Errorf("unable to decode secret data: %w", err)
+		return fmt.Errorf("unable to resolve secret data: %w", err)
+	}
+
+	// Rotating a secret removes and recreates it, so leave it alone when only its
+	// metadata differs and the data it holds is the same
+	if actualSecret, ok := actual.(*SecretResource); ok {
+		if actualHash := actualSecret.GetAnnotations()[labels.LabelSecretHash]; actualHash != "" &&
+			secretDataMatches(actualHash, decodedData) {
+			return nil
+		}
 	}
 
 	// Create secret spec
@@ -165,11 +183,21 @@ func (sm *SecretManager) CompareResources(desired, actual Resource) (bool, error
 		return false, fmt.Errorf("expected SecretResource for actual, got %T", actual)
 	}
 
-	// Compare secret type
-	if desiredSecret.Spec.Type != actualSecret.Spec.Type {
+	// Podman does not store the type, so an untyped secret reads back as opaque
+	if secretType(desiredSecret.Spec) != secretType(actualSecret.Spec) {
 		return false, nil
 	}
 
+	// Secrets created by cutepod carry a hash of their data, which catches rotated values.
+	// Data that cannot be resolved counts as changed, so the update reports why.
+	if actualHash := actualSecret.GetAnnotations()[labels.LabelSecretHash]; actualHash != "" {
+		decodedData, err := desiredSecret.ResolveData()
+		if err != nil {
+			return false, nil
+		}
+		return secretDataMatches(actualHash, decodedData), nil
+	}
+
 	// Compare secret data
 	if !sm.compareSecretData(desiredSecret.Spec.Data, actualSecret.Spec.Data) {
 		return false, nil
@@ -183,7 +211,21 @@ func (sm *SecretManager) CompareResources(desired, actual Resource) (bool, error
 func (sm *SecretManager) convertPodmanSecretToResource(secret podman.SecretInfo) *SecretResource {
 	resource := NewSecretResource()
 	resource.ObjectMeta.Name = secret.Name
-	resource.SetLabels(secret.Labels)
+
+	// The data hash and delete protection are bookkeeping rather than user labels
+	secretLabels := make(map[string]string, len(secret.Labels))
+	annotations := make(map[string]string)
+	for key, value := range secret.Labels {
+		if key == labels.LabelSecretHash || key == labels.AnnotationDeleteProtect {
+			annotations[key] = value
+			continue
+		}
+		secretLabels[key] = value
+	}
+	resource.SetLabels(secretLabels)
+	if len(annotations) > 0 {
+		resource.SetAnnotations(annotations)
+	}
 
 	// Set default secret type
 	resource.Spec.Type = SecretTypeOpaque
@@ -221,17 +263,43 @@ func (sm *SecretManager) buildSecretSpec(secret *SecretResource, decodedData map
 	}
 
 	spec := podman.SecretSpec{
-		Name:   secret.GetName(),
-		Data:   combinedData,
-		Labels: secret.GetLabels(),
+		Name: secret.GetName(),
+		Data: combinedData,
+		Labels: labels.MergeLabels(withDeleteProtection(secret, secret.GetLabels()), map[string]string{
+			labels.LabelSecretHash: newSecretDataHash(decodedData),
+		}),
 	}
 
-	// Initialize labels map if nil
-	if spec.Labels == nil {
-		spec.Labels = make(map[string]string)
+	return spec
+}
+
+// newSecretDataHash returns the value of the secret hash label for data, as a random
+// salt and the HMAC-SHA256 of the data keyed by it. The salt keeps the label from
+// matching the same value across secrets or a table of hashes of common values.
+func newSecretDataHash(data map[string][]byte) string {
+	salt := rand.Text()
+	return salt + ":" + hex.EncodeToString(secretDataSum(hmac.New(sha256.New, []byte(salt)), data))
+}
+
+// secretDataMatches reports whether hash, a secret hash label value, records data.
+// Labels from before the salt was added hold a plain SHA-256 of the data.
+func secretDataMatches(hash string, data map[string][]byte) bool {
+	salt, sum, salted := strings.Cut(hash, ":")
+	if !salted {
+		return hmac.Equal([]byte(hash), []byte(hex.EncodeToString(secretDataSum(sha256.New(), data))))
 	}
+	return hmac.Equal([]byte(sum), []byte(hex.EncodeToString(secretDataSum(hmac.New(sha256.New, []byte(salt)), data))))
+}
 
-	return spec
+// secretDataSum hashes secret data independently of key order
+func secretDataSum(h hash.Hash, data map[string][]byte) []byte {
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		h.Write([]byte(key))
+		h.Write([]byte{0})
+		h.Write(data[key])
+		h.Write([]byte{0})
+	}
+	return h.Sum(nil)
 }
 
 func (sm *SecretManager) compareSecretData(desired, actual map[string]string) bool {
@@ -2,9 +2,14 @@ package resource
 
 import (
 	"context"
+	"crypto/sha256"
 	"cutepod/internal/labels"
 	"cutepod/internal/podman"
 	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -226,6 +231,58 @@ func TestSecretManager_UpdateResource(t *testing.T) {
 	}
 }
 
+func TestSecretManager_UpdateResource_UnchangedDataIsKept(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	manager := NewSecretManager(mockClient)
+
+	desired := NewSecretResource()
+	desired.ObjectMeta.Name = "test-secret"
+	desired.Spec.Data = map[string]string{
+		"token": base64.StdEncoding.EncodeToString([]byte("abc123")),
+	}
+	desired.SetLabels(map[string]string{"tier": "backend"})
+	decodedData, err := desired.ResolveData()
+	if err != nil {
+		t.Fatalf("ResolveData failed: %v", err)
+	}
+
+	// The live secret holds the same data but lacks the new label
+	actual := NewSecretResource()
+	actual.ObjectMeta.Name = "test-secret"
+	actual.SetAnnotations(map[string]string{labels.LabelSecretHash: newSecretDataHash(decodedData)})
+
+	if err := manager.UpdateResource(context.Background(), desired, actual); err != nil {
+		t.Fatalf("UpdateResource failed: %v", err)
+	}
+
+	for _, operation := range []string{"UpdateSecret", "RemoveSecret", "CreateSecret"} {
+		if calls := mockClient.GetCallCount(operation); calls != 0 {
+			t.Errorf("Expected %s not to be called for unchanged data, got %d calls", operation, calls)
+		}
+	}
+}
+
+func TestSecretDataHash_IsSaltedPerSecret(t *testing.T) {
+	data := map[string][]byte{"password": []byte("hunter2")}
+
+	first, second := newSecretDataHash(data), newSecretDataHash(data)
+	if first == second {
+		t.Errorf("Expected the same data to get different salted hashes, got %s twice", first)
+	}
+	if !secretDataMatches(first, data) || !secretDataMatches(second, data) {
+		t.Error("Expected both hashes to match the data they were computed from")
+	}
+	if secretDataMatches(first, map[string][]byte{"password": []byte("hunter3")}) {
+		t.Error("Expected a hash not to match different data")
+	}
+
+	// Labels written before the salt was added still match
+	legacy := hex.EncodeToString(secretDataSum(sha256.New(), data))
+	if !secretDataMatches(legacy, data) {
+		t.Error("Expected an unsalted hash of the data to match")
+	}
+}
+
 func TestSecretManager_DeleteResource(t *testing.T) {
 	mockClient := podman.NewMockPodmanClient()
 	manager := NewSecretManager(mockClient)
@@ -498,3 +555,76 @@ func TestSecretResource_SetData(t *testing.T) {
 		t.Errorf("Expected password '%s', got '%s'", expectedPassword, secret.Spec.Data["password"])
 	}
 }
+
+func TestSecretManager_ResolvesSourceAndDetectsRotation(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	manager := NewSecretManager(mockClient)
+	ctx := context.Background()
+
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("hunter2"), 0600); err != nil {
+		t.Fatalf("Failed to write password file: %v", err)
+	}
+	t.Setenv("CUTEPOD_TEST_API_TOKEN", "token-1")
+
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "credentials"
+	secret.Spec.Type = SecretTypeOpaque
+	secret.Spec.Data = map[string]string{"username": base64.StdEncoding.EncodeToString([]byte("admin"))}
+	secret.Spec.Source = &SecretSource{
+		FromFile: map[string]string{"password": passwordFile},
+		FromEnv:  map[string]string{"token": "CUTEPOD_TEST_API_TOKEN"},
+	}
+	secret.SetLabels(labels.GetStandardLabels("test-name", "test-version"))
+
+	data, err := secret.ResolveData()
+	if err != nil {
+		t.Fatalf("ResolveData failed: %v", err)
+	}
+	if string(data["username"]) != "admin" || string(data["password"]) != "hunter2" || string(data["token"]) != "token-1" {
+		t.Errorf("Expected data from the manifest, the file and the environment, got %v", data)
+	}
+
+	if err := manager.CreateResource(ctx, secret); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	actual, err := manager.GetActualState(ctx, "test-name")
+	if err != nil || len(actual) != 1 {
+		t.Fatalf("Expected 1 secret, got %d (err: %v)", len(actual), err)
+	}
+	if _, exists := actual[0].GetLabels()[labels.LabelSecretHash]; exists {
+		t.Error("Expected the secret hash to be kept out of the labels")
+	}
+
+	if match, err := manager.CompareResources(secret, actual[0]); err != nil || !match {
+		t.Fatalf("Expected the secret to match (err: %v)", err)
+	}
+
+	t.Setenv("CUTEPOD_TEST_API_TOKEN", "token-2")
+	if match, _ := manager.CompareResources(secret, actual[0]); match {
+		t.Error("Expected a rotated value to require an update")
+	}
+}
+
+func TestSecretResource_ResolveData_ReportsMissingSources(t *testing.T) {
+	secret := NewSecretResource()
+	secret.ObjectMeta.Name = "credentials"
+	secret.Spec.Source = &SecretSource{
+		FromFile: map[string]string{"password": filepath.Join(t.TempDir(), "missing")},
+		FromEnv:  map[string]string{"token": "CUTEPOD_TEST_UNSET_VARIABLE"},
+	}
+
+	_, err := secret.ResolveData()
+	if err == nil {
+		t.Fatal("Expected an error for the missing file and variable")
+	}
+	for _, expected := range []string{
+		"unable to read file for key 'password'",
+		"environment variable CUTEPOD_TEST_UNSET_VARIABLE for key 'token' is not set",
+	} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected %q in %v", expected, err)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package resource
+
+import (
+	"cutepod/internal/labels"
+	"fmt"
+)
+
+// secretRotated reports whether updating the pair changes the data a secret holds.
+// Secrets without a data hash, or whose data cannot be resolved, count as rotated.
+func secretRotated(pair ResourcePair) bool {
+	desired, ok := pair.Desired.(*SecretResource)
+	if !ok {
+		return false
+	}
+	actual, ok := pair.Actual.(*SecretResource)
+	if !ok {
+		return true
+	}
+
+	actualHash := actual.GetAnnotations()[labels.LabelSecretHash]
+	if actualHash == "" {
+		return true
+	}
+	decodedData, err := desired.ResolveData()
+	if err != nil {
+		return true
+	}
+	return !secretDataMatches(actualHash, decodedData)
+}
+
+// recreateSecretDependents marks the containers that use a rotated secret for
+// recreation, moving unchanged ones to the updates. Podman copies secrets into a
+// container when it is created, so a container keeps the old values until then.
+func recreateSecretDependents(diff *StateDiff, actualStateByType map[ResourceType][]Resource) {
+	rotated := make(map[string]bool)
+	for _, pair := range diff.ToUpdate {
+		if pair.Desired.GetType() == ResourceTypeSecret && secretRotated(pair) {
+			rotated[pair.Desired.GetName()] = true
+		}
+	}
+	if len(rotated) == 0 {
+		return
+	}
+
+	// Containers updated anyway might be updated in place, which keeps the old values
+	for i, pair := range diff.ToUpdate {
+		if reasons := rotatedSecretReasons(pair.Desired, rotated); len(reasons) > 0 {
+			diff.ToUpdate[i].Reasons = append(diff.ToUpdate[i].Reasons, reasons...)
+			diff.ToUpdate[i].Recreate = true
+		}
+	}
+
+	actualContainers := make(map[string]Resource)
+	for _, container := range actualStateByType[ResourceTypeContainer] {
+		actualContainers[container.GetName()] = container
+	}
+
+	unchanged := make([]Resource, 0, len(diff.Unchanged))
+	for _, resource := range diff.Unchanged {
+		actual, exists := actualContainers[resource.GetName()]
+		reasons := rotatedSecretReasons(resource, rotated)
+		if !exists || len(reasons) == 0 {
+			unchanged = append(unchanged, resource)
+			continue
+		}
+
+		diff.ToUpdate = append(diff.ToUpdate, ResourcePair{
+			Desired:  resource,
+			Actual:   actual,
+			Reasons:  reasons,
+			Recreate: true,
+		})
+	}
+	diff.Unchanged = unchanged
+}
+
+// rotatedSecretReasons explains which rotated secrets a container uses
+func rotatedSecretReasons(resource Resource, rotated map[string]bool) []string {
+	if resource.GetType() != ResourceTypeContainer {
+		return nil
+	}
+
+	var reasons []string
+	seen := make(map[string]bool)
+	for _, dep := range resource.GetDependencies() {
+		if dep.Type != ResourceTypeSecret || !rotated[dep.Name] || seen[dep.Name] {
+			continue
+		}
+		seen[dep.Name] = true
+		reasons = append(reasons, fmt.Sprintf("secret '%s' is rotated", dep.Name))
+	}
+	return reasons
+}
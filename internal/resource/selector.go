@@ -0,0 +1,61 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// labelSelectorKey is the context key of the label selector set by WithLabelSelector
+type labelSelectorKey struct{}
+
+// WithLabelSelector returns a context under which reconciliation only considers the
+// chart's resources carrying every label of selector, both in the manifests and in the
+// actual state read from Podman. This reconciles one component of a chart while leaving
+// the others, including their orphans, alone.
+func WithLabelSelector(ctx context.Context, selector map[string]string) context.Context {
+	return context.WithValue(ctx, labelSelectorKey{}, selector)
+}
+
+// labelSelector returns the label selector set on ctx, if any
+func labelSelector(ctx context.Context) map[string]string {
+	selector, _ := ctx.Value(labelSelectorKey{}).(map[string]string)
+	return selector
+}
+
+// chartFilters returns the Podman filters listing the chart's resources by its label
+// under labelPrefix, narrowed by the label selector of ctx
+func chartFilters(ctx context.Context, labelPrefix, chartName string) map[string][]string {
+	selector := labelSelector(ctx)
+	labelFilters := []string{labels.GetChartLabelValueWithPrefix(chartName, labelPrefix)}
+	for _, key := range slices.Sorted(maps.Keys(selector)) {
+		labelFilters = append(labelFilters, fmt.Sprintf("%s=%s", key, selector[key]))
+	}
+	return map[string][]string{"label": labelFilters}
+}
+
+// selectManifests keeps the manifests matching the label selector of ctx
+func selectManifests(ctx context.Context, manifests []Resource) []Resource {
+	selector := labelSelector(ctx)
+	if len(selector) == 0 {
+		return manifests
+	}
+
+	selected := make([]Resource, 0, len(manifests))
+	for _, manifest := range manifests {
+		manifestLabels := manifest.GetLabels()
+		matches := true
+		for key, value := range selector {
+			if labelValue, exists := manifestLabels[key]; !exists || labelValue != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			selected = append(selected, manifest)
+		}
+	}
+	return selected
+}
@@ -0,0 +1,86 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"testing"
+)
+
+// newComponentContainer returns a container of the demo chart labeled as part of component
+func newComponentContainer(name, image, component string) *ContainerResource {
+	container := newExplainTestContainer(image)
+	container.ObjectMeta.Name = name
+	container.SetLabels(labels.MergeLabels(container.GetLabels(), map[string]string{"component": component}))
+	return container
+}
+
+func TestReconcile_LabelSelectorReconcilesSubset(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	manifests := []Resource{
+		newComponentContainer("web", "nginx:1.25", "frontend"),
+		newComponentContainer("api", "nginx:1.25", "backend"),
+	}
+	if _, err := controller.Reconcile(ctx, manifests, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// Only the frontend is selected, so the backend is neither updated nor treated as an
+	// orphan although its manifest changed
+	selectedCtx := WithLabelSelector(ctx, map[string]string{"component": "frontend"})
+	manifests = []Resource{
+		newComponentContainer("web", "nginx:1.26", "frontend"),
+		newComponentContainer("api", "nginx:1.26", "backend"),
+	}
+	result, err := controller.Reconcile(selectedCtx, manifests, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.Errors) != 0 {
+		t.Fatalf("Expected no errors, got %+v", result.Errors)
+	}
+
+	for _, actions := range [][]ResourceAction{result.CreatedResources, result.UpdatedResources, result.DeletedResources} {
+		for _, action := range actions {
+			if action.Name != "web" {
+				t.Errorf("Expected only web to be reconciled, got %+v", action)
+			}
+		}
+	}
+	if len(result.CreatedResources)+len(result.UpdatedResources) == 0 {
+		t.Error("Expected web to be recreated or updated for its new image")
+	}
+
+	api, err := mockClient.InspectContainer(ctx, "api")
+	if err != nil || api.Image != "nginx:1.25" {
+		t.Errorf("Expected api to be left on its old image, got %+v (err: %v)", api, err)
+	}
+
+	// Without the selector the backend is reconciled too
+	result, err = controller.Reconcile(ctx, manifests, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if api, err := mockClient.InspectContainer(ctx, "api"); err != nil || api.Image != "nginx:1.26" {
+		t.Errorf("Expected api to be moved to the new image, got %+v (err: %v)", api, err)
+	}
+}
+
+func TestChartFilters_AddsSelectorLabels(t *testing.T) {
+	ctx := WithLabelSelector(context.Background(), map[string]string{"tier": "db", "component": "api"})
+
+	filters := chartFilters(ctx, "", "demo")
+
+	expected := []string{"cutepod.io/chart=demo", "component=api", "tier=db"}
+	if len(filters["label"]) != len(expected) {
+		t.Fatalf("Expected label filters %v, got %v", expected, filters["label"])
+	}
+	for i, filter := range expected {
+		if filters["label"][i] != filter {
+			t.Errorf("Expected label filter %d to be %s, got %s", i, filter, filters["label"][i])
+		}
+	}
+}
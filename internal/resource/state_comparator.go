@@ -1,7 +1,13 @@
 package resource
 
 import (
+	"cutepod/internal/labels"
 	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"sync"
 )
 
 // StateComparator handles the core logic of comparing desired vs actual state
@@ -26,25 +32,88 @@ type StateDiff struct {
 
 // ResourcePair represents a pair of desired and actual resources for comparison
 type ResourcePair struct {
-	Desired Resource `json:"desired"`
-	Actual  Resource `json:"actual"`
+	Desired Resource    `json:"desired"`
+	Actual  Resource    `json:"actual"`
+	Reasons []string    `json:"reasons,omitempty"`
+	Diffs   []FieldDiff `json:"diffs,omitempty"`
+	// Recreate replaces the resource although its own spec may be unchanged, such as a
+	// container whose secret is rotated in the same reconcile
+	Recreate bool `json:"recreate,omitempty"`
+}
+
+// defaultIgnoredLabelKeys are the bookkeeping labels and annotations cutepod sets itself
+var defaultIgnoredLabelKeys = []string{
+	labels.LabelSpecHash,
+	labels.LabelContainerfileHash,
+	labels.LabelSecretHash,
+	labels.LabelSecretEnvKeys,
+	labels.LabelEmptyDirs,
+	labels.LabelPreStop,
+	labels.AnnotationPaused,
+	labels.AnnotationStopped,
 }
 
 // DefaultStateComparator implements StateComparator
 type DefaultStateComparator struct {
 	managers map[ResourceType]ResourceManager
+
+	// IgnoredLabelKeys lists label and annotation keys left out when comparing them.
+	// Entries are path.Match patterns, so "ci.example.com/*" ignores every key under
+	// that prefix.
+	IgnoredLabelKeys []string
+
+	// unchanged remembers the pairs that last compared as unchanged, keyed by resource,
+	// so that watch loops skip comparing them again
+	unchanged   map[string]comparisonCacheEntry
+	unchangedMu sync.Mutex
+}
+
+// comparisonCacheEntry records a desired resource that compared as unchanged with an
+// actual resource
+type comparisonCacheEntry struct {
+	desired   Resource
+	actualKey string
+}
+
+// comparisonKeyer is implemented by managers whose resources carry a hash of their
+// spec. comparisonKey summarises everything CompareResources reads from the actual
+// resource, starting with that hash, and reports false when the comparison also
+// depends on state outside of the two resources.
+type comparisonKeyer interface {
+	comparisonKey(desired, actual Resource) (string, bool)
 }
 
 // NewStateComparator creates a new state comparator
 func NewStateComparator() StateComparator {
 	return &DefaultStateComparator{
-		managers: make(map[ResourceType]ResourceManager),
+		managers:         make(map[ResourceType]ResourceManager),
+		IgnoredLabelKeys: slices.Clone(defaultIgnoredLabelKeys),
+		unchanged:        make(map[string]comparisonCacheEntry),
 	}
 }
 
+// SetIgnoredLabelKeys sets the label and annotation keys to ignore in comparisons, in
+// addition to cutepod's own bookkeeping keys
+func (sc *DefaultStateComparator) SetIgnoredLabelKeys(keys []string) {
+	sc.IgnoredLabelKeys = slices.Concat(defaultIgnoredLabelKeys, keys)
+}
+
 // SetResourceManager sets the resource manager for a specific resource type
 func (sc *DefaultStateComparator) SetResourceManager(resourceType ResourceType, manager ResourceManager) {
 	sc.managers[resourceType] = manager
+
+	// Verdicts of the previous manager no longer apply
+	sc.ClearComparisonCache()
+}
+
+// ClearComparisonCache forgets every unchanged verdict. Verdicts are remembered per
+// desired resource, so a caller that changes a desired resource in place must clear the
+// cache before comparing it again.
+func (sc *DefaultStateComparator) ClearComparisonCache() {
+	sc.unchangedMu.Lock()
+	defer sc.unchangedMu.Unlock()
+
+	clear(sc.unchanged)
 }
 
 // CompareStates compares desired vs actual resources and returns a diff
@@ -74,7 +143,7 @@ func (sc *DefaultStateComparator) CompareStates(desired, actual []Resource) (*St
 	for key, desiredRes := range desiredMap {
 		if actualRes, exists := actualMap[key]; exists {
 			// Resource exists, check if it needs updating
-			shouldUpdate, _, err := sc.ShouldUpdate(desiredRes, actualRes)
+			shouldUpdate, reasons, fieldDiffs, err := sc.compareResourcePair(desiredRes, actualRes)
 			if err != nil {
 				return nil, fmt.Errorf("failed to compare resources %s: %w", key, err)
 			}
@@ -83,6 +152,8 @@ func (sc *DefaultStateComparator) CompareStates(desired, actual []Resource) (*St
 				diff.ToUpdate = append(diff.ToUpdate, ResourcePair{
 					Desired: desiredRes,
 					Actual:  actualRes,
+					Reasons: reasons,
+					Diffs:   fieldDiffs,
 				})
 			} else {
 				diff.Unchanged = append(diff.Unchanged, desiredRes)
@@ -105,13 +176,20 @@ func (sc *DefaultStateComparator) CompareStates(desired, actual []Resource) (*St
 
 // ShouldUpdate determines if a resource should be updated
 func (sc *DefaultStateComparator) ShouldUpdate(desired, actual Resource) (bool, []string, error) {
+	shouldUpdate, reasons, _, err := sc.compareResourcePair(desired, actual)
+	return shouldUpdate, reasons, err
+}
+
+// compareResourcePair determines if a resource should be updated and returns both the
+// coarse reasons and the field-level differences
+func (sc *DefaultStateComparator) compareResourcePair(desired, actual Resource) (bool, []string, []FieldDiff, error) {
 	if desired.GetType() != actual.GetType() {
-		return false, nil, fmt.Errorf("resource type mismatch: desired=%s, actual=%s",
+		return false, nil, nil, fmt.Errorf("resource type mismatch: desired=%s, actual=%s",
 			desired.GetType(), actual.GetType())
 	}
 
 	if desired.GetName() != actual.GetName() {
-		return false, nil, fmt.Errorf("resource name mismatch: desired=%s, actual=%s",
+		return false, nil, nil, fmt.Errorf("resource name mismatch: desired=%s, actual=%s",
 			desired.GetName(), actual.GetName())
 	}
 
@@ -122,38 +200,95 @@ func (sc *DefaultStateComparator) ShouldUpdate(desired, actual Resource) (bool,
 		return sc.basicComparison(desired, actual)
 	}
 
+	// A pair that compared as unchanged before does not need comparing again
+	key := sc.getResourceKey(desired)
+	entry, cacheable := comparisonCacheKey(manager, desired, actual)
+	if cacheable && sc.cachedUnchanged(key, entry) {
+		return false, []string{}, []FieldDiff{}, nil
+	}
+
 	// Use the manager's comparison logic
 	matches, err := manager.CompareResources(desired, actual)
 	if err != nil {
-		return false, nil, fmt.Errorf("failed to compare resources using manager: %w", err)
+		return false, nil, nil, fmt.Errorf("failed to compare resources using manager: %w", err)
+	}
+
+	sc.unchangedMu.Lock()
+	if matches && cacheable {
+		sc.unchanged[key] = entry
+	} else {
+		delete(sc.unchanged, key)
 	}
+	sc.unchangedMu.Unlock()
 
 	if matches {
-		return false, []string{}, nil
+		return false, []string{}, []FieldDiff{}, nil
 	}
 
 	// Resources don't match, determine the reasons
-	reasons := sc.determineUpdateReasons(desired, actual)
-	return true, reasons, nil
+	reasons, fieldDiffs := sc.determineUpdateReasons(desired, actual)
+	return true, reasons, fieldDiffs, nil
+}
+
+// cachedUnchanged reports whether the resource last compared as unchanged with the same
+// desired resource and an equivalent actual resource
+func (sc *DefaultStateComparator) cachedUnchanged(key string, entry comparisonCacheEntry) bool {
+	sc.unchangedMu.Lock()
+	defer sc.unchangedMu.Unlock()
+
+	cached, exists := sc.unchanged[key]
+	return exists && cached == entry
+}
+
+// comparisonCacheKey returns the cache entry for a pair, or false if the manager cannot
+// summarise its resources cheaply
+func comparisonCacheKey(manager ResourceManager, desired, actual Resource) (comparisonCacheEntry, bool) {
+	keyer, ok := manager.(comparisonKeyer)
+	if !ok {
+		return comparisonCacheEntry{}, false
+	}
+
+	actualKey, ok := keyer.comparisonKey(desired, actual)
+	if !ok {
+		return comparisonCacheEntry{}, false
+	}
+	return comparisonCacheEntry{desired: desired, actualKey: actualKey}, true
+}
+
+// writeComparisonKey appends a map to a comparison key in a stable order
+func writeComparisonKey(key *strings.Builder, values map[string]string) {
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		key.WriteString(name)
+		key.WriteByte('=')
+		key.WriteString(values[name])
+		key.WriteByte(0)
+	}
 }
 
 // basicComparison performs a basic comparison when no specific manager is available
-func (sc *DefaultStateComparator) basicComparison(desired, actual Resource) (bool, []string, error) {
+func (sc *DefaultStateComparator) basicComparison(desired, actual Resource) (bool, []string, []FieldDiff, error) {
 	reasons := make([]string, 0)
+	fieldDiffs := make([]FieldDiff, 0)
 
 	// Compare labels
-	desiredLabels := desired.GetLabels()
-	actualLabels := actual.GetLabels()
+	desiredLabels := sc.withoutIgnoredKeys(desired.GetLabels())
+	actualLabels := sc.withoutIgnoredKeys(actual.GetLabels())
 
 	if !sc.compareMaps(desiredLabels, actualLabels) {
 		reasons = append(reasons, "labels differ")
+		fieldDiffs = append(fieldDiffs, FieldDiff{
+			Path:     "metadata.labels",
+			OldValue: formatStringMap(actualLabels),
+			NewValue: formatStringMap(desiredLabels),
+		})
 	}
 
-	return len(reasons) > 0, reasons, nil
+	return len(reasons) > 0, reasons, fieldDiffs, nil
 }
 
 // determineUpdateReasons analyzes the differences between desired and actual resources
-func (sc *DefaultStateComparator) determineUpdateReasons(desired, actual Resource) []string {
+// and returns the coarse reasons along with the field-level differences
+func (sc *DefaultStateComparator) determineUpdateReasons(desired, actual Resource) ([]string, []FieldDiff) {
 	reasons := make([]string, 0)
 
 	desiredLabels := desired.GetLabels()
@@ -178,7 +313,13 @@ func (sc *DefaultStateComparator) determineUpdateReasons(desired, actual Resourc
 		reasons = append(reasons, "configuration changed")
 	}
 
-	return reasons
+	fieldDiffs, err := diffResources(desired, actual, sc.IgnoredLabelKeys)
+	if err != nil {
+		reasons = append(reasons, "resource type conversion failed")
+		fieldDiffs = make([]FieldDiff, 0)
+	}
+
+	return reasons, fieldDiffs
 }
 
 // compareContainerResources compares container-specific fields
@@ -193,11 +334,35 @@ func (sc *DefaultStateComparator) compareContainerResources(desired, actual Reso
 		return reasons
 	}
 
+	if isContainerPaused(actualContainer) {
+		reasons = append(reasons, "container is paused")
+	}
+
+	if needsRestart(desiredContainer, actualContainer) {
+		if actualContainer.GetAnnotations()[labels.AnnotationStopped] == neverStartedState {
+			reasons = append(reasons, "container was never started")
+		} else {
+			reasons = append(reasons, "container is stopped")
+		}
+	}
+
+	if restartRequested(desiredContainer, actualContainer) {
+		reasons = append(reasons, "restart nonce changed")
+	}
+
+	if forcesRecreate(desiredContainer) {
+		reasons = append(reasons, "recreate forced by annotation")
+	}
+
+	if !sc.compareMaps(userAnnotations(desiredContainer), userAnnotations(actualContainer)) {
+		reasons = append(reasons, "annotations changed")
+	}
+
 	if desiredContainer.Spec.Image != actualContainer.Spec.Image {
 		reasons = append(reasons, "image changed")
 	}
 
-	if !sc.compareStringSlices(desiredContainer.Spec.Command, actualContainer.Spec.Command) {
+	if desiredCommand, err := containerCommand(desiredContainer.Spec); err != nil || !sc.compareStringSlices(desiredCommand, actualContainer.Spec.Command) {
 		reasons = append(reasons, "command changed")
 	}
 
@@ -240,6 +405,10 @@ func (sc *DefaultStateComparator) compareNetworkResources(desired, actual Resour
 		reasons = append(reasons, "subnet changed")
 	}
 
+	if desiredNetwork.Spec.Internal != actualNetwork.Spec.Internal {
+		reasons = append(reasons, "internal changed")
+	}
+
 	if !sc.compareMaps(desiredNetwork.Spec.Options, actualNetwork.Spec.Options) {
 		reasons = append(reasons, "options changed")
 	}
@@ -294,7 +463,7 @@ func (sc *DefaultStateComparator) compareSecretResources(desired, actual Resourc
 		return reasons
 	}
 
-	if desiredSecret.Spec.Type != actualSecret.Spec.Type {
+	if secretType(desiredSecret.Spec) != secretType(actualSecret.Spec) {
 		reasons = append(reasons, "secret type changed")
 	}
 
@@ -319,7 +488,11 @@ func (sc *DefaultStateComparator) getResourceKey(resource Resource) string {
 	return fmt.Sprintf("%s/%s", resource.GetType(), resource.GetName())
 }
 
+// compareMaps compares two label or annotation maps, leaving out ignored keys
 func (sc *DefaultStateComparator) compareMaps(map1, map2 map[string]string) bool {
+	map1 = sc.withoutIgnoredKeys(map1)
+	map2 = sc.withoutIgnoredKeys(map2)
+
 	if len(map1) != len(map2) {
 		return false
 	}
@@ -333,6 +506,32 @@ func (sc *DefaultStateComparator) compareMaps(map1, map2 map[string]string) bool
 	return true
 }
 
+// withoutIgnoredKeys returns a copy of values without the keys matching IgnoredLabelKeys
+func (sc *DefaultStateComparator) withoutIgnoredKeys(values map[string]string) map[string]string {
+	return withoutMatchingKeys(values, sc.IgnoredLabelKeys)
+}
+
+// withoutMatchingKeys returns a copy of values without the keys matching one of patterns
+func withoutMatchingKeys(values map[string]string, patterns []string) map[string]string {
+	filtered := make(map[string]string, len(values))
+	for key, value := range values {
+		if !matchesKeyPattern(key, patterns) {
+			filtered[key] = value
+		}
+	}
+	return filtered
+}
+
+// matchesKeyPattern reports whether key matches one of patterns
+func matchesKeyPattern(key string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, key); pattern == key || (err == nil && matched) {
+			return true
+		}
+	}
+	return false
+}
+
 func (sc *DefaultStateComparator) compareStringSlices(slice1, slice2 []string) bool {
 	if len(slice1) != len(slice2) {
 		return false
@@ -0,0 +1,323 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"slices"
+	"testing"
+)
+
+func TestStateComparator_CompareStatesAttachesFieldDiffs(t *testing.T) {
+	comparator := NewStateComparator()
+	comparator.SetResourceManager(ResourceTypeContainer, NewContainerManager(podman.NewMockPodmanClient()))
+
+	actual := newExplainTestContainer("nginx:1.25")
+	desired := newExplainTestContainer("nginx:1.25")
+	desired.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}}
+
+	diff, err := comparator.CompareStates([]Resource{desired}, []Resource{actual})
+	if err != nil {
+		t.Fatalf("CompareStates failed: %v", err)
+	}
+
+	if len(diff.ToUpdate) != 1 {
+		t.Fatalf("Expected 1 resource to update, got %d", len(diff.ToUpdate))
+	}
+
+	pair := diff.ToUpdate[0]
+	if len(pair.Reasons) == 0 {
+		t.Error("Expected update reasons to be attached to the resource pair")
+	}
+
+	if len(pair.Diffs) != 1 {
+		t.Fatalf("Expected exactly 1 field diff, got %d: %+v", len(pair.Diffs), pair.Diffs)
+	}
+
+	fieldDiff := pair.Diffs[0]
+	if fieldDiff.Path != "spec.ports" {
+		t.Errorf("Expected diff path 'spec.ports', got '%s'", fieldDiff.Path)
+	}
+	if fieldDiff.OldValue != "" {
+		t.Errorf("Expected empty old value, got '%s'", fieldDiff.OldValue)
+	}
+	if fieldDiff.NewValue != "[8080:80/tcp]" {
+		t.Errorf("Expected new value '[8080:80/tcp]', got '%s'", fieldDiff.NewValue)
+	}
+}
+
+func TestStateComparator_ShouldUpdateReportsRestartNonce(t *testing.T) {
+	comparator := NewStateComparator()
+	comparator.SetResourceManager(ResourceTypeContainer, NewContainerManager(podman.NewMockPodmanClient()))
+
+	actual := newExplainTestContainer("nginx:1.25")
+	actual.SetAnnotations(map[string]string{labels.AnnotationRestartNonce: "1"})
+	desired := newExplainTestContainer("nginx:1.25")
+	desired.SetAnnotations(map[string]string{labels.AnnotationRestartNonce: "2", labels.AnnotationForceRecreate: "true"})
+
+	shouldUpdate, reasons, err := comparator.ShouldUpdate(desired, actual)
+	if err != nil {
+		t.Fatalf("ShouldUpdate failed: %v", err)
+	}
+	if !shouldUpdate {
+		t.Fatal("Expected a changed restart nonce to require an update")
+	}
+	for _, expected := range []string{"restart nonce changed", "recreate forced by annotation"} {
+		if !slices.Contains(reasons, expected) {
+			t.Errorf("Expected reason %q, got %v", expected, reasons)
+		}
+	}
+}
+
+func TestStateComparator_SecretDiffsHideValues(t *testing.T) {
+	comparator := NewStateComparator()
+	comparator.SetResourceManager(ResourceTypeSecret, NewSecretManager(podman.NewMockPodmanClient()))
+
+	actual := NewSecretResource()
+	actual.ObjectMeta.Name = "db"
+	actual.Spec.Data = map[string]string{"password": "old-secret"}
+
+	desired := NewSecretResource()
+	desired.ObjectMeta.Name = "db"
+	desired.Spec.Data = map[string]string{"password": "new-secret"}
+
+	diff, err := comparator.CompareStates([]Resource{desired}, []Resource{actual})
+	if err != nil {
+		t.Fatalf("CompareStates failed: %v", err)
+	}
+
+	if len(diff.ToUpdate) != 1 {
+		t.Fatalf("Expected 1 resource to update, got %d", len(diff.ToUpdate))
+	}
+
+	for _, fieldDiff := range diff.ToUpdate[0].Diffs {
+		if fieldDiff.OldValue == "old-secret" || fieldDiff.NewValue == "new-secret" {
+			t.Errorf("Secret value leaked into field diff: %+v", fieldDiff)
+		}
+	}
+}
+
+func TestReconciliationController_DryRunIncludesFieldDiffs(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	result, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.26")}, "demo", true)
+	if err != nil {
+		t.Fatalf("Dry-run reconcile failed: %v", err)
+	}
+
+	if len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected 1 updated resource, got %d", len(result.UpdatedResources))
+	}
+
+	diffs := result.UpdatedResources[0].Diffs
+	if len(diffs) != 1 || diffs[0].Path != "spec.image" {
+		t.Fatalf("Expected a single spec.image diff, got %+v", diffs)
+	}
+	if diffs[0].OldValue != "nginx:1.25" || diffs[0].NewValue != "nginx:1.26" {
+		t.Errorf("Unexpected image diff values: %+v", diffs[0])
+	}
+}
+
+func TestStateComparator_IgnoredLabelKeys(t *testing.T) {
+	comparator := NewStateComparator().(*DefaultStateComparator)
+	comparator.SetIgnoredLabelKeys([]string{"ci.example.com/*"})
+
+	actual := NewNetworkResource()
+	actual.ObjectMeta.Name = "backend"
+	actual.SetLabels(map[string]string{
+		"tier":                   "db",
+		"ci.example.com/build":   "41",
+		"cutepod.io/spec-hash":   "abc123",
+		"ci.example.com/started": "2024-05-01T10:00:00Z",
+	})
+
+	desired := NewNetworkResource()
+	desired.ObjectMeta.Name = "backend"
+	desired.SetLabels(map[string]string{
+		"tier":                 "db",
+		"ci.example.com/build": "42",
+	})
+
+	diff, err := comparator.CompareStates([]Resource{desired}, []Resource{actual})
+	if err != nil {
+		t.Fatalf("CompareStates failed: %v", err)
+	}
+	if len(diff.Unchanged) != 1 || len(diff.ToUpdate) != 0 {
+		t.Fatalf("Expected an ignored-label-only change to be unchanged, got %+v", diff.ToUpdate)
+	}
+
+	desired.GetLabels()["tier"] = "cache"
+	diff, err = comparator.CompareStates([]Resource{desired}, []Resource{actual})
+	if err != nil {
+		t.Fatalf("CompareStates failed: %v", err)
+	}
+	if len(diff.ToUpdate) != 1 {
+		t.Fatalf("Expected a change to a compared label to require an update, got %+v", diff)
+	}
+	if diffs := diff.ToUpdate[0].Diffs; len(diffs) != 1 || diffs[0].NewValue != "[tier=cache]" {
+		t.Errorf("Expected the diff to leave out ignored labels, got %+v", diffs)
+	}
+}
+
+func TestReconcile_IgnoredAnnotationDoesNotRecreate(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient, WithRecreateOnAnnotationChange(), WithIgnoredLabelKeys("ci.example.com/*"))
+	ctx := context.Background()
+
+	newContainer := func(build, team string) Resource {
+		container := newExplainTestContainer("nginx:1.25")
+		container.SetAnnotations(map[string]string{"ci.example.com/build": build, "team": team})
+		return container
+	}
+	if _, err := controller.Reconcile(ctx, []Resource{newContainer("41", "web")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	result, err := controller.Reconcile(ctx, []Resource{newContainer("42", "web")}, "demo", false)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.UpdatedResources) != 0 || mockClient.GetCallCount("CreateContainer") != 1 {
+		t.Fatalf("Expected an ignored annotation not to recreate the container, got %+v", result.UpdatedResources)
+	}
+
+	result, err = controller.Reconcile(ctx, []Resource{newContainer("43", "api")}, "demo", true)
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if len(result.UpdatedResources) != 1 {
+		t.Fatalf("Expected a compared annotation to update the container, got %+v", result.UpdatedResources)
+	}
+	diffs := result.UpdatedResources[0].Diffs
+	if len(diffs) != 1 || diffs[0].Path != "metadata.annotations" || diffs[0].OldValue != "[team=web]" || diffs[0].NewValue != "[team=api]" {
+		t.Errorf("Expected the diff to leave out ignored annotations, got %+v", diffs)
+	}
+}
+
+// countingManager counts how often resources are compared
+type countingManager struct {
+	ResourceManager
+	compares int
+}
+
+func (m *countingManager) CompareResources(desired, actual Resource) (bool, error) {
+	m.compares++
+	return m.ResourceManager.CompareResources(desired, actual)
+}
+
+func (m *countingManager) comparisonKey(desired, actual Resource) (string, bool) {
+	return m.ResourceManager.(comparisonKeyer).comparisonKey(desired, actual)
+}
+
+func TestStateComparator_CachesUnchangedVerdicts(t *testing.T) {
+	ctx := context.Background()
+	manager := &countingManager{ResourceManager: NewContainerManager(podman.NewMockPodmanClient())}
+	comparator := NewStateComparator().(*DefaultStateComparator)
+	comparator.SetResourceManager(ResourceTypeContainer, manager)
+
+	desired := newExplainTestContainer("nginx:1.25")
+	if err := manager.CreateResource(ctx, desired); err != nil {
+		t.Fatalf("CreateResource failed: %v", err)
+	}
+
+	compare := func(desired Resource) *StateDiff {
+		t.Helper()
+		actual, err := manager.GetActualState(ctx, "demo")
+		if err != nil {
+			t.Fatalf("GetActualState failed: %v", err)
+		}
+		diff, err := comparator.CompareStates([]Resource{desired}, actual)
+		if err != nil {
+			t.Fatalf("CompareStates failed: %v", err)
+		}
+		return diff
+	}
+
+	for range 3 {
+		if diff := compare(desired); len(diff.Unchanged) != 1 {
+			t.Fatalf("Expected the container to be unchanged, got %+v", diff)
+		}
+	}
+	if manager.compares != 1 {
+		t.Errorf("Expected the unchanged pair to be compared once, got %d", manager.compares)
+	}
+
+	// A desired resource changed in place is only compared again once the cache is cleared
+	desired.Spec.Image = "nginx:1.26"
+	comparator.ClearComparisonCache()
+	if diff := compare(desired); len(diff.ToUpdate) != 1 || manager.compares != 2 {
+		t.Errorf("Expected the changed container to be compared again and updated, got %d compares", manager.compares)
+	}
+
+	// A change to the actual resource outside of its spec hash invalidates the verdict
+	desired.Spec.Image = "nginx:1.25"
+	compare(desired)
+	actual, err := manager.GetActualState(ctx, "demo")
+	if err != nil {
+		t.Fatalf("GetActualState failed: %v", err)
+	}
+	paused := actual[0].(*ContainerResource)
+	paused.SetAnnotations(labels.MergeLabels(paused.GetAnnotations(), map[string]string{labels.AnnotationPaused: "true"}))
+	diff, err := comparator.CompareStates([]Resource{desired}, actual)
+	if err != nil {
+		t.Fatalf("CompareStates failed: %v", err)
+	}
+	if len(diff.ToUpdate) != 1 || manager.compares != 4 {
+		t.Errorf("Expected the paused container to be compared again and updated, got %d compares", manager.compares)
+	}
+
+	// Comparisons that read a Containerfile are never cached
+	built := newExplainTestContainer("localhost/app:dev")
+	built.Spec.Build = &BuildSpec{Context: t.TempDir()}
+	for range 2 {
+		compare(built)
+	}
+	if manager.compares != 6 {
+		t.Errorf("Expected build containers to be compared every time, got %d compares", manager.compares)
+	}
+}
+
+func BenchmarkStateComparator_SteadyState(b *testing.B) {
+	manager := NewContainerManager(podman.NewMockPodmanClient())
+	comparator := NewStateComparator().(*DefaultStateComparator)
+	comparator.SetResourceManager(ResourceTypeContainer, manager)
+
+	desired := newExplainTestContainer("nginx:1.25")
+	desired.Spec.Env = []EnvVar{{Name: "MODE", Value: "production"}, {Name: "WORKERS", Value: "4"}}
+	desired.Spec.Ports = []ContainerPort{{ContainerPort: 80, HostPort: 8080}, {ContainerPort: 443, HostPort: 8443}}
+	desired.Spec.Networks = []NetworkAttachment{{Name: "backend"}}
+	if err := manager.CreateResource(context.Background(), desired); err != nil {
+		b.Fatalf("CreateResource failed: %v", err)
+	}
+	actual, err := manager.GetActualState(context.Background(), "demo")
+	if err != nil || len(actual) != 1 {
+		b.Fatalf("Expected 1 container, got %d (err: %v)", len(actual), err)
+	}
+	manifests := []Resource{desired}
+	if diff, err := comparator.CompareStates(manifests, actual); err != nil || len(diff.Unchanged) != 1 {
+		b.Fatalf("Expected the container to be unchanged, got %+v (err: %v)", diff, err)
+	}
+
+	b.Run("uncached", func(b *testing.B) {
+		for b.Loop() {
+			comparator.ClearComparisonCache()
+			if _, err := comparator.CompareStates(manifests, actual); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		for b.Loop() {
+			if _, err := comparator.CompareStates(manifests, actual); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
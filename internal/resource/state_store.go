@@ -0,0 +1,147 @@
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// StateStore persists the status of the last reconcile of each chart
+type StateStore interface {
+	// Save stores the status of the chart's last reconcile, replacing any earlier one
+	Save(chartName string, status *ReconciliationStatus) error
+
+	// Load returns the stored status of the chart, or nil if it was never saved
+	Load(chartName string) (*ReconciliationStatus, error)
+}
+
+// MemoryStateStore keeps statuses in memory, so they are lost when the process exits
+type MemoryStateStore struct {
+	mu       sync.RWMutex
+	statuses map[string]*ReconciliationStatus
+}
+
+// NewMemoryStateStore creates a new MemoryStateStore
+func NewMemoryStateStore() *MemoryStateStore {
+	return &MemoryStateStore{
+		statuses: make(map[string]*ReconciliationStatus),
+	}
+}
+
+// Save implements StateStore
+func (s *MemoryStateStore) Save(chartName string, status *ReconciliationStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.statuses[chartName] = status
+	return nil
+}
+
+// Load implements StateStore
+func (s *MemoryStateStore) Load(chartName string) (*ReconciliationStatus, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.statuses[chartName], nil
+}
+
+// FileStateStore keeps the status of each chart in <dir>/<chart>.json, so it survives
+// restarts. Error causes are not persisted; their messages carry the details.
+type FileStateStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// NewFileStateStore creates a new FileStateStore writing to dir, which is created on
+// the first save
+func NewFileStateStore(dir string) *FileStateStore {
+	return &FileStateStore{
+		dir: dir,
+	}
+}
+
+// Save implements StateStore. The file is replaced atomically, so a crash never leaves
+// a partially written status behind.
+func (s *FileStateStore) Save(chartName string, status *ReconciliationStatus) error {
+	path, err := s.statusPath(chartName)
+	if err != nil {
+		return err
+	}
+
+	// Causes are arbitrary error values that cannot be decoded again
+	stored := *status
+	stored.Errors = make([]*ReconciliationError, 0, len(status.Errors))
+	for _, reconcileErr := range status.Errors {
+		withoutCause := *reconcileErr
+		withoutCause.Cause = nil
+		stored.Errors = append(stored.Errors, &withoutCause)
+	}
+
+	data, err := json.MarshalIndent(&stored, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status of chart %s: %w", chartName, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create state directory %s: %w", s.dir, err)
+	}
+
+	tempFile, err := os.CreateTemp(s.dir, ".cutepod-state-*")
+	if err != nil {
+		return fmt.Errorf("failed to save status of chart %s: %w", chartName, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to save status of chart %s: %w", chartName, err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to save status of chart %s: %w", chartName, err)
+	}
+
+	if err := os.Rename(tempFile.Name(), path); err != nil {
+		return fmt.Errorf("failed to save status of chart %s: %w", chartName, err)
+	}
+
+	return nil
+}
+
+// Load implements StateStore
+func (s *FileStateStore) Load(chartName string) (*ReconciliationStatus, error) {
+	path, err := s.statusPath(chartName)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	data, err := os.ReadFile(path)
+	s.mu.Unlock()
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status of chart %s: %w", chartName, err)
+	}
+
+	var status ReconciliationStatus
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("failed to decode status of chart %s: %w", chartName, err)
+	}
+
+	return &status, nil
+}
+
+// statusPath returns the file holding the status of the chart
+func (s *FileStateStore) statusPath(chartName string) (string, error) {
+	if chartName == "" || chartName == "." || chartName == ".." || chartName != filepath.Base(chartName) {
+		return "", fmt.Errorf("invalid chart name %q", chartName)
+	}
+	return filepath.Join(s.dir, chartName+".json"), nil
+}
@@ -0,0 +1,82 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFileStateStore_SaveAndLoad(t *testing.T) {
+	store := NewFileStateStore(t.TempDir())
+
+	status, err := store.Load("demo")
+	if err != nil || status != nil {
+		t.Fatalf("Expected no status before the first save, got %+v (%v)", status, err)
+	}
+
+	saved := &ReconciliationStatus{
+		ChartName:      "demo",
+		LastReconciled: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		ResourceCounts: map[string]int{"created": 2},
+		Status:         "degraded",
+		Errors: []*ReconciliationError{
+			NewPodmanAPIError(ResourceReference{Type: ResourceTypeContainer, Name: "web"}, "failed to create resource", errors.New("boom"), true),
+		},
+	}
+	if err := store.Save("demo", saved); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	loaded, err := store.Load("demo")
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if !loaded.LastReconciled.Equal(saved.LastReconciled) || loaded.Status != "degraded" || loaded.ResourceCounts["created"] != 2 {
+		t.Errorf("Expected the saved status back, got %+v", loaded)
+	}
+	if len(loaded.Errors) != 1 || loaded.Errors[0].Message != "failed to create resource" || loaded.Errors[0].Cause != nil {
+		t.Errorf("Expected the error to be kept without its cause, got %+v", loaded.Errors)
+	}
+	if saved.Errors[0].Cause == nil {
+		t.Error("Expected saving to leave the caller's status untouched")
+	}
+}
+
+func TestFileStateStore_RejectsPathLikeChartNames(t *testing.T) {
+	store := NewFileStateStore(t.TempDir())
+
+	for _, chartName := range []string{"", "..", "../demo", "a/b"} {
+		if err := store.Save(chartName, &ReconciliationStatus{}); err == nil {
+			t.Errorf("Expected chart name %q to be rejected", chartName)
+		}
+	}
+}
+
+func TestGetStatus_SurvivesControllerRestart(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	controller := NewReconciliationController(mockClient, WithStateStore(NewFileStateStore(dir)))
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	before, err := controller.GetStatus("demo")
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+
+	restarted := NewReconciliationController(mockClient, WithStateStore(NewFileStateStore(dir)))
+	after, err := restarted.GetStatus("demo")
+	if err != nil {
+		t.Fatalf("GetStatus failed: %v", err)
+	}
+	if after.LastReconciled.IsZero() || !after.LastReconciled.Equal(before.LastReconciled) {
+		t.Errorf("Expected the last reconcile time %v to survive the restart, got %v", before.LastReconciled, after.LastReconciled)
+	}
+	if after.ResourceCounts["created"] != 1 {
+		t.Errorf("Expected the recorded counts to survive the restart, got %v", after.ResourceCounts)
+	}
+}
@@ -0,0 +1,67 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResourceStats aggregates resource usage of the running containers of a chart
+type ResourceStats struct {
+	ChartName  string                            `json:"chart_name"`
+	Containers map[string]*podman.ContainerStats `json:"containers"`
+	Total      podman.ContainerStats             `json:"total"`
+	Timestamp  time.Time                         `json:"timestamp"`
+}
+
+// GetResourceStats samples resource usage of every running container of the chart.
+// Stopped and paused containers are not included.
+func (rc *DefaultReconciliationController) GetResourceStats(ctx context.Context, chartName string) (*ResourceStats, error) {
+	connectedClient := podman.NewConnectedClient(rc.podmanClient)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	containers, err := podmanClient.ListContainers(
+		ctx,
+		map[string][]string{
+			"label": {labels.GetChartLabelValueWithPrefix(chartName, rc.labelPrefix)},
+		},
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list containers: %w", err)
+	}
+
+	stats := &ResourceStats{
+		ChartName:  chartName,
+		Containers: make(map[string]*podman.ContainerStats, len(containers)),
+		Timestamp:  time.Now(),
+	}
+
+	for _, container := range containers {
+		name := strings.TrimPrefix(container.Names[0], "/")
+
+		containerStats, err := podmanClient.ContainerStats(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get stats for container %s: %w", name, err)
+		}
+
+		stats.Containers[name] = containerStats
+		stats.Total.CPUPercent += containerStats.CPUPercent
+		stats.Total.MemoryUsage += containerStats.MemoryUsage
+		stats.Total.MemoryLimit += containerStats.MemoryLimit
+		stats.Total.NetworkRx += containerStats.NetworkRx
+		stats.Total.NetworkTx += containerStats.NetworkTx
+		stats.Total.BlockRead += containerStats.BlockRead
+		stats.Total.BlockWrite += containerStats.BlockWrite
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,74 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"testing"
+)
+
+func TestGetResourceStats_AggregatesRunningContainers(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	web := newExplainTestContainer("nginx:1.25")
+	worker := newExplainTestContainer("busybox:1.36")
+	worker.ObjectMeta.Name = "worker"
+
+	if _, err := controller.Reconcile(ctx, []Resource{web, worker}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	mockClient.SetContainerStats("web", podman.ContainerStats{CPUPercent: 10, MemoryUsage: 100, MemoryLimit: 1000, NetworkRx: 5})
+	mockClient.SetContainerStats("worker", podman.ContainerStats{CPUPercent: 2.5, MemoryUsage: 50, MemoryLimit: 1000, NetworkRx: 7})
+
+	stats, err := controller.GetResourceStats(ctx, "demo")
+	if err != nil {
+		t.Fatalf("GetResourceStats failed: %v", err)
+	}
+
+	if len(stats.Containers) != 2 {
+		t.Fatalf("Expected stats for 2 containers, got %d", len(stats.Containers))
+	}
+	if stats.Containers["web"].CPUPercent != 10 {
+		t.Errorf("Expected web CPU 10%%, got %v", stats.Containers["web"].CPUPercent)
+	}
+	if stats.Total.CPUPercent != 12.5 {
+		t.Errorf("Expected total CPU 12.5%%, got %v", stats.Total.CPUPercent)
+	}
+	if stats.Total.MemoryUsage != 150 || stats.Total.MemoryLimit != 2000 {
+		t.Errorf("Expected total memory 150/2000, got %d/%d", stats.Total.MemoryUsage, stats.Total.MemoryLimit)
+	}
+	if stats.Total.NetworkRx != 12 {
+		t.Errorf("Expected total network rx 12, got %d", stats.Total.NetworkRx)
+	}
+
+	// Stopped containers are left out
+	if err := mockClient.StopContainer(ctx, "worker", 0); err != nil {
+		t.Fatalf("StopContainer failed: %v", err)
+	}
+
+	stats, err = controller.GetResourceStats(ctx, "demo")
+	if err != nil {
+		t.Fatalf("GetResourceStats failed: %v", err)
+	}
+	if _, exists := stats.Containers["worker"]; exists || len(stats.Containers) != 1 {
+		t.Errorf("Expected only the running container, got %v", stats.Containers)
+	}
+}
+
+func TestGetResourceStats_ReportsFailingContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	mockClient.SetShouldFailOperation("ContainerStats", true)
+
+	if _, err := controller.GetResourceStats(ctx, "demo"); err == nil {
+		t.Error("Expected an error when stats cannot be read")
+	}
+}
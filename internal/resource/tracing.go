@@ -0,0 +1,91 @@
+package resource
+
+import (
+	"context"
+	"errors"
+)
+
+// Tracer starts spans around reconciliation work. It mirrors the small subset of the
+// OpenTelemetry tracer API the controller needs, so an OpenTelemetry tracer can be
+// plugged in through a thin adapter without cutepod depending on it.
+type Tracer interface {
+	// Start creates a span as a child of any span already carried by ctx
+	Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span)
+}
+
+// Span is a single traced operation
+type Span interface {
+	// SetAttributes adds attributes to the span
+	SetAttributes(attributes ...SpanAttribute)
+
+	// RecordError marks the span as failed with the given error
+	RecordError(err error)
+
+	// End completes the span
+	End()
+}
+
+// SpanAttribute is a key/value pair recorded on a span
+type SpanAttribute struct {
+	Key   string
+	Value string
+}
+
+// Attribute creates a span attribute
+func Attribute(key, value string) SpanAttribute {
+	return SpanAttribute{Key: key, Value: value}
+}
+
+// Span attribute keys used by the reconciliation controller
+const (
+	SpanAttributeChart        = "cutepod.chart"
+	SpanAttributeDryRun       = "cutepod.dry_run"
+	SpanAttributeResourceType = "cutepod.resource.type"
+	SpanAttributeResourceName = "cutepod.resource.name"
+	SpanAttributeError        = "error"
+)
+
+// noopTracer is the default tracer; it creates spans that do nothing
+type noopTracer struct{}
+
+// NewNoopTracer returns a tracer that discards all spans
+func NewNoopTracer() Tracer {
+	return noopTracer{}
+}
+
+// Start implements Tracer interface
+func (noopTracer) Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attributes ...SpanAttribute) {}
+func (noopSpan) RecordError(err error)                     {}
+func (noopSpan) End()                                      {}
+
+// resourceSpanAttributes returns the attributes identifying a resource on a span
+func resourceSpanAttributes(resource Resource) []SpanAttribute {
+	return []SpanAttribute{
+		Attribute(SpanAttributeResourceType, string(resource.GetType())),
+		Attribute(SpanAttributeResourceName, resource.GetName()),
+	}
+}
+
+// endSpan records err on the span, if any, and ends it
+func endSpan(span Span, err error) {
+	if err != nil {
+		span.SetAttributes(Attribute(SpanAttributeError, "true"))
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// endActionSpan ends a per-resource span, marking it failed when the action recorded an error
+func endActionSpan(span Span, action *ResourceAction) {
+	if action.Error != "" {
+		endSpan(span, errors.New(action.Error))
+		return
+	}
+	span.End()
+}
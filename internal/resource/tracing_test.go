@@ -0,0 +1,130 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"sync"
+	"testing"
+)
+
+// recordingTracer keeps every span it starts so tests can inspect them
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name       string
+	attributes map[string]string
+	errors     []error
+	ended      bool
+}
+
+func (t *recordingTracer) Start(ctx context.Context, spanName string, attributes ...SpanAttribute) (context.Context, Span) {
+	span := &recordingSpan{name: spanName, attributes: make(map[string]string)}
+	span.SetAttributes(attributes...)
+
+	t.mu.Lock()
+	t.spans = append(t.spans, span)
+	t.mu.Unlock()
+
+	return ctx, span
+}
+
+func (t *recordingTracer) find(name string) *recordingSpan {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, span := range t.spans {
+		if span.name == name {
+			return span
+		}
+	}
+	return nil
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...SpanAttribute) {
+	for _, attribute := range attributes {
+		s.attributes[attribute.Key] = attribute.Value
+	}
+}
+
+func (s *recordingSpan) RecordError(err error) {
+	s.errors = append(s.errors, err)
+}
+
+func (s *recordingSpan) End() {
+	s.ended = true
+}
+
+func TestReconcile_CreatesPhaseAndResourceSpans(t *testing.T) {
+	tracer := &recordingTracer{}
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithTracer(tracer))
+
+	container := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(context.Background(), []Resource{container}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	root := tracer.find("reconcile")
+	if root == nil {
+		t.Fatal("Expected a root reconcile span")
+	}
+	if root.attributes[SpanAttributeChart] != "demo" {
+		t.Errorf("Expected chart attribute 'demo', got '%s'", root.attributes[SpanAttributeChart])
+	}
+
+	phases := []string{
+		"reconcile.validate",
+		"reconcile.build_graph",
+		"reconcile.get_state",
+		"reconcile.compare",
+		"reconcile.execute",
+	}
+	for _, phase := range phases {
+		span := tracer.find(phase)
+		if span == nil {
+			t.Errorf("Expected a span for phase %s", phase)
+			continue
+		}
+		if !span.ended {
+			t.Errorf("Expected span %s to be ended", phase)
+		}
+	}
+
+	create := tracer.find("reconcile.create")
+	if create == nil {
+		t.Fatal("Expected a per-resource create span")
+	}
+	if create.attributes[SpanAttributeResourceType] != string(ResourceTypeContainer) {
+		t.Errorf("Expected resource type attribute 'container', got '%s'", create.attributes[SpanAttributeResourceType])
+	}
+	if create.attributes[SpanAttributeResourceName] != "web" {
+		t.Errorf("Expected resource name attribute 'web', got '%s'", create.attributes[SpanAttributeResourceName])
+	}
+	if len(create.errors) != 0 {
+		t.Errorf("Expected no errors on create span, got %v", create.errors)
+	}
+}
+
+func TestReconcile_RecordsErrorOnFailedPhase(t *testing.T) {
+	tracer := &recordingTracer{}
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithTracer(tracer))
+
+	container := newExplainTestContainer("nginx:1.25")
+	duplicate := newExplainTestContainer("nginx:1.25")
+	if _, err := controller.Reconcile(context.Background(), []Resource{container, duplicate}, "demo", false); err == nil {
+		t.Fatal("Expected validation error for duplicate resources")
+	}
+
+	validate := tracer.find("reconcile.validate")
+	if validate == nil || len(validate.errors) == 0 {
+		t.Fatal("Expected the validate span to record an error")
+	}
+	if validate.attributes[SpanAttributeError] != "true" {
+		t.Error("Expected the validate span to be marked as errored")
+	}
+
+	if root := tracer.find("reconcile"); root == nil || len(root.errors) == 0 {
+		t.Error("Expected the root span to record the error")
+	}
+}
@@ -0,0 +1,144 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// resourceStopper is implemented by managers whose resources can be stopped while the
+// resources they depend on are replaced, and started again afterwards
+type resourceStopper interface {
+	// StopResource stops a running resource, reporting whether it was running
+	StopResource(ctx context.Context, resource Resource) (bool, error)
+	// StartResource starts a resource that is not running
+	StartResource(ctx context.Context, resource Resource) error
+}
+
+// updatesInDependencyOrder orders updates by the creation level of their resource, so
+// that a resource is updated after the ones it depends on, such as a secret before the
+// containers recreated to pick it up. Updates keep their order within a level.
+func updatesInDependencyOrder(toUpdate []ResourcePair, creationOrder [][]Resource) []ResourcePair {
+	levels := make(map[ResourceReference]int)
+	for levelIndex, level := range creationOrder {
+		for _, resource := range level {
+			levels[ResourceReference{Type: resource.GetType(), Name: resource.GetName()}] = levelIndex
+		}
+	}
+
+	ordered := slices.Clone(toUpdate)
+	slices.SortStableFunc(ordered, func(a, b ResourcePair) int {
+		return levels[ResourceReference{Type: a.Desired.GetType(), Name: a.Desired.GetName()}] -
+			levels[ResourceReference{Type: b.Desired.GetType(), Name: b.Desired.GetName()}]
+	})
+	return ordered
+}
+
+// drainDependents stops the running containers that depend on a volume, network or
+// secret that its update replaces. Dependents are stopped before any update, dependents
+// of dependents first, and are returned in creation order to be started again once the
+// updates are done.
+func (rc *DefaultReconciliationController) drainDependents(ctx context.Context, result *ReconciliationResult, diff *StateDiff, creationOrder [][]Resource) []Resource {
+	stopper, ok := rc.managers[ResourceTypeContainer].(resourceStopper)
+	if !ok {
+		return nil
+	}
+
+	replaced := make(map[ResourceReference]bool)
+	for _, pair := range diff.ToUpdate {
+		if replacedByUpdate(pair) {
+			replaced[ResourceReference{Type: pair.Desired.GetType(), Name: pair.Desired.GetName()}] = true
+		}
+	}
+	if len(replaced) == 0 {
+		return nil
+	}
+
+	// Replicas of a rolling update are left to the rollout, which stops and recreates
+	// them a batch at a time instead of all at once
+	rolling := make(map[string]bool)
+	for _, replicas := range rc.replicaGroups(diff.ToUpdate) {
+		for _, pair := range replicas {
+			rolling[pair.Desired.GetName()] = true
+		}
+	}
+
+	// Only containers that already exist can be running
+	existing := make(map[string]bool)
+	for _, resource := range diff.Unchanged {
+		if resource.GetType() == ResourceTypeContainer {
+			existing[resource.GetName()] = true
+		}
+	}
+	for _, pair := range diff.ToUpdate {
+		if pair.Desired.GetType() == ResourceTypeContainer {
+			existing[pair.Desired.GetName()] = true
+		}
+	}
+
+	// Creation order puts a container after the ones it depends on, so its dependents
+	// have been found by the time it is reached
+	var dependents []Resource
+	for _, level := range creationOrder {
+		for _, resource := range level {
+			if resource.GetType() != ResourceTypeContainer || !existing[resource.GetName()] || rolling[resource.GetName()] {
+				continue
+			}
+			for _, dep := range resource.GetDependencies() {
+				if replaced[ResourceReference{Type: dep.Type, Name: dep.Name}] {
+					dependents = append(dependents, resource)
+					replaced[ResourceReference{Type: ResourceTypeContainer, Name: resource.GetName()}] = true
+					break
+				}
+			}
+		}
+	}
+
+	var drained []Resource
+	for _, resource := range slices.Backward(dependents) {
+		wasRunning, err := stopper.StopResource(ctx, resource)
+		if err != nil {
+			rc.addError(result, ErrorTypePodmanAPI,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to stop container before updating its dependencies: %v", err), err, true)
+			continue
+		}
+		if wasRunning {
+			drained = append(drained, resource)
+		}
+	}
+	slices.Reverse(drained)
+	return drained
+}
+
+// replacedByUpdate reports whether updating the resource removes what containers use.
+// Networks and named volumes are recreated, and secrets only when their data changed.
+// A hostPath directory is never removed, and an emptyDir one is kept while mounted.
+func replacedByUpdate(pair ResourcePair) bool {
+	switch desired := pair.Desired.(type) {
+	case *NetworkResource:
+		return true
+	case *VolumeResource:
+		return desired.Spec.Type == VolumeTypeVolume
+	case *SecretResource:
+		return secretRotated(pair)
+	}
+	return false
+}
+
+// restartDrained starts the containers drainDependents stopped. Containers recreated by
+// their own update are already running and are left alone.
+func (rc *DefaultReconciliationController) restartDrained(ctx context.Context, result *ReconciliationResult, drained []Resource) {
+	stopper, ok := rc.managers[ResourceTypeContainer].(resourceStopper)
+	if !ok {
+		return
+	}
+
+	for _, resource := range drained {
+		if err := stopper.StartResource(ctx, resource); err != nil {
+			rc.addError(result, ErrorTypePodmanAPI,
+				ResourceReference{Type: resource.GetType(), Name: resource.GetName()},
+				fmt.Sprintf("failed to start container after updating its dependencies: %v", err), err, true)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package resource
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -94,7 +95,8 @@ type VolumeSecurityContext struct {
 
 // SELinuxVolumeOptions defines SELinux options for volume mounts
 type SELinuxVolumeOptions struct {
-	Level string `json:"level,omitempty"` // "shared" (z flag) or "private" (Z flag)
+	Level   string `json:"level,omitempty"`   // "shared" (z flag) or "private" (Z flag)
+	Context string `json:"context,omitempty"` // Full context such as system_u:object_r:container_file_t:s0, overriding level
 }
 
 // VolumeOwnership defines ownership settings for volumes
@@ -257,6 +259,19 @@ func (v *VolumeResource) validateVolume() []error {
 	return errs
 }
 
+// validateSELinuxContext checks that a full SELinux context has its user, role, type and
+// level components. The level may hold colons itself, as in s0:c1,c2.
+func validateSELinuxContext(context string) error {
+	if strings.ContainsAny(context, "\" \t\n") {
+		return fmt.Errorf("SELinux context %q must not contain quotes or whitespace", context)
+	}
+	parts := strings.SplitN(context, ":", 4)
+	if len(parts) != 4 || slices.Contains(parts, "") {
+		return fmt.Errorf("SELinux context %q must have four colon-separated components, user:role:type:level, such as system_u:object_r:container_file_t:s0", context)
+	}
+	return nil
+}
+
 // validateSecurityContext validates volume security context
 func (v *VolumeResource) validateSecurityContext() []error {
 	var errs []error
@@ -273,6 +288,11 @@ func (v *VolumeResource) validateSecurityContext() []error {
 				errs = append(errs, fmt.Errorf("invalid seLinuxOptions.level: %s (supported: 'shared', 'private')", sc.SELinuxOptions.Level))
 			}
 		}
+		if sc.SELinuxOptions.Context != "" {
+			if err := validateSELinuxContext(sc.SELinuxOptions.Context); err != nil {
+				errs = append(errs, fmt.Errorf("invalid seLinuxOptions.context: %w", err))
+			}
+		}
 	}
 
 	// Validate ownership
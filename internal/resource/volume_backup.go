@@ -0,0 +1,588 @@
+package resource
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/rand"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/containers/podman/v5/pkg/specgen"
+)
+
+// defaultVolumeHelperImage runs the helper containers that read and write named volumes
+const defaultVolumeHelperImage = "docker.io/library/busybox:latest"
+
+// volumeHelperMountPath is where a helper container mounts the named volume it works on
+const volumeHelperMountPath = "/volume"
+
+// volumeRestoreStagingDir is the directory inside a volume an archive is extracted to,
+// before it replaces the volume's contents
+const volumeRestoreStagingDir = ".cutepod-restore"
+
+// volumeRestoreSwapScript replaces the contents of the mounted volume with those of the
+// staging directory
+const volumeRestoreSwapScript = `set -e
+cd ` + volumeHelperMountPath + `
+for entry in * .[!.]* ..?*; do
+	if [ "$entry" != ` + volumeRestoreStagingDir + ` ] && { [ -e "$entry" ] || [ -L "$entry" ]; }; then rm -rf "$entry"; fi
+done
+cd ` + volumeRestoreStagingDir + `
+for entry in * .[!.]* ..?*; do
+	if [ -e "$entry" ] || [ -L "$entry" ]; then mv "$entry" ..; fi
+done
+cd .. && rmdir ` + volumeRestoreStagingDir
+
+// BackupVolume writes the contents of a volume to a tar archive at destPath. Named
+// volumes are read through a helper container, since their data lives where Podman runs.
+// The archive is written next to destPath first and only renamed into place once it is
+// complete, so a failed backup never replaces an earlier one.
+func (vm *VolumeManager) BackupVolume(ctx context.Context, volume *VolumeResource, destPath string) error {
+	var sourcePath string
+	if volume.Spec.Type != VolumeTypeVolume {
+		var err error
+		if sourcePath, err = vm.resolveVolumeDataPath(ctx, volume); err != nil {
+			return err
+		}
+	}
+
+	// Creating the temporary archive doubles as the writability check of the destination
+	tempFile, err := os.CreateTemp(filepath.Dir(destPath), ".cutepod-backup-*")
+	if err != nil {
+		return fmt.Errorf("destination %s is not writable: %w", destPath, err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if sourcePath != "" {
+		err = writeVolumeArchive(sourcePath, tempFile)
+	} else {
+		err = vm.withVolumeHelper(ctx, volume, func(client podman.PodmanClient, helper string) error {
+			// A trailing "/." copies the contents without the directory itself
+			return client.CopyFromContainer(ctx, helper, volumeHelperMountPath+"/.", tempFile)
+		})
+	}
+	if err != nil {
+		tempFile.Close()
+		return fmt.Errorf("failed to back up volume '%s': %w", volume.GetName(), err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return fmt.Errorf("failed to back up volume '%s': %w", volume.GetName(), err)
+	}
+
+	if err := os.Rename(tempFile.Name(), destPath); err != nil {
+		return fmt.Errorf("failed to move backup into place at %s: %w", destPath, err)
+	}
+
+	return nil
+}
+
+// RestoreVolume replaces the contents of a volume with those of the tar archive at
+// srcPath. The archive is checked in full, then extracted into a staging directory inside
+// the volume, which only replaces the existing contents once extraction succeeded. Named
+// volumes are written through a helper container. Callers must make sure no running
+// container uses the volume.
+func (vm *VolumeManager) RestoreVolume(ctx context.Context, volume *VolumeResource, srcPath string) error {
+	if err := verifyVolumeArchive(srcPath); err != nil {
+		return fmt.Errorf("invalid archive %s: %w", srcPath, err)
+	}
+
+	archive, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("failed to open archive %s: %w", srcPath, err)
+	}
+	defer archive.Close()
+
+	if volume.Spec.Type == VolumeTypeVolume {
+		if err := vm.restoreNamedVolume(ctx, volume, archive); err != nil {
+			return fmt.Errorf("failed to restore volume '%s': %w", volume.GetName(), err)
+		}
+		return nil
+	}
+
+	targetPath, err := vm.resolveVolumeDataPath(ctx, volume)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(targetPath, 0755); err != nil {
+		return fmt.Errorf("failed to create volume path %s: %w", targetPath, err)
+	}
+
+	stagingPath, err := os.MkdirTemp(targetPath, volumeRestoreStagingDir+"-*")
+	if err != nil {
+		return fmt.Errorf("failed to create staging directory in %s: %w", targetPath, err)
+	}
+	if err := extractVolumeArchive(archive, stagingPath); err != nil {
+		os.RemoveAll(stagingPath)
+		return fmt.Errorf("failed to restore volume '%s': %w", volume.GetName(), err)
+	}
+	if err := replaceVolumeContents(targetPath, stagingPath); err != nil {
+		return fmt.Errorf("failed to restore volume '%s': %w", volume.GetName(), err)
+	}
+
+	return nil
+}
+
+// restoreNamedVolume uploads the archive into the staging directory of a named volume
+// through a helper container, and swaps it in once the upload succeeded
+func (vm *VolumeManager) restoreNamedVolume(ctx context.Context, volume *VolumeResource, archive io.Reader) error {
+	return vm.withVolumeHelper(ctx, volume, func(client podman.PodmanClient, helper string) error {
+		// Entries are moved under the staging directory while they are uploaded
+		reader, writer := io.Pipe()
+		go func() {
+			writer.CloseWithError(prefixArchiveEntries(archive, writer, volumeRestoreStagingDir))
+		}()
+
+		err := client.CopyToContainer(ctx, helper, volumeHelperMountPath, reader)
+		reader.Close()
+		if err != nil {
+			stagingPath := volumeHelperMountPath + "/" + volumeRestoreStagingDir
+			if _, cleanupErr := client.ExecContainer(ctx, helper, []string{"rm", "-rf", stagingPath}); cleanupErr != nil {
+				vm.logger.Warn("failed to remove restore staging directory", "volume", volume.GetName(), "error", cleanupErr)
+			}
+			return err
+		}
+
+		exitCode, err := client.ExecContainer(ctx, helper, []string{"sh", "-c", volumeRestoreSwapScript})
+		if err != nil {
+			return err
+		}
+		if exitCode != 0 {
+			return fmt.Errorf("replacing the volume contents exited with code %d", exitCode)
+		}
+		return nil
+	})
+}
+
+// withVolumeHelper runs fn against a helper container that mounts the named volume at
+// volumeHelperMountPath. The helper is removed once fn returns.
+func (vm *VolumeManager) withVolumeHelper(ctx context.Context, volume *VolumeResource, fn func(client podman.PodmanClient, helper string) error) error {
+	connectedClient := podman.NewConnectedClient(vm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	image := vm.helperImage
+	if image == "" {
+		image = defaultVolumeHelperImage
+	}
+	if _, err := podmanClient.GetImage(ctx, image); err != nil {
+		if err := podmanClient.PullImage(ctx, image); err != nil {
+			return fmt.Errorf("failed to pull volume helper image %s: %w", image, err)
+		}
+	}
+
+	spec := specgen.NewSpecGenerator(image, false)
+	spec.Name = "cutepod-volume-helper-" + strings.ToLower(rand.Text())
+	spec.Command = []string{"tail", "-f", "/dev/null"}
+	spec.Volumes = []*specgen.NamedVolume{{Name: volume.GetName(), Dest: volumeHelperMountPath}}
+	// The volume may be labeled for the containers that use it
+	spec.SelinuxOpts = []string{"disable"}
+
+	if _, err := podmanClient.CreateContainer(ctx, spec); err != nil {
+		return fmt.Errorf("failed to create volume helper container: %w", err)
+	}
+	defer func() {
+		// Cleaned up even when ctx was cancelled
+		cleanupCtx := context.WithoutCancel(ctx)
+		podmanClient.StopContainer(cleanupCtx, spec.Name, 0)
+		if err := podmanClient.RemoveContainer(cleanupCtx, spec.Name); err != nil {
+			vm.logger.Warn("failed to remove volume helper container", "container", spec.Name, "error", err)
+		}
+	}()
+
+	if err := podmanClient.StartContainer(ctx, spec.Name); err != nil {
+		return fmt.Errorf("failed to start volume helper container: %w", err)
+	}
+
+	return fn(podmanClient, spec.Name)
+}
+
+// replaceVolumeContents removes everything in targetPath but the staging directory, then
+// moves the staging directory's entries into targetPath
+func replaceVolumeContents(targetPath, stagingPath string) error {
+	entries, err := os.ReadDir(targetPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Name() == filepath.Base(stagingPath) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(targetPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	staged, err := os.ReadDir(stagingPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range staged {
+		if err := os.Rename(filepath.Join(stagingPath, entry.Name()), filepath.Join(targetPath, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(stagingPath)
+}
+
+// prefixArchiveEntries copies the archive read from r to w, moving every entry under dir
+func prefixArchiveEntries(r io.Reader, w io.Writer, dir string) error {
+	tarReader := tar.NewReader(r)
+	tarWriter := tar.NewWriter(w)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		header.Name = path.Join(dir, header.Name)
+		if header.Typeflag == tar.TypeDir {
+			header.Name += "/"
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tarWriter, tarReader); err != nil {
+			return err
+		}
+	}
+
+	return tarWriter.Close()
+}
+
+// resolveVolumeDataPath returns the host directory holding a volume's data. Named volumes
+// are read through the mountpoint Podman reports for them.
+func (vm *VolumeManager) resolveVolumeDataPath(ctx context.Context, volume *VolumeResource) (string, error) {
+	if volume.Spec.Type != VolumeTypeVolume {
+		pathInfo, err := vm.pathManager.ResolveVolumePath(volume, &VolumeMount{Name: volume.GetName()})
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve path of volume '%s': %w", volume.GetName(), err)
+		}
+		if pathInfo.IsFile {
+			return "", fmt.Errorf("volume '%s' is a file; only directory volumes can be backed up", volume.GetName())
+		}
+		return pathInfo.SourcePath, nil
+	}
+
+	connectedClient := podman.NewConnectedClient(vm.client)
+	defer connectedClient.Close()
+
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return "", fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	info, err := podmanClient.InspectVolume(ctx, volume.GetName())
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect volume '%s': %w", volume.GetName(), err)
+	}
+	if info.Mountpoint == "" {
+		return "", fmt.Errorf("volume '%s' has no mountpoint", volume.GetName())
+	}
+
+	return info.Mountpoint, nil
+}
+
+// writeVolumeArchive writes the contents of sourcePath to w, with paths relative to it
+func writeVolumeArchive(sourcePath string, w io.Writer) error {
+	tarWriter := tar.NewWriter(w)
+
+	err := filepath.WalkDir(sourcePath, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == sourcePath {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(sourcePath, path)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(relativePath)
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tarWriter, file)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+// verifyVolumeArchive reads an archive through to the end, checking that every entry
+// stays inside the directory it is extracted to
+func verifyVolumeArchive(path string) error {
+	archive, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	var names []string
+	symlinks := make(map[string]bool)
+
+	tarReader := tar.NewReader(archive)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		name, err := archiveEntryPath("", header.Name)
+		if err != nil {
+			return err
+		}
+		names = append(names, name)
+		if header.Typeflag == tar.TypeSymlink {
+			symlinks[name] = true
+		}
+		if _, err := io.Copy(io.Discard, tarReader); err != nil {
+			return err
+		}
+	}
+
+	// An entry under a symlink would be written wherever the link points
+	for _, name := range names {
+		for parent := filepath.Dir(name); parent != "."; parent = filepath.Dir(parent) {
+			if symlinks[parent] {
+				return fmt.Errorf("archive entry %s is nested under symlink %s", name, parent)
+			}
+		}
+	}
+	return nil
+}
+
+// extractVolumeArchive extracts directories, regular files and symlinks from r into
+// targetPath. Symlinks are created last so that no entry is written through one.
+func extractVolumeArchive(r io.Reader, targetPath string) error {
+	symlinks := make(map[string]string)
+
+	tarReader := tar.NewReader(r)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		path, err := archiveEntryPath(targetPath, header.Name)
+		if err != nil {
+			return err
+		}
+		mode := fs.FileMode(header.Mode).Perm()
+
+		if err := checkInsideTarget(targetPath, filepath.Dir(path)); err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, mode); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return err
+			}
+			if err := extractArchiveFile(tarReader, path, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			symlinks[path] = header.Linkname
+		default:
+			return fmt.Errorf("unsupported entry type %q for %s", header.Typeflag, header.Name)
+		}
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(symlinks)) {
+		link := symlinks[path]
+		if err := checkInsideTarget(targetPath, filepath.Dir(path)); err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.Symlink(link, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractArchiveFile writes the current archive entry to path
+func extractArchiveFile(r io.Reader, path string, mode fs.FileMode) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, r); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// checkInsideTarget resolves the symlinks of the deepest existing ancestor of dir and
+// checks that it stays inside targetPath, so that nothing is written through a link
+func checkInsideTarget(targetPath, dir string) error {
+	root, err := filepath.EvalSymlinks(targetPath)
+	if err != nil {
+		return err
+	}
+
+	existing := dir
+	for {
+		if _, err := os.Lstat(existing); err == nil {
+			break
+		}
+		if existing == targetPath || existing == filepath.Dir(existing) {
+			return nil
+		}
+		existing = filepath.Dir(existing)
+	}
+
+	resolved, err := filepath.EvalSymlinks(existing)
+	if err != nil {
+		return err
+	}
+	if resolved != root && !strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+		return fmt.Errorf("archive entry path %s resolves outside the volume", dir)
+	}
+	return nil
+}
+
+// archiveEntryPath joins an archive entry name onto targetPath, rejecting names that
+// would escape it
+func archiveEntryPath(targetPath, name string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(name))
+	if filepath.IsAbs(cleaned) || cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("archive entry %s escapes the volume", name)
+	}
+	return filepath.Join(targetPath, cleaned), nil
+}
+
+// BackupVolume archives the contents of a volume declared in the manifests to destPath
+func (rc *DefaultReconciliationController) BackupVolume(ctx context.Context, manifests []Resource, chartName, volumeName, destPath string) error {
+	volumeManager, volume, err := rc.findVolume(manifests, volumeName)
+	if err != nil {
+		return err
+	}
+	return volumeManager.BackupVolume(ctx, volume, destPath)
+}
+
+// RestoreVolume replaces the contents of a volume declared in the manifests with the
+// archive at srcPath. It refuses to restore a volume a running container of the chart uses.
+func (rc *DefaultReconciliationController) RestoreVolume(ctx context.Context, manifests []Resource, chartName, volumeName, srcPath string) error {
+	volumeManager, volume, err := rc.findVolume(manifests, volumeName)
+	if err != nil {
+		return err
+	}
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	if containerManager, exists := rc.managers[ResourceTypeContainer]; exists {
+		actualContainers, err := containerManager.GetActualState(ctx, chartName)
+		if err != nil {
+			return fmt.Errorf("failed to get actual state for %s: %w", ResourceTypeContainer, err)
+		}
+
+		for _, actual := range actualContainers {
+			container, ok := actual.(*ContainerResource)
+			if !ok || container.GetAnnotations()[labels.AnnotationStopped] != "" {
+				continue
+			}
+			if containerUsesVolume(manifests, actual.GetName(), volumeName) {
+				return fmt.Errorf("volume '%s' is in use by running container '%s'", volumeName, actual.GetName())
+			}
+		}
+	}
+
+	return volumeManager.RestoreVolume(ctx, volume, srcPath)
+}
+
+// findVolume returns the volume manager and the named volume from the manifests
+func (rc *DefaultReconciliationController) findVolume(manifests []Resource, volumeName string) (*VolumeManager, *VolumeResource, error) {
+	volumeManager, ok := rc.managers[ResourceTypeVolume].(*VolumeManager)
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported resource type: %s", ResourceTypeVolume)
+	}
+
+	for _, manifest := range manifests {
+		if volume, ok := manifest.(*VolumeResource); ok && volume.GetName() == volumeName {
+			return volumeManager, volume, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("volume '%s' not found in manifests", volumeName)
+}
+
+// containerUsesVolume reports whether the manifests mount the volume into the named container
+func containerUsesVolume(manifests []Resource, containerName, volumeName string) bool {
+	for _, manifest := range manifests {
+		container, ok := manifest.(*ContainerResource)
+		if !ok || container.GetName() != containerName {
+			continue
+		}
+		for _, mount := range container.Spec.Volumes {
+			if mount.Name == volumeName {
+				return true
+			}
+		}
+	}
+	return false
+}
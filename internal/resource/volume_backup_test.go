@@ -0,0 +1,355 @@
+package resource
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// newBackupTestVolume creates a hostPath volume "data" backed by a temporary directory
+func newBackupTestVolume(t *testing.T) *VolumeResource {
+	t.Helper()
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeHostPath
+	volume.Spec.HostPath = &HostPathVolumeSource{Path: t.TempDir()}
+	return volume
+}
+
+func TestVolumeManager_BackupAndRestoreHostPathVolume(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	ctx := context.Background()
+	dataPath := volume.Spec.HostPath.Path
+
+	if err := os.MkdirAll(filepath.Join(dataPath, "db"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataPath, "db", "rows"), []byte("v1"), 0640); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink("db/rows", filepath.Join(dataPath, "latest")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	archivePath := filepath.Join(t.TempDir(), "data.tar")
+	if err := vm.BackupVolume(ctx, volume, archivePath); err != nil {
+		t.Fatalf("BackupVolume failed: %v", err)
+	}
+
+	// Change the volume after the backup
+	if err := os.WriteFile(filepath.Join(dataPath, "db", "rows"), []byte("v2"), 0640); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dataPath, "stray"), []byte("x"), 0640); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if err := vm.RestoreVolume(ctx, volume, archivePath); err != nil {
+		t.Fatalf("RestoreVolume failed: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dataPath, "latest"))
+	if err != nil || string(content) != "v1" {
+		t.Errorf("Expected the backed up content through the symlink, got %q (%v)", content, err)
+	}
+	info, err := os.Stat(filepath.Join(dataPath, "db", "rows"))
+	if err != nil || info.Mode().Perm() != 0640 {
+		t.Errorf("Expected the file mode to be restored, got %v (%v)", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dataPath, "stray")); !os.IsNotExist(err) {
+		t.Errorf("Expected files added after the backup to be removed, got %v", err)
+	}
+}
+
+func TestVolumeManager_BackupRejectsUnwritableDestination(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+
+	destPath := filepath.Join(t.TempDir(), "missing", "data.tar")
+	err := vm.BackupVolume(context.Background(), volume, destPath)
+	if err == nil || !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("Expected the destination to be reported as not writable, got %v", err)
+	}
+}
+
+func TestVolumeManager_RestoreRejectsEscapingArchive(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	ctx := context.Background()
+
+	// Build an archive whose single entry points outside the volume
+	archivePath := filepath.Join(t.TempDir(), "evil.tar")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	tarWriter := tar.NewWriter(archive)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "../payload", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if _, err := tarWriter.Write([]byte("x")); err != nil {
+		t.Fatalf("Failed to write entry: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	archive.Close()
+
+	if err := os.WriteFile(filepath.Join(volume.Spec.HostPath.Path, "keep"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	err = vm.RestoreVolume(ctx, volume, archivePath)
+	if err == nil || !strings.Contains(err.Error(), "escapes the volume") {
+		t.Fatalf("Expected the archive to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(volume.Spec.HostPath.Path, "keep")); err != nil {
+		t.Errorf("Expected the volume to be left alone, got %v", err)
+	}
+}
+
+func TestVolumeManager_RestoreRejectsEntriesUnderSymlink(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	ctx := context.Background()
+	outside := t.TempDir()
+
+	// A symlink out of the volume, then a file written through it
+	var buffer bytes.Buffer
+	tarWriter := tar.NewWriter(&buffer)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "a", Linkname: outside, Typeflag: tar.TypeSymlink}); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "a/x", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if _, err := tarWriter.Write([]byte("x")); err != nil {
+		t.Fatalf("Failed to write entry: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "evil.tar")
+	if err := os.WriteFile(archivePath, buffer.Bytes(), 0644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+
+	err := vm.RestoreVolume(ctx, volume, archivePath)
+	if err == nil || !strings.Contains(err.Error(), "nested under symlink a") {
+		t.Fatalf("Expected the archive to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "x")); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written outside the volume, got %v", err)
+	}
+
+	// A symlink already in the target is not followed either
+	targetPath := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(targetPath, "a")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+	var nested bytes.Buffer
+	tarWriter = tar.NewWriter(&nested)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: "a/x", Mode: 0644, Size: 1, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("Failed to write header: %v", err)
+	}
+	if _, err := tarWriter.Write([]byte("x")); err != nil {
+		t.Fatalf("Failed to write entry: %v", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+	if err := extractVolumeArchive(&nested, targetPath); err == nil || !strings.Contains(err.Error(), "resolves outside the volume") {
+		t.Fatalf("Expected the entry to be rejected, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "x")); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing to be written outside the volume, got %v", err)
+	}
+}
+
+// writeTestArchive writes a tar archive of the given headers, giving regular files
+// their name as content
+func writeTestArchive(t *testing.T, w io.Writer, headers ...*tar.Header) {
+	t.Helper()
+
+	tarWriter := tar.NewWriter(w)
+	for _, header := range headers {
+		if header.Typeflag == tar.TypeReg {
+			header.Size = int64(len(header.Name))
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			t.Fatalf("Failed to write header: %v", err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tarWriter.Write([]byte(header.Name)); err != nil {
+				t.Fatalf("Failed to write entry: %v", err)
+			}
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		t.Fatalf("Failed to close archive: %v", err)
+	}
+}
+
+func TestVolumeManager_RestoreKeepsDataWhenExtractionFails(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	dataPath := volume.Spec.HostPath.Path
+
+	if err := os.WriteFile(filepath.Join(dataPath, "keep"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	// Hard links pass the archive check but cannot be extracted
+	archivePath := filepath.Join(t.TempDir(), "data.tar")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	writeTestArchive(t, archive,
+		&tar.Header{Name: "rows", Mode: 0644, Typeflag: tar.TypeReg},
+		&tar.Header{Name: "rows-link", Linkname: "rows", Typeflag: tar.TypeLink},
+	)
+	archive.Close()
+
+	if err := vm.RestoreVolume(context.Background(), volume, archivePath); err == nil {
+		t.Fatal("Expected the restore to fail")
+	}
+
+	entries, err := os.ReadDir(dataPath)
+	if err != nil {
+		t.Fatalf("Failed to read volume: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "keep" {
+		t.Errorf("Expected the volume to keep its data and no staging directory, got %v", entries)
+	}
+}
+
+// newNamedBackupTestVolume returns a named volume "data"
+func newNamedBackupTestVolume() *VolumeResource {
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "data"
+	volume.Spec.Type = VolumeTypeVolume
+	volume.Spec.Volume = &VolumeVolumeSource{}
+	return volume
+}
+
+func TestVolumeManager_BackupAndRestoreNamedVolumeThroughHelper(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	vm := NewVolumeManager(mockClient)
+	volume := newNamedBackupTestVolume()
+	ctx := context.Background()
+
+	var contents bytes.Buffer
+	writeTestArchive(t, &contents,
+		&tar.Header{Name: "db/", Mode: 0755, Typeflag: tar.TypeDir},
+		&tar.Header{Name: "db/rows", Mode: 0640, Typeflag: tar.TypeReg},
+	)
+	mockClient.SetVolumeArchive("data", contents.Bytes())
+
+	archivePath := filepath.Join(t.TempDir(), "data.tar")
+	if err := vm.BackupVolume(ctx, volume, archivePath); err != nil {
+		t.Fatalf("BackupVolume failed: %v", err)
+	}
+	backup, err := os.ReadFile(archivePath)
+	if err != nil || !bytes.Equal(backup, contents.Bytes()) {
+		t.Fatalf("Expected the archive copied out of the helper, got %d bytes (%v)", len(backup), err)
+	}
+
+	if err := vm.RestoreVolume(ctx, volume, archivePath); err != nil {
+		t.Fatalf("RestoreVolume failed: %v", err)
+	}
+
+	// The archive is uploaded into the staging directory, then swapped in
+	var names []string
+	tarReader := tar.NewReader(bytes.NewReader(mockClient.GetVolumeArchive("data")))
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read uploaded archive: %v", err)
+		}
+		names = append(names, header.Name)
+	}
+	if !slices.Equal(names, []string{".cutepod-restore/db/", ".cutepod-restore/db/rows"}) {
+		t.Errorf("Expected the entries under the staging directory, got %v", names)
+	}
+	execs := mockClient.GetExecs()
+	if len(execs) != 1 || !slices.Equal(execs[0].Command, []string{"sh", "-c", volumeRestoreSwapScript}) {
+		t.Errorf("Expected the staging directory to be swapped in, got %+v", execs)
+	}
+
+	containers, err := mockClient.ListContainers(ctx, nil, true)
+	if err != nil || len(containers) != 0 {
+		t.Errorf("Expected the helper containers to be removed, got %d (%v)", len(containers), err)
+	}
+	if mockClient.GetCallCount("PullImage") != 1 {
+		t.Errorf("Expected the helper image to be pulled once, got %d", mockClient.GetCallCount("PullImage"))
+	}
+}
+
+func TestVolumeManager_RestoreNamedVolumeKeepsDataWhenUploadFails(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	mockClient.SetShouldFailOperation("CopyToContainer", true)
+	vm := NewVolumeManager(mockClient)
+
+	archivePath := filepath.Join(t.TempDir(), "data.tar")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	writeTestArchive(t, archive, &tar.Header{Name: "rows", Mode: 0644, Typeflag: tar.TypeReg})
+	archive.Close()
+
+	if err := vm.RestoreVolume(context.Background(), newNamedBackupTestVolume(), archivePath); err == nil {
+		t.Fatal("Expected the restore to fail")
+	}
+
+	// Only the staging directory is removed; the volume's contents are never touched
+	execs := mockClient.GetExecs()
+	if len(execs) != 1 || !slices.Equal(execs[0].Command, []string{"rm", "-rf", "/volume/.cutepod-restore"}) {
+		t.Errorf("Expected only the staging directory to be cleaned up, got %+v", execs)
+	}
+}
+
+func TestReconciliationController_RestoreVolumeRefusesRunningContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient)
+	ctx := context.Background()
+
+	// Run the container first; only the manifests need to declare the mount
+	if _, err := controller.Reconcile(ctx, []Resource{newExplainTestContainer("postgres:16")}, "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	container := newExplainTestContainer("postgres:16")
+	container.Spec.Volumes = []VolumeMount{{Name: "data", MountPath: "/var/lib/postgresql"}}
+	manifests := []Resource{newBackupTestVolume(t), container}
+
+	archivePath := filepath.Join(t.TempDir(), "data.tar")
+	if err := controller.BackupVolume(ctx, manifests, "demo", "data", archivePath); err != nil {
+		t.Fatalf("BackupVolume failed: %v", err)
+	}
+
+	err := controller.RestoreVolume(ctx, manifests, "demo", "data", archivePath)
+	if err == nil || !strings.Contains(err.Error(), "in use by running container 'web'") {
+		t.Fatalf("Expected the restore to be refused, got %v", err)
+	}
+
+	if err := mockClient.StopContainer(ctx, "web", 0); err != nil {
+		t.Fatalf("Failed to stop container: %v", err)
+	}
+	if err := controller.RestoreVolume(ctx, manifests, "demo", "data", archivePath); err != nil {
+		t.Errorf("Expected the restore to succeed once the container is stopped, got %v", err)
+	}
+}
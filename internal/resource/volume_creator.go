@@ -2,10 +2,12 @@ package resource
 
 import (
 	"context"
+	"cutepod/internal/labels"
 	"cutepod/internal/podman"
 	"fmt"
 	"os"
-	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // VolumeCreator defines the interface for creating different types of volumes
@@ -206,22 +208,51 @@ func (c *EmptyDirVolumeCreator) CreateVolume(ctx context.Context, client podman.
 	return pathInfo, nil
 }
 
-// DeleteVolume deletes an emptyDir volume
+// DeleteVolume deletes an emptyDir volume. Containers sharing the emptyDir all bind the
+// same directory, so it is kept while any container, in this chart or another, still
+// mounts it; the last one to go leaves it to the next reconcile that deletes the volume.
 func (c *EmptyDirVolumeCreator) DeleteVolume(ctx context.Context, client podman.PodmanClient, volume *VolumeResource) error {
+	references, err := c.countReferences(ctx, client, volume.GetName())
+	if err != nil {
+		return err
+	}
+	if references > 0 {
+		return nil
+	}
 	return c.pathManager.CleanupEmptyDirVolume(volume.GetName())
 }
 
-// resolveEmptyDirBase resolves the base path for an emptyDir volume
-func (c *EmptyDirVolumeCreator) resolveEmptyDirBase(volume *VolumeResource) (*VolumePathInfo, error) {
-	// Create a unique temporary directory for this emptyDir volume
-	emptyDirPath := filepath.Join(c.pathManager.tempDirBase, volume.GetName())
+// countReferences counts the containers that mount the emptyDir volume, going by the
+// emptyDirs label they are created with
+func (c *EmptyDirVolumeCreator) countReferences(ctx context.Context, client podman.PodmanClient, volumeName string) (int, error) {
+	connectedClient := podman.NewConnectedClient(client)
+	defer connectedClient.Close()
 
-	return &VolumePathInfo{
-		SourcePath:       emptyDirPath,
-		IsFile:           false, // emptyDir is always a directory
-		RequiresCreation: true,  // Always needs creation
-		PathType:         HostPathDirectoryOrCreate,
-	}, nil
+	podmanClient, err := connectedClient.GetClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("unable to connect to podman: %w", err)
+	}
+
+	containers, err := podmanClient.ListContainers(ctx, map[string][]string{
+		"label": {labels.LabelEmptyDirs},
+	}, true)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list containers using emptyDir volume %s: %w", volumeName, err)
+	}
+
+	references := 0
+	for _, container := range containers {
+		if slices.Contains(strings.Split(container.Labels[labels.LabelEmptyDirs], ","), volumeName) {
+			references++
+		}
+	}
+	return references, nil
+}
+
+// resolveEmptyDirBase resolves the base path for an emptyDir volume through the path
+// manager, so the volume is created where containers mount it from
+func (c *EmptyDirVolumeCreator) resolveEmptyDirBase(volume *VolumeResource) (*VolumePathInfo, error) {
+	return c.pathManager.resolveEmptyDirVolume(volume, &VolumeMount{Name: volume.GetName()})
 }
 
 // applySizeLimit applies size constraints to an emptyDir volume
@@ -303,7 +334,7 @@ func (c *NamedVolumeCreator) DeleteVolume(ctx context.Context, client podman.Pod
 func (c *NamedVolumeCreator) buildNamedVolumeSpec(volume *VolumeResource) podman.VolumeSpec {
 	spec := podman.VolumeSpec{
 		Name:   volume.GetName(),
-		Labels: volume.GetLabels(),
+		Labels: withDeleteProtection(volume, volume.GetLabels()),
 	}
 
 	if volume.Spec.Volume != nil {
@@ -113,7 +113,7 @@ func TestEmptyDirVolumeCreator_CreateVolume(t *testing.T) {
 		t.Fatalf("CreateVolume failed: %v", err)
 	}
 
-	expectedPath := filepath.Join(pathManager.tempDirBase, "test-emptydir")
+	expectedPath := filepath.Join(pathManager.tempDirBase, "emptydir", "test-emptydir")
 	if pathInfo.SourcePath != expectedPath {
 		t.Errorf("Expected source path %s, got %s", expectedPath, pathInfo.SourcePath)
 	}
@@ -133,6 +133,41 @@ func TestEmptyDirVolumeCreator_CreateVolume(t *testing.T) {
 	}
 }
 
+func TestEmptyDirVolumeCreator_ContainerMountsCreatedPath(t *testing.T) {
+	pathManager := NewVolumePathManager(t.TempDir())
+	creator := NewEmptyDirVolumeCreator(pathManager, nil)
+	ctx := context.Background()
+	mockClient := podman.NewMockPodmanClient()
+
+	volume := NewVolumeResource()
+	volume.ObjectMeta.Name = "scratch"
+	volume.Spec.Type = VolumeTypeEmptyDir
+	volume.Spec.EmptyDir = &EmptyDirVolumeSource{}
+
+	created, err := creator.CreateVolume(ctx, mockClient, volume)
+	if err != nil {
+		t.Fatalf("CreateVolume failed: %v", err)
+	}
+
+	registry := NewManifestRegistry()
+	if err := registry.AddResource(volume); err != nil {
+		t.Fatalf("AddResource failed: %v", err)
+	}
+	cm := NewContainerManagerWithRegistry(mockClient, registry)
+	cm.pathManager = pathManager
+
+	container := newExplainTestContainer("nginx:1.25")
+	container.Spec.Volumes = []VolumeMount{{Name: "scratch", MountPath: "/scratch"}}
+
+	mounts, err := cm.convertVolumeMounts(container.Spec.Volumes, container)
+	if err != nil {
+		t.Fatalf("convertVolumeMounts failed: %v", err)
+	}
+	if len(mounts) != 1 || mounts[0].Source != created.SourcePath {
+		t.Errorf("Expected the container to mount %s, got %+v", created.SourcePath, mounts)
+	}
+}
+
 func TestEmptyDirVolumeCreator_CreateVolume_WithSizeLimit(t *testing.T) {
 	pathManager := NewVolumePathManager("")
 	permissionMgr, _ := NewVolumePermissionManager()
@@ -13,6 +13,27 @@ type VolumeManager struct {
 	pathManager     *VolumePathManager
 	permissionMgr   *VolumePermissionManager
 	creatorRegistry *VolumeCreatorRegistry
+	labelPrefix     string
+	logger          Logger
+
+	// helperImage runs the helper containers that back up and restore named volumes
+	helperImage string
+}
+
+// SetLogger sets the logger of the manager and of the volume helpers it uses
+func (vm *VolumeManager) SetLogger(logger Logger) {
+	vm.logger = logger
+	if vm.pathManager != nil {
+		vm.pathManager.logger = logger
+	}
+	if vm.permissionMgr != nil {
+		vm.permissionMgr.logger = logger
+	}
+}
+
+// SetHostPathPrefixes restricts the hostPath volumes the manager creates to the given prefixes
+func (vm *VolumeManager) SetHostPathPrefixes(prefixes []string) {
+	vm.pathManager.SetAllowedPrefixes(prefixes)
 }
 
 // NewVolumeManager creates a new VolumeManager
@@ -32,6 +53,7 @@ func NewVolumeManager(client podman.PodmanClient) *VolumeManager {
 		pathManager:     pathManager,
 		permissionMgr:   permissionMgr,
 		creatorRegistry: creatorRegistry,
+		logger:          defaultLogger,
 	}
 }
 
@@ -50,9 +72,15 @@ func NewVolumeManagerWithPathManager(client podman.PodmanClient, pathManager *Vo
 		pathManager:     pathManager,
 		permissionMgr:   permissionMgr,
 		creatorRegistry: creatorRegistry,
+		logger:          defaultLogger,
 	}
 }
 
+// SetLabelPrefix makes the manager list volumes by the chart label under prefix
+func (vm *VolumeManager) SetLabelPrefix(prefix string) {
+	vm.labelPrefix = prefix
+}
+
 // GetResourceType returns the resource type this manager handles
 func (vm *VolumeManager) GetResourceType() ResourceType {
 	return ResourceTypeVolume
@@ -81,12 +109,7 @@ func (vm *VolumeManager) GetActualState(ctx context.Context, chartName string) (
 		return nil, fmt.Errorf("unable to connect to podman: %w", err)
 	}
 
-	volumes, err := podmanClient.ListVolumes(
-		ctx,
-		map[string][]string{
-			"label": {labels.GetChartLabelValue(chartName)},
-		},
-	)
+	volumes, err := podmanClient.ListVolumes(ctx, chartFilters(ctx, vm.labelPrefix, chartName))
 	if err != nil {
 		return nil, fmt.Errorf("unable to list volumes: %w", err)
 	}
@@ -197,7 +220,17 @@ func (vm *VolumeManager) CompareResources(desired, actual Resource) (bool, error
 func (vm *VolumeManager) convertPodmanVolumeToResource(volume podman.VolumeInfo) *VolumeResource {
 	resource := NewVolumeResource()
 	resource.ObjectMeta.Name = volume.Name
-	resource.SetLabels(volume.Labels)
+
+	// Delete protection is read back as the annotation it was created from
+	volumeLabels := make(map[string]string, len(volume.Labels))
+	for key, value := range volume.Labels {
+		if key == labels.AnnotationDeleteProtect {
+			resource.SetAnnotations(map[string]string{key: value})
+			continue
+		}
+		volumeLabels[key] = value
+	}
+	resource.SetLabels(volumeLabels)
 
 	// Determine volume type based on driver and options
 	if volume.Driver == "local" {
@@ -348,7 +381,7 @@ func (vm *VolumeManager) compareSELinuxOptions(desired, actual *SELinuxVolumeOpt
 		return false
 	}
 
-	return desired.Level == actual.Level
+	return desired.Level == actual.Level && desired.Context == actual.Context
 }
 
 func (vm *VolumeManager) compareOwnership(desired, actual *VolumeOwnership) bool {
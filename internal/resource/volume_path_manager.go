@@ -11,6 +11,7 @@ import (
 type VolumePathManager struct {
 	tempDirBase       string
 	hostPathValidator *HostPathValidator
+	logger            Logger
 }
 
 // HostPathValidator provides security validation for host paths
@@ -26,10 +27,14 @@ type VolumePathInfo struct {
 	PathType         HostPathType // Type of path (for validation)
 }
 
-// NewVolumePathManager creates a new VolumePathManager
+// defaultTempDirBase is where emptyDir volumes live unless another base is configured
+const defaultTempDirBase = "/tmp/cutepod-volumes"
+
+// NewVolumePathManager creates a new VolumePathManager. emptyDir volumes are created
+// under tempDirBase, or under /tmp/cutepod-volumes if it is empty.
 func NewVolumePathManager(tempDirBase string) *VolumePathManager {
 	if tempDirBase == "" {
-		tempDirBase = "/tmp/cutepod-volumes"
+		tempDirBase = defaultTempDirBase
 	}
 
 	return &VolumePathManager{
@@ -43,7 +48,7 @@ func NewVolumePathManager(tempDirBase string) *VolumePathManager {
 // NewVolumePathManagerWithRestrictions creates a VolumePathManager with path restrictions
 func NewVolumePathManagerWithRestrictions(tempDirBase string, allowedPrefixes []string) *VolumePathManager {
 	if tempDirBase == "" {
-		tempDirBase = "/tmp/cutepod-volumes"
+		tempDirBase = defaultTempDirBase
 	}
 
 	return &VolumePathManager{
@@ -54,6 +59,31 @@ func NewVolumePathManagerWithRestrictions(tempDirBase string, allowedPrefixes []
 	}
 }
 
+// validateTempDirBase checks that a configured base directory is absolute and that
+// volumes can be created in it, creating it if needed
+func validateTempDirBase(tempDirBase string) error {
+	if !filepath.IsAbs(tempDirBase) {
+		return fmt.Errorf("volume base directory must be an absolute path, got: %s", tempDirBase)
+	}
+
+	if err := os.MkdirAll(tempDirBase, 0755); err != nil {
+		return fmt.Errorf("failed to create volume base directory %s: %w", tempDirBase, err)
+	}
+
+	probe, err := os.CreateTemp(tempDirBase, ".cutepod-probe-*")
+	if err != nil {
+		return fmt.Errorf("volume base directory %s is not writable: %w", tempDirBase, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+// SetAllowedPrefixes restricts hostPath volumes to paths under the given prefixes; an
+// empty list allows all paths
+func (vpm *VolumePathManager) SetAllowedPrefixes(allowedPrefixes []string) {
+	vpm.hostPathValidator.allowedPrefixes = allowedPrefixes
+}
+
 // ResolveVolumePath resolves the source path for a volume mount, handling subPath resolution
 func (vpm *VolumePathManager) ResolveVolumePath(volume *VolumeResource, mount *VolumeMount) (*VolumePathInfo, error) {
 	if volume == nil {
@@ -285,7 +315,8 @@ func (vpm *VolumePathManager) validatePathTypeRequirements(pathInfo *VolumePathI
 	return nil
 }
 
-// getEmptyDirPath returns the temporary directory path for an emptyDir volume
+// getEmptyDirPath returns the temporary directory path for an emptyDir volume. It is
+// the only place that path is built; creation, mounting and cleanup all go through it.
 func (vpm *VolumePathManager) getEmptyDirPath(volumeName string) string {
 	return filepath.Join(vpm.tempDirBase, "emptydir", volumeName)
 }
@@ -301,7 +332,7 @@ func (vpm *VolumePathManager) ensureDirectoryPath(path string, volume *VolumeRes
 	if volume.Spec.SecurityContext != nil {
 		if err := vpm.applySecurityContext(path, volume.Spec.SecurityContext); err != nil {
 			// In rootless mode, ownership changes may fail - log warning but continue
-			fmt.Printf("Warning: failed to apply security context to directory %s: %v (continuing anyway)\n", path, err)
+			loggerOrDefault(vpm.logger).Warn("failed to apply security context to directory, continuing anyway", "path", path, "error", err)
 		}
 	}
 
@@ -329,7 +360,7 @@ func (vpm *VolumePathManager) ensureFilePath(path string, volume *VolumeResource
 	if volume.Spec.SecurityContext != nil {
 		if err := vpm.applySecurityContext(path, volume.Spec.SecurityContext); err != nil {
 			// In rootless mode, ownership changes may fail - log warning but continue
-			fmt.Printf("Warning: failed to apply security context to file %s: %v (continuing anyway)\n", path, err)
+			loggerOrDefault(vpm.logger).Warn("failed to apply security context to file, continuing anyway", "path", path, "error", err)
 		}
 	}
 
@@ -392,7 +423,9 @@ func (hpv *HostPathValidator) validateHostPath(hostPath string) error {
 	if len(hpv.allowedPrefixes) > 0 {
 		allowed := false
 		for _, prefix := range hpv.allowedPrefixes {
-			if strings.HasPrefix(hostPath, prefix) {
+			// Match whole path components, so "/srv" does not allow "/srvdata"
+			prefix = strings.TrimSuffix(prefix, "/")
+			if hostPath == prefix || strings.HasPrefix(hostPath, prefix+"/") {
 				allowed = true
 				break
 			}
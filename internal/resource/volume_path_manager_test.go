@@ -660,6 +660,19 @@ func TestHostPathValidator_validateHostPath(t *testing.T) {
 			wantErr:         true,
 			errMsg:          "not within allowed prefixes",
 		},
+		{
+			name:            "allowed prefix - partial component",
+			allowedPrefixes: []string{"/srv/"},
+			hostPath:        "/srvdata/app",
+			wantErr:         true,
+			errMsg:          "not within allowed prefixes",
+		},
+		{
+			name:            "allowed prefix - exact match",
+			allowedPrefixes: []string{"/srv/"},
+			hostPath:        "/srv",
+			wantErr:         false,
+		},
 	}
 
 	for _, tt := range tests {
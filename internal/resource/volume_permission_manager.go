@@ -14,6 +14,7 @@ type VolumePermissionManager struct {
 	seLinuxEnabled bool
 	rootlessMode   bool
 	userNSMapping  *UserNamespaceMapping
+	logger         Logger
 }
 
 // UserNamespaceMapping represents user namespace mapping configuration
@@ -51,6 +52,11 @@ func (vpe *VolumePermissionError) Error() string {
 		vpe.VolumeName, vpe.OriginalError.Error(), vpe.Suggestion)
 }
 
+// Unwrap returns the error that was diagnosed
+func (vpe *VolumePermissionError) Unwrap() error {
+	return vpe.OriginalError
+}
+
 // NewVolumePermissionManager creates a new VolumePermissionManager
 func NewVolumePermissionManager() (*VolumePermissionManager, error) {
 	vpm := &VolumePermissionManager{}
@@ -171,6 +177,11 @@ func (vpm *VolumePermissionManager) DetermineSELinuxLabel(volume *VolumeResource
 		return ""
 	}
 
+	// A full context takes precedence over the z and Z relabeling options
+	if context := seLinuxContext(volume, mount); context != "" {
+		return seLinuxContextOption(context)
+	}
+
 	// Check explicit mount options first
 	if mount.MountOptions != nil && mount.MountOptions.SELinuxLabel != "" {
 		return mount.MountOptions.SELinuxLabel
@@ -193,6 +204,24 @@ func (vpm *VolumePermissionManager) DetermineSELinuxLabel(volume *VolumeResource
 	return "Z"
 }
 
+// seLinuxContext returns the full SELinux context set on the mount or, failing that, on
+// the volume's security context
+func seLinuxContext(volume *VolumeResource, mount *VolumeMount) string {
+	if mount.MountOptions != nil && mount.MountOptions.SELinuxContext != "" {
+		return mount.MountOptions.SELinuxContext
+	}
+	if volume.Spec.SecurityContext != nil && volume.Spec.SecurityContext.SELinuxOptions != nil {
+		return volume.Spec.SecurityContext.SELinuxOptions.Context
+	}
+	return ""
+}
+
+// seLinuxContextOption returns the mount option labelling a mount with a full SELinux
+// context. The context is quoted, since a level such as s0:c1,c2 holds commas.
+func seLinuxContextOption(context string) string {
+	return `context="` + context + `"`
+}
+
 // HandleUserNamespaceMapping handles user namespace mapping for rootless Podman
 func (vpm *VolumePermissionManager) HandleUserNamespaceMapping(volume *VolumeResource, container *ContainerResource) (*UIDGIDMapping, *UIDGIDMapping, error) {
 	if !vpm.rootlessMode || vpm.userNSMapping == nil {
@@ -295,7 +324,8 @@ func (vpm *VolumePermissionManager) ManageHostDirectoryOwnership(hostPath string
 	if uid != -1 || gid != -1 {
 		if err := os.Chown(hostPath, uid, gid); err != nil {
 			// In rootless mode, ownership changes may fail - log warning but continue
-			fmt.Printf("Warning: failed to set ownership on %s to %d:%d: %v (continuing anyway)\n", hostPath, uid, gid, err)
+			loggerOrDefault(vpm.logger).Warn("failed to set ownership, continuing anyway",
+				"path", hostPath, "uid", uid, "gid", gid, "error", err)
 		}
 	}
 
@@ -85,6 +85,28 @@ func TestDetermineSELinuxLabel(t *testing.T) {
 			sharedAccess:   false,
 			expected:       "Z",
 		},
+		{
+			name:           "Full context on the mount overrides the label",
+			seLinuxEnabled: true,
+			volume:         createTestVolume("test-vol", &VolumeSecurityContext{SELinuxOptions: &SELinuxVolumeOptions{Level: "private"}}),
+			mount: createTestVolumeMount("test-vol", "/mnt", &VolumeMountOptions{
+				SELinuxLabel:   "z",
+				SELinuxContext: "system_u:object_r:container_file_t:s0",
+			}),
+			sharedAccess: true,
+			expected:     `context="system_u:object_r:container_file_t:s0"`,
+		},
+		{
+			name:           "Full context from the volume security context",
+			seLinuxEnabled: true,
+			volume: createTestVolume("test-vol", &VolumeSecurityContext{SELinuxOptions: &SELinuxVolumeOptions{
+				Level:   "shared",
+				Context: "system_u:object_r:container_file_t:s0:c1,c2",
+			}}),
+			mount:        createTestVolumeMount("test-vol", "/mnt", nil),
+			sharedAccess: true,
+			expected:     `context="system_u:object_r:container_file_t:s0:c1,c2"`,
+		},
 		{
 			name:           "Default shared access",
 			seLinuxEnabled: true,
@@ -326,6 +348,16 @@ func TestBuildPodmanMountOptions(t *testing.T) {
 			sharedAccess:   true,
 			expected:       []string{"bind", "ro", "z"},
 		},
+		{
+			name:           "With a full SELinux context",
+			seLinuxEnabled: true,
+			volume:         createTestHostPathVolume("test-vol", "/host/path"),
+			mount: createTestVolumeMount("test-vol", "/mnt", &VolumeMountOptions{
+				SELinuxContext: "system_u:object_r:container_file_t:s0",
+			}),
+			sharedAccess: true,
+			expected:     []string{"bind", `context="system_u:object_r:container_file_t:s0"`},
+		},
 	}
 
 	for _, tt := range tests {
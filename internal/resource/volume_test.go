@@ -245,6 +245,41 @@ func TestVolumeResource_Validate_SecurityContext(t *testing.T) {
 			expectError: true,
 			errorMsg:    "invalid seLinuxOptions.level",
 		},
+		{
+			name: "valid full SELinux context",
+			volume: &VolumeResource{
+				Spec: CuteVolumeSpec{
+					Type: VolumeTypeHostPath,
+					HostPath: &HostPathVolumeSource{
+						Path: "/tmp/test",
+					},
+					SecurityContext: &VolumeSecurityContext{
+						SELinuxOptions: &SELinuxVolumeOptions{
+							Context: "system_u:object_r:container_file_t:s0:c1,c2",
+						},
+					},
+				},
+			},
+			expectError: false,
+		},
+		{
+			name: "SELinux context missing its level",
+			volume: &VolumeResource{
+				Spec: CuteVolumeSpec{
+					Type: VolumeTypeHostPath,
+					HostPath: &HostPathVolumeSource{
+						Path: "/tmp/test",
+					},
+					SecurityContext: &VolumeSecurityContext{
+						SELinuxOptions: &SELinuxVolumeOptions{
+							Context: "system_u:object_r:container_file_t",
+						},
+					},
+				},
+			},
+			expectError: true,
+			errorMsg:    "must have four colon-separated components",
+		},
 		{
 			name: "negative user ID",
 			volume: &VolumeResource{
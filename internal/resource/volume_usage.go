@@ -0,0 +1,140 @@
+package resource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"time"
+)
+
+// VolumeUsage reports how much data a volume holds
+type VolumeUsage struct {
+	Name      string     `json:"name"`
+	Type      VolumeType `json:"type"`
+	Path      string     `json:"path"`
+	SizeBytes int64      `json:"size_bytes"`
+	Files     int        `json:"files"`
+	// Warnings lists what could not be measured; the size then only covers the rest
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ChartVolumeUsage aggregates the usage of the volumes of a chart
+type ChartVolumeUsage struct {
+	ChartName  string                  `json:"chart_name"`
+	Volumes    map[string]*VolumeUsage `json:"volumes"`
+	TotalBytes int64                   `json:"total_bytes"`
+	Timestamp  time.Time               `json:"timestamp"`
+}
+
+// GetVolumeUsage measures the size of the files in a volume by walking its path. Podman
+// does not report the size of named volumes, so their mountpoint is walked too, which
+// only works when it is reachable from this host. Entries that cannot be read, such as
+// directories owned by another user, are skipped with a warning instead of failing.
+func (vm *VolumeManager) GetVolumeUsage(ctx context.Context, volume *VolumeResource) (*VolumeUsage, error) {
+	path, err := vm.resolveVolumeUsagePath(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &VolumeUsage{
+		Name: volume.GetName(),
+		Type: volume.Spec.Type,
+		Path: path,
+	}
+	if err := measureVolumePath(usage); err != nil {
+		return nil, fmt.Errorf("failed to measure volume '%s': %w", volume.GetName(), err)
+	}
+
+	return usage, nil
+}
+
+// resolveVolumeUsagePath returns the host path of the volume, which may be a single
+// file for hostPath volumes
+func (vm *VolumeManager) resolveVolumeUsagePath(ctx context.Context, volume *VolumeResource) (string, error) {
+	if volume.Spec.Type == VolumeTypeVolume {
+		return vm.resolveVolumeDataPath(ctx, volume)
+	}
+
+	pathInfo, err := vm.pathManager.ResolveVolumePath(volume, &VolumeMount{Name: volume.GetName()})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path of volume '%s': %w", volume.GetName(), err)
+	}
+	return pathInfo.SourcePath, nil
+}
+
+// measureVolumePath adds up the regular files under usage.Path. Symlinks are not
+// followed, so data outside the volume is not counted.
+func measureVolumePath(usage *VolumeUsage) error {
+	root := usage.Path
+	if resolved, err := filepath.EvalSymlinks(root); err == nil {
+		root = resolved
+	}
+
+	return filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			switch {
+			case path == root && errors.Is(err, fs.ErrNotExist):
+				usage.Warnings = append(usage.Warnings, fmt.Sprintf("path %s does not exist on this host", usage.Path))
+				return nil
+			case errors.Is(err, fs.ErrPermission):
+				usage.Warnings = append(usage.Warnings, fmt.Sprintf("skipped %s: %v", path, err))
+				if entry != nil && entry.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			// Removed while walking
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		usage.SizeBytes += info.Size()
+		usage.Files++
+		return nil
+	})
+}
+
+// GetVolumeUsage measures every volume declared in the manifests, so that volumes that
+// keep growing can be spotted by comparing the results over time
+func (rc *DefaultReconciliationController) GetVolumeUsage(ctx context.Context, manifests []Resource, chartName string) (*ChartVolumeUsage, error) {
+	volumeManager, ok := rc.managers[ResourceTypeVolume].(*VolumeManager)
+	if !ok {
+		return nil, fmt.Errorf("unsupported resource type: %s", ResourceTypeVolume)
+	}
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	usage := &ChartVolumeUsage{
+		ChartName: chartName,
+		Volumes:   make(map[string]*VolumeUsage),
+		Timestamp: time.Now(),
+	}
+
+	for _, manifest := range manifests {
+		volume, ok := manifest.(*VolumeResource)
+		if !ok {
+			continue
+		}
+
+		volumeUsage, err := volumeManager.GetVolumeUsage(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+		usage.Volumes[volume.GetName()] = volumeUsage
+		usage.TotalBytes += volumeUsage.SizeBytes
+	}
+
+	return usage, nil
+}
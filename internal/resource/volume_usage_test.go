@@ -0,0 +1,101 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestVolumeManager_GetVolumeUsageOfHostPathVolume(t *testing.T) {
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	dataPath := volume.Spec.HostPath.Path
+
+	if err := os.MkdirAll(filepath.Join(dataPath, "db"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	files := map[string]int{"db/rows": 4096, "db/index": 512, "config": 100}
+	for name, size := range files {
+		if err := os.WriteFile(filepath.Join(dataPath, name), make([]byte, size), 0644); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+	}
+	// Symlinks are not followed, so the file outside the volume is not counted
+	outside := filepath.Join(t.TempDir(), "outside")
+	if err := os.WriteFile(outside, make([]byte, 1000), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dataPath, "link")); err != nil {
+		t.Fatalf("Failed to create symlink: %v", err)
+	}
+
+	usage, err := vm.GetVolumeUsage(context.Background(), volume)
+	if err != nil {
+		t.Fatalf("GetVolumeUsage failed: %v", err)
+	}
+	if usage.SizeBytes != 4708 || usage.Files != 3 || len(usage.Warnings) != 0 {
+		t.Errorf("Expected 3 files of 4708 bytes without warnings, got %+v", usage)
+	}
+}
+
+func TestVolumeManager_GetVolumeUsageSkipsUnreadableDirectories(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+
+	vm := NewVolumeManager(podman.NewMockPodmanClient())
+	volume := newBackupTestVolume(t)
+	dataPath := volume.Spec.HostPath.Path
+
+	if err := os.WriteFile(filepath.Join(dataPath, "readable"), make([]byte, 300), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	private := filepath.Join(dataPath, "private")
+	if err := os.MkdirAll(private, 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(private, "hidden"), make([]byte, 700), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	if err := os.Chmod(private, 0); err != nil {
+		t.Fatalf("Failed to change mode: %v", err)
+	}
+	t.Cleanup(func() { os.Chmod(private, 0755) })
+
+	usage, err := vm.GetVolumeUsage(context.Background(), volume)
+	if err != nil {
+		t.Fatalf("GetVolumeUsage failed: %v", err)
+	}
+	if usage.SizeBytes != 300 || len(usage.Warnings) != 1 || !strings.Contains(usage.Warnings[0], "private") {
+		t.Errorf("Expected a partial result with a warning about the private directory, got %+v", usage)
+	}
+}
+
+func TestReconciliationController_GetVolumeUsage(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	data := newBackupTestVolume(t)
+	data.SetLabels(labels.GetStandardLabels("demo", "1.0.0"))
+	if err := os.WriteFile(filepath.Join(data.Spec.HostPath.Path, "rows"), make([]byte, 2048), 0644); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+	// A volume whose path was not created yet is reported empty with a warning
+	cache := newBackupTestVolume(t)
+	cache.ObjectMeta.Name = "cache"
+	cache.Spec.HostPath.Path = filepath.Join(cache.Spec.HostPath.Path, "missing")
+
+	usage, err := controller.GetVolumeUsage(context.Background(), []Resource{data, cache}, "demo")
+	if err != nil {
+		t.Fatalf("GetVolumeUsage failed: %v", err)
+	}
+	if usage.TotalBytes != 2048 || len(usage.Volumes) != 2 {
+		t.Fatalf("Expected 2 volumes holding 2048 bytes, got %+v", usage)
+	}
+	if warnings := usage.Volumes["cache"].Warnings; len(warnings) != 1 || !strings.Contains(warnings[0], "does not exist") {
+		t.Errorf("Expected a warning about the missing path, got %v", warnings)
+	}
+}
@@ -0,0 +1,185 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/labels"
+	"cutepod/internal/podman"
+	"fmt"
+	"time"
+)
+
+// WatchEvent reports the outcome of a single watch cycle, or the progress of an image
+// pull, in which case only ChartName, PullProgress and Timestamp are set
+type WatchEvent struct {
+	ChartName string                `json:"chart_name"`
+	Cycle     int                   `json:"cycle"`
+	Drifted   bool                  `json:"drifted"`
+	Skipped   bool                  `json:"skipped"`
+	Trigger   *podman.Event         `json:"trigger,omitempty"`
+	Result    *ReconciliationResult `json:"result,omitempty"`
+	Error     string                `json:"error,omitempty"`
+	Timestamp time.Time             `json:"timestamp"`
+
+	// PullProgress is set on the events reporting an image pull of a reconcile
+	PullProgress *podman.PullProgress `json:"pull_progress,omitempty"`
+}
+
+// EventHook receives watch events and image pull progress; it is called synchronously
+// from the watch loop and from image pulls
+type EventHook func(event WatchEvent)
+
+// WithEventHook sets the hook that receives an event for every watch cycle and every
+// step of an image pull
+func WithEventHook(hook EventHook) ControllerOption {
+	return func(rc *DefaultReconciliationController) {
+		if hook != nil {
+			rc.eventHook = hook
+		}
+	}
+}
+
+// Watch reconciles the chart every interval until ctx is cancelled. Each cycle compares
+// the actual state with the manifests and only reconciles when drift is detected, so a
+// manually stopped or removed resource is repaired on the next cycle. A container of the
+// chart dying (including OOM kills) triggers a cycle right away rather than on the next
+// tick; if Podman events are unavailable, Watch falls back to polling only. A cycle is
+// skipped when another one for the same chart is still running.
+func (rc *DefaultReconciliationController) Watch(ctx context.Context, manifests []Resource, chartName string, interval time.Duration) error {
+	if interval <= 0 {
+		return fmt.Errorf("watch interval must be positive, got %s", interval)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// A nil channel never delivers, which leaves the ticker as the only trigger
+	containerEvents, err := rc.podmanClient.WatchEvents(ctx, containerDiedFilters(chartName, rc.labelPrefix))
+	if err != nil {
+		containerEvents = nil
+	}
+
+	var trigger *podman.Event
+	for cycle := 1; ; cycle++ {
+		rc.runWatchCycle(ctx, manifests, chartName, cycle, trigger)
+		trigger = nil
+
+		for waiting := true; waiting; {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-ticker.C:
+				waiting = false
+			case event, ok := <-containerEvents:
+				if !ok {
+					// The event stream ended; keep polling
+					containerEvents = nil
+					continue
+				}
+				trigger = &event
+				waiting = false
+			}
+		}
+	}
+}
+
+// containerDiedFilters selects the events of containers of the chart exiting, by its
+// label under labelPrefix
+func containerDiedFilters(chartName, labelPrefix string) map[string][]string {
+	return map[string][]string{
+		"type":  {"container"},
+		"event": {"died"},
+		"label": {labels.GetChartLabelValueWithPrefix(chartName, labelPrefix)},
+	}
+}
+
+// runWatchCycle detects drift and reconciles if needed, then reports the cycle.
+// trigger is the Podman event that caused the cycle, or nil for a scheduled one.
+func (rc *DefaultReconciliationController) runWatchCycle(ctx context.Context, manifests []Resource, chartName string, cycle int, trigger *podman.Event) {
+	event := WatchEvent{
+		ChartName: chartName,
+		Cycle:     cycle,
+		Trigger:   trigger,
+		Timestamp: time.Now(),
+	}
+
+	if !rc.acquireWatchCycle(chartName) {
+		event.Skipped = true
+		rc.emitEvent(event)
+		return
+	}
+	defer rc.releaseWatchCycle(chartName)
+
+	drifted, err := rc.detectDrift(ctx, manifests, chartName)
+	if err != nil {
+		event.Error = err.Error()
+		rc.emitEvent(event)
+		return
+	}
+
+	event.Drifted = drifted
+	if drifted {
+		result, err := rc.Reconcile(ctx, manifests, chartName, false)
+		event.Result = result
+		if err != nil {
+			event.Error = err.Error()
+		}
+	}
+
+	rc.emitEvent(event)
+}
+
+// detectDrift reports whether the actual state differs from the manifests
+func (rc *DefaultReconciliationController) detectDrift(ctx context.Context, manifests []Resource, chartName string) (bool, error) {
+	// Errors are only collected here to satisfy the helpers; the drift check itself is side-effect free
+	scratch := &ReconciliationResult{Errors: make([]*ReconciliationError, 0)}
+
+	ctx, closeConnection := rc.withSharedConnection(ctx)
+	defer closeConnection()
+
+	actualStateByType, err := rc.getCurrentStateWithRetry(ctx, chartName, scratch)
+	if err != nil {
+		return false, fmt.Errorf("failed to get actual state: %w", err)
+	}
+
+	// Prepared as reconcile does, so a prefix or selector does not show up as drift
+	assignPodMembers(manifests)
+	rc.applyLabelPrefix(manifests)
+	manifests = selectManifests(ctx, manifests)
+
+	stateDiff, err := rc.compareAllStatesWithValidation(manifests, actualStateByType, scratch)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare states: %w", err)
+	}
+
+	if len(scratch.Errors) > 0 {
+		return false, fmt.Errorf("drift check failed: %w", scratch.Errors[len(scratch.Errors)-1])
+	}
+
+	return len(stateDiff.ToCreate) > 0 || len(stateDiff.ToUpdate) > 0 || len(stateDiff.ToDelete) > 0, nil
+}
+
+// acquireWatchCycle marks a cycle for chartName as running, returning false if one already is
+func (rc *DefaultReconciliationController) acquireWatchCycle(chartName string) bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if rc.watchCycles[chartName] {
+		return false
+	}
+	rc.watchCycles[chartName] = true
+	return true
+}
+
+// releaseWatchCycle marks the running cycle for chartName as finished
+func (rc *DefaultReconciliationController) releaseWatchCycle(chartName string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	delete(rc.watchCycles, chartName)
+}
+
+func (rc *DefaultReconciliationController) emitEvent(event WatchEvent) {
+	if rc.eventHook != nil {
+		rc.eventHook(event)
+	}
+}
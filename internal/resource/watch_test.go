@@ -0,0 +1,168 @@
+package resource
+
+import (
+	"context"
+	"cutepod/internal/podman"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWatch_RepairsDriftedContainer(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make([]WatchEvent, 0)
+	hook := func(event WatchEvent) {
+		if event.PullProgress != nil {
+			return
+		}
+		events = append(events, event)
+
+		switch event.Cycle {
+		case 1:
+			// Simulate someone removing the container by hand
+			if err := mockClient.RemoveContainer(ctx, "web"); err != nil {
+				t.Errorf("Failed to remove container: %v", err)
+			}
+		case 3:
+			cancel()
+		}
+	}
+
+	controller := NewReconciliationController(mockClient, WithEventHook(hook))
+
+	err := controller.Watch(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", 10*time.Millisecond)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("Expected 3 watch events, got %d", len(events))
+	}
+
+	for i, expectedDrift := range []bool{true, true, false} {
+		event := events[i]
+		if event.Error != "" {
+			t.Errorf("Cycle %d reported error: %s", event.Cycle, event.Error)
+		}
+		if event.Drifted != expectedDrift {
+			t.Errorf("Cycle %d: expected drifted=%v, got %v", event.Cycle, expectedDrift, event.Drifted)
+		}
+		if expectedDrift && (event.Result == nil || len(event.Result.CreatedResources) != 1) {
+			t.Errorf("Cycle %d: expected the container to be (re)created, got %+v", event.Cycle, event.Result)
+		}
+		if !expectedDrift && event.Result != nil {
+			t.Errorf("Cycle %d: expected no reconciliation without drift", event.Cycle)
+		}
+	}
+}
+
+func TestWatch_ReactsToContainerDeath(t *testing.T) {
+	mockClient := podman.NewMockPodmanClient()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := make([]WatchEvent, 0)
+	hook := func(event WatchEvent) {
+		if event.PullProgress != nil {
+			return
+		}
+		events = append(events, event)
+
+		switch event.Cycle {
+		case 1:
+			// The container dies and is cleaned up; no tick is due for an hour
+			if err := mockClient.RemoveContainer(ctx, "web"); err != nil {
+				t.Errorf("Failed to remove container: %v", err)
+			}
+			mockClient.EmitEvent(podman.Event{
+				Type:       "container",
+				Action:     "died",
+				Name:       "web",
+				Attributes: map[string]string{"cutepod.io/chart": "demo"},
+			})
+		case 2:
+			cancel()
+		}
+	}
+
+	controller := NewReconciliationController(mockClient, WithEventHook(hook))
+
+	err := controller.Watch(ctx, []Resource{newExplainTestContainer("nginx:1.25")}, "demo", time.Hour)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 watch events, got %d", len(events))
+	}
+	if events[0].Trigger != nil {
+		t.Errorf("Expected the first cycle to be scheduled, got trigger %+v", events[0].Trigger)
+	}
+
+	triggered := events[1]
+	if triggered.Trigger == nil || triggered.Trigger.Name != "web" || triggered.Trigger.Action != "died" {
+		t.Fatalf("Expected the second cycle to be triggered by web dying, got %+v", triggered.Trigger)
+	}
+	if !triggered.Drifted || triggered.Result == nil || len(triggered.Result.CreatedResources) != 1 {
+		t.Errorf("Expected the dead container to be recreated, got %+v", triggered.Result)
+	}
+}
+
+func TestWatch_SkipsConcurrentCycle(t *testing.T) {
+	var skipped []WatchEvent
+	controller := NewReconciliationController(podman.NewMockPodmanClient(), WithEventHook(func(event WatchEvent) {
+		skipped = append(skipped, event)
+	})).(*DefaultReconciliationController)
+
+	if !controller.acquireWatchCycle("demo") {
+		t.Fatal("Expected first cycle to be acquired")
+	}
+	defer controller.releaseWatchCycle("demo")
+
+	controller.runWatchCycle(context.Background(), []Resource{newExplainTestContainer("nginx:1.25")}, "demo", 1, nil)
+
+	if len(skipped) != 1 || !skipped[0].Skipped {
+		t.Fatalf("Expected the concurrent cycle to be skipped, got %+v", skipped)
+	}
+}
+
+func TestWatch_RejectsNonPositiveInterval(t *testing.T) {
+	controller := NewReconciliationController(podman.NewMockPodmanClient())
+
+	if err := controller.Watch(context.Background(), nil, "demo", 0); err == nil {
+		t.Error("Expected an error for a zero interval")
+	}
+}
+
+func TestDetectDrift_PreparesManifestsLikeReconcile(t *testing.T) {
+	manifests := func() []Resource {
+		return []Resource{
+			newComponentContainer("web", "nginx:1.25", "frontend"),
+			newComponentContainer("api", "nginx:1.25", "backend"),
+		}
+	}
+
+	// Only the selected component was reconciled, so the other one is not drift
+	mockClient := podman.NewMockPodmanClient()
+	controller := NewReconciliationController(mockClient).(*DefaultReconciliationController)
+	ctx := WithLabelSelector(context.Background(), map[string]string{"component": "backend"})
+	if _, err := controller.Reconcile(ctx, manifests(), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if drifted, err := controller.detectDrift(ctx, manifests(), "demo"); err != nil || drifted {
+		t.Errorf("Expected no drift under the label selector, got %v (err: %v)", drifted, err)
+	}
+
+	// Prefixed labels on the containers match the prefixed manifests
+	mockClient = podman.NewMockPodmanClient()
+	controller = NewReconciliationController(mockClient, WithLabelPrefix("example.com/")).(*DefaultReconciliationController)
+	if _, err := controller.Reconcile(context.Background(), manifests(), "demo", false); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if drifted, err := controller.detectDrift(context.Background(), manifests(), "demo"); err != nil || drifted {
+		t.Errorf("Expected no drift with a label prefix, got %v (err: %v)", drifted, err)
+	}
+}